@@ -17,18 +17,27 @@ import (
 	"github.com/anigmaa/backend/internal/infrastructure/cache"
 	"github.com/anigmaa/backend/internal/infrastructure/database"
 	"github.com/anigmaa/backend/internal/infrastructure/payment"
+	"github.com/anigmaa/backend/internal/infrastructure/realtime"
 	"github.com/anigmaa/backend/internal/infrastructure/storage"
 	"github.com/anigmaa/backend/internal/repository/postgres"
+	redisRepo "github.com/anigmaa/backend/internal/repository/redis"
+	"github.com/anigmaa/backend/internal/services"
 	"github.com/anigmaa/backend/internal/usecase/analytics"
+	"github.com/anigmaa/backend/internal/usecase/calendar"
 	"github.com/anigmaa/backend/internal/usecase/community"
+	"github.com/anigmaa/backend/internal/usecase/email"
 	"github.com/anigmaa/backend/internal/usecase/event"
 	"github.com/anigmaa/backend/internal/usecase/feed_ranking"
+	"github.com/anigmaa/backend/internal/usecase/invitation"
+	"github.com/anigmaa/backend/internal/usecase/notification"
 	"github.com/anigmaa/backend/internal/usecase/post"
 	"github.com/anigmaa/backend/internal/usecase/qna"
+	"github.com/anigmaa/backend/internal/usecase/search"
 	"github.com/anigmaa/backend/internal/usecase/ticket"
 	"github.com/anigmaa/backend/internal/usecase/user"
 	"github.com/anigmaa/backend/internal/workers"
 	"github.com/anigmaa/backend/pkg/jwt"
+	"github.com/anigmaa/backend/pkg/qrcode"
 	"github.com/anigmaa/backend/pkg/validator"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -87,6 +96,9 @@ func main() {
 	defer redisClient.Close()
 	log.Println("✓ Connected to Redis cache")
 
+	// Initialize realtime hub (stream fan-out over Redis pub/sub)
+	realtimeHub := realtime.NewHub(redisClient)
+
 	// Initialize validator
 	validate := validator.New()
 
@@ -100,9 +112,29 @@ func main() {
 	}
 	log.Printf("✓ Storage initialized (type: %s)", cfg.Storage.Type)
 
-	// Initialize Midtrans payment client
-	midtransClient := payment.NewMidtransClient(&cfg.Midtrans)
-	log.Printf("✓ Midtrans client initialized (mode: %s)", map[bool]string{true: "production", false: "sandbox"}[cfg.Midtrans.IsProduction])
+	// Initialize payment gateway
+	var paymentGateway payment.Gateway
+	if cfg.Midtrans.Gateway == "fake" {
+		paymentGateway = payment.NewFakeGateway(&cfg.Midtrans)
+		log.Printf("⚠ Using fake payment gateway (webhooks to %s)", cfg.Midtrans.FakeWebhookURL)
+	} else {
+		paymentGateway = payment.NewMidtransClient(&cfg.Midtrans)
+		log.Printf("✓ Midtrans client initialized (mode: %s)", map[bool]string{true: "production", false: "sandbox"}[cfg.Midtrans.IsProduction])
+	}
+
+	// Initialize ticket QR signer
+	var qrSigner *qrcode.Signer
+	if cfg.QR.SigningKey != "" {
+		qrSigner, err = qrcode.NewSigner(cfg.QR.SigningKey)
+		if err != nil {
+			log.Fatalf("Failed to initialize QR signer: %v", err)
+		}
+		log.Println("✓ Ticket QR signer initialized")
+	} else {
+		// Only reachable outside production, see config.Validate
+		qrSigner = qrcode.NewSignerFromSecret(cfg.JWT.Secret)
+		log.Println("⚠ WARNING: QR_SIGNING_KEY not set, deriving the ticket QR key from the JWT secret. Set a dedicated key before going live: tickets signed with this key stop verifying once it changes")
+	}
 
 	// Initialize repositories
 	userRepo := postgres.NewUserRepository(db)
@@ -114,15 +146,29 @@ func main() {
 	qnaRepo := postgres.NewQnARepository(db)
 	communityRepo := postgres.NewCommunityRepository(db)
 	authTokenRepo := postgres.NewAuthTokenRepository(db)
+	authSessionRepo := postgres.NewAuthSessionRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	searchRepo := postgres.NewSearchRepository(db)
+	invitationRepo := postgres.NewInvitationRepository(db)
+	emailRepo := postgres.NewEmailRepository(db)
+	emailService := services.NewEmailService(&cfg.Email, emailRepo)
+	calendarRepo := postgres.NewCalendarRepository(db)
+	tokenRevocationRepo := redisRepo.NewTokenRevocationRepository(redisClient.GetClient())
+	timelineRepo := redisRepo.NewTimelineRepository(redisClient.GetClient())
 
 	// Initialize use cases
-	userUsecase := user.NewUsecase(userRepo, authTokenRepo, jwtManager, cfg.Google.ClientID)
-	eventUsecase := event.NewUsecase(eventRepo, userRepo)
-	postUsecase := post.NewUsecase(postRepo, commentRepo, interactionRepo, eventRepo, userRepo)
-	ticketUsecase := ticket.NewUsecase(ticketRepo, eventRepo, userRepo, midtransClient)
+	notificationUsecase := notification.NewUsecase(notificationRepo, realtimeHub)
+	emailUsecase := email.NewUsecase(emailRepo, userRepo, emailService)
+	userUsecase := user.NewUsecase(userRepo, authTokenRepo, jwtManager, cfg.Google.ClientID, notificationUsecase, emailUsecase, tokenRevocationRepo, authSessionRepo, timelineRepo)
+	ticketUsecase := ticket.NewUsecase(ticketRepo, eventRepo, userRepo, paymentGateway, realtimeHub, qrSigner, notificationUsecase, emailUsecase)
+	eventUsecase := event.NewUsecase(eventRepo, userRepo, notificationUsecase, emailUsecase, ticketUsecase, realtimeHub)
+	postUsecase := post.NewUsecase(postRepo, timelineRepo, commentRepo, interactionRepo, eventRepo, userRepo, notificationUsecase)
 	analyticsUsecase := analytics.NewUsecase(eventRepo, ticketRepo)
-	qnaUsecase := qna.NewUsecase(qnaRepo, eventRepo)
-	communityUsecase := community.NewUsecase(communityRepo)
+	qnaUsecase := qna.NewUsecase(qnaRepo, eventRepo, realtimeHub)
+	communityUsecase := community.NewUsecase(communityRepo, notificationUsecase)
+	searchUsecase := search.NewUsecase(searchRepo)
+	invitationUsecase := invitation.NewUsecase(invitationRepo, eventRepo, userRepo, notificationUsecase)
+	calendarUsecase := calendar.NewUsecase(calendarRepo, eventUsecase, cfg.Server.PublicURL)
 	feedRanker := feed_ranking.NewRanker()
 
 	// Initialize HTTP handlers
@@ -136,8 +182,13 @@ func main() {
 	qnaHandler := handler.NewQnAHandler(qnaUsecase, validate)
 	uploadHandler := handler.NewUploadHandler(storageService)
 	communityHandler := handler.NewCommunityHandler(communityUsecase, validate)
-	paymentHandler := handler.NewPaymentHandler(midtransClient, ticketUsecase)
+	paymentHandler := handler.NewPaymentHandler(paymentGateway, ticketUsecase)
 	feedRankingHandler := handler.NewFeedRankingHandler(feedRanker)
+	notificationHandler := handler.NewNotificationHandler(notificationUsecase)
+	streamHandler := handler.NewStreamHandler(realtimeHub)
+	searchHandler := handler.NewSearchHandler(searchUsecase)
+	invitationHandler := handler.NewInvitationHandler(invitationUsecase, validate)
+	calendarHandler := handler.NewCalendarHandler(calendarUsecase)
 
 	// Setup router
 	router := gin.Default()
@@ -218,17 +269,22 @@ func main() {
 			// Google OAuth only - traditional auth removed
 			// Email verification removed - Google already verifies emails
 			auth.POST("/google", authHandler.LoginWithGoogle)
+			// The refresh token authenticates itself; the access token may already be expired
+			auth.POST("/refresh", authHandler.RefreshToken)
 		}
 
 		// Protected routes (auth required)
-		authMiddleware := middleware.JWTAuth(jwtManager)
+		authMiddleware := middleware.JWTAuth(jwtManager, tokenRevocationRepo)
+		optionalAuthMiddleware := middleware.OptionalJWTAuth(jwtManager, tokenRevocationRepo)
 
 		// Auth routes (with authentication)
 		authProtected := v1.Group("/auth")
 		authProtected.Use(authMiddleware)
 		{
 			authProtected.POST("/logout", authHandler.Logout)
-			authProtected.POST("/refresh", authHandler.RefreshToken)
+			authProtected.GET("/sessions", authHandler.GetSessions)
+			authProtected.DELETE("/sessions", authHandler.RevokeOtherSessions)
+			authProtected.DELETE("/sessions/:id", authHandler.RevokeSession)
 		}
 
 		// User routes
@@ -251,12 +307,16 @@ func main() {
 			users.GET("/:id/posts", postHandler.GetUserPosts) // Get posts by user ID
 		}
 
-		// Event routes - public (no auth required)
+		// Event routes - public (auth optional, needed to see friends-only and private events)
 		events := v1.Group("/events")
+		events.Use(optionalAuthMiddleware)
 		{
 			events.GET("/:id", eventHandler.GetEventByID)
 			events.GET("/:id/attendees", eventHandler.GetEventAttendees)
+			events.GET("/:id/series", eventHandler.GetEventSeries)
 			events.GET("/:id/interest/count", eventHandler.GetEventInterestCount)
+			events.GET("/:id/tiers", ticketHandler.GetEventTiers)
+			events.GET("/:id/calendar.ics", calendarHandler.GetEventCalendar)
 		}
 
 		eventsProtected := v1.Group("/events")
@@ -286,6 +346,54 @@ func main() {
 			eventsProtected.PATCH("/:id/archive", eventHandler.ArchiveEvent)
 			eventsProtected.PATCH("/:id/unarchive", eventHandler.UnarchiveEvent)
 
+			// Join requests of events that require approval (host only)
+			eventsProtected.GET("/:id/join-requests", eventHandler.GetJoinRequests)
+			eventsProtected.POST("/:id/join-requests/approve", eventHandler.ApproveJoinRequests)
+			eventsProtected.POST("/:id/join-requests/reject", eventHandler.RejectJoinRequests)
+			eventsProtected.POST("/:id/join-requests/:userId/approve", eventHandler.ApproveJoinRequest)
+			eventsProtected.POST("/:id/join-requests/:userId/reject", eventHandler.RejectJoinRequest)
+
+			// Ticket tier management (host only)
+			eventsProtected.POST("/:id/tiers", ticketHandler.CreateTier)
+			eventsProtected.PUT("/:id/tiers/:tierId", ticketHandler.UpdateTier)
+			eventsProtected.DELETE("/:id/tiers/:tierId", ticketHandler.DeleteTier)
+
+			// Promo codes (host manages, buyers preview with /apply)
+			eventsProtected.POST("/:id/promo-codes", ticketHandler.CreatePromoCode)
+			eventsProtected.GET("/:id/promo-codes", ticketHandler.GetEventPromoCodes)
+			eventsProtected.POST("/:id/promo-codes/apply", ticketHandler.QuotePromoCode)
+			eventsProtected.PUT("/:id/promo-codes/:promoId", ticketHandler.UpdatePromoCode)
+			eventsProtected.DELETE("/:id/promo-codes/:promoId", ticketHandler.DeletePromoCode)
+
+			// Waitlist (users queue for full events, host views the queue)
+			eventsProtected.POST("/:id/waitlist", ticketHandler.JoinWaitlist)
+			eventsProtected.GET("/:id/waitlist/me", ticketHandler.GetMyWaitlistEntry)
+			eventsProtected.DELETE("/:id/waitlist", ticketHandler.LeaveWaitlist)
+			eventsProtected.GET("/:id/waitlist", ticketHandler.GetEventWaitlist)
+
+			// Staff roles (host manages co-hosts, check-in staff and Q&A moderators)
+			eventsProtected.GET("/:id/staff", eventHandler.GetEventStaff)
+			eventsProtected.PUT("/:id/staff/:userId", eventHandler.SetEventStaffRole)
+			eventsProtected.DELETE("/:id/staff/:userId", eventHandler.RemoveEventStaff)
+
+			// Check-in kiosks and live counts (host and check-in staff)
+			eventsProtected.GET("/:id/check-in-stats", ticketHandler.GetCheckInStats)
+			eventsProtected.POST("/:id/kiosk-tokens", ticketHandler.CreateKioskToken)
+			eventsProtected.GET("/:id/kiosk-tokens", ticketHandler.GetKioskTokens)
+			eventsProtected.DELETE("/:id/kiosk-tokens/:tokenId", ticketHandler.RevokeKioskToken)
+
+			// Offline check-in: download a signed ticket manifest, upload scans once back online
+			eventsProtected.GET("/:id/check-in-manifest", ticketHandler.GetCheckInManifest)
+			eventsProtected.POST("/:id/check-ins/sync", ticketHandler.SyncCheckIns)
+
+			// Invitations and invite links (host only)
+			eventsProtected.POST("/:id/invitations", invitationHandler.InviteUsers)
+			eventsProtected.GET("/:id/invitations", invitationHandler.GetEventInvitations)
+			eventsProtected.DELETE("/:id/invitations/:invitationId", invitationHandler.RevokeInvitation)
+			eventsProtected.POST("/:id/invite-links", invitationHandler.CreateInviteLink)
+			eventsProtected.GET("/:id/invite-links", invitationHandler.GetEventInviteLinks)
+			eventsProtected.DELETE("/:id/invite-links/:linkId", invitationHandler.RevokeInviteLink)
+
 			// Event Q&A endpoints
 			eventsProtected.GET("/:id/qna", qnaHandler.GetEventQnA)
 			eventsProtected.POST("/:id/qna", qnaHandler.AskQuestion)
@@ -293,6 +401,7 @@ func main() {
 
 		// Post routes - Public routes (view only)
 		postsPublic := v1.Group("/posts")
+		postsPublic.Use(optionalAuthMiddleware) // Viewer decides which followers-only posts are visible
 		{
 			// IMPORTANT: Static routes MUST come before parameterized routes
 
@@ -300,6 +409,21 @@ func main() {
 			postsPublic.GET("/:id", postHandler.GetPostByID)
 		}
 
+		// Search route - public, signed-in viewers also find what only they may see
+		searchRoutes := v1.Group("/search")
+		searchRoutes.Use(optionalAuthMiddleware)
+		{
+			searchRoutes.GET("", searchHandler.Search)
+		}
+
+		// Hashtag routes - public, viewer decides which followers-only posts are visible
+		hashtags := v1.Group("/hashtags")
+		hashtags.Use(optionalAuthMiddleware)
+		{
+			hashtags.GET("/trending", postHandler.GetTrendingHashtags)
+			hashtags.GET("/:tag/posts", postHandler.GetHashtagPosts)
+		}
+
 		// Post routes - Protected routes (require auth)
 		posts := v1.Group("/posts")
 		posts.Use(authMiddleware)
@@ -351,13 +475,20 @@ func main() {
 			tickets.GET("/my-tickets", ticketHandler.GetMyTickets)
 			tickets.GET("/:id", ticketHandler.GetTicketByID)
 			tickets.POST("/check-in", ticketHandler.CheckIn)
+			tickets.POST("/check-in/qr", ticketHandler.CheckInWithQR)
+			tickets.GET("/qr-public-key", ticketHandler.GetQRPublicKey)
 			tickets.POST("/:id/cancel", ticketHandler.CancelTicket)
+			tickets.GET("/:id/refunds", ticketHandler.GetTicketRefunds)
 			tickets.GET("/transactions/:id", ticketHandler.GetTransaction)
 		}
 
 		// Event tickets (host only)
 		v1.GET("/events/:id/tickets", authMiddleware, eventHandler.GetEventTickets)
 
+		// Payment reconciliation reports (host for their events, admin for all)
+		v1.GET("/events/:id/payment-reconciliation", authMiddleware, ticketHandler.GetEventReconciliationReport)
+		v1.GET("/admin/payment-reconciliation", authMiddleware, ticketHandler.GetReconciliationReport)
+
 		// Analytics routes (host only)
 		analytics := v1.Group("/analytics")
 		analytics.Use(authMiddleware)
@@ -372,6 +503,7 @@ func main() {
 		// These routes will always return 404 since usernames no longer exist
 		// TODO: Replace with user ID-based routes (e.g., /users/:id/profile)
 		profile := v1.Group("/profile")
+		profile.Use(optionalAuthMiddleware)
 		{
 			profile.GET("/:username", profileHandler.GetProfileByUsername)
 			profile.GET("/:username/posts", profileHandler.GetProfilePosts)
@@ -416,6 +548,55 @@ func main() {
 			communities.GET("/:id/members", communityHandler.GetCommunityMembers)
 		}
 
+		// Notification routes (protected)
+		notifications := v1.Group("/notifications")
+		notifications.Use(authMiddleware)
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+			notifications.POST("/read-all", notificationHandler.MarkAllAsRead)
+			notifications.POST("/:id/read", notificationHandler.MarkAsRead)
+		}
+
+		// Invitation routes (protected)
+		invitations := v1.Group("/invitations")
+		invitations.Use(authMiddleware)
+		{
+			invitations.GET("", invitationHandler.GetMyInvitations)
+			invitations.POST("/:id/accept", invitationHandler.AcceptInvitation)
+			invitations.POST("/:id/decline", invitationHandler.DeclineInvitation)
+		}
+
+		// Invite link routes (protected)
+		inviteLinks := v1.Group("/invite-links")
+		inviteLinks.Use(authMiddleware)
+		{
+			inviteLinks.POST("/:token/redeem", invitationHandler.RedeemInviteLink)
+		}
+
+		// Calendar feed routes (protected)
+		calendarRoutes := v1.Group("/calendar")
+		calendarRoutes.Use(authMiddleware)
+		{
+			calendarRoutes.GET("/feed", calendarHandler.GetMyCalendarFeed)
+			calendarRoutes.POST("/feed/reset", calendarHandler.ResetMyCalendarFeed)
+		}
+
+		// Calendar feed for subscriptions (public - the secret token in the URL authenticates it)
+		v1.GET("/calendar/feeds/:token", middleware.RateLimit(60, time.Minute), calendarHandler.GetCalendarFeed)
+
+		// Check-in kiosks (public - the kiosk token in the X-Kiosk-Token header authenticates them)
+		kiosk := v1.Group("/kiosk")
+		{
+			kiosk.GET("/session", ticketHandler.GetKioskSession)
+			kiosk.POST("/check-in", ticketHandler.KioskCheckIn)
+			kiosk.GET("/manifest", ticketHandler.GetKioskManifest)
+			kiosk.POST("/check-ins/sync", ticketHandler.SyncKioskCheckIns)
+		}
+
+		// Real-time stream (protected, Server-Sent Events)
+		v1.GET("/stream", authMiddleware, streamHandler.Stream)
+
 		// Webhook routes (public - no auth required)
 		// Tighter rate limit: Midtrans retries at most ~10x, so 30/min is generous.
 		webhooks := v1.Group("/webhooks")
@@ -431,6 +612,9 @@ func main() {
 			payments.POST("/initiate", paymentHandler.InitiatePayment)
 			payments.POST("/status", paymentHandler.CheckPaymentStatus)
 			payments.GET("/client-key", paymentHandler.GetClientKey)
+			if cfg.Midtrans.Gateway == "fake" {
+				payments.POST("/fake/:order_id/simulate", paymentHandler.SimulatePayment)
+			}
 		}
 	}
 
@@ -440,6 +624,27 @@ func main() {
 	expiryWorker := workers.NewTicketExpiryWorker(ticketUsecase, 5*time.Minute)
 	go expiryWorker.Start(workerCtx)
 	log.Println("✓ Ticket expiry worker started")
+	reconciliationWorker := workers.NewPaymentReconciliationWorker(ticketUsecase, 10*time.Minute, 15*time.Minute)
+	go reconciliationWorker.Start(workerCtx)
+	log.Println("✓ Payment reconciliation worker started")
+	waitlistWorker := workers.NewWaitlistWorker(ticketUsecase, time.Minute)
+	go waitlistWorker.Start(workerCtx)
+	log.Println("✓ Waitlist worker started")
+	seriesWorker := workers.NewEventSeriesWorker(eventUsecase, time.Hour)
+	go seriesWorker.Start(workerCtx)
+	log.Println("✓ Event series worker started")
+	lifecycleWorker := workers.NewEventLifecycleWorker(eventUsecase, redisClient, time.Minute)
+	go lifecycleWorker.Start(workerCtx)
+	log.Println("✓ Event lifecycle worker started")
+	if emailService.IsConfigured() {
+		outboxWorker := workers.NewEmailOutboxWorker(emailUsecase, 30*time.Second)
+		go outboxWorker.Start(workerCtx)
+		log.Println("✓ Email outbox worker started")
+	} else {
+		log.Println("⚠ SMTP_HOST not set, emails are dropped instead of sent")
+	}
+	go realtimeHub.Run(workerCtx)
+	log.Println("✓ Realtime hub started")
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.Server.Port)
@@ -20,13 +20,16 @@ type Config struct {
 	Midtrans MidtransConfig
 	Google   GoogleConfig
 	CORS     CORSConfig
+	QR       QRConfig
+	Email    EmailConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port     string
-	Env      string
-	LogLevel string
+	Port      string
+	Env       string
+	LogLevel  string
+	PublicURL string // public base URL of the API, used in links it hands out such as calendar feeds
 }
 
 // DatabaseConfig holds database configuration
@@ -72,6 +75,14 @@ type MidtransConfig struct {
 	ServerKey    string
 	ClientKey    string
 	IsProduction bool
+	BaseURL      string // optional override of the Midtrans host, e.g. a local stand-in
+
+	// Gateway selects the payment provider: "midtrans" (default) or "fake"
+	Gateway string
+	// Fake gateway settings (local development and integration tests only)
+	FakeWebhookURL string        // where simulated webhooks are delivered
+	FakeOutcome    string        // settlement, expire or fraud; empty = only on demand
+	FakeDelay      time.Duration // delay before FakeOutcome is applied
 }
 
 // GoogleConfig holds Google OAuth configuration
@@ -79,6 +90,21 @@ type GoogleConfig struct {
 	ClientID string
 }
 
+// QRConfig holds ticket QR signing configuration
+type QRConfig struct {
+	SigningKey string // base64-encoded 32-byte Ed25519 seed
+}
+
+// EmailConfig holds SMTP configuration for transactional email
+type EmailConfig struct {
+	SMTPHost     string // empty = emails are dropped, not sent; required in production
+	SMTPPort     int
+	SMTPUsername string // optional, e.g. for a local SMTP stand-in
+	SMTPPassword string
+	FromEmail    string
+	FromName     string
+}
+
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
 	AllowedOrigins []string
@@ -91,11 +117,14 @@ func Load() (*Config, error) {
 		log.Println("No .env file found, reading from environment variables")
 	}
 
+	port := getEnv("PORT", "8080")
+
 	config := &Config{
 		Server: ServerConfig{
-			Port:     getEnv("PORT", "8080"),
-			Env:      getEnv("ENV", "development"),
-			LogLevel: getEnv("LOG_LEVEL", "debug"),
+			Port:      port,
+			Env:       getEnv("ENV", "development"),
+			LogLevel:  getEnv("LOG_LEVEL", "debug"),
+			PublicURL: getEnv("PUBLIC_URL", ""),
 		},
 		Database: DatabaseConfig{
 			Host:         getEnv("DB_HOST", "localhost"),
@@ -131,6 +160,12 @@ func Load() (*Config, error) {
 			ServerKey:    getEnv("MIDTRANS_SERVER_KEY", ""),
 			ClientKey:    getEnv("MIDTRANS_CLIENT_KEY", ""),
 			IsProduction: getEnvAsBool("MIDTRANS_IS_PRODUCTION", false),
+			BaseURL:      getEnv("MIDTRANS_BASE_URL", ""),
+
+			Gateway:        getEnv("PAYMENT_GATEWAY", "midtrans"),
+			FakeWebhookURL: getEnv("PAYMENT_FAKE_WEBHOOK_URL", "http://localhost:"+port+"/api/v1/webhooks/midtrans"),
+			FakeOutcome:    getEnv("PAYMENT_FAKE_OUTCOME", ""),
+			FakeDelay:      parseDuration(getEnv("PAYMENT_FAKE_DELAY", "3s")),
 		},
 		Google: GoogleConfig{
 			ClientID: getEnv("GOOGLE_CLIENT_ID", ""),
@@ -138,6 +173,22 @@ func Load() (*Config, error) {
 		CORS: CORSConfig{
 			AllowedOrigins: getEnvAsSlice("ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
 		},
+		QR: QRConfig{
+			SigningKey: getEnv("QR_SIGNING_KEY", ""),
+		},
+		Email: EmailConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FromEmail:    getEnv("EMAIL_FROM", "noreply@anigmaa.com"),
+			FromName:     getEnv("EMAIL_FROM_NAME", "Anigmaa"),
+		},
+	}
+
+	// Outside production the links point at this server itself
+	if config.Server.PublicURL == "" && config.Server.Env != "production" {
+		config.Server.PublicURL = "http://localhost:" + port
 	}
 
 	// Validate required config
@@ -159,6 +210,21 @@ func (c *Config) Validate() error {
 	if c.JWT.Secret == "" || c.JWT.Secret == "your-secret-key" {
 		return fmt.Errorf("JWT_SECRET must be set with a secure value")
 	}
+	if c.Midtrans.Gateway != "midtrans" && c.Midtrans.Gateway != "fake" {
+		return fmt.Errorf("PAYMENT_GATEWAY must be midtrans or fake")
+	}
+	if c.Midtrans.Gateway == "fake" && c.Server.Env == "production" {
+		return fmt.Errorf("PAYMENT_GATEWAY=fake is not allowed in production")
+	}
+	if c.QR.SigningKey == "" && c.Server.Env == "production" {
+		return fmt.Errorf("QR_SIGNING_KEY is required in production")
+	}
+	if c.Server.PublicURL == "" {
+		return fmt.Errorf("PUBLIC_URL is required in production")
+	}
+	if c.Email.SMTPHost == "" && c.Server.Env == "production" {
+		return fmt.Errorf("SMTP_HOST is required in production")
+	}
 	return nil
 }
 
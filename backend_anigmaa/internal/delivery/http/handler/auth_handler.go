@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/anigmaa/backend/internal/delivery/http/middleware"
+	"github.com/anigmaa/backend/internal/domain/auth"
 	"github.com/anigmaa/backend/internal/domain/user"
 	userUsecase "github.com/anigmaa/backend/internal/usecase/user"
 	"github.com/anigmaa/backend/pkg/response"
@@ -28,26 +29,44 @@ func NewAuthHandler(userUsecase *userUsecase.Usecase, validator *validator.Valid
 
 // Logout godoc
 // @Summary Logout user
-// @Description Logout the current user (invalidate token)
+// @Description Logout the current user. Revokes the access token used for this request and, when provided, the refresh token.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body map[string]string false "Optional refresh token to revoke"
 // @Success 200 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// In a stateless JWT system, logout is typically handled on the client side
-	// The client should remove the token from storage
-	// For server-side logout, you would need to implement token blacklisting
-	// using Redis or similar storage
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// Body is optional; older clients send none
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	if err := h.userUsecase.Logout(c.Request.Context(), claims, req.RefreshToken); err != nil {
+		if err == userUsecase.ErrUnauthorized {
+			response.Unauthorized(c, "Refresh token does not belong to this user")
+			return
+		}
+		response.InternalError(c, "Failed to logout", err.Error())
+		return
+	}
 
 	response.Success(c, http.StatusOK, "Logout successful", nil)
 }
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Get a new access token using a refresh token
+// @Description Exchange a refresh token for a new access/refresh token pair. The presented refresh token is rotated and cannot be used again; replaying it revokes the whole device session.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -71,10 +90,14 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	// Call usecase
 	authResp, err := h.userUsecase.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		if err == userUsecase.ErrUnauthorized {
+		if err == userUsecase.ErrUnauthorized || err == userUsecase.ErrUserNotFound {
 			response.Unauthorized(c, "Invalid or expired refresh token")
 			return
 		}
+		if err == userUsecase.ErrTokenReused {
+			response.Unauthorized(c, "Refresh token reuse detected, please login again")
+			return
+		}
 		response.InternalError(c, "Failed to refresh token", err.Error())
 		return
 	}
@@ -237,7 +260,12 @@ func (h *AuthHandler) LoginWithGoogle(c *gin.Context) {
 	}
 
 	// Call usecase
-	authResp, err := h.userUsecase.LoginWithGoogle(c.Request.Context(), &req)
+	device := &auth.DeviceInfo{
+		DeviceName: c.GetHeader("X-Device-Name"),
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+	}
+	authResp, err := h.userUsecase.LoginWithGoogle(c.Request.Context(), &req, device)
 	if err != nil {
 		response.Error(c, http.StatusUnauthorized, err.Error(), "UNAUTHORIZED", "")
 		return
@@ -245,3 +273,100 @@ func (h *AuthHandler) LoginWithGoogle(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Login successful", authResp)
 }
+
+// GetSessions godoc
+// @Summary List active sessions
+// @Description List the current user's active device sessions. The session of the calling token is flagged with is_current.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]auth.SessionResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /auth/sessions [get]
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sessions, err := h.userUsecase.GetSessions(c.Request.Context(), claims.UserID, claims.GetSessionID())
+	if err != nil {
+		response.InternalError(c, "Failed to get sessions", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Sessions retrieved successfully", sessions)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Revoke one of the current user's device sessions. Its refresh token stops working immediately.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	// Parse session ID from path
+	sessionIDStr := c.Param("id")
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid session ID", err.Error())
+		return
+	}
+
+	if err := h.userUsecase.RevokeSession(c.Request.Context(), claims.UserID, sessionID); err != nil {
+		if err == userUsecase.ErrSessionNotFound {
+			response.NotFound(c, "Session not found")
+			return
+		}
+		response.InternalError(c, "Failed to revoke session", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Session revoked successfully", nil)
+}
+
+// RevokeOtherSessions godoc
+// @Summary Revoke all other sessions
+// @Description Revoke every device session of the current user except the one making this request
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /auth/sessions [delete]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	claims, exists := middleware.GetTokenClaims(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	revoked, err := h.userUsecase.RevokeOtherSessions(c.Request.Context(), claims.UserID, claims.GetSessionID())
+	if err != nil {
+		response.InternalError(c, "Failed to revoke sessions", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Other sessions revoked successfully", gin.H{
+		"revoked": revoked,
+	})
+}
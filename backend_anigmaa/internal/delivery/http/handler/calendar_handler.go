@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/anigmaa/backend/internal/delivery/http/middleware"
+	calendarUsecase "github.com/anigmaa/backend/internal/usecase/calendar"
+	"github.com/anigmaa/backend/pkg/ical"
+	"github.com/anigmaa/backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CalendarHandler handles iCalendar export and calendar feed HTTP requests
+type CalendarHandler struct {
+	calendarUsecase *calendarUsecase.Usecase
+}
+
+// NewCalendarHandler creates a new calendar handler
+func NewCalendarHandler(calendarUsecase *calendarUsecase.Usecase) *CalendarHandler {
+	return &CalendarHandler{
+		calendarUsecase: calendarUsecase,
+	}
+}
+
+// GetEventCalendar godoc
+// @Summary Export an event as iCalendar
+// @Description Download an event as an .ics file to add it to Google, Apple or Outlook Calendar. Importing it again after the event changed updates the calendar entry.
+// @Tags calendar
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {file} file "iCalendar file"
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/calendar.ics [get]
+func (h *CalendarHandler) GetEventCalendar(c *gin.Context) {
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	// Get user ID from context (optional, needed for friends-only and private events)
+	userIDStr, _ := middleware.GetUserID(c)
+	userID, _ := uuid.Parse(userIDStr)
+
+	ics, err := h.calendarUsecase.GetEventCalendar(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleCalendarError(c, err, "Failed to export event")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%s.ics"`, eventID))
+	c.Data(http.StatusOK, ical.ContentType, ics)
+}
+
+// GetMyCalendarFeed godoc
+// @Summary Get my calendar feed
+// @Description Get the current user's calendar feed, which calendar apps can subscribe to. It lists the upcoming events the user holds tickets for and the events they host. The feed is created on first use, and its secret URL is only returned then; reset the feed to get a new URL.
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=calendar.FeedResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/feed [get]
+func (h *CalendarHandler) GetMyCalendarFeed(c *gin.Context) {
+	userID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.calendarUsecase.GetFeedURL(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalError(c, "Failed to get calendar feed", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Calendar feed retrieved successfully", feed)
+}
+
+// ResetMyCalendarFeed godoc
+// @Summary Reset my calendar feed URL
+// @Description Replace the secret URL of the current user's calendar feed. Calendar apps subscribed to the old URL stop receiving updates.
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=calendar.FeedResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/feed/reset [post]
+func (h *CalendarHandler) ResetMyCalendarFeed(c *gin.Context) {
+	userID, ok := h.currentUserID(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.calendarUsecase.ResetFeedURL(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalError(c, "Failed to reset calendar feed", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Calendar feed reset successfully", feed)
+}
+
+// GetCalendarFeed godoc
+// @Summary Calendar feed
+// @Description iCalendar feed for calendar app subscriptions. The token in the path authenticates the request; get the URL from GET /calendar/feed. The .ics suffix is optional.
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally followed by .ics"
+// @Success 200 {file} file "iCalendar feed"
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/feeds/{token} [get]
+func (h *CalendarHandler) GetCalendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	ics, err := h.calendarUsecase.GetFeed(c.Request.Context(), token)
+	if err != nil {
+		h.handleCalendarError(c, err, "Failed to get calendar feed")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, ical.ContentType, ics)
+}
+
+// currentUserID gets the authenticated user's ID, responding with an error if there's none
+func (h *CalendarHandler) currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// handleCalendarError maps calendar usecase errors to HTTP responses
+func (h *CalendarHandler) handleCalendarError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, calendarUsecase.ErrEventNotFound):
+		response.NotFound(c, "Event not found")
+	case errors.Is(err, calendarUsecase.ErrEventForbidden):
+		response.Forbidden(c, "You don't have access to this event")
+	case errors.Is(err, calendarUsecase.ErrFeedNotFound):
+		response.NotFound(c, "Calendar feed not found")
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -82,6 +83,10 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 			response.BadRequest(c, "Cannot create event in the past", err.Error())
 			return
 		}
+		if errors.Is(err, eventUsecase.ErrInvalidRecurrence) || err == eventUsecase.ErrInvalidTimezone {
+			response.BadRequest(c, "Invalid recurrence", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to create event", err.Error())
 		return
 	}
@@ -95,6 +100,7 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param q query string false "Full-text search over title, description and location"
 // @Param category query string false "Event category"
 // @Param is_free query bool false "Filter free events"
 // @Param status query string false "Event status"
@@ -103,8 +109,10 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 // @Param lng query number false "Longitude for location-based search"
 // @Param radius query number false "Search radius in kilometers"
 // @Param limit query int false "Limit" default(20)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {object} response.Response{data=[]event.EventWithDetails}
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page (default order only)"
+// @Param offset query int false "Offset (deprecated without mode, use cursor)" default(0)
+// @Param include_total query bool false "Also count all matching events" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]event.EventWithDetails}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /events [get]
@@ -116,36 +124,45 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 		return
 	}
 
-	// Set defaults
-	if filter.Limit == 0 {
-		filter.Limit = 20
-	}
-
-	// Store original limit and offset for pagination
-	originalLimit := filter.Limit
-	originalOffset := filter.Offset
-
-	// Get total count for pagination
-	total, err := h.eventUsecase.CountEvents(c.Request.Context(), &filter)
-	if err != nil {
-		// If count fails, default to 0 but continue
-		total = 0
-	}
+	// Parse pagination parameters; limit and offset are bound into the filter too
+	page := parsePageQuery(c)
+	filter.Limit = page.Limit
+	filter.Offset = page.Offset
 
 	// Get user ID from context (optional, for checking interest status)
 	userIDStr, _ := middleware.GetUserID(c)
 	userID, _ := uuid.Parse(userIDStr)
 
 	// Call usecase
-	events, err := h.eventUsecase.ListEvents(c.Request.Context(), &filter, userID)
+	events, nextCursor, err := h.eventUsecase.ListEvents(c.Request.Context(), &filter, userID, page.Cursor)
 	if err != nil {
+		if err == eventUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to get events", err.Error())
 		return
 	}
 
-	// Create pagination metadata with correct total
-	meta := response.NewPaginationMeta(total, originalLimit, originalOffset, len(events))
-	response.Paginated(c, http.StatusOK, "Events retrieved successfully", events, meta)
+	// Discovery modes are ranked and paged by offset only, so they keep the total count
+	if filter.Mode != "" {
+		total, err := h.eventUsecase.CountEvents(c.Request.Context(), &filter, userID)
+		if err != nil {
+			// If count fails, default to 0 but continue
+			total = 0
+		}
+		meta := response.NewPaginationMeta(total, filter.Limit, filter.Offset, len(events))
+		response.Paginated(c, http.StatusOK, "Events retrieved successfully", events, meta)
+		return
+	}
+
+	// Total count is optional
+	total := 0
+	if page.IncludeTotal {
+		total, _ = h.eventUsecase.CountEvents(c.Request.Context(), &filter, userID)
+	}
+
+	response.Paginated(c, http.StatusOK, "Events retrieved successfully", events, page.meta(total, nextCursor))
 }
 
 // GetEventByID godoc
@@ -158,6 +175,7 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 // @Param id path string true "Event ID" format(uuid)
 // @Success 200 {object} response.Response{data=event.EventWithDetails}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /events/{id} [get]
@@ -181,6 +199,10 @@ func (h *EventHandler) GetEventByID(c *gin.Context) {
 			response.NotFound(c, "Event not found")
 			return
 		}
+		if err == eventUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
 		response.InternalError(c, "Failed to get event", err.Error())
 		return
 	}
@@ -190,7 +212,7 @@ func (h *EventHandler) GetEventByID(c *gin.Context) {
 
 // UpdateEvent godoc
 // @Summary Update event
-// @Description Update an existing event (host only)
+// @Description Update an existing event (host only). Occurrences of a series are edited on their own unless apply_to is "series", which also updates the upcoming occurrences and can change the recurrence.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -202,6 +224,7 @@ func (h *EventHandler) GetEventByID(c *gin.Context) {
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /events/{id} [put]
 func (h *EventHandler) UpdateEvent(c *gin.Context) {
@@ -255,6 +278,18 @@ func (h *EventHandler) UpdateEvent(c *gin.Context) {
 			response.BadRequest(c, "End time must be after start time", err.Error())
 			return
 		}
+		if err == eventUsecase.ErrCapacityBelowSold {
+			response.Conflict(c, "Max attendees can't be lower than the tickets already sold", err.Error())
+			return
+		}
+		if err == eventUsecase.ErrNotSeriesEvent || err == eventUsecase.ErrInvalidApplyTo {
+			response.BadRequest(c, "Invalid series update", err.Error())
+			return
+		}
+		if errors.Is(err, eventUsecase.ErrInvalidRecurrence) || err == eventUsecase.ErrInvalidTimezone {
+			response.BadRequest(c, "Invalid recurrence", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to update event", err.Error())
 		return
 	}
@@ -318,15 +353,17 @@ func (h *EventHandler) DeleteEvent(c *gin.Context) {
 
 // JoinEvent godoc
 // @Summary Join an event
-// @Description Join an event as an attendee
+// @Description Join an event as an attendee. Events that require approval get a pending join request instead, with the optional answer to the event's requirements.
 // @Tags events
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Event ID" format(uuid)
+// @Param request body event.JoinEventRequest false "Answer to the event's requirements"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -353,12 +390,26 @@ func (h *EventHandler) JoinEvent(c *gin.Context) {
 		return
 	}
 
+	// The body is optional; it only carries the answer to the event's requirements
+	var req event.JoinEventRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err.Error())
+			return
+		}
+	}
+
 	// Call usecase
-	if err := h.eventUsecase.JoinEvent(c.Request.Context(), eventID, userID); err != nil {
+	status, err := h.eventUsecase.JoinEvent(c.Request.Context(), eventID, userID, &req)
+	if err != nil {
 		if err == eventUsecase.ErrEventNotFound {
 			response.NotFound(c, "Event not found")
 			return
 		}
+		if err == eventUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
 		if err == eventUsecase.ErrEventFull {
 			response.Conflict(c, "Event is full", err.Error())
 			return
@@ -367,16 +418,29 @@ func (h *EventHandler) JoinEvent(c *gin.Context) {
 			response.Conflict(c, "Already joined this event", err.Error())
 			return
 		}
+		if err == eventUsecase.ErrJoinPending {
+			response.Conflict(c, "Join request is awaiting approval", err.Error())
+			return
+		}
+		if err == eventUsecase.ErrJoinRejected {
+			response.Forbidden(c, "Your join request was rejected by the host")
+			return
+		}
 		response.InternalError(c, "Failed to join event", err.Error())
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Joined event successfully", nil)
+	data := gin.H{"status": status}
+	if status == event.AttendeePending {
+		response.Success(c, http.StatusOK, "Join request sent", data)
+		return
+	}
+	response.Success(c, http.StatusOK, "Joined event successfully", data)
 }
 
 // LeaveEvent godoc
 // @Summary Leave an event
-// @Description Leave an event you have joined
+// @Description Leave an event you have joined, or withdraw a pending join request
 // @Tags events
 // @Accept json
 // @Produce json
@@ -484,8 +548,12 @@ func (h *EventHandler) GetNearbyEvents(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
+	// Get user ID from context (optional, for friends-only and private events)
+	userIDStr, _ := middleware.GetUserID(c)
+	userID, _ := uuid.Parse(userIDStr)
+
 	// Request limit+1 to check if there are more results
-	events, err := h.eventUsecase.GetNearbyEvents(c.Request.Context(), lat, lng, radius, limit+1)
+	events, err := h.eventUsecase.GetNearbyEvents(c.Request.Context(), lat, lng, radius, limit+1, userID)
 	if err != nil {
 		response.InternalError(c, "Failed to get nearby events", err.Error())
 		return
@@ -534,7 +602,7 @@ func (h *EventHandler) GetMyEvents(c *gin.Context) {
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
 	// Request limit+1 to check if there are more results
-	events, err := h.eventUsecase.GetEventsByHost(c.Request.Context(), userID, limit+1, offset)
+	events, err := h.eventUsecase.GetEventsByHost(c.Request.Context(), userID, userID, limit+1, offset)
 	if err != nil {
 		response.InternalError(c, "Failed to get events", err.Error())
 		return
@@ -583,14 +651,14 @@ func (h *EventHandler) GetHostedEvents(c *gin.Context) {
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
 	// Get total count for pagination
-	total, err := h.eventUsecase.CountHostedEvents(c.Request.Context(), userID)
+	total, err := h.eventUsecase.CountHostedEvents(c.Request.Context(), userID, userID)
 	if err != nil {
 		// If count fails, default to 0 but continue
 		total = 0
 	}
 
 	// Get hosted events
-	events, err := h.eventUsecase.GetByHost(c.Request.Context(), userID, limit, offset)
+	events, err := h.eventUsecase.GetByHost(c.Request.Context(), userID, userID, limit, offset)
 	if err != nil {
 		response.InternalError(c, "Failed to get hosted events", err.Error())
 		return
@@ -662,6 +730,7 @@ func (h *EventHandler) GetJoinedEvents(c *gin.Context) {
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} response.Response{data=[]map[string]interface{}}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /events/{id}/attendees [get]
@@ -674,6 +743,24 @@ func (h *EventHandler) GetEventAttendees(c *gin.Context) {
 		return
 	}
 
+	// Get user ID from context (optional, for friends-only and private events)
+	userIDStr, _ := middleware.GetUserID(c)
+	userID, _ := uuid.Parse(userIDStr)
+
+	// Attendees of friends-only and private events are only listed to people with access
+	if err := h.eventUsecase.CheckEventAccess(c.Request.Context(), eventID, userID); err != nil {
+		if err == eventUsecase.ErrEventNotFound {
+			response.NotFound(c, "Event not found")
+			return
+		}
+		if err == eventUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
+		response.InternalError(c, "Failed to get attendees", err.Error())
+		return
+	}
+
 	// Parse query parameters
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
@@ -704,7 +791,7 @@ func (h *EventHandler) GetEventAttendees(c *gin.Context) {
 	}
 
 	// Get free event attendees from event_attendees table
-	freeAttendees, freeCount, err := h.eventUsecase.GetAttendeesWithDetails(c.Request.Context(), eventID, limit+offset, 0)
+	freeAttendees, freeCount, err := h.eventUsecase.GetAttendeesWithDetails(c.Request.Context(), eventID, userID, limit+offset, 0)
 	if err == nil {
 		// Convert to map format
 		for _, fa := range freeAttendees {
@@ -744,7 +831,7 @@ func (h *EventHandler) GetEventAttendees(c *gin.Context) {
 
 // GetEventTickets godoc
 // @Summary Get event tickets
-// @Description Get all tickets for an event (host only)
+// @Description Get all tickets for an event (host and co-hosts only)
 // @Tags events
 // @Accept json
 // @Produce json
@@ -752,7 +839,7 @@ func (h *EventHandler) GetEventAttendees(c *gin.Context) {
 // @Param id path string true "Event ID" format(uuid)
 // @Param limit query int false "Limit" default(50)
 // @Param offset query int false "Offset" default(0)
-// @Success 200 {object} response.Response
+// @Success 200 {object} response.Response{data=[]ticket.TicketWithDetails}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
@@ -767,7 +854,7 @@ func (h *EventHandler) GetEventTickets(c *gin.Context) {
 		return
 	}
 
-	_, err := uuid.Parse(userIDStr)
+	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
 		response.BadRequest(c, "Invalid user ID", err.Error())
 		return
@@ -785,13 +872,20 @@ func (h *EventHandler) GetEventTickets(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	// Note: This would call a ticket usecase method
-	// For now, return a placeholder response
-	response.Success(c, http.StatusOK, "Event tickets endpoint (to be implemented with ticket usecase)", gin.H{
-		"event_id": eventID,
-		"limit":    limit,
-		"offset":   offset,
-	})
+	tickets, err := h.ticketUsecase.GetEventTickets(c.Request.Context(), eventID, userID, limit, offset)
+	if err != nil {
+		switch err {
+		case ticketUsecase.ErrEventNotFound:
+			response.NotFound(c, "Event not found")
+		case ticketUsecase.ErrUnauthorized:
+			response.Forbidden(c, "Only the event host and co-hosts can view its tickets")
+		default:
+			response.InternalError(c, "Failed to get event tickets", err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Event tickets retrieved successfully", tickets)
 }
 
 // AddEventImages godoc
@@ -933,6 +1027,7 @@ func (h *EventHandler) DeleteEventImage(c *gin.Context) {
 // @Success 200 {object} response.Response{data=map[string]interface{}}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /events/{id}/interest [post]
@@ -965,12 +1060,16 @@ func (h *EventHandler) ToggleEventInterest(c *gin.Context) {
 			response.NotFound(c, "Event not found")
 			return
 		}
+		if err == eventUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
 		response.InternalError(c, "Failed to toggle interest", err.Error())
 		return
 	}
 
 	// Get current interest count
-	interestCount, _ := h.eventUsecase.GetInterestsCount(c.Request.Context(), eventID)
+	interestCount, _ := h.eventUsecase.GetInterestsCount(c.Request.Context(), eventID, userID)
 
 	// Return response
 	data := map[string]interface{}{
@@ -992,6 +1091,7 @@ func (h *EventHandler) ToggleEventInterest(c *gin.Context) {
 // @Success 200 {object} response.Response{data=map[string]interface{}}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /events/{id}/interest/status [get]
@@ -1024,12 +1124,16 @@ func (h *EventHandler) GetEventInterestStatus(c *gin.Context) {
 			response.NotFound(c, "Event not found")
 			return
 		}
+		if err == eventUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
 		response.InternalError(c, "Failed to check interest status", err.Error())
 		return
 	}
 
 	// Get current interest count
-	interestCount, _ := h.eventUsecase.GetInterestsCount(c.Request.Context(), eventID)
+	interestCount, _ := h.eventUsecase.GetInterestsCount(c.Request.Context(), eventID, userID)
 
 	// Return response
 	data := map[string]interface{}{
@@ -1049,6 +1153,7 @@ func (h *EventHandler) GetEventInterestStatus(c *gin.Context) {
 // @Param id path string true "Event ID" format(uuid)
 // @Success 200 {object} response.Response{data=map[string]interface{}}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /events/{id}/interest/count [get]
@@ -1061,13 +1166,21 @@ func (h *EventHandler) GetEventInterestCount(c *gin.Context) {
 		return
 	}
 
+	// Get user ID from context (optional, for friends-only and private events)
+	userIDStr, _ := middleware.GetUserID(c)
+	userID, _ := uuid.Parse(userIDStr)
+
 	// Get interest count
-	interestCount, err := h.eventUsecase.GetInterestsCount(c.Request.Context(), eventID)
+	interestCount, err := h.eventUsecase.GetInterestsCount(c.Request.Context(), eventID, userID)
 	if err != nil {
 		if err == eventUsecase.ErrEventNotFound {
 			response.NotFound(c, "Event not found")
 			return
 		}
+		if err == eventUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
 		response.InternalError(c, "Failed to get interest count", err.Error())
 		return
 	}
@@ -1189,3 +1302,444 @@ func (h *EventHandler) UnarchiveEvent(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Event unarchived successfully", updatedEvent)
 }
+
+// GetJoinRequests godoc
+// @Summary Get join requests of an event
+// @Description Get the join requests of an event that requires approval, oldest first, with the requesters' answers to the event's requirements (host only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param status query string false "Request status (pending, confirmed, rejected)" default(pending)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.PaginatedResponse{data=[]event.JoinRequest}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/join-requests [get]
+func (h *EventHandler) GetJoinRequests(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	status := event.AttendeeStatus(c.DefaultQuery("status", string(event.AttendeePending)))
+	switch status {
+	case event.AttendeePending, event.AttendeeConfirmed, event.AttendeeRejected:
+	default:
+		response.BadRequest(c, "Invalid status", "status must be one of pending, confirmed, rejected")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	requests, total, err := h.eventUsecase.GetJoinRequests(c.Request.Context(), eventID, userID, status, limit, offset)
+	if err != nil {
+		h.handleJoinRequestError(c, err, "Failed to get join requests")
+		return
+	}
+
+	meta := response.NewPaginationMeta(total, limit, offset, len(requests))
+	response.Paginated(c, http.StatusOK, "Join requests retrieved successfully", requests, meta)
+}
+
+// ApproveJoinRequests godoc
+// @Summary Approve join requests
+// @Description Approve the pending join requests of several users at once (host only). Fails when approving them all would overbook the event.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body event.ReviewJoinRequestsRequest true "Users to approve"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/join-requests/approve [post]
+func (h *EventHandler) ApproveJoinRequests(c *gin.Context) {
+	h.reviewJoinRequests(c, true)
+}
+
+// RejectJoinRequests godoc
+// @Summary Reject join requests
+// @Description Reject the pending join requests of several users at once (host only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body event.ReviewJoinRequestsRequest true "Users to reject"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/join-requests/reject [post]
+func (h *EventHandler) RejectJoinRequests(c *gin.Context) {
+	h.reviewJoinRequests(c, false)
+}
+
+// ApproveJoinRequest godoc
+// @Summary Approve a join request
+// @Description Approve the pending join request of a user (host only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/join-requests/{userId}/approve [post]
+func (h *EventHandler) ApproveJoinRequest(c *gin.Context) {
+	h.reviewJoinRequests(c, true)
+}
+
+// RejectJoinRequest godoc
+// @Summary Reject a join request
+// @Description Reject the pending join request of a user (host only)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/join-requests/{userId}/reject [post]
+func (h *EventHandler) RejectJoinRequest(c *gin.Context) {
+	h.reviewJoinRequests(c, false)
+}
+
+// reviewJoinRequests approves or rejects the join request of the userId path
+// parameter, or of the users in the request body when there is none
+func (h *EventHandler) reviewJoinRequests(c *gin.Context, approve bool) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	hostID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	var req event.ReviewJoinRequestsRequest
+	if requesterIDStr := c.Param("userId"); requesterIDStr != "" {
+		requesterID, err := uuid.Parse(requesterIDStr)
+		if err != nil {
+			response.BadRequest(c, "Invalid user ID", err.Error())
+			return
+		}
+		req.UserIDs = []uuid.UUID{requesterID}
+	} else {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err.Error())
+			return
+		}
+		if err := h.validator.Validate(&req); err != nil {
+			response.BadRequest(c, "Validation failed", err.Error())
+			return
+		}
+	}
+
+	reviewed, err := h.eventUsecase.ReviewJoinRequests(c.Request.Context(), eventID, hostID, req.UserIDs, approve)
+	if err != nil {
+		h.handleJoinRequestError(c, err, "Failed to review join requests")
+		return
+	}
+
+	// A single request that wasn't pending is reported as missing
+	if c.Param("userId") != "" && len(reviewed) == 0 {
+		response.NotFound(c, "Pending join request not found")
+		return
+	}
+
+	message := "Join requests rejected"
+	if approve {
+		message = "Join requests approved"
+	}
+	response.Success(c, http.StatusOK, message, gin.H{"user_ids": reviewed})
+}
+
+// handleJoinRequestError maps join request errors of the event usecase to HTTP responses
+func (h *EventHandler) handleJoinRequestError(c *gin.Context, err error, message string) {
+	switch err {
+	case eventUsecase.ErrEventNotFound:
+		response.NotFound(c, "Event not found")
+	case eventUsecase.ErrUnauthorized:
+		response.Forbidden(c, "Only the event host can review join requests")
+	case eventUsecase.ErrNotApprovalEvent:
+		response.BadRequest(c, "Event does not require approval", err.Error())
+	case eventUsecase.ErrEventFull:
+		response.Conflict(c, "Not enough spots left to approve these requests", err.Error())
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
+
+// GetEventSeries godoc
+// @Summary Get the series of an event
+// @Description Get the recurring series an event is an occurrence of, with its upcoming occurrences
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=event.SeriesWithOccurrences}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/series [get]
+func (h *EventHandler) GetEventSeries(c *gin.Context) {
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	// Get user ID from context (optional, for friends-only and private events)
+	userIDStr, _ := middleware.GetUserID(c)
+	userID, _ := uuid.Parse(userIDStr)
+
+	series, err := h.eventUsecase.GetEventSeries(c.Request.Context(), eventID, userID)
+	if err != nil {
+		if err == eventUsecase.ErrEventNotFound {
+			response.NotFound(c, "Event not found")
+			return
+		}
+		if err == eventUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
+		if err == eventUsecase.ErrNotSeriesEvent {
+			response.NotFound(c, "Event is not part of a series")
+			return
+		}
+		response.InternalError(c, "Failed to get event series", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Event series retrieved successfully", series)
+}
+
+// GetEventStaff godoc
+// @Summary Get the staff of an event
+// @Description Get the co-hosts, check-in staff and Q&A moderators of an event (host and staff only)
+// @Tags events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=[]event.StaffWithUser}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/staff [get]
+func (h *EventHandler) GetEventStaff(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	staff, err := h.eventUsecase.GetStaff(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleStaffError(c, err, "Failed to get event staff")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Event staff retrieved successfully", staff)
+}
+
+// SetEventStaffRole godoc
+// @Summary Give a user a role on an event
+// @Description Make a user a co-host, check-in staff or Q&A moderator of an event, replacing the role they had (host only). Co-hosts can check in attendees, moderate Q&A and see the attendee list; check-in staff can check in attendees and issue kiosk tokens; Q&A moderators can answer and remove questions.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param userId path string true "User ID" format(uuid)
+// @Param request body event.SetStaffRoleRequest true "Role"
+// @Success 200 {object} response.Response{data=event.Staff}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/staff/{userId} [put]
+func (h *EventHandler) SetEventStaffRole(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	hostID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event and staff user IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	staffUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req event.SetStaffRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.BadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	staff, err := h.eventUsecase.SetStaffRole(c.Request.Context(), eventID, hostID, staffUserID, &req)
+	if err != nil {
+		h.handleStaffError(c, err, "Failed to set staff role")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Staff role set successfully", staff)
+}
+
+// RemoveEventStaff godoc
+// @Summary Remove a user's role on an event
+// @Description Take a user's staff role on an event away. The host can remove anyone; staff can step down themselves.
+// @Tags events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/staff/{userId} [delete]
+func (h *EventHandler) RemoveEventStaff(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event and staff user IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	staffUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	if err := h.eventUsecase.RemoveStaff(c.Request.Context(), eventID, userID, staffUserID); err != nil {
+		h.handleStaffError(c, err, "Failed to remove staff member")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Staff member removed successfully", nil)
+}
+
+// handleStaffError maps staff errors of the event usecase to HTTP responses
+func (h *EventHandler) handleStaffError(c *gin.Context, err error, message string) {
+	switch err {
+	case eventUsecase.ErrEventNotFound:
+		response.NotFound(c, "Event not found")
+	case eventUsecase.ErrStaffNotFound:
+		response.NotFound(c, "User is not on the staff of this event")
+	case eventUsecase.ErrStaffUserNotFound:
+		response.NotFound(c, "User not found")
+	case eventUsecase.ErrUnauthorized:
+		response.Forbidden(c, "You don't have permission to manage this event's staff")
+	case eventUsecase.ErrCannotStaffHost:
+		response.BadRequest(c, err.Error(), err.Error())
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
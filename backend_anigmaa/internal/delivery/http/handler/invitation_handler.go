@@ -0,0 +1,548 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/anigmaa/backend/internal/delivery/http/middleware"
+	"github.com/anigmaa/backend/internal/domain/invitation"
+	invitationUsecase "github.com/anigmaa/backend/internal/usecase/invitation"
+	"github.com/anigmaa/backend/pkg/response"
+	"github.com/anigmaa/backend/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// InvitationHandler handles event invitation and invite link HTTP requests
+type InvitationHandler struct {
+	invitationUsecase *invitationUsecase.Usecase
+	validator         *validator.Validator
+}
+
+// NewInvitationHandler creates a new invitation handler
+func NewInvitationHandler(invitationUsecase *invitationUsecase.Usecase, validator *validator.Validator) *InvitationHandler {
+	return &InvitationHandler{
+		invitationUsecase: invitationUsecase,
+		validator:         validator,
+	}
+}
+
+// InviteUsers godoc
+// @Summary Invite users to an event
+// @Description Invite users to an event and notify them (host only). Users already invited are skipped.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body invitation.CreateInvitationsRequest true "Users to invite"
+// @Success 201 {object} response.Response{data=[]invitation.Invitation}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/invitations [post]
+func (h *InvitationHandler) InviteUsers(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	var req invitation.CreateInvitationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	invitations, err := h.invitationUsecase.InviteUsers(c.Request.Context(), eventID, userID, &req)
+	if err != nil {
+		h.handleInvitationError(c, err, "Failed to invite users")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Invitations sent successfully", invitations)
+}
+
+// GetEventInvitations godoc
+// @Summary Get event invitations
+// @Description Get the invitations of an event with their status (host only)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param status query string false "Filter by status: pending, accepted, declined, revoked"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.PaginatedResponse{data=[]invitation.InvitationWithDetails}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/invitations [get]
+func (h *InvitationHandler) GetEventInvitations(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	status, ok := parseInvitationStatus(c)
+	if !ok {
+		response.BadRequest(c, "Invalid status", "status must be one of pending, accepted, declined, revoked")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	invitations, total, err := h.invitationUsecase.GetEventInvitations(c.Request.Context(), eventID, userID, status, limit, offset)
+	if err != nil {
+		h.handleInvitationError(c, err, "Failed to get invitations")
+		return
+	}
+
+	meta := response.NewPaginationMeta(total, limit, offset, len(invitations))
+	response.Paginated(c, http.StatusOK, "Invitations retrieved successfully", invitations, meta)
+}
+
+// RevokeInvitation godoc
+// @Summary Revoke an invitation
+// @Description Revoke a pending or accepted invitation (host only). Invitees who already joined keep their place.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param invitationId path string true "Invitation ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/invitations/{invitationId} [delete]
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event and invitation IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid invitation ID", err.Error())
+		return
+	}
+
+	if err := h.invitationUsecase.RevokeInvitation(c.Request.Context(), eventID, invitationID, userID); err != nil {
+		h.handleInvitationError(c, err, "Failed to revoke invitation")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invitation revoked successfully", nil)
+}
+
+// CreateInviteLink godoc
+// @Summary Create an invite link
+// @Description Create an expiring, optionally usage-capped invite link for an event (host only). Links expire after 7 days by default and never outlive the event.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body invitation.CreateInviteLinkRequest false "Usage cap and expiry"
+// @Success 201 {object} response.Response{data=invitation.InviteLink}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/invite-links [post]
+func (h *InvitationHandler) CreateInviteLink(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	// The body is optional; an empty one creates a link with the defaults
+	var req invitation.CreateInviteLinkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	link, err := h.invitationUsecase.CreateInviteLink(c.Request.Context(), eventID, userID, &req)
+	if err != nil {
+		h.handleInvitationError(c, err, "Failed to create invite link")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Invite link created successfully", link)
+}
+
+// GetEventInviteLinks godoc
+// @Summary Get event invite links
+// @Description Get the invite links of an event with their usage (host only)
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=[]invitation.InviteLink}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/invite-links [get]
+func (h *InvitationHandler) GetEventInviteLinks(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	links, err := h.invitationUsecase.GetEventInviteLinks(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleInvitationError(c, err, "Failed to get invite links")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invite links retrieved successfully", links)
+}
+
+// RevokeInviteLink godoc
+// @Summary Revoke an invite link
+// @Description Stop an invite link from being redeemed (host only). Invitations already accepted through it stay valid.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param linkId path string true "Invite link ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/invite-links/{linkId} [delete]
+func (h *InvitationHandler) RevokeInviteLink(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event and link IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid invite link ID", err.Error())
+		return
+	}
+
+	if err := h.invitationUsecase.RevokeInviteLink(c.Request.Context(), eventID, linkID, userID); err != nil {
+		h.handleInvitationError(c, err, "Failed to revoke invite link")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invite link revoked successfully", nil)
+}
+
+// GetMyInvitations godoc
+// @Summary Get my invitations
+// @Description Get the event invitations the current user received
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status: pending, accepted, declined, revoked"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.PaginatedResponse{data=[]invitation.InvitationWithDetails}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /invitations [get]
+func (h *InvitationHandler) GetMyInvitations(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	status, ok := parseInvitationStatus(c)
+	if !ok {
+		response.BadRequest(c, "Invalid status", "status must be one of pending, accepted, declined, revoked")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	invitations, total, err := h.invitationUsecase.GetMyInvitations(c.Request.Context(), userID, status, limit, offset)
+	if err != nil {
+		response.InternalError(c, "Failed to get invitations", err.Error())
+		return
+	}
+
+	meta := response.NewPaginationMeta(total, limit, offset, len(invitations))
+	response.Paginated(c, http.StatusOK, "Invitations retrieved successfully", invitations, meta)
+}
+
+// AcceptInvitation godoc
+// @Summary Accept an invitation
+// @Description Accept a pending event invitation, which grants access to the event
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID" format(uuid)
+// @Success 200 {object} response.Response{data=invitation.Invitation}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /invitations/{id}/accept [post]
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	h.respondToInvitation(c, true)
+}
+
+// DeclineInvitation godoc
+// @Summary Decline an invitation
+// @Description Decline a pending event invitation
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID" format(uuid)
+// @Success 200 {object} response.Response{data=invitation.Invitation}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /invitations/{id}/decline [post]
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	h.respondToInvitation(c, false)
+}
+
+func (h *InvitationHandler) respondToInvitation(c *gin.Context, accept bool) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse invitation ID from path
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid invitation ID", err.Error())
+		return
+	}
+
+	inv, err := h.invitationUsecase.RespondToInvitation(c.Request.Context(), invitationID, userID, accept)
+	if err != nil {
+		h.handleInvitationError(c, err, "Failed to respond to invitation")
+		return
+	}
+
+	message := "Invitation declined"
+	if accept {
+		message = "Invitation accepted"
+	}
+	response.Success(c, http.StatusOK, message, inv)
+}
+
+// RedeemInviteLink godoc
+// @Summary Redeem an invite link
+// @Description Accept an invitation through an invite link token, which grants access to the event
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Invite link token"
+// @Success 200 {object} response.Response{data=map[string]interface{}}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 410 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /invite-links/{token}/redeem [post]
+func (h *InvitationHandler) RedeemInviteLink(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	eventID, err := h.invitationUsecase.RedeemInviteLink(c.Request.Context(), c.Param("token"), userID)
+	if err != nil {
+		h.handleInvitationError(c, err, "Failed to redeem invite link")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Invite link redeemed successfully", map[string]interface{}{
+		"event_id": eventID,
+	})
+}
+
+// parseInvitationStatus reads the optional status filter from the query string
+func parseInvitationStatus(c *gin.Context) (*invitation.Status, bool) {
+	raw := c.Query("status")
+	if raw == "" {
+		return nil, true
+	}
+
+	status := invitation.Status(raw)
+	switch status {
+	case invitation.StatusPending, invitation.StatusAccepted, invitation.StatusDeclined, invitation.StatusRevoked:
+		return &status, true
+	default:
+		return nil, false
+	}
+}
+
+// handleInvitationError maps invitation usecase errors to HTTP responses
+func (h *InvitationHandler) handleInvitationError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, invitationUsecase.ErrEventNotFound):
+		response.NotFound(c, "Event not found")
+	case errors.Is(err, invitationUsecase.ErrInvitationNotFound):
+		response.NotFound(c, "Invitation not found")
+	case errors.Is(err, invitationUsecase.ErrInviteLinkNotFound):
+		response.NotFound(c, "Invite link not found")
+	case errors.Is(err, invitationUsecase.ErrInviteeNotFound):
+		response.NotFound(c, "Invited user not found")
+	case errors.Is(err, invitationUsecase.ErrUnauthorized):
+		response.Forbidden(c, "Only the event host can manage invitations")
+	case errors.Is(err, invitationUsecase.ErrInvitationRevoked):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, invitationUsecase.ErrCannotInviteHost), errors.Is(err, invitationUsecase.ErrInvalidInviteLink):
+		response.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, invitationUsecase.ErrInvitationNotPending), errors.Is(err, invitationUsecase.ErrInvitationNotActive),
+		errors.Is(err, invitationUsecase.ErrInviteLinkExhausted), errors.Is(err, invitationUsecase.ErrEventEnded):
+		response.Conflict(c, err.Error(), err.Error())
+	case errors.Is(err, invitationUsecase.ErrInviteLinkExpired):
+		response.Error(c, http.StatusGone, "Invite link has expired or been revoked", "INVITE_LINK_EXPIRED", err.Error())
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anigmaa/backend/internal/delivery/http/middleware"
+	"github.com/anigmaa/backend/internal/domain/notification"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
+	"github.com/anigmaa/backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NotificationHandler handles notification-related HTTP requests
+type NotificationHandler struct {
+	notificationUsecase *notificationUsecase.Usecase
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationUsecase *notificationUsecase.Usecase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUsecase: notificationUsecase,
+	}
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get the current user's notifications, newest first, using cursor pagination
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param unread_only query bool false "Only return unread notifications" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]notification.NotificationWithActor}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	limit = notificationUsecase.PageSize(limit)
+	cursor := c.Query("cursor")
+	unreadOnly, _ := strconv.ParseBool(c.DefaultQuery("unread_only", "false"))
+
+	notifications, nextCursor, err := h.notificationUsecase.GetNotifications(c.Request.Context(), userID, cursor, limit, unreadOnly)
+	if err != nil {
+		if err == notificationUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to get notifications", err.Error())
+		return
+	}
+
+	// Ensure we return empty array instead of null
+	if notifications == nil {
+		notifications = []notification.NotificationWithActor{}
+	}
+
+	meta := response.NewCursorPaginationMeta(limit, nextCursor)
+	response.Paginated(c, http.StatusOK, "Notifications retrieved successfully", notifications, meta)
+}
+
+// GetUnreadCount godoc
+// @Summary Get unread notification count
+// @Description Get the number of unread notifications for the current user
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=notification.UnreadCountResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	count, err := h.notificationUsecase.GetUnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalError(c, "Failed to get unread count", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Unread count retrieved successfully", notification.UnreadCountResponse{
+		UnreadCount: count,
+	})
+}
+
+// MarkAsRead godoc
+// @Summary Mark notification as read
+// @Description Mark a single notification of the current user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse notification ID from path
+	notificationIDStr := c.Param("id")
+	notificationID, err := uuid.Parse(notificationIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid notification ID", err.Error())
+		return
+	}
+
+	if err := h.notificationUsecase.MarkAsRead(c.Request.Context(), notificationID, userID); err != nil {
+		if err == notificationUsecase.ErrNotificationNotFound {
+			response.NotFound(c, "Notification not found")
+			return
+		}
+		response.InternalError(c, "Failed to mark notification as read", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Notification marked as read", nil)
+}
+
+// MarkAllAsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=notification.MarkAllReadResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	updated, err := h.notificationUsecase.MarkAllAsRead(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalError(c, "Failed to mark notifications as read", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "All notifications marked as read", notification.MarkAllReadResponse{
+		Updated: updated,
+	})
+}
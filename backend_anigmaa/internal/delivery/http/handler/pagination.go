@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/anigmaa/backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// pageQuery holds the pagination parameters of a list request.
+// Lists are paged by cursor; offset is still accepted from older clients and is
+// ignored once a cursor is passed. Counting the total costs an extra query, so it
+// is only done when the client asks for it with include_total=true.
+type pageQuery struct {
+	Limit        int
+	Offset       int
+	Cursor       string
+	IncludeTotal bool
+}
+
+// parsePageQuery reads limit, offset, cursor and include_total from the query string
+func parsePageQuery(c *gin.Context) pageQuery {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	includeTotal, _ := strconv.ParseBool(c.DefaultQuery("include_total", "false"))
+
+	q := pageQuery{
+		Limit:        limit,
+		Offset:       offset,
+		Cursor:       c.Query("cursor"),
+		IncludeTotal: includeTotal,
+	}
+	if q.Cursor != "" {
+		q.Offset = 0
+	}
+	return q
+}
+
+// meta creates the pagination metadata of a page. total is only reported when
+// the client asked for it.
+func (q pageQuery) meta(total int, nextCursor string) *response.PaginationMeta {
+	meta := response.NewCursorPaginationMeta(q.Limit, nextCursor)
+	meta.Offset = q.Offset
+	if q.IncludeTotal {
+		meta.Total = &total
+	}
+	return meta
+}
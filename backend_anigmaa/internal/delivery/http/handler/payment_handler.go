@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 
@@ -14,15 +16,15 @@ import (
 
 // PaymentHandler handles payment-related HTTP requests
 type PaymentHandler struct {
-	midtransClient *payment.MidtransClient
-	ticketUsecase  *ticket_uc.Usecase
+	gateway       payment.Gateway
+	ticketUsecase *ticket_uc.Usecase
 }
 
-// NewPaymentHandler creates a new payment handler for Midtrans
-func NewPaymentHandler(midtransClient *payment.MidtransClient, ticketUsecase *ticket_uc.Usecase) *PaymentHandler {
+// NewPaymentHandler creates a new payment handler for the configured payment gateway
+func NewPaymentHandler(gateway payment.Gateway, ticketUsecase *ticket_uc.Usecase) *PaymentHandler {
 	return &PaymentHandler{
-		midtransClient: midtransClient,
-		ticketUsecase:  ticketUsecase,
+		gateway:       gateway,
+		ticketUsecase: ticketUsecase,
 	}
 }
 
@@ -92,13 +94,11 @@ func (h *PaymentHandler) InitiatePayment(c *gin.Context) {
 		Duration: 1,
 	}
 
-	snapReq := &payment.SnapRequest{
-		TransactionDetails: payment.TransactionDetails{
-			OrderID:     req.OrderID,
-			GrossAmount: req.Amount,
-		},
-		CustomerDetails: customerDetails,
-		ItemDetails:     itemDetails,
+	chargeReq := &payment.ChargeRequest{
+		OrderID:  req.OrderID,
+		Amount:   req.Amount,
+		Customer: customerDetails,
+		Items:    itemDetails,
 		EnabledPayments: []string{
 			"qris", "gopay", "shopeepay", "ovo", "dana",
 		},
@@ -106,15 +106,15 @@ func (h *PaymentHandler) InitiatePayment(c *gin.Context) {
 		Expiry:    expiry,
 	}
 
-	snapResp, err := h.midtransClient.CreateSnapToken(c.Request.Context(), snapReq)
+	chargeResp, err := h.gateway.CreateCharge(c.Request.Context(), chargeReq)
 	if err != nil {
 		response.InternalError(c, "Failed to create payment token", err.Error())
 		return
 	}
 
 	response.Success(c, http.StatusOK, "Payment initiated successfully", InitiatePaymentResponse{
-		Token:       snapResp.Token,
-		RedirectURL: snapResp.RedirectURL,
+		Token:       chargeResp.Token,
+		RedirectURL: chargeResp.RedirectURL,
 	})
 }
 
@@ -153,7 +153,7 @@ func (h *PaymentHandler) CheckPaymentStatus(c *gin.Context) {
 		return
 	}
 
-	status, err := h.midtransClient.GetTransactionStatus(c.Request.Context(), req.OrderID)
+	status, err := h.gateway.GetChargeStatus(c.Request.Context(), req.OrderID)
 	if err != nil {
 		response.InternalError(c, "Failed to get transaction status", err.Error())
 		return
@@ -165,88 +165,62 @@ func (h *PaymentHandler) CheckPaymentStatus(c *gin.Context) {
 		GrossAmount:       status.GrossAmount,
 		Currency:          status.Currency,
 		PaymentType:       status.PaymentType,
-		TransactionStatus: status.TransactionStatus,
+		TransactionStatus: status.ProviderStatus,
 		TransactionTime:   status.TransactionTime,
 		FraudStatus:       status.FraudStatus,
 	})
 }
 
-// MidtransWebhookRequest represents the webhook notification from Midtrans
-type MidtransWebhookRequest struct {
-	TransactionID     string `json:"transaction_id"`
-	OrderID           string `json:"order_id"`
-	GrossAmount       string `json:"gross_amount"`
-	Currency          string `json:"currency"`
-	PaymentType       string `json:"payment_type"`
-	TransactionTime   string `json:"transaction_time"`
-	TransactionStatus string `json:"transaction_status"`
-	FraudStatus       string `json:"fraud_status,omitempty"`
-	StatusCode        string `json:"status_code"`
-	SignatureKey      string `json:"signature_key"`
-}
-
-// mapMidtransStatus converts a Midtrans transaction_status string to the
-// internal TransactionStatus enum.
-// Returns (status, ok) — ok is false for statuses that require no DB action
-// (e.g. "pending" means payment not yet attempted, nothing to update).
-func mapMidtransStatus(midtransStatus string) (ticket.TransactionStatus, bool) {
-	switch midtransStatus {
-	case "capture", "settlement":
-		return ticket.TransactionSuccess, true
-	case "deny", "cancel", "expire", "failure":
-		return ticket.TransactionFailed, true
-	case "refund", "partial_refund":
-		return ticket.TransactionRefunded, true
-	default:
-		// "pending" and unknown statuses: no action needed
-		return "", false
-	}
-}
-
-// MidtransWebhook handles payment notifications from Midtrans.
+// MidtransWebhook handles payment notifications from the payment gateway.
 //
-// Security: SHA-512 signature is verified before any processing.
+// Security: the gateway verifies the notification signature (SHA-512 for
+// Midtrans) before any processing.
 // Idempotency: ProcessPaymentCallback skips already-terminal transactions,
 // so duplicate webhook deliveries are safe.
+// Refund notifications are reconciled against local refunds by ProcessRefundCallback,
+// which is idempotent in the same way.
 //
 // @Summary Midtrans payment notification webhook
 // @Description Handle payment notifications from Midtrans
 // @Tags payments
 // @Accept json
 // @Produce json
-// @Param notification body MidtransWebhookRequest true "Payment notification"
+// @Param notification body payment.MidtransNotification true "Payment notification"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Router /api/v1/webhooks/midtrans [post]
 func (h *PaymentHandler) MidtransWebhook(c *gin.Context) {
-	var notification MidtransWebhookRequest
-	if err := c.ShouldBindJSON(&notification); err != nil {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
 		response.BadRequest(c, "Invalid notification format", err.Error())
 		return
 	}
 
-	// Verify SHA-512 signature: SHA512(order_id + status_code + gross_amount + server_key)
-	if !h.midtransClient.VerifySignature(
-		notification.OrderID,
-		notification.StatusCode,
-		notification.GrossAmount,
-		notification.SignatureKey,
-	) {
-		log.Printf("[Webhook] INVALID signature for order_id=%s status=%s — rejecting",
-			notification.OrderID, notification.TransactionStatus)
-		response.BadRequest(c, "Invalid signature", "Webhook signature verification failed")
+	notification, err := h.gateway.VerifyWebhook(body)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			log.Printf("[Webhook] INVALID signature from %s — rejecting", h.gateway.Name())
+			response.BadRequest(c, "Invalid signature", "Webhook signature verification failed")
+			return
+		}
+		response.BadRequest(c, "Invalid notification format", err.Error())
 		return
 	}
 
-	log.Printf("[Webhook] received order_id=%s midtrans_status=%s payment_type=%s",
-		notification.OrderID, notification.TransactionStatus, notification.PaymentType)
+	log.Printf("[Webhook] received order_id=%s provider_status=%s fraud_status=%s payment_type=%s",
+		notification.OrderID, notification.ProviderStatus, notification.FraudStatus, notification.PaymentType)
+
+	if notification.Status == payment.ChargeRefunded {
+		h.handleRefundNotification(c, notification)
+		return
+	}
 
-	// Map Midtrans status to internal status.
-	internalStatus, shouldProcess := mapMidtransStatus(notification.TransactionStatus)
+	// Map gateway status to internal status.
+	internalStatus, shouldProcess := ticket_uc.TransactionStatusFromCharge(notification.Status)
 	if !shouldProcess {
 		// "pending" or unknown: acknowledge without touching the DB.
 		log.Printf("[Webhook] no action needed for status=%s order_id=%s",
-			notification.TransactionStatus, notification.OrderID)
+			notification.ProviderStatus, notification.OrderID)
 		response.Success(c, http.StatusOK, "Webhook acknowledged", gin.H{
 			"order_id": notification.OrderID,
 			"action":   "none",
@@ -280,6 +254,25 @@ func (h *PaymentHandler) MidtransWebhook(c *gin.Context) {
 	})
 }
 
+// handleRefundNotification reconciles a refund notification with local refund records.
+// Like payment notifications, processing errors are logged and acknowledged with 200.
+func (h *PaymentHandler) handleRefundNotification(c *gin.Context, notification *payment.WebhookNotification) {
+	if err := h.ticketUsecase.ProcessRefundCallback(c.Request.Context(), notification.OrderID, notification.Refunds, notification.PartialRefund); err != nil {
+		log.Printf("[Webhook] ERROR processing refund for order_id=%s: %v", notification.OrderID, err)
+		response.Success(c, http.StatusOK, "Webhook received", gin.H{
+			"order_id": notification.OrderID,
+			"warning":  fmt.Sprintf("processing error: %v", err),
+		})
+		return
+	}
+
+	log.Printf("[Webhook] SUCCESS refund order_id=%s refunds=%d partial=%t", notification.OrderID, len(notification.Refunds), notification.PartialRefund)
+	response.Success(c, http.StatusOK, "Webhook processed successfully", gin.H{
+		"order_id":        notification.OrderID,
+		"internal_status": string(ticket.TransactionRefunded),
+	})
+}
+
 // GetClientKey returns the client key for frontend initialization (sandbox only)
 // @Summary Get Midtrans Client Key
 // @Description Returns the client key for frontend initialization (sandbox only)
@@ -289,7 +282,55 @@ func (h *PaymentHandler) MidtransWebhook(c *gin.Context) {
 // @Router /api/v1/payments/client-key [get]
 func (h *PaymentHandler) GetClientKey(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Client key retrieved", gin.H{
-		"client_key": h.midtransClient.GetClientKey(),
+		"client_key": h.gateway.ClientKey(),
+		"gateway":    h.gateway.Name(),
 		"is_sandbox": true,
 	})
 }
+
+// SimulatePaymentRequest represents the outcome to simulate on the fake gateway
+type SimulatePaymentRequest struct {
+	Outcome string `json:"outcome" binding:"required,oneof=settlement expire fraud"`
+}
+
+// SimulatePayment resolves a fake gateway charge (local development only)
+// @Summary Simulate payment outcome
+// @Description Resolve a fake gateway charge as settled, expired or denied for fraud. The matching webhook is delivered to the app. Only available when PAYMENT_GATEWAY=fake.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param order_id path string true "Order ID"
+// @Param request body SimulatePaymentRequest true "Outcome to simulate"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/payments/fake/{order_id}/simulate [post]
+func (h *PaymentHandler) SimulatePayment(c *gin.Context) {
+	fake, ok := h.gateway.(*payment.FakeGateway)
+	if !ok {
+		response.NotFound(c, "Payment simulation is only available with the fake gateway")
+		return
+	}
+
+	var req SimulatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request format", err.Error())
+		return
+	}
+
+	orderID := c.Param("order_id")
+	if err := fake.Simulate(c.Request.Context(), orderID, payment.FakeOutcome(req.Outcome)); err != nil {
+		if errors.Is(err, payment.ErrFakeChargeNotFound) {
+			response.NotFound(c, "Charge not found")
+			return
+		}
+		response.InternalError(c, "Failed to simulate payment", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Payment outcome simulated", gin.H{
+		"order_id": orderID,
+		"outcome":  req.Outcome,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/anigmaa/backend/internal/delivery/http/middleware"
 	"github.com/anigmaa/backend/internal/domain/comment"
@@ -31,14 +32,17 @@ func NewPostHandler(postUsecase *postUsecase.Usecase, validator *validator.Valid
 
 // GetFeed godoc
 // @Summary Get user feed
-// @Description Get personalized feed of posts for the current user
+// @Description Get the current user's home timeline (own posts and posts by followed authors, mixed with discovery posts, newest first), using cursor pagination. Signed-out visitors get the public posts, newest first. Offset is still accepted for older clients.
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Limit" default(20)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {object} response.Response{data=[]post.PostWithDetails}
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
+// @Param include_total query bool false "Also count all public posts (signed-out visitors only)" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]post.PostResponse}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /posts/feed [get]
@@ -65,53 +69,52 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 		log.Printf("DEBUG: public feed request (no user authentication)")
 	}
 
-	// Get total count for pagination
-	total := 0
+	// Authenticated: home timeline with cursor pagination
 	if exists {
-		total, err = h.postUsecase.CountFeed(c.Request.Context(), userID)
+		posts, nextCursor, err := h.postUsecase.GetFeed(c.Request.Context(), userID, c.Query("cursor"), limit, offset)
 		if err != nil {
-			// If count fails, default to 0 but continue
-			total = 0
+			if err == postUsecase.ErrInvalidCursor {
+				response.BadRequest(c, "Invalid cursor", err.Error())
+				return
+			}
+			response.InternalError(c, "Failed to get feed", err.Error())
+			return
 		}
-	} else {
-		// For public feed, get count of public posts
-		total, err = h.postUsecase.CountPublicPosts(c.Request.Context())
-		if err != nil {
-			total = 0
+
+		postResponses := make([]post.PostResponse, len(posts))
+		for i, p := range posts {
+			postResponses[i] = p.ToResponse()
 		}
-	}
 
-	// Call usecase to get posts
-	var posts []post.PostWithDetails
-	if exists {
-		// Authenticated: get personalized feed
-		posts, err = h.postUsecase.GetFeed(c.Request.Context(), userID, limit, offset)
-	} else {
-		// Public: get public posts
-		posts, err = h.postUsecase.GetPublicPosts(c.Request.Context(), limit, offset)
+		meta := response.NewCursorPaginationMeta(limit, nextCursor)
+		response.Paginated(c, http.StatusOK, "Feed retrieved successfully", postResponses, meta)
+		return
 	}
 
+	// Public: newest public posts with cursor pagination
+	page := parsePageQuery(c)
+	posts, nextCursor, err := h.postUsecase.GetPublicPosts(c.Request.Context(), page.Cursor, page.Limit, page.Offset)
 	if err != nil {
+		if err == postUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to get feed", err.Error())
 		return
 	}
 
-	// DEBUG: Log posts results
-	log.Printf("DEBUG: Retrieved %d posts from feed", len(posts))
-	for i, p := range posts {
-		log.Printf("DEBUG: Post[%d] ID=%v, IsLikedByUser=%v, AuthorID=%v",
-			i, p.ID, p.IsLikedByUser, p.AuthorID)
-	}
-
 	// Transform to Flutter-compatible response format
 	postResponses := make([]post.PostResponse, len(posts))
 	for i, p := range posts {
 		postResponses[i] = p.ToResponse()
 	}
 
-	// Create pagination metadata with correct total
-	meta := response.NewPaginationMeta(total, limit, offset, len(posts))
-	response.Paginated(c, http.StatusOK, "Feed retrieved successfully", postResponses, meta)
+	total := 0
+	if page.IncludeTotal {
+		total, _ = h.postUsecase.CountPublicPosts(c.Request.Context())
+	}
+
+	response.Paginated(c, http.StatusOK, "Feed retrieved successfully", postResponses, page.meta(total, nextCursor))
 }
 
 // CreatePost godoc
@@ -177,7 +180,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 // GetPostByID godoc
 // @Summary Get post by ID
-// @Description Get detailed information about a specific post
+// @Description Get detailed information about a specific post. Followers-only and private posts are only returned to viewers allowed to see them.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -628,15 +631,17 @@ func (h *PostHandler) AddComment(c *gin.Context) {
 
 // GetComments godoc
 // @Summary Get post comments
-// @Description Get comments for a specific post
+// @Description Get comments for a specific post, most liked first, using cursor pagination
 // @Tags posts
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Post ID" format(uuid)
 // @Param limit query int false "Limit" default(20)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {object} response.Response{data=[]comment.CommentWithDetails}
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
+// @Param include_total query bool false "Also count all comments" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]comment.CommentWithDetails}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -655,16 +660,19 @@ func (h *PostHandler) GetComments(c *gin.Context) {
 	userID, _ := uuid.Parse(userIDStr)
 
 	// Parse query parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	page := parsePageQuery(c)
 
 	// Call usecase
-	comments, err := h.postUsecase.GetCommentsByPost(c.Request.Context(), postID, userID, limit, offset)
+	comments, nextCursor, err := h.postUsecase.GetCommentsByPost(c.Request.Context(), postID, userID, page.Cursor, page.Limit, page.Offset)
 	if err != nil {
 		if err == postUsecase.ErrPostNotFound {
 			response.NotFound(c, "Post not found")
 			return
 		}
+		if err == postUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to get comments", err.Error())
 		return
 	}
@@ -674,7 +682,12 @@ func (h *PostHandler) GetComments(c *gin.Context) {
 		comments = []comment.CommentWithDetails{}
 	}
 
-	response.Success(c, http.StatusOK, "Comments retrieved successfully", comments)
+	total := 0
+	if page.IncludeTotal {
+		total, _ = h.postUsecase.CountComments(c.Request.Context(), postID)
+	}
+
+	response.Paginated(c, http.StatusOK, "Comments retrieved successfully", comments, page.meta(total, nextCursor))
 }
 
 // UpdateComment godoc
@@ -1113,7 +1126,7 @@ func (h *PostHandler) GetUserPosts(c *gin.Context) {
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
 	// Get total count for pagination
-	total, err := h.postUsecase.CountUserPosts(c.Request.Context(), authorID)
+	total, err := h.postUsecase.CountUserPosts(c.Request.Context(), authorID, viewerID)
 	if err != nil {
 		// If count fails, default to 0 but continue
 		total = 0
@@ -1251,3 +1264,83 @@ func (h *PostHandler) UnarchivePost(c *gin.Context) {
 
 	response.Success(c, http.StatusOK, "Post unarchived successfully", unarchivedPost)
 }
+
+// GetHashtagPosts godoc
+// @Summary Get posts by hashtag
+// @Description Get the posts using a hashtag, newest first, using cursor pagination. The tag is matched case-insensitively, with or without its leading '#'.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param tag path string true "Hashtag"
+// @Param limit query int false "Limit" default(20)
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
+// @Param include_total query bool false "Also count all posts using the hashtag" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]post.PostResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /hashtags/{tag}/posts [get]
+func (h *PostHandler) GetHashtagPosts(c *gin.Context) {
+	tag := c.Param("tag")
+
+	// Get viewer ID (optional) for visibility and interaction flags
+	viewerID := uuid.Nil
+	if viewerIDStr, exists := middleware.GetUserID(c); exists {
+		viewerID, _ = uuid.Parse(viewerIDStr)
+	}
+
+	// Parse query parameters
+	page := parsePageQuery(c)
+
+	posts, nextCursor, err := h.postUsecase.GetHashtagPosts(c.Request.Context(), tag, viewerID, page.Cursor, page.Limit, page.Offset)
+	if err != nil {
+		if err == postUsecase.ErrInvalidHashtag {
+			response.BadRequest(c, "Invalid hashtag", err.Error())
+			return
+		}
+		if err == postUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to get hashtag posts", err.Error())
+		return
+	}
+
+	// Transform to Flutter-compatible response format
+	postResponses := make([]post.PostResponse, len(posts))
+	for i, p := range posts {
+		postResponses[i] = p.ToResponse()
+	}
+
+	total := 0
+	if page.IncludeTotal {
+		total, _ = h.postUsecase.CountHashtagPosts(c.Request.Context(), tag, viewerID)
+	}
+
+	response.Paginated(c, http.StatusOK, "Posts retrieved successfully", postResponses, page.meta(total, nextCursor))
+}
+
+// GetTrendingHashtags godoc
+// @Summary Get trending hashtags
+// @Description Get the hashtags used by the most authors in public posts over a recent window
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param hours query int false "Window in hours (max 168)" default(24)
+// @Param limit query int false "Limit (max 50)" default(10)
+// @Success 200 {object} response.Response{data=[]post.TrendingHashtag}
+// @Failure 500 {object} response.Response
+// @Router /hashtags/trending [get]
+func (h *PostHandler) GetTrendingHashtags(c *gin.Context) {
+	// Parse query parameters
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	hashtags, err := h.postUsecase.GetTrendingHashtags(c.Request.Context(), time.Duration(hours)*time.Hour, limit)
+	if err != nil {
+		response.InternalError(c, "Failed to get trending hashtags", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Trending hashtags retrieved successfully", hashtags)
+}
@@ -107,7 +107,7 @@ func (h *ProfileHandler) GetProfilePosts(c *gin.Context) {
 	}
 
 	// Get total count for pagination
-	total, err := h.postUsecase.CountUserPosts(c.Request.Context(), user.ID)
+	total, err := h.postUsecase.CountUserPosts(c.Request.Context(), user.ID, viewerID)
 	if err != nil {
 		// If count fails, default to 0 but continue
 		total = 0
@@ -161,15 +161,21 @@ func (h *ProfileHandler) GetProfileEvents(c *gin.Context) {
 		return
 	}
 
+	// Friends-only and private events are only listed to viewers with access
+	viewerID := uuid.Nil
+	if viewerIDStr, exists := middleware.GetUserID(c); exists {
+		viewerID, _ = uuid.Parse(viewerIDStr)
+	}
+
 	// Get total count for pagination
-	total, err := h.eventUsecase.CountHostedEvents(c.Request.Context(), user.ID)
+	total, err := h.eventUsecase.CountHostedEvents(c.Request.Context(), user.ID, viewerID)
 	if err != nil {
 		// If count fails, default to 0 but continue
 		total = 0
 	}
 
 	// Get events
-	events, err := h.eventUsecase.GetByHost(c.Request.Context(), user.ID, limit, offset)
+	events, err := h.eventUsecase.GetByHost(c.Request.Context(), user.ID, viewerID, limit, offset)
 	if err != nil {
 		response.InternalError(c, "Failed to get user events", err.Error())
 		return
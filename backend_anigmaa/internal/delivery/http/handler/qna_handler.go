@@ -2,7 +2,6 @@ package handler
 
 import (
 	"net/http"
-	"strconv"
 
 	"github.com/anigmaa/backend/internal/delivery/http/middleware"
 	"github.com/anigmaa/backend/internal/domain/qna"
@@ -29,15 +28,17 @@ func NewQnAHandler(qnaUsecase *qnaUsecase.Usecase, validator *validator.Validato
 
 // GetEventQnA godoc
 // @Summary Get event Q&A
-// @Description Get all questions and answers for an event
+// @Description Get the questions and answers for an event, most upvoted first, using cursor pagination
 // @Tags qna
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Event ID" format(uuid)
 // @Param limit query int false "Limit" default(20)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {object} response.Response{data=[]qna.QnAWithDetails}
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
+// @Param include_total query bool false "Also count all questions" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]qna.QnAWithDetails}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -56,23 +57,19 @@ func (h *QnAHandler) GetEventQnA(c *gin.Context) {
 	userID, _ := uuid.Parse(userIDStr)
 
 	// Parse query parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	// Get total count for pagination
-	total, err := h.qnaUsecase.CountEventQnA(c.Request.Context(), eventID)
-	if err != nil {
-		// If count fails, default to 0 but continue
-		total = 0
-	}
+	page := parsePageQuery(c)
 
 	// Get Q&A list
-	qnaList, err := h.qnaUsecase.GetEventQnA(c.Request.Context(), eventID, userID, limit, offset)
+	qnaList, nextCursor, err := h.qnaUsecase.GetEventQnA(c.Request.Context(), eventID, userID, page.Cursor, page.Limit, page.Offset)
 	if err != nil {
 		if err == qnaUsecase.ErrEventNotFound {
 			response.NotFound(c, "Event not found")
 			return
 		}
+		if err == qnaUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to get Q&A", err.Error())
 		return
 	}
@@ -82,9 +79,13 @@ func (h *QnAHandler) GetEventQnA(c *gin.Context) {
 		qnaList = []qna.QnAWithDetails{}
 	}
 
-	// Create pagination metadata with correct total
-	meta := response.NewPaginationMeta(total, limit, offset, len(qnaList))
-	response.Paginated(c, http.StatusOK, "Q&A retrieved successfully", qnaList, meta)
+	// Total count is optional
+	total := 0
+	if page.IncludeTotal {
+		total, _ = h.qnaUsecase.CountEventQnA(c.Request.Context(), eventID)
+	}
+
+	response.Paginated(c, http.StatusOK, "Q&A retrieved successfully", qnaList, page.meta(total, nextCursor))
 }
 
 // AskQuestion godoc
@@ -264,7 +265,7 @@ func (h *QnAHandler) RemoveUpvote(c *gin.Context) {
 
 // AnswerQuestion godoc
 // @Summary Answer a question
-// @Description Answer a question (event organizer only)
+// @Description Answer a question (event host, co-hosts and Q&A moderators only)
 // @Tags qna
 // @Accept json
 // @Produce json
@@ -274,6 +275,7 @@ func (h *QnAHandler) RemoveUpvote(c *gin.Context) {
 // @Success 200 {object} response.Response{data=qna.QnA}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /qna/{id}/answer [post]
@@ -324,6 +326,10 @@ func (h *QnAHandler) AnswerQuestion(c *gin.Context) {
 			response.Conflict(c, "Question already answered", err.Error())
 			return
 		}
+		if err == qnaUsecase.ErrUnauthorized {
+			response.Forbidden(c, "Only the event host and Q&A moderators can answer questions")
+			return
+		}
 		response.InternalError(c, "Failed to answer question", err.Error())
 		return
 	}
@@ -333,7 +339,7 @@ func (h *QnAHandler) AnswerQuestion(c *gin.Context) {
 
 // DeleteQuestion godoc
 // @Summary Delete a question
-// @Description Delete a question (author only)
+// @Description Delete a question (author, event host, co-hosts and Q&A moderators only)
 // @Tags qna
 // @Accept json
 // @Produce json
@@ -375,7 +381,7 @@ func (h *QnAHandler) DeleteQuestion(c *gin.Context) {
 			return
 		}
 		if err == qnaUsecase.ErrUnauthorized {
-			response.Forbidden(c, "Only the question author can delete this question")
+			response.Forbidden(c, "Only the question author and Q&A moderators can delete this question")
 			return
 		}
 		response.InternalError(c, "Failed to delete question", err.Error())
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anigmaa/backend/internal/delivery/http/middleware"
+	"github.com/anigmaa/backend/internal/domain/search"
+	searchUsecase "github.com/anigmaa/backend/internal/usecase/search"
+	"github.com/anigmaa/backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SearchHandler handles search HTTP requests
+type SearchHandler struct {
+	searchUsecase *searchUsecase.Usecase
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchUsecase *searchUsecase.Usecase) *SearchHandler {
+	return &SearchHandler{
+		searchUsecase: searchUsecase,
+	}
+}
+
+// Search godoc
+// @Summary Search
+// @Description Full-text search across events, posts, communities and users, best match first. Matches Indonesian and English word forms and supports web search syntax ("exact phrase", -exclude, OR). Snippets wrap matched words in <b></b>.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query" minlength(2) maxlength(200)
+// @Param types query string false "Comma-separated result types: event, post, community, user (default all)"
+// @Param limit query int false "Limit (max 50)" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.PaginatedResponse{data=[]search.Result}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	types, err := searchUsecase.ParseTypes(c.Query("types"))
+	if err != nil {
+		response.BadRequest(c, "Invalid search types", err.Error())
+		return
+	}
+
+	// Get viewer ID (optional) so they also find what only they may see
+	viewerID := uuid.Nil
+	if viewerIDStr, exists := middleware.GetUserID(c); exists {
+		viewerID, _ = uuid.Parse(viewerIDStr)
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	query := &search.Query{
+		Text:     c.Query("q"),
+		Types:    types,
+		ViewerID: viewerID,
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	results, hasNext, err := h.searchUsecase.Search(c.Request.Context(), query)
+	if err != nil {
+		if err == searchUsecase.ErrQueryTooShort || err == searchUsecase.ErrQueryTooLong {
+			response.BadRequest(c, "Invalid search query", err.Error())
+			return
+		}
+		response.InternalError(c, "Failed to search", err.Error())
+		return
+	}
+
+	// Ranked results have no stable total, so none is reported
+	meta := &response.PaginationMeta{
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+		HasNext: hasNext,
+	}
+	response.Paginated(c, http.StatusOK, "Search results retrieved successfully", results, meta)
+}
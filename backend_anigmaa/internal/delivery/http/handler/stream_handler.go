@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/anigmaa/backend/internal/delivery/http/middleware"
+	"github.com/anigmaa/backend/internal/infrastructure/realtime"
+	"github.com/anigmaa/backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// streamHeartbeatInterval keeps idle connections alive through proxies and load balancers
+const streamHeartbeatInterval = 25 * time.Second
+
+// StreamHandler handles the real-time event stream
+type StreamHandler struct {
+	hub *realtime.Hub
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(hub *realtime.Hub) *StreamHandler {
+	return &StreamHandler{
+		hub: hub,
+	}
+}
+
+// Stream godoc
+// @Summary Real-time event stream
+// @Description Server-Sent Events stream for the current user. Pushes new notifications (notification), Q&A answers (qna_answered), ticket payment confirmations (payment_update) and event status changes (event_status_changed). A ping event is sent periodically to keep the connection open.
+// @Tags stream
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Success 200 {object} realtime.Message
+// @Failure 401 {object} response.Response
+// @Router /stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// The server-wide WriteTimeout would cut the stream after a few seconds;
+	// lift it for this long-lived connection only
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	messages, unsubscribe := h.hub.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // disable nginx response buffering
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// Tell the client the stream is live
+	c.SSEvent("ready", gin.H{"user_id": userID})
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-messages:
+			c.SSEvent(msg.Type, msg)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now().UTC()})
+			c.Writer.Flush()
+		}
+	}
+}
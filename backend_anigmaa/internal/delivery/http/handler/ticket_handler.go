@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -38,6 +39,7 @@ func NewTicketHandler(ticketUsecase *ticketUsecase.Usecase, validator *validator
 // @Success 201 {object} response.Response{data=ticket.PurchaseTicketResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -77,14 +79,43 @@ func (h *TicketHandler) PurchaseTicket(c *gin.Context) {
 			response.NotFound(c, "Event not found")
 			return
 		}
+		if err == ticketUsecase.ErrEventForbidden {
+			response.Forbidden(c, "You don't have access to this event")
+			return
+		}
+		if err == ticketUsecase.ErrApprovalRequired {
+			response.Forbidden(c, "The host must approve your join request first")
+			return
+		}
 		if err == ticketUsecase.ErrEventFull {
-			response.Conflict(c, "Event is full", err.Error())
+			response.Conflict(c, "Event is full, join the waitlist to be offered the next free spot", err.Error())
 			return
 		}
 		if err == ticketUsecase.ErrAlreadyPurchased {
 			response.Conflict(c, "Already purchased ticket for this event", err.Error())
 			return
 		}
+		if err == ticketUsecase.ErrTierNotFound {
+			response.NotFound(c, "Ticket tier not found")
+			return
+		}
+		if err == ticketUsecase.ErrTierRequired || err == ticketUsecase.ErrTierNotOnSale {
+			response.BadRequest(c, err.Error(), err.Error())
+			return
+		}
+		if err == ticketUsecase.ErrTierSoldOut {
+			response.Conflict(c, "Ticket tier is sold out", err.Error())
+			return
+		}
+		if err == ticketUsecase.ErrTierLimitReached {
+			response.Conflict(c, "Ticket limit per user reached for this tier", err.Error())
+			return
+		}
+		if err == ticketUsecase.ErrPromoCodeNotFound || err == ticketUsecase.ErrPromoCodeInvalid ||
+			err == ticketUsecase.ErrPromoCodeExhausted || err == ticketUsecase.ErrPromoCodeLimitReached {
+			h.handlePromoCodeError(c, err, "Failed to purchase ticket")
+			return
+		}
 		response.InternalError(c, "Failed to purchase ticket", err.Error())
 		return
 	}
@@ -100,14 +131,17 @@ func (h *TicketHandler) PurchaseTicket(c *gin.Context) {
 
 // GetMyTickets godoc
 // @Summary Get my tickets
-// @Description Get all tickets for the current user
+// @Description Get the current user's tickets, newest first, using cursor pagination
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Limit" default(20)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {object} response.Response{data=[]ticket.TicketWithDetails}
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
+// @Param include_total query bool false "Also count all tickets" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]ticket.TicketWithDetails}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /tickets/my [get]
@@ -126,26 +160,26 @@ func (h *TicketHandler) GetMyTickets(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	// Get total count for pagination
-	total, err := h.ticketUsecase.CountUserTickets(c.Request.Context(), userID)
-	if err != nil {
-		// If count fails, default to 0 but continue
-		total = 0
-	}
+	page := parsePageQuery(c)
 
 	// Get user tickets
-	tickets, err := h.ticketUsecase.GetUserTickets(c.Request.Context(), userID, limit, offset)
+	tickets, nextCursor, err := h.ticketUsecase.GetUserTickets(c.Request.Context(), userID, page.Cursor, page.Limit, page.Offset)
 	if err != nil {
+		if err == ticketUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to get tickets", err.Error())
 		return
 	}
 
-	// Create pagination metadata with correct total
-	meta := response.NewPaginationMeta(total, limit, offset, len(tickets))
-	response.Paginated(c, http.StatusOK, "Tickets retrieved successfully", tickets, meta)
+	// Total count is optional
+	total := 0
+	if page.IncludeTotal {
+		total, _ = h.ticketUsecase.CountUserTickets(c.Request.Context(), userID)
+	}
+
+	response.Paginated(c, http.StatusOK, "Tickets retrieved successfully", tickets, page.meta(total, nextCursor))
 }
 
 // GetTicketByID godoc
@@ -205,21 +239,35 @@ func (h *TicketHandler) GetTicketByID(c *gin.Context) {
 
 // CheckIn godoc
 // @Summary Check in with ticket
-// @Description Check in to an event using attendance code
+// @Description Check in to an event using attendance code. Only the event host, co-hosts and check-in staff can check in attendees. Send the same scan_id when retrying a scan; the retry gets the original outcome.
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param event_id path string true "Event ID" format(uuid)
 // @Param request body ticket.CheckInRequest true "Check-in data"
-// @Success 200 {object} response.Response{data=ticket.Ticket}
+// @Success 200 {object} response.Response{data=ticket.TicketWithDetails}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /tickets/events/{event_id}/checkin [post]
 func (h *TicketHandler) CheckIn(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
 	var req ticket.CheckInRequest
 
 	// Parse request body
@@ -242,34 +290,103 @@ func (h *TicketHandler) CheckIn(c *gin.Context) {
 	}
 
 	// Call usecase
-	checkedInTicket, err := h.ticketUsecase.CheckIn(c.Request.Context(), eventID, &req)
+	result, err := h.ticketUsecase.CheckIn(c.Request.Context(), eventID, userID, &req)
 	if err != nil {
-		if err == ticketUsecase.ErrTicketNotFound {
-			response.NotFound(c, "Ticket not found")
-			return
-		}
-		if err == ticketUsecase.ErrInvalidAttendanceCode {
-			response.BadRequest(c, "Invalid attendance code", err.Error())
-			return
-		}
-		if err == ticketUsecase.ErrAlreadyCheckedIn {
-			response.Conflict(c, "Ticket already checked in", err.Error())
-			return
-		}
-		if err == ticketUsecase.ErrTicketNotActive {
-			response.BadRequest(c, "Ticket is not active", err.Error())
-			return
-		}
-		response.InternalError(c, "Failed to check in", err.Error())
+		h.handleCheckInError(c, err, "Failed to check in")
+		return
+	}
+
+	h.respondCheckedInTicket(c, result)
+}
+
+// CheckInWithQR godoc
+// @Summary Check in with signed ticket QR
+// @Description Check in to an event by submitting the raw payload read from a ticket QR code. The payload signature and expiry are verified; only the event host, co-hosts and check-in staff can check in attendees. Send the same scan_id when retrying a scan; the retry gets the original outcome.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ticket.QRCheckInRequest true "Scanned QR data"
+// @Success 200 {object} response.Response{data=ticket.TicketWithDetails}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tickets/check-in/qr [post]
+func (h *TicketHandler) CheckInWithQR(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req ticket.QRCheckInRequest
+
+	// Parse request body
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	// Validate request
+	if err := h.validator.Validate(&req); err != nil {
+		response.BadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	// Parse event ID from request body
+	eventID, err := uuid.Parse(req.EventID)
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	// Call usecase
+	result, err := h.ticketUsecase.CheckInWithQR(c.Request.Context(), eventID, userID, &req)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to check in")
+		return
+	}
+
+	h.respondCheckedInTicket(c, result)
+}
+
+// respondCheckedInTicket responds with the checked-in ticket, or a conflict if
+// the scan found it already checked in
+func (h *TicketHandler) respondCheckedInTicket(c *gin.Context, result *ticket.CheckInResult) {
+	if result.Outcome == ticket.CheckInDuplicate {
+		response.Conflict(c, "Ticket already checked in", ticketUsecase.ErrAlreadyCheckedIn.Error())
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Checked in successfully", checkedInTicket)
+	response.Success(c, http.StatusOK, "Checked in successfully", result.Ticket)
+}
+
+// GetQRPublicKey godoc
+// @Summary Get ticket QR public key
+// @Description Get the Ed25519 public key that scanner apps use to verify ticket QR signatures offline
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=ticket.QRPublicKeyResponse}
+// @Failure 401 {object} response.Response
+// @Router /tickets/qr-public-key [get]
+func (h *TicketHandler) GetQRPublicKey(c *gin.Context) {
+	response.Success(c, http.StatusOK, "QR public key retrieved successfully", h.ticketUsecase.GetQRPublicKey())
 }
 
 // CancelTicket godoc
 // @Summary Cancel ticket
-// @Description Cancel a ticket and request refund
+// @Description Cancel a ticket. Paid tickets are refunded through Midtrans first; the refund status can be followed at /tickets/{id}/refunds.
 // @Tags tickets
 // @Accept json
 // @Produce json
@@ -281,6 +398,7 @@ func (h *TicketHandler) CheckIn(c *gin.Context) {
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
 // @Router /tickets/{id}/cancel [post]
 func (h *TicketHandler) CancelTicket(c *gin.Context) {
 	// Get user ID from context
@@ -322,6 +440,10 @@ func (h *TicketHandler) CancelTicket(c *gin.Context) {
 			response.BadRequest(c, "Cannot cancel ticket - event has already started", err.Error())
 			return
 		}
+		if err == ticketUsecase.ErrRefundFailed {
+			response.Error(c, http.StatusBadGateway, "Refund could not be processed, please try again later", "REFUND_FAILED", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to cancel ticket", err.Error())
 		return
 	}
@@ -329,22 +451,22 @@ func (h *TicketHandler) CancelTicket(c *gin.Context) {
 	response.Success(c, http.StatusOK, "Ticket cancelled successfully", nil)
 }
 
-// GetAttendanceCode godoc
-// @Summary Get attendance code
-// @Description Get the attendance code for a ticket
+// GetTicketRefunds godoc
+// @Summary Get ticket refunds
+// @Description Get the refunds of a cancelled ticket and their status (pending, succeeded, failed)
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Ticket ID" format(uuid)
-// @Success 200 {object} response.Response{data=map[string]string}
+// @Success 200 {object} response.Response{data=[]ticket.Refund}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
-// @Router /tickets/{id}/attendance-code [get]
-func (h *TicketHandler) GetAttendanceCode(c *gin.Context) {
+// @Router /tickets/{id}/refunds [get]
+func (h *TicketHandler) GetTicketRefunds(c *gin.Context) {
 	// Get user ID from context
 	userIDStr, exists := middleware.GetUserID(c)
 	if !exists {
@@ -366,8 +488,7 @@ func (h *TicketHandler) GetAttendanceCode(c *gin.Context) {
 		return
 	}
 
-	// Call usecase
-	attendanceCode, err := h.ticketUsecase.GetAttendanceCode(c.Request.Context(), ticketID, userID)
+	refunds, err := h.ticketUsecase.GetTicketRefunds(c.Request.Context(), ticketID, userID)
 	if err != nil {
 		if err == ticketUsecase.ErrTicketNotFound {
 			response.NotFound(c, "Ticket not found")
@@ -377,28 +498,30 @@ func (h *TicketHandler) GetAttendanceCode(c *gin.Context) {
 			response.Forbidden(c, "You don't have access to this ticket")
 			return
 		}
-		response.InternalError(c, "Failed to get attendance code", err.Error())
+		response.InternalError(c, "Failed to get refunds", err.Error())
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Attendance code retrieved successfully", gin.H{
-		"attendance_code": attendanceCode,
-	})
+	response.Success(c, http.StatusOK, "Refunds retrieved successfully", refunds)
 }
 
-// GetUpcomingTickets godoc
-// @Summary Get upcoming tickets
-// @Description Get user's upcoming event tickets
+// CreateTier godoc
+// @Summary Create ticket tier
+// @Description Add a ticket tier (e.g. early bird, VIP, group) to an event (host only)
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param limit query int false "Limit" default(10)
-// @Success 200 {object} response.Response{data=[]ticket.TicketWithDetails}
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body ticket.CreateTierRequest true "Ticket tier data"
+// @Success 201 {object} response.Response{data=ticket.Tier}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
-// @Router /tickets/upcoming [get]
-func (h *TicketHandler) GetUpcomingTickets(c *gin.Context) {
+// @Router /events/{id}/tiers [post]
+func (h *TicketHandler) CreateTier(c *gin.Context) {
 	// Get user ID from context
 	userIDStr, exists := middleware.GetUserID(c)
 	if !exists {
@@ -412,32 +535,80 @@ func (h *TicketHandler) GetUpcomingTickets(c *gin.Context) {
 		return
 	}
 
-	// Parse query parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
 
-	// Call usecase
-	tickets, err := h.ticketUsecase.GetUpcomingTickets(c.Request.Context(), userID, limit)
+	var req ticket.CreateTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	tier, err := h.ticketUsecase.CreateTier(c.Request.Context(), eventID, userID, &req)
 	if err != nil {
-		response.InternalError(c, "Failed to get upcoming tickets", err.Error())
+		h.handleTierError(c, err, "Failed to create ticket tier")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Upcoming tickets retrieved successfully", tickets)
+	response.Success(c, http.StatusCreated, "Ticket tier created successfully", tier)
 }
 
-// GetPastTickets godoc
-// @Summary Get past tickets
-// @Description Get user's past event tickets
+// GetEventTiers godoc
+// @Summary Get event ticket tiers
+// @Description Get the ticket tiers of an event with their price, quota, sold count and sale window
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=[]ticket.Tier}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/tiers [get]
+func (h *TicketHandler) GetEventTiers(c *gin.Context) {
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	// Get user ID from context (optional, for friends-only and private events)
+	userIDStr, _ := middleware.GetUserID(c)
+	userID, _ := uuid.Parse(userIDStr)
+
+	tiers, err := h.ticketUsecase.GetEventTiers(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleTierError(c, err, "Failed to get ticket tiers")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Ticket tiers retrieved successfully", tiers)
+}
+
+// UpdateTier godoc
+// @Summary Update ticket tier
+// @Description Update a ticket tier of an event (host only)
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param limit query int false "Limit" default(10)
-// @Success 200 {object} response.Response{data=[]ticket.TicketWithDetails}
+// @Param id path string true "Event ID" format(uuid)
+// @Param tierId path string true "Tier ID" format(uuid)
+// @Param request body ticket.UpdateTierRequest true "Ticket tier changes"
+// @Success 200 {object} response.Response{data=ticket.Tier}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
-// @Router /tickets/past [get]
-func (h *TicketHandler) GetPastTickets(c *gin.Context) {
+// @Router /events/{id}/tiers/{tierId} [put]
+func (h *TicketHandler) UpdateTier(c *gin.Context) {
 	// Get user ID from context
 	userIDStr, exists := middleware.GetUserID(c)
 	if !exists {
@@ -451,82 +622,122 @@ func (h *TicketHandler) GetPastTickets(c *gin.Context) {
 		return
 	}
 
-	// Parse query parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	// Parse event and tier IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+	tierID, err := uuid.Parse(c.Param("tierId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid tier ID", err.Error())
+		return
+	}
 
-	// Call usecase
-	tickets, err := h.ticketUsecase.GetPastTickets(c.Request.Context(), userID, limit)
+	var req ticket.UpdateTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	tier, err := h.ticketUsecase.UpdateTier(c.Request.Context(), eventID, tierID, userID, &req)
 	if err != nil {
-		response.InternalError(c, "Failed to get past tickets", err.Error())
+		h.handleTierError(c, err, "Failed to update ticket tier")
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Past tickets retrieved successfully", tickets)
+	response.Success(c, http.StatusOK, "Ticket tier updated successfully", tier)
 }
 
-// VerifyTicket godoc
-// @Summary Verify ticket
-// @Description Verify a ticket is valid for an event
+// DeleteTier godoc
+// @Summary Delete ticket tier
+// @Description Delete a ticket tier that has no tickets sold (host only)
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param ticket_id path string true "Ticket ID" format(uuid)
-// @Param event_id path string true "Event ID" format(uuid)
-// @Success 200 {object} response.Response{data=map[string]bool}
+// @Param id path string true "Event ID" format(uuid)
+// @Param tierId path string true "Tier ID" format(uuid)
+// @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
-// @Router /tickets/{ticket_id}/verify/{event_id} [get]
-func (h *TicketHandler) VerifyTicket(c *gin.Context) {
-	// Parse ticket ID from path
-	ticketIDStr := c.Param("ticket_id")
-	ticketID, err := uuid.Parse(ticketIDStr)
+// @Router /events/{id}/tiers/{tierId} [delete]
+func (h *TicketHandler) DeleteTier(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		response.BadRequest(c, "Invalid ticket ID", err.Error())
+		response.BadRequest(c, "Invalid user ID", err.Error())
 		return
 	}
 
-	// Parse event ID from path
-	eventIDStr := c.Param("event_id")
-	eventID, err := uuid.Parse(eventIDStr)
+	// Parse event and tier IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		response.BadRequest(c, "Invalid event ID", err.Error())
 		return
 	}
-
-	// Call usecase
-	isValid, err := h.ticketUsecase.VerifyTicket(c.Request.Context(), ticketID, eventID)
+	tierID, err := uuid.Parse(c.Param("tierId"))
 	if err != nil {
-		if err == ticketUsecase.ErrTicketNotFound {
-			response.NotFound(c, "Ticket not found")
-			return
-		}
-		response.InternalError(c, "Failed to verify ticket", err.Error())
+		response.BadRequest(c, "Invalid tier ID", err.Error())
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Ticket verification completed", gin.H{
-		"is_valid": isValid,
-	})
+	if err := h.ticketUsecase.DeleteTier(c.Request.Context(), eventID, tierID, userID); err != nil {
+		h.handleTierError(c, err, "Failed to delete ticket tier")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Ticket tier deleted successfully", nil)
 }
 
-// GetTransaction godoc
-// @Summary Get transaction details
-// @Description Get details of a specific ticket transaction
+// handleTierError maps ticket tier usecase errors to HTTP responses
+func (h *TicketHandler) handleTierError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ticketUsecase.ErrEventNotFound):
+		response.NotFound(c, "Event not found")
+	case errors.Is(err, ticketUsecase.ErrTierNotFound):
+		response.NotFound(c, "Ticket tier not found")
+	case errors.Is(err, ticketUsecase.ErrEventForbidden):
+		response.Forbidden(c, "You don't have access to this event")
+	case errors.Is(err, ticketUsecase.ErrUnauthorized):
+		response.Forbidden(c, "Only the event host can manage ticket tiers")
+	case errors.Is(err, ticketUsecase.ErrInvalidTier):
+		response.BadRequest(c, "Invalid ticket tier", err.Error())
+	case errors.Is(err, ticketUsecase.ErrTierHasSales):
+		response.Conflict(c, "Ticket tier already has tickets sold", err.Error())
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
+
+// CreatePromoCode godoc
+// @Summary Create promo code
+// @Description Create a percentage or fixed discount code for an event, optionally restricted to a tier (host only)
 // @Tags tickets
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path string true "Transaction ID"
-// @Success 200 {object} response.Response{data=ticket.TicketTransaction}
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body ticket.CreatePromoCodeRequest true "Promo code data"
+// @Success 201 {object} response.Response{data=ticket.PromoCode}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
-// @Router /tickets/transactions/{id} [get]
-func (h *TicketHandler) GetTransaction(c *gin.Context) {
+// @Router /events/{id}/promo-codes [post]
+func (h *TicketHandler) CreatePromoCode(c *gin.Context) {
 	// Get user ID from context
 	userIDStr, exists := middleware.GetUserID(c)
 	if !exists {
@@ -540,31 +751,1251 @@ func (h *TicketHandler) GetTransaction(c *gin.Context) {
 		return
 	}
 
-	// Parse transaction ID from path
-	transactionID := c.Param("id")
-	if transactionID == "" {
-		response.BadRequest(c, "Transaction ID is required", "")
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
 		return
 	}
 
-	// Call usecase
-	transaction, err := h.ticketUsecase.GetTransaction(c.Request.Context(), transactionID, userID)
+	var req ticket.CreatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	promo, err := h.ticketUsecase.CreatePromoCode(c.Request.Context(), eventID, userID, &req)
 	if err != nil {
-		if err == ticketUsecase.ErrTicketNotFound {
-			response.NotFound(c, "Transaction not found")
+		h.handlePromoCodeError(c, err, "Failed to create promo code")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Promo code created successfully", promo)
+}
+
+// GetEventPromoCodes godoc
+// @Summary Get event promo codes
+// @Description Get the promo codes of an event with their usage (host only)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=[]ticket.PromoCode}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/promo-codes [get]
+func (h *TicketHandler) GetEventPromoCodes(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	promos, err := h.ticketUsecase.GetEventPromoCodes(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handlePromoCodeError(c, err, "Failed to get promo codes")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Promo codes retrieved successfully", promos)
+}
+
+// UpdatePromoCode godoc
+// @Summary Update promo code
+// @Description Update the discount, caps, validity window or active flag of a promo code (host only)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param promoId path string true "Promo code ID" format(uuid)
+// @Param request body ticket.UpdatePromoCodeRequest true "Promo code changes"
+// @Success 200 {object} response.Response{data=ticket.PromoCode}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/promo-codes/{promoId} [put]
+func (h *TicketHandler) UpdatePromoCode(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event and promo code IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+	promoID, err := uuid.Parse(c.Param("promoId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid promo code ID", err.Error())
+		return
+	}
+
+	var req ticket.UpdatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	promo, err := h.ticketUsecase.UpdatePromoCode(c.Request.Context(), eventID, promoID, userID, &req)
+	if err != nil {
+		h.handlePromoCodeError(c, err, "Failed to update promo code")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Promo code updated successfully", promo)
+}
+
+// DeletePromoCode godoc
+// @Summary Delete promo code
+// @Description Delete a promo code; tickets already sold keep their discount (host only)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param promoId path string true "Promo code ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/promo-codes/{promoId} [delete]
+func (h *TicketHandler) DeletePromoCode(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event and promo code IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+	promoID, err := uuid.Parse(c.Param("promoId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid promo code ID", err.Error())
+		return
+	}
+
+	if err := h.ticketUsecase.DeletePromoCode(c.Request.Context(), eventID, promoID, userID); err != nil {
+		h.handlePromoCodeError(c, err, "Failed to delete promo code")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Promo code deleted successfully", nil)
+}
+
+// QuotePromoCode godoc
+// @Summary Apply promo code
+// @Description Preview the ticket price after applying a promo code. Nothing is reserved until purchase.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body ticket.ApplyPromoCodeRequest true "Promo code and tier"
+// @Success 200 {object} response.Response{data=ticket.PromoCodeQuote}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/promo-codes/apply [post]
+func (h *TicketHandler) QuotePromoCode(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	var req ticket.ApplyPromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	quote, err := h.ticketUsecase.QuotePromoCode(c.Request.Context(), eventID, userID, &req)
+	if err != nil {
+		h.handlePromoCodeError(c, err, "Failed to apply promo code")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Promo code applied", quote)
+}
+
+// handlePromoCodeError maps promo code usecase errors to HTTP responses
+func (h *TicketHandler) handlePromoCodeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ticketUsecase.ErrEventNotFound):
+		response.NotFound(c, "Event not found")
+	case errors.Is(err, ticketUsecase.ErrTierNotFound):
+		response.NotFound(c, "Ticket tier not found")
+	case errors.Is(err, ticketUsecase.ErrPromoCodeNotFound):
+		response.NotFound(c, "Promo code not found")
+	case errors.Is(err, ticketUsecase.ErrEventForbidden):
+		response.Forbidden(c, "You don't have access to this event")
+	case errors.Is(err, ticketUsecase.ErrUnauthorized):
+		response.Forbidden(c, "Only the event host can manage promo codes")
+	case errors.Is(err, ticketUsecase.ErrInvalidPromoCode), errors.Is(err, ticketUsecase.ErrPromoCodeInvalid),
+		errors.Is(err, ticketUsecase.ErrTierRequired), errors.Is(err, ticketUsecase.ErrTierNotOnSale):
+		response.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ticketUsecase.ErrPromoCodeExists), errors.Is(err, ticketUsecase.ErrPromoCodeExhausted),
+		errors.Is(err, ticketUsecase.ErrPromoCodeLimitReached), errors.Is(err, ticketUsecase.ErrTierSoldOut):
+		response.Conflict(c, err.Error(), err.Error())
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
+
+// GetEventReconciliationReport godoc
+// @Summary Get event payment reconciliation report
+// @Description Get payment discrepancies found by the reconciliation worker for an event (host only)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]ticket.ReconciliationEntry}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/payment-reconciliation [get]
+func (h *TicketHandler) GetEventReconciliationReport(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventIDStr := c.Param("id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	entries, total, err := h.ticketUsecase.GetEventReconciliationReport(c.Request.Context(), eventID, userID, limit, offset)
+	if err != nil {
+		if err == ticketUsecase.ErrEventNotFound {
+			response.NotFound(c, "Event not found")
 			return
 		}
 		if err == ticketUsecase.ErrUnauthorized {
-			response.Forbidden(c, "You don't have access to this transaction")
+			response.Forbidden(c, "Only the event host can view the reconciliation report")
 			return
 		}
-		if err.Error() == "transaction not found" {
-			response.NotFound(c, "Transaction not found")
+		response.InternalError(c, "Failed to get reconciliation report", err.Error())
+		return
+	}
+
+	meta := response.NewPaginationMeta(total, limit, offset, len(entries))
+	response.Paginated(c, http.StatusOK, "Reconciliation report retrieved successfully", entries, meta)
+}
+
+// GetReconciliationReport godoc
+// @Summary Get payment reconciliation report
+// @Description Get payment discrepancies found by the reconciliation worker across all events (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]ticket.ReconciliationEntry}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/payment-reconciliation [get]
+func (h *TicketHandler) GetReconciliationReport(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	entries, total, err := h.ticketUsecase.GetReconciliationReport(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		if err == ticketUsecase.ErrUnauthorized {
+			response.Forbidden(c, "Admin access required")
 			return
 		}
-		response.InternalError(c, "Failed to get transaction", err.Error())
+		response.InternalError(c, "Failed to get reconciliation report", err.Error())
 		return
 	}
 
-	response.Success(c, http.StatusOK, "Transaction retrieved successfully", transaction)
+	meta := response.NewPaginationMeta(total, limit, offset, len(entries))
+	response.Paginated(c, http.StatusOK, "Reconciliation report retrieved successfully", entries, meta)
+}
+
+// GetAttendanceCode godoc
+// @Summary Get attendance code
+// @Description Get the attendance code for a ticket
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Ticket ID" format(uuid)
+// @Success 200 {object} response.Response{data=map[string]string}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tickets/{id}/attendance-code [get]
+func (h *TicketHandler) GetAttendanceCode(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse ticket ID from path
+	ticketIDStr := c.Param("id")
+	ticketID, err := uuid.Parse(ticketIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid ticket ID", err.Error())
+		return
+	}
+
+	// Call usecase
+	attendanceCode, err := h.ticketUsecase.GetAttendanceCode(c.Request.Context(), ticketID, userID)
+	if err != nil {
+		if err == ticketUsecase.ErrTicketNotFound {
+			response.NotFound(c, "Ticket not found")
+			return
+		}
+		if err == ticketUsecase.ErrUnauthorized {
+			response.Forbidden(c, "You don't have access to this ticket")
+			return
+		}
+		response.InternalError(c, "Failed to get attendance code", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Attendance code retrieved successfully", gin.H{
+		"attendance_code": attendanceCode,
+	})
+}
+
+// GetUpcomingTickets godoc
+// @Summary Get upcoming tickets
+// @Description Get user's upcoming event tickets
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(10)
+// @Success 200 {object} response.Response{data=[]ticket.TicketWithDetails}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tickets/upcoming [get]
+func (h *TicketHandler) GetUpcomingTickets(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	// Call usecase
+	tickets, err := h.ticketUsecase.GetUpcomingTickets(c.Request.Context(), userID, limit)
+	if err != nil {
+		response.InternalError(c, "Failed to get upcoming tickets", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Upcoming tickets retrieved successfully", tickets)
+}
+
+// GetPastTickets godoc
+// @Summary Get past tickets
+// @Description Get user's past event tickets
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(10)
+// @Success 200 {object} response.Response{data=[]ticket.TicketWithDetails}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tickets/past [get]
+func (h *TicketHandler) GetPastTickets(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse query parameters
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	// Call usecase
+	tickets, err := h.ticketUsecase.GetPastTickets(c.Request.Context(), userID, limit)
+	if err != nil {
+		response.InternalError(c, "Failed to get past tickets", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Past tickets retrieved successfully", tickets)
+}
+
+// VerifyTicket godoc
+// @Summary Verify ticket
+// @Description Verify a ticket is valid for an event
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ticket_id path string true "Ticket ID" format(uuid)
+// @Param event_id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=map[string]bool}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tickets/{ticket_id}/verify/{event_id} [get]
+func (h *TicketHandler) VerifyTicket(c *gin.Context) {
+	// Parse ticket ID from path
+	ticketIDStr := c.Param("ticket_id")
+	ticketID, err := uuid.Parse(ticketIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid ticket ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventIDStr := c.Param("event_id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	// Call usecase
+	isValid, err := h.ticketUsecase.VerifyTicket(c.Request.Context(), ticketID, eventID)
+	if err != nil {
+		if err == ticketUsecase.ErrTicketNotFound {
+			response.NotFound(c, "Ticket not found")
+			return
+		}
+		response.InternalError(c, "Failed to verify ticket", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Ticket verification completed", gin.H{
+		"is_valid": isValid,
+	})
+}
+
+// GetTransaction godoc
+// @Summary Get transaction details
+// @Description Get details of a specific ticket transaction
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} response.Response{data=ticket.TicketTransaction}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tickets/transactions/{id} [get]
+func (h *TicketHandler) GetTransaction(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse transaction ID from path
+	transactionID := c.Param("id")
+	if transactionID == "" {
+		response.BadRequest(c, "Transaction ID is required", "")
+		return
+	}
+
+	// Call usecase
+	transaction, err := h.ticketUsecase.GetTransaction(c.Request.Context(), transactionID, userID)
+	if err != nil {
+		if err == ticketUsecase.ErrTicketNotFound {
+			response.NotFound(c, "Transaction not found")
+			return
+		}
+		if err == ticketUsecase.ErrUnauthorized {
+			response.Forbidden(c, "You don't have access to this transaction")
+			return
+		}
+		if err.Error() == "transaction not found" {
+			response.NotFound(c, "Transaction not found")
+			return
+		}
+		response.InternalError(c, "Failed to get transaction", err.Error())
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Transaction retrieved successfully", transaction)
+}
+
+// JoinWaitlist godoc
+// @Summary Join event waitlist
+// @Description Queue for a full event. When a spot frees up the next user in line is offered it for 30 minutes and can buy it through the normal purchase flow; a lapsed offer passes to the next user.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 201 {object} response.Response{data=ticket.WaitlistEntry}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/waitlist [post]
+func (h *TicketHandler) JoinWaitlist(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	entry, err := h.ticketUsecase.JoinWaitlist(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleWaitlistError(c, err, "Failed to join waitlist")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Joined waitlist successfully", entry)
+}
+
+// GetMyWaitlistEntry godoc
+// @Summary Get my waitlist entry
+// @Description Get the current user's position in an event's waitlist, or the spot they were offered and until when they can buy it
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=ticket.WaitlistEntry}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/waitlist/me [get]
+func (h *TicketHandler) GetMyWaitlistEntry(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	entry, err := h.ticketUsecase.GetWaitlistEntry(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleWaitlistError(c, err, "Failed to get waitlist entry")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Waitlist entry retrieved successfully", entry)
+}
+
+// LeaveWaitlist godoc
+// @Summary Leave event waitlist
+// @Description Leave an event's waitlist. An offered spot that is given up passes to the next user in line.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/waitlist [delete]
+func (h *TicketHandler) LeaveWaitlist(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	if err := h.ticketUsecase.LeaveWaitlist(c.Request.Context(), eventID, userID); err != nil {
+		h.handleWaitlistError(c, err, "Failed to leave waitlist")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Left waitlist successfully", nil)
+}
+
+// GetEventWaitlist godoc
+// @Summary Get event waitlist
+// @Description Get the users waiting for an event in queue order, users holding an offered spot first (host only)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.PaginatedResponse{data=[]ticket.WaitlistEntryWithUser}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/waitlist [get]
+func (h *TicketHandler) GetEventWaitlist(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	entries, total, err := h.ticketUsecase.GetEventWaitlist(c.Request.Context(), eventID, userID, limit, offset)
+	if err != nil {
+		h.handleWaitlistError(c, err, "Failed to get waitlist")
+		return
+	}
+
+	meta := response.NewPaginationMeta(total, limit, offset, len(entries))
+	response.Paginated(c, http.StatusOK, "Waitlist retrieved successfully", entries, meta)
+}
+
+// handleWaitlistError maps waitlist usecase errors to HTTP responses
+func (h *TicketHandler) handleWaitlistError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ticketUsecase.ErrEventNotFound):
+		response.NotFound(c, "Event not found")
+	case errors.Is(err, ticketUsecase.ErrNotWaitlisted):
+		response.NotFound(c, "Not on the waitlist for this event")
+	case errors.Is(err, ticketUsecase.ErrEventForbidden):
+		response.Forbidden(c, "You don't have access to this event")
+	case errors.Is(err, ticketUsecase.ErrUnauthorized):
+		response.Forbidden(c, "Only the event host can view the waitlist")
+	case errors.Is(err, ticketUsecase.ErrHostCannotWaitlist), errors.Is(err, ticketUsecase.ErrEventStarted):
+		response.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ticketUsecase.ErrAlreadyWaitlisted), errors.Is(err, ticketUsecase.ErrAlreadyPurchased):
+		response.Conflict(c, err.Error(), err.Error())
+	default:
+		response.InternalError(c, message, err.Error())
+	}
+}
+
+// GetCheckInStats godoc
+// @Summary Get live check-in counts
+// @Description Get how many attendees of an event are checked in, in total and per gate. Changes are also pushed over the event stream as check_in_stats. Only the event host, co-hosts and check-in staff can view them.
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=ticket.CheckInStats}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/check-in-stats [get]
+func (h *TicketHandler) GetCheckInStats(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	stats, err := h.ticketUsecase.GetCheckInStats(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to get check-in stats")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Check-in stats retrieved successfully", stats)
+}
+
+// CreateKioskToken godoc
+// @Summary Issue a kiosk token
+// @Description Issue a token that lets a device check in attendees at one gate of the event without signing in. The token is only shown in this response. It expires 12 hours after the event ends unless an earlier expires_at is given. Only the event host, co-hosts and check-in staff can issue tokens.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body ticket.CreateKioskTokenRequest true "Gate and expiry"
+// @Success 201 {object} response.Response{data=ticket.KioskTokenResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/kiosk-tokens [post]
+func (h *TicketHandler) CreateKioskToken(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	var req ticket.CreateKioskTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.BadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	token, err := h.ticketUsecase.IssueKioskToken(c.Request.Context(), eventID, userID, &req)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to issue kiosk token")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, "Kiosk token issued successfully", token)
+}
+
+// GetKioskTokens godoc
+// @Summary List kiosk tokens
+// @Description List the kiosk tokens of an event. Co-hosts see every token; check-in staff only the ones they issued.
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=[]ticket.KioskToken}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/kiosk-tokens [get]
+func (h *TicketHandler) GetKioskTokens(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	tokens, err := h.ticketUsecase.GetKioskTokens(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to get kiosk tokens")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Kiosk tokens retrieved successfully", tokens)
+}
+
+// RevokeKioskToken godoc
+// @Summary Revoke a kiosk token
+// @Description Revoke a kiosk token; the device using it can't check in attendees anymore. Co-hosts can revoke any token of the event, others only the ones they issued.
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param tokenId path string true "Kiosk token ID" format(uuid)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/kiosk-tokens/{tokenId} [delete]
+func (h *TicketHandler) RevokeKioskToken(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event and token IDs from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	tokenID, err := uuid.Parse(c.Param("tokenId"))
+	if err != nil {
+		response.BadRequest(c, "Invalid kiosk token ID", err.Error())
+		return
+	}
+
+	if err := h.ticketUsecase.RevokeKioskToken(c.Request.Context(), eventID, tokenID, userID); err != nil {
+		h.handleCheckInError(c, err, "Failed to revoke kiosk token")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Kiosk token revoked successfully", nil)
+}
+
+// GetKioskSession godoc
+// @Summary Get kiosk session
+// @Description Get the event and gate a kiosk token checks attendees in to, with the live check-in counts. Authenticated by the kiosk token in the X-Kiosk-Token header instead of a user session.
+// @Tags kiosk
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Success 200 {object} response.Response{data=ticket.KioskSession}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /kiosk/session [get]
+func (h *TicketHandler) GetKioskSession(c *gin.Context) {
+	session, err := h.ticketUsecase.GetKioskSession(c.Request.Context(), c.GetHeader(kioskTokenHeader))
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to get kiosk session")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Kiosk session retrieved successfully", session)
+}
+
+// KioskCheckIn godoc
+// @Summary Check in at a kiosk
+// @Description Check in an attendee at the kiosk token's gate, from their ticket QR or attendance code. Scanning a ticket that is already checked in responds with outcome "duplicate" and where it was first let in. Send the same scan_id when retrying a scan; the retry gets the original outcome. Authenticated by the kiosk token in the X-Kiosk-Token header instead of a user session.
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Param request body ticket.KioskCheckInRequest true "Scanned ticket"
+// @Success 200 {object} response.Response{data=ticket.CheckInResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /kiosk/check-in [post]
+func (h *TicketHandler) KioskCheckIn(c *gin.Context) {
+	var req ticket.KioskCheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.BadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	result, err := h.ticketUsecase.KioskCheckIn(c.Request.Context(), c.GetHeader(kioskTokenHeader), &req)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to check in")
+		return
+	}
+
+	message := "Checked in successfully"
+	if result.Outcome == ticket.CheckInDuplicate {
+		message = "Ticket already checked in"
+	}
+	response.Success(c, http.StatusOK, message, result)
+}
+
+// GetCheckInManifest godoc
+// @Summary Get the offline check-in manifest
+// @Description Download every ticket of an event so a scanner can keep checking people in while offline. The manifest is signed with the ticket QR key; verify it with GET /tickets/qr-public-key before trusting it. Attendance codes are listed as the hex SHA-256 of "<event_id>:<attendance_code>". Upload the scans made offline with POST /events/{id}/check-ins/sync. Only the event host, co-hosts and check-in staff can download it.
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Success 200 {object} response.Response{data=ticket.SignedCheckInManifest}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/check-in-manifest [get]
+func (h *TicketHandler) GetCheckInManifest(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	manifest, err := h.ticketUsecase.GetCheckInManifest(c.Request.Context(), eventID, userID)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to get check-in manifest")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Check-in manifest retrieved successfully", manifest)
+}
+
+// SyncCheckIns godoc
+// @Summary Sync offline check-ins
+// @Description Upload the scans a scanner made while offline, with the time each was made. Tickets are checked in as of their earliest scan, even when another scanner already checked them in later; the other scans become duplicates and are flagged as conflicts. Invalid scans are rejected one by one. Uploading the same scan_id again returns its original outcome, so a failed sync can be retried as is. Only the event host, co-hosts and check-in staff can sync.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID" format(uuid)
+// @Param request body ticket.SyncCheckInsRequest true "Offline scans"
+// @Success 200 {object} response.Response{data=ticket.SyncCheckInsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /events/{id}/check-ins/sync [post]
+func (h *TicketHandler) SyncCheckIns(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := middleware.GetUserID(c)
+	if !exists {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse event ID from path
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid event ID", err.Error())
+		return
+	}
+
+	var req ticket.SyncCheckInsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.BadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	result, err := h.ticketUsecase.SyncCheckIns(c.Request.Context(), eventID, userID, &req)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to sync check-ins")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Check-ins synced successfully", result)
+}
+
+// GetKioskManifest godoc
+// @Summary Get the offline check-in manifest of a kiosk
+// @Description Download every ticket of the kiosk token's event so the kiosk can keep checking people in while offline. See GET /events/{id}/check-in-manifest for the format. Authenticated by the kiosk token in the X-Kiosk-Token header instead of a user session.
+// @Tags kiosk
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Success 200 {object} response.Response{data=ticket.SignedCheckInManifest}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /kiosk/manifest [get]
+func (h *TicketHandler) GetKioskManifest(c *gin.Context) {
+	manifest, err := h.ticketUsecase.GetKioskManifest(c.Request.Context(), c.GetHeader(kioskTokenHeader))
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to get check-in manifest")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Check-in manifest retrieved successfully", manifest)
+}
+
+// SyncKioskCheckIns godoc
+// @Summary Sync offline kiosk check-ins
+// @Description Upload the scans a kiosk made at its token's gate while offline. See POST /events/{id}/check-ins/sync for how conflicts are resolved; the gate of each scan is ignored. Authenticated by the kiosk token in the X-Kiosk-Token header instead of a user session.
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Kiosk-Token header string true "Kiosk token"
+// @Param request body ticket.SyncCheckInsRequest true "Offline scans"
+// @Success 200 {object} response.Response{data=ticket.SyncCheckInsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /kiosk/check-ins/sync [post]
+func (h *TicketHandler) SyncKioskCheckIns(c *gin.Context) {
+	var req ticket.SyncCheckInsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.BadRequest(c, "Validation failed", err.Error())
+		return
+	}
+
+	result, err := h.ticketUsecase.SyncKioskCheckIns(c.Request.Context(), c.GetHeader(kioskTokenHeader), &req)
+	if err != nil {
+		h.handleCheckInError(c, err, "Failed to sync check-ins")
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Check-ins synced successfully", result)
+}
+
+// kioskTokenHeader carries the token of kiosk requests
+const kioskTokenHeader = "X-Kiosk-Token"
+
+// handleCheckInError maps check-in and kiosk usecase errors to HTTP responses
+func (h *TicketHandler) handleCheckInError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ticketUsecase.ErrInvalidKioskToken):
+		response.Unauthorized(c, "Kiosk token is invalid, expired or revoked")
+	case errors.Is(err, ticketUsecase.ErrEventNotFound):
+		response.NotFound(c, "Event not found")
+	case errors.Is(err, ticketUsecase.ErrTicketNotFound):
+		response.NotFound(c, "Ticket not found")
+	case errors.Is(err, ticketUsecase.ErrKioskTokenNotFound):
+		response.NotFound(c, "Kiosk token not found")
+	case errors.Is(err, ticketUsecase.ErrUnauthorized):
+		response.Forbidden(c, "Only the event host, co-hosts and check-in staff can check in attendees")
+	case errors.Is(err, ticketUsecase.ErrInvalidQR):
+		response.BadRequest(c, "Invalid ticket QR", err.Error())
+	case errors.Is(err, ticketUsecase.ErrQRExpired):
+		response.BadRequest(c, "Ticket QR has expired", err.Error())
+	case errors.Is(err, ticketUsecase.ErrInvalidAttendanceCode):
+		response.BadRequest(c, "Invalid attendance code", err.Error())
+	case errors.Is(err, ticketUsecase.ErrTicketNotActive):
+		response.BadRequest(c, "Ticket is not active", err.Error())
+	case errors.Is(err, ticketUsecase.ErrGateRequired),
+		errors.Is(err, ticketUsecase.ErrCheckInClosed),
+		errors.Is(err, ticketUsecase.ErrInvalidKioskExpiry):
+		response.BadRequest(c, err.Error(), err.Error())
+	case errors.Is(err, ticketUsecase.ErrScanIDReused):
+		response.Conflict(c, err.Error(), err.Error())
+	default:
+		response.InternalError(c, message, err.Error())
+	}
 }
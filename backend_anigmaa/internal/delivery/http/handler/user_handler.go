@@ -183,15 +183,17 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 // GetFollowers godoc
 // @Summary Get user's followers
-// @Description Get a list of users following the specified user
+// @Description Get the users following the specified user, most recent first, using cursor pagination
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID" format(uuid)
 // @Param limit query int false "Limit" default(20)
-// @Param offset query int false "Offset" default(0)
-// @Success 200 {object} response.Response{data=[]user.User}
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param offset query int false "Offset (deprecated, use cursor)" default(0)
+// @Param include_total query bool false "Also count all followers" default(false)
+// @Success 200 {object} response.PaginatedResponse{data=[]user.Follower}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -206,35 +208,35 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-
-	// Get total count for pagination
-	total, err := h.userUsecase.CountFollowers(c.Request.Context(), userID)
-	if err != nil {
-		// If count fails, default to 0 but continue
-		total = 0
-	}
+	page := parsePageQuery(c)
 
 	// Get followers
-	followers, err := h.userUsecase.GetFollowers(c.Request.Context(), userID, limit, offset)
+	followers, nextCursor, err := h.userUsecase.GetFollowers(c.Request.Context(), userID, page.Cursor, page.Limit, page.Offset)
 	if err != nil {
 		if err == userUsecase.ErrUserNotFound {
 			response.NotFound(c, "User not found")
 			return
 		}
+		if err == userUsecase.ErrInvalidCursor {
+			response.BadRequest(c, "Invalid cursor", err.Error())
+			return
+		}
 		response.InternalError(c, "Failed to get followers", err.Error())
 		return
 	}
 
 	// Ensure we return empty array instead of null when no followers
 	if followers == nil {
-		followers = []user.User{}
+		followers = []user.Follower{}
 	}
 
-	// Create pagination metadata with correct total
-	meta := response.NewPaginationMeta(total, limit, offset, len(followers))
-	response.Paginated(c, http.StatusOK, "Followers retrieved successfully", followers, meta)
+	// Total count is optional
+	total := 0
+	if page.IncludeTotal {
+		total, _ = h.userUsecase.CountFollowers(c.Request.Context(), userID)
+	}
+
+	response.Paginated(c, http.StatusOK, "Followers retrieved successfully", followers, page.meta(total, nextCursor))
 }
 
 // GetFollowing godoc
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"log"
+	"net/http"
 	"strings"
 
+	"github.com/anigmaa/backend/internal/domain/auth"
 	"github.com/anigmaa/backend/internal/domain/user"
 	"github.com/anigmaa/backend/pkg/jwt"
 	"github.com/anigmaa/backend/pkg/response"
@@ -10,8 +13,8 @@ import (
 	"github.com/google/uuid"
 )
 
-// JWTAuth middleware validates JWT token
-func JWTAuth(jwtManager *jwt.JWTManager) gin.HandlerFunc {
+// JWTAuth middleware validates JWT token and rejects tokens revoked by logout or account deletion
+func JWTAuth(jwtManager *jwt.JWTManager, revocationRepo auth.RevocationRepository) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -38,9 +41,61 @@ func JWTAuth(jwtManager *jwt.JWTManager) gin.HandlerFunc {
 			return
 		}
 
+		// Refresh tokens may only be used at /auth/refresh
+		if claims.IsRefreshToken() {
+			response.Unauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		// Check revocation list
+		revoked, err := isRevoked(c, revocationRepo, claims)
+		if err != nil {
+			// Fail closed: without the revocation list we cannot tell a logged-out token from a valid one
+			log.Printf("[JWTAuth] Failed to check token revocation: %v", err)
+			response.Error(c, http.StatusServiceUnavailable, "Authentication temporarily unavailable", "SERVICE_UNAVAILABLE", "")
+			c.Abort()
+			return
+		}
+		if revoked {
+			response.Unauthorized(c, "Token has been revoked")
+			c.Abort()
+			return
+		}
+
 		// Set user ID in context (convert UUID to string)
 		c.Set("user_id", claims.UserID.String())
 		c.Set("email", claims.Email)
+		c.Set("token_claims", claims)
+
+		c.Next()
+	}
+}
+
+// OptionalJWTAuth sets the user in context when the request carries a valid,
+// unrevoked access token, and otherwise lets the request through anonymously
+func OptionalJWTAuth(jwtManager *jwt.JWTManager, revocationRepo auth.RevocationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.Next()
+			return
+		}
+
+		claims, err := jwtManager.Verify(parts[1])
+		if err != nil || claims.IsRefreshToken() {
+			c.Next()
+			return
+		}
+
+		if revoked, err := isRevoked(c, revocationRepo, claims); err != nil || revoked {
+			c.Next()
+			return
+		}
+
+		c.Set("user_id", claims.UserID.String())
+		c.Set("email", claims.Email)
+		c.Set("token_claims", claims)
 
 		c.Next()
 	}
@@ -55,6 +110,23 @@ func GetUserID(c *gin.Context) (string, bool) {
 	return userID.(string), true
 }
 
+// GetTokenClaims gets the verified JWT claims of the current request from context
+func GetTokenClaims(c *gin.Context) (*jwt.Claims, bool) {
+	claims, exists := c.Get("token_claims")
+	if !exists {
+		return nil, false
+	}
+	return claims.(*jwt.Claims), true
+}
+
+// isRevoked checks both the per-token and the user-wide revocation entries
+func isRevoked(c *gin.Context, revocationRepo auth.RevocationRepository, claims *jwt.Claims) (bool, error) {
+	if revocationRepo == nil {
+		return false, nil
+	}
+	return auth.IsRevoked(c.Request.Context(), revocationRepo, claims)
+}
+
 // GetEmail gets the email from context
 func GetEmail(c *gin.Context) (string, bool) {
 	email, exists := c.Get("email")
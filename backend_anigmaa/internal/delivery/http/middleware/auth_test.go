@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/auth"
+	"github.com/anigmaa/backend/pkg/jwt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fakeRevocationRepo holds revoked jtis and user-wide cut-offs in memory
+type fakeRevocationRepo struct {
+	auth.RevocationRepository
+	tokens  map[string]bool
+	cutoffs map[uuid.UUID]time.Time
+	err     error
+}
+
+func (r *fakeRevocationRepo) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return r.tokens[tokenID], r.err
+}
+
+func (r *fakeRevocationRepo) IsUserTokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	cutoff, ok := r.cutoffs[userID]
+	return ok && issuedAt.Unix() <= cutoff.Unix(), nil
+}
+
+// serveJWTAuth sends a request with the given bearer token through JWTAuth and
+// returns the response status
+func serveJWTAuth(t *testing.T, jwtManager *jwt.JWTManager, repo auth.RevocationRepository, token string) int {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/me", JWTAuth(jwtManager, repo), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestJWTAuth_Revocation(t *testing.T) {
+	jwtManager := jwt.NewJWTManager("test-secret", time.Hour, 24*time.Hour)
+	userID := uuid.New()
+	token, err := jwtManager.Generate(userID, "budi@example.com")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	claims, err := jwtManager.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		repo  *fakeRevocationRepo
+		token string
+		want  int
+	}{
+		{"valid token", &fakeRevocationRepo{}, token, http.StatusNoContent},
+		{"no token", &fakeRevocationRepo{}, "", http.StatusUnauthorized},
+		{"malformed token", &fakeRevocationRepo{}, "not-a-jwt", http.StatusUnauthorized},
+		{"revoked jti", &fakeRevocationRepo{tokens: map[string]bool{claims.ID: true}}, token, http.StatusUnauthorized},
+		{"issued before the user's cut-off", &fakeRevocationRepo{cutoffs: map[uuid.UUID]time.Time{userID: time.Now()}}, token, http.StatusUnauthorized},
+		{"issued after the user's cut-off", &fakeRevocationRepo{cutoffs: map[uuid.UUID]time.Time{userID: time.Now().Add(-time.Hour)}}, token, http.StatusNoContent},
+		{"Redis unavailable", &fakeRevocationRepo{err: errors.New("connection refused")}, token, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serveJWTAuth(t, jwtManager, tt.repo, tt.token); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJWTAuth_RejectsRefreshTokens(t *testing.T) {
+	jwtManager := jwt.NewJWTManager("test-secret", time.Hour, 24*time.Hour)
+	pair, err := jwtManager.GenerateTokenPair(uuid.New(), "budi@example.com", uuid.New())
+	if err != nil {
+		t.Fatalf("GenerateTokenPair() error = %v", err)
+	}
+
+	if got := serveJWTAuth(t, jwtManager, &fakeRevocationRepo{}, pair.RefreshToken); got != http.StatusUnauthorized {
+		t.Errorf("status with a refresh token = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := serveJWTAuth(t, jwtManager, &fakeRevocationRepo{}, pair.AccessToken); got != http.StatusNoContent {
+		t.Errorf("status with its access token = %d, want %d", got, http.StatusNoContent)
+	}
+}
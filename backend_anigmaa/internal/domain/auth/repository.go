@@ -2,7 +2,9 @@ package auth
 
 import (
 	"context"
+	"time"
 
+	"github.com/anigmaa/backend/pkg/jwt"
 	"github.com/google/uuid"
 )
 
@@ -23,3 +25,52 @@ type Repository interface {
 	// DeleteTokensByUser deletes all tokens for a specific user
 	DeleteTokensByUser(ctx context.Context, userID uuid.UUID, tokenType TokenType) error
 }
+
+// SessionRepository defines the interface for device session persistence
+type SessionRepository interface {
+	// CreateSession creates a new device session
+	CreateSession(ctx context.Context, session *Session) error
+
+	// GetSessionByID gets a session by ID
+	GetSessionByID(ctx context.Context, id uuid.UUID) (*Session, error)
+
+	// RotateSession replaces the session's refresh token, but only if oldRefreshTokenID is still current.
+	// Returns sql.ErrNoRows if the session was rotated or revoked concurrently.
+	RotateSession(ctx context.Context, id uuid.UUID, oldRefreshTokenID, newRefreshTokenID, newAccessTokenID string, expiresAt time.Time) error
+
+	// ListActiveSessions lists a user's unrevoked, unexpired sessions, most recently used first
+	ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]Session, error)
+
+	// RevokeSession revokes a single session
+	RevokeSession(ctx context.Context, id uuid.UUID, reason string) error
+
+	// RevokeUserSessions revokes all of a user's active sessions except the given one (uuid.Nil to revoke all)
+	RevokeUserSessions(ctx context.Context, userID, exceptSessionID uuid.UUID, reason string) ([]Session, error)
+}
+
+// RevocationRepository stores revoked JWTs until they would have expired anyway
+type RevocationRepository interface {
+	// RevokeToken revokes a single token by its jti for the given remaining lifetime
+	RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error
+
+	// IsTokenRevoked checks whether a token's jti has been revoked
+	IsTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+
+	// RevokeUserTokens revokes every token of a user issued at or before the given time
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error
+
+	// IsUserTokenRevoked checks whether a token issued at issuedAt falls under a user-wide revocation
+	IsUserTokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error)
+}
+
+// IsRevoked checks a token against both the per-token and the user-wide revocation entries
+func IsRevoked(ctx context.Context, repo RevocationRepository, claims *jwt.Claims) (bool, error) {
+	revoked, err := repo.IsTokenRevoked(ctx, claims.ID)
+	if err != nil || revoked {
+		return revoked, err
+	}
+	if claims.IssuedAt == nil {
+		return false, nil
+	}
+	return repo.IsUserTokenRevoked(ctx, claims.UserID, claims.IssuedAt.Time)
+}
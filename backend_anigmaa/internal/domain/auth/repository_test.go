@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/pkg/jwt"
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// fakeRevocationRepo keeps revoked jtis and per-user cut-offs the way the Redis
+// repository does, comparing issue times in whole seconds
+type fakeRevocationRepo struct {
+	RevocationRepository
+	tokens  map[string]bool
+	cutoffs map[uuid.UUID]time.Time
+	err     error
+}
+
+func (r *fakeRevocationRepo) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return r.tokens[tokenID], r.err
+}
+
+func (r *fakeRevocationRepo) IsUserTokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	cutoff, ok := r.cutoffs[userID]
+	return ok && issuedAt.Unix() <= cutoff.Unix(), nil
+}
+
+func TestIsRevoked(t *testing.T) {
+	userID := uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+	claims := func(id string, issuedAt time.Time) *jwt.Claims {
+		c := &jwt.Claims{UserID: userID}
+		c.ID = id
+		if !issuedAt.IsZero() {
+			c.IssuedAt = jwtlib.NewNumericDate(issuedAt)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		repo    *fakeRevocationRepo
+		claims  *jwt.Claims
+		want    bool
+		wantErr bool
+	}{
+		{"valid token", &fakeRevocationRepo{}, claims("a", time.Now()), false, false},
+		{"revoked jti", &fakeRevocationRepo{tokens: map[string]bool{"a": true}}, claims("a", time.Now()), true, false},
+		{"other jti revoked", &fakeRevocationRepo{tokens: map[string]bool{"b": true}}, claims("a", time.Now()), false, false},
+		{"issued before the user cut-off", &fakeRevocationRepo{cutoffs: map[uuid.UUID]time.Time{userID: deletedAt}}, claims("a", deletedAt.Add(-time.Minute)), true, false},
+		{"issued in the cut-off second", &fakeRevocationRepo{cutoffs: map[uuid.UUID]time.Time{userID: deletedAt}}, claims("a", deletedAt), true, false},
+		{"issued after the user cut-off", &fakeRevocationRepo{cutoffs: map[uuid.UUID]time.Time{userID: deletedAt}}, claims("a", deletedAt.Add(time.Minute)), false, false},
+		{"no issue time", &fakeRevocationRepo{cutoffs: map[uuid.UUID]time.Time{userID: deletedAt}}, claims("a", time.Time{}), false, false},
+		{"revocation list unavailable", &fakeRevocationRepo{err: errors.New("connection refused")}, claims("a", time.Now()), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsRevoked(context.Background(), tt.repo, tt.claims)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IsRevoked() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session revocation reasons
+const (
+	RevokedReasonLogout            = "logout"
+	RevokedReasonUserRevoked       = "user_revoked"
+	RevokedReasonRefreshTokenReuse = "refresh_token_reuse"
+	RevokedReasonAccountDeleted    = "account_deleted"
+)
+
+// Session represents a device session, i.e. one refresh token family
+type Session struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	RefreshTokenID string     `json:"-" db:"refresh_token_id"`
+	AccessTokenID  *string    `json:"-" db:"access_token_id"`
+	DeviceName     *string    `json:"device_name,omitempty" db:"device_name"`
+	UserAgent      *string    `json:"user_agent,omitempty" db:"user_agent"`
+	IPAddress      *string    `json:"ip_address,omitempty" db:"ip_address"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt     time.Time  `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevokedReason  *string    `json:"revoked_reason,omitempty" db:"revoked_reason"`
+}
+
+// IsActive checks if the session can still be refreshed
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// DeviceInfo describes the client a session is created for
+type DeviceInfo struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
+
+// SessionResponse represents a session as shown to its owner
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName *string   `json:"device_name,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+// ToResponse converts a session to its API representation
+func (s *Session) ToResponse(currentSessionID uuid.UUID) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		DeviceName: s.DeviceName,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+		IsCurrent:  s.ID == currentSessionID,
+	}
+}
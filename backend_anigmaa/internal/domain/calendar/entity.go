@@ -0,0 +1,24 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedToken is the secret in a user's calendar feed URL. Anyone with the URL can
+// read the feed, so only a hash of the secret is stored and the URL is reset
+// rather than shown again when the user asks.
+type FeedToken struct {
+	UserID    uuid.UUID `json:"-" db:"user_id"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// FeedResponse is a user's calendar feed subscription. The URLs are only set
+// when the feed was just created or reset.
+type FeedResponse struct {
+	URL       string    `json:"url,omitempty"`        // https URL of the feed
+	WebcalURL string    `json:"webcal_url,omitempty"` // same feed as webcal://, which opens the subscribe dialog of calendar apps
+	CreatedAt time.Time `json:"created_at"`
+}
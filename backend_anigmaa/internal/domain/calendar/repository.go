@@ -0,0 +1,23 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for calendar feed data access
+type Repository interface {
+	// GetFeedToken gets a user's feed token; sql.ErrNoRows if they don't have one
+	GetFeedToken(ctx context.Context, userID uuid.UUID) (*FeedToken, error)
+	// GetFeedTokenByHash gets a feed token by the hash of its secret; sql.ErrNoRows if it doesn't exist
+	GetFeedTokenByHash(ctx context.Context, tokenHash string) (*FeedToken, error)
+	// SaveFeedToken stores a user's feed token, replacing the one they had
+	SaveFeedToken(ctx context.Context, token *FeedToken) error
+	// GetFeedEvents gets the events in a user's feed: the hostedLimit events they
+	// created last that aren't archived, and the ticketLimit first events starting
+	// after from that they hold an active ticket for
+	GetFeedEvents(ctx context.Context, userID uuid.UUID, from time.Time, hostedLimit, ticketLimit int) ([]event.Event, error)
+}
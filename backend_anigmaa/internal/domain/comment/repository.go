@@ -3,6 +3,7 @@ package comment
 import (
 	"context"
 
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -16,7 +17,8 @@ type Repository interface {
 	Delete(ctx context.Context, commentID uuid.UUID) error
 
 	// Comment queries
-	GetByPost(ctx context.Context, postID, userID uuid.UUID, limit, offset int) ([]CommentWithDetails, error)
+	// GetByPost lists comments most liked first; before is a scored cursor (likes count) or nil
+	GetByPost(ctx context.Context, postID, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]CommentWithDetails, error)
 	GetReplies(ctx context.Context, parentCommentID, userID uuid.UUID, limit, offset int) ([]CommentWithDetails, error)
 	GetCount(ctx context.Context, postID uuid.UUID) (int, error)
 
@@ -0,0 +1,61 @@
+package email
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Template identifies a transactional email and the templates it's rendered from
+type Template string
+
+const (
+	TemplateWelcome            Template = "welcome"
+	TemplateTicketConfirmation Template = "ticket_confirmation"
+	TemplatePaymentReceipt     Template = "payment_receipt"
+	TemplateTicketCancelled    Template = "ticket_cancelled"
+	TemplateRefundIssued       Template = "refund_issued"
+	TemplateEventUpdated       Template = "event_updated"
+	TemplateEventCancelled     Template = "event_cancelled"
+
+	// Plain-text emails queued by services.EmailService
+	TemplateEmailVerification Template = "email_verification"
+	TemplatePasswordReset     Template = "password_reset"
+	TemplateBulk              Template = "bulk"
+)
+
+// Status is the delivery status of an email in the outbox
+type Status string
+
+const (
+	StatusPending Status = "pending" // waiting for its next delivery attempt
+	StatusSent    Status = "sent"
+	StatusFailed  Status = "failed" // gave up after the last attempt
+)
+
+// Email is a rendered email in the outbox. It's stored before it's sent, so
+// emails survive restarts and failed deliveries are retried.
+type Email struct {
+	ID            uuid.UUID    `json:"id" db:"id"`
+	UserID        *uuid.UUID   `json:"user_id,omitempty" db:"user_id"`
+	Recipient     string       `json:"recipient" db:"recipient"`
+	Template      Template     `json:"template" db:"template"`
+	Language      string       `json:"language" db:"language"`
+	Subject       string       `json:"subject" db:"subject"`
+	HTMLBody      string       `json:"html_body" db:"html_body"`
+	Attachments   []Attachment `json:"attachments,omitempty" db:"-"`
+	Status        Status       `json:"status" db:"status"`
+	Attempts      int          `json:"attempts" db:"attempts"`
+	LastError     *string      `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" db:"next_attempt_at"`
+	SentAt        *time.Time   `json:"sent_at,omitempty" db:"sent_at"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+}
+
+// Attachment is a file sent with an email
+type Attachment struct {
+	EmailID     uuid.UUID `json:"-" db:"email_id"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Content     []byte    `json:"-" db:"content"`
+}
@@ -0,0 +1,21 @@
+package email
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for the email outbox
+type Repository interface {
+	// Enqueue stores an email with its attachments, due for delivery right away
+	Enqueue(ctx context.Context, email *Email) error
+	// ClaimDue claims up to limit pending emails that are due, with their attachments.
+	// Each claim counts as an attempt and hides the email from other claims for lease.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Email, error)
+	MarkSent(ctx context.Context, id uuid.UUID) error
+	// MarkFailed records a failed attempt. The email is retried at retryAt, or
+	// given up on if retryAt is nil.
+	MarkFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error
+}
@@ -0,0 +1,28 @@
+package event
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrForbidden is returned when a user may not view an event
+var ErrForbidden = errors.New("not allowed to view this event")
+
+// CheckAccess returns ErrForbidden unless the user may view the event (see Event.CanView).
+// userID is uuid.Nil for anonymous viewers.
+func CheckAccess(ctx context.Context, repo Repository, evt *Event, userID uuid.UUID) error {
+	if evt.Privacy == PrivacyPublic {
+		return nil
+	}
+
+	access, err := repo.GetAccess(ctx, evt.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !evt.CanView(*access) {
+		return ErrForbidden
+	}
+	return nil
+}
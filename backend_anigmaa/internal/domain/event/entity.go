@@ -1,11 +1,20 @@
 package event
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrEventFull is returned when confirming attendees would overbook an event
+	ErrEventFull = errors.New("event is full")
+	// ErrCapacityBelowSold is returned when an edit lowers MaxAttendees below the
+	// tickets already sold
+	ErrCapacityBelowSold = errors.New("max_attendees can't be lower than the tickets already sold")
+)
+
 // EventStatus represents the status of an event
 type EventStatus string
 
@@ -65,6 +74,11 @@ type Event struct {
 	Privacy          EventPrivacy  `json:"privacy" db:"privacy"`
 	Requirements     *string       `json:"requirements,omitempty" db:"requirements"`
 	TicketingEnabled bool          `json:"ticketing_enabled" db:"ticketing_enabled"`
+	RequiresApproval bool          `json:"requires_approval" db:"requires_approval"`         // Join requests wait for the host's approval
+	SeriesID         *uuid.UUID    `json:"series_id,omitempty" db:"series_id"`               // Recurring series this event is an occurrence of
+	OccurrenceStart  *time.Time    `json:"occurrence_start,omitempty" db:"occurrence_start"` // Slot of the series rule, kept when the start time is edited
+	SeriesOverride   bool          `json:"series_override" db:"series_override"`             // Edited on its own; series-wide edits skip it
+	CalendarSequence int           `json:"-" db:"calendar_sequence"`                         // iCalendar SEQUENCE, bumped by the database when the time, place or title changes
 	TicketsSold      int           `json:"tickets_sold" db:"tickets_sold"`
 	CreatedAt        time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time     `json:"updated_at" db:"updated_at"`
@@ -73,37 +87,75 @@ type Event struct {
 // EventWithDetails includes additional event information
 type EventWithDetails struct {
 	Event
-	HostName            string       `json:"host_name" db:"host_name"`
-	HostAvatarURL       *string      `json:"host_avatar_url" db:"host_avatar_url"`
-	ImageURLs           []string     `json:"image_urls" db:"-"`
-	AttendeesCount      int          `json:"attendees_count" db:"attendees_count"`
-	InterestsCount      int          `json:"interests_count" db:"interests_count"`
-	InterestedUserIDs   []uuid.UUID  `json:"interested_user_ids" db:"-"` // List of user IDs interested in this event
-	IsUserAttending     bool         `json:"is_user_attending" db:"is_user_attending"`
-	IsUserInterested    bool         `json:"is_user_interested" db:"is_user_interested"`
-	IsUserHost          bool         `json:"is_user_host" db:"is_user_host"`
-	IsArchived          bool         `json:"is_archived" db:"is_archived"`
-	Distance            *float64     `json:"distance,omitempty" db:"distance"` // Distance in km from user
-}
-
-// EventAttendee represents an event attendee
+	HostName             string          `json:"host_name" db:"host_name"`
+	HostAvatarURL        *string         `json:"host_avatar_url" db:"host_avatar_url"`
+	ImageURLs            []string        `json:"image_urls" db:"-"`
+	AttendeesCount       int             `json:"attendees_count" db:"attendees_count"`
+	InterestsCount       int             `json:"interests_count" db:"interests_count"`
+	InterestedUserIDs    []uuid.UUID     `json:"interested_user_ids" db:"-"` // List of user IDs interested in this event
+	IsUserAttending      bool            `json:"is_user_attending" db:"is_user_attending"`
+	IsUserInterested     bool            `json:"is_user_interested" db:"is_user_interested"`
+	IsUserHost           bool            `json:"is_user_host" db:"is_user_host"`
+	UserAttendanceStatus *AttendeeStatus `json:"user_attendance_status,omitempty" db:"user_attendance_status"` // Viewer's join status, incl. pending requests
+	IsArchived           bool            `json:"is_archived" db:"is_archived"`
+	Distance             *float64        `json:"distance,omitempty" db:"distance"` // Distance in km from user
+}
+
+// EventAttendee represents an event attendee, or a join request of an event that requires approval
 type EventAttendee struct {
-	ID       uuid.UUID      `json:"id" db:"id"`
-	EventID  uuid.UUID      `json:"event_id" db:"event_id"`
-	UserID   uuid.UUID      `json:"user_id" db:"user_id"`
-	JoinedAt time.Time      `json:"joined_at" db:"joined_at"`
-	Status   AttendeeStatus `json:"status" db:"status"`
+	ID                 uuid.UUID      `json:"id" db:"id"`
+	EventID            uuid.UUID      `json:"event_id" db:"event_id"`
+	UserID             uuid.UUID      `json:"user_id" db:"user_id"`
+	JoinedAt           time.Time      `json:"joined_at" db:"joined_at"`
+	Status             AttendeeStatus `json:"status" db:"status"`
+	RequirementsAnswer *string        `json:"requirements_answer,omitempty" db:"requirements_answer"` // Answer to the event's Requirements
+	RespondedAt        *time.Time     `json:"responded_at,omitempty" db:"responded_at"`               // When the host approved or rejected the request
 }
 
 // AttendeeStatus represents the status of an attendee
 type AttendeeStatus string
 
 const (
-	AttendeePending   AttendeeStatus = "pending"
+	AttendeePending   AttendeeStatus = "pending" // Join request awaiting the host's approval
 	AttendeeConfirmed AttendeeStatus = "confirmed"
 	AttendeeCancelled AttendeeStatus = "cancelled"
+	AttendeeRejected  AttendeeStatus = "rejected" // Join request rejected by the host
+)
+
+// JoinRequest is a pending or reviewed join request with the requester's details
+type JoinRequest struct {
+	EventAttendee
+	UserName      string  `json:"user_name" db:"user_name"`
+	UserUsername  *string `json:"user_username,omitempty" db:"user_username"`
+	UserAvatarURL *string `json:"user_avatar_url,omitempty" db:"user_avatar_url"`
+}
+
+// StaffRole is a role the host grants someone on their event
+type StaffRole string
+
+const (
+	StaffCoHost       StaffRole = "co_host"       // Checks people in, moderates Q&A and sees the event's tickets
+	StaffCheckIn      StaffRole = "check_in"      // Checks people in at the door
+	StaffQnAModerator StaffRole = "qna_moderator" // Answers and removes questions
 )
 
+// Staff is a user the host granted a role on an event
+type Staff struct {
+	EventID   uuid.UUID `json:"event_id" db:"event_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Role      StaffRole `json:"role" db:"role"`
+	AddedBy   uuid.UUID `json:"added_by" db:"added_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// StaffWithUser is a staff member with their user details
+type StaffWithUser struct {
+	Staff
+	UserName      string  `json:"user_name" db:"user_name"`
+	UserUsername  *string `json:"user_username,omitempty" db:"user_username"`
+	UserAvatarURL *string `json:"user_avatar_url,omitempty" db:"user_avatar_url"`
+}
+
 // EventInterest represents a user's interest/like in an event
 type EventInterest struct {
 	ID        uuid.UUID `json:"id" db:"id"`
@@ -112,6 +164,81 @@ type EventInterest struct {
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
+// StatusChange describes an event status transition pushed to real-time streams
+type StatusChange struct {
+	EventID        uuid.UUID   `json:"event_id"`
+	Title          string      `json:"title"`
+	PreviousStatus EventStatus `json:"previous_status"`
+	Status         EventStatus `json:"status"`
+}
+
+// Schedule is what attendees plan around: when and where an event takes place,
+// and whether it still does
+type Schedule struct {
+	StartTime       time.Time
+	EndTime         time.Time
+	LocationName    string
+	LocationAddress string
+	LocationLat     float64
+	LocationLng     float64
+	Status          EventStatus
+}
+
+// ChangedFrom reports whether the schedule differs from previous, so attendees
+// need telling. Edits of e.g. the description, images or capacity don't count.
+func (s Schedule) ChangedFrom(previous Schedule) bool {
+	return !s.StartTime.Equal(previous.StartTime) || !s.EndTime.Equal(previous.EndTime) ||
+		s.LocationName != previous.LocationName || s.LocationAddress != previous.LocationAddress ||
+		s.LocationLat != previous.LocationLat || s.LocationLng != previous.LocationLng ||
+		s.Status != previous.Status
+}
+
+// StatusTransition is an event the lifecycle worker moved to a new status
+type StatusTransition struct {
+	Event
+	PreviousStatus EventStatus `db:"previous_status"`
+}
+
+// ReminderKind identifies a reminder sent to an event's ticket holders before it starts
+type ReminderKind string
+
+const (
+	Reminder24h ReminderKind = "24h"
+	Reminder1h  ReminderKind = "1h"
+)
+
+// SeriesDateLayout is the layout of a series' exception dates
+const SeriesDateLayout = "2006-01-02"
+
+// EventSeries is a recurring event. Its occurrences are regular events linked
+// by SeriesID and generated ahead of time from the rule, so attendees, tickets
+// and capacity stay per occurrence.
+type EventSeries struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	HostID         uuid.UUID `json:"host_id" db:"host_id"`
+	RRule          string    `json:"rrule" db:"rrule"`                     // RFC 5545 RRULE, see ParseRecurrence
+	Timezone       string    `json:"timezone" db:"timezone"`               // IANA zone the rule is evaluated in
+	StartsAt       time.Time `json:"starts_at" db:"starts_at"`             // DTSTART: the first occurrence's start
+	ExceptionDates []string  `json:"exception_dates" db:"exception_dates"` // Local dates (YYYY-MM-DD) left out of the series
+	GeneratedUntil time.Time `json:"generated_until" db:"generated_until"` // Occurrences exist up to this time
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SeriesWithOccurrences is a series with its upcoming occurrences, for linking them in the UI
+type SeriesWithOccurrences struct {
+	EventSeries
+	Occurrences []Event `json:"occurrences"`
+}
+
+// SeriesScope tells UpdateEvent which occurrences of a series an edit applies to
+type SeriesScope string
+
+const (
+	ScopeOccurrence SeriesScope = "occurrence" // Only the edited occurrence
+	ScopeSeries     SeriesScope = "series"     // The edited and all upcoming occurrences not edited on their own
+)
+
 // EventImage represents an event image
 type EventImage struct {
 	ID       uuid.UUID `json:"id" db:"id"`
@@ -122,45 +249,73 @@ type EventImage struct {
 
 // CreateEventRequest represents event creation data
 type CreateEventRequest struct {
-	Title            string        `json:"title" binding:"required,min=3,max=100"`
-	Description      string        `json:"description" binding:"required,min=10"`
-	Category         EventCategory `json:"category" binding:"required"`
-	StartTime        time.Time     `json:"start_time" binding:"required"`
-	EndTime          time.Time     `json:"end_time" binding:"required"`
-	LocationName     string        `json:"location_name" binding:"required"`
-	LocationAddress  string        `json:"location_address" binding:"required"`
-	LocationLat      float64       `json:"location_lat" binding:"required,min=-90,max=90"`
-	LocationLng      float64       `json:"location_lng" binding:"required,min=-180,max=180"`
-	MaxAttendees     int           `json:"max_attendees" binding:"required,min=3,max=100"`
-	Price            *float64      `json:"price,omitempty" binding:"omitempty,min=0"`
-	IsFree           bool          `json:"is_free"`
-	Privacy          EventPrivacy  `json:"privacy" binding:"required"`
-	Requirements     *string       `json:"requirements,omitempty"`
-	TicketingEnabled bool          `json:"ticketing_enabled"`
-	ImageURLs        []string      `json:"image_urls,omitempty"`
+	Title            string             `json:"title" binding:"required,min=3,max=100"`
+	Description      string             `json:"description" binding:"required,min=10"`
+	Category         EventCategory      `json:"category" binding:"required"`
+	StartTime        time.Time          `json:"start_time" binding:"required"`
+	EndTime          time.Time          `json:"end_time" binding:"required"`
+	LocationName     string             `json:"location_name" binding:"required"`
+	LocationAddress  string             `json:"location_address" binding:"required"`
+	LocationLat      float64            `json:"location_lat" binding:"required,min=-90,max=90"`
+	LocationLng      float64            `json:"location_lng" binding:"required,min=-180,max=180"`
+	MaxAttendees     int                `json:"max_attendees" binding:"required,min=3,max=100"`
+	Price            *float64           `json:"price,omitempty" binding:"omitempty,min=0"`
+	IsFree           bool               `json:"is_free"`
+	Privacy          EventPrivacy       `json:"privacy" binding:"required"`
+	Requirements     *string            `json:"requirements,omitempty"`
+	TicketingEnabled bool               `json:"ticketing_enabled"`
+	RequiresApproval bool               `json:"requires_approval"`
+	Recurrence       *RecurrenceRequest `json:"recurrence,omitempty"` // Makes the event the first occurrence of a series
+	ImageURLs        []string           `json:"image_urls,omitempty"`
+}
+
+// RecurrenceRequest represents the recurrence of an event series
+type RecurrenceRequest struct {
+	RRule          string   `json:"rrule" binding:"required"` // e.g. FREQ=WEEKLY;BYDAY=SA or FREQ=MONTHLY;BYDAY=1SA
+	Timezone       string   `json:"timezone,omitempty"`       // Defaults to UTC
+	ExceptionDates []string `json:"exception_dates,omitempty" binding:"omitempty,max=366,dive,datetime=2006-01-02"`
 }
 
 // UpdateEventRequest represents event update data
 type UpdateEventRequest struct {
-	Title           *string        `json:"title,omitempty" binding:"omitempty,min=3,max=100"`
-	Description     *string        `json:"description,omitempty" binding:"omitempty,min=10"`
-	Category        *EventCategory `json:"category,omitempty"`
-	StartTime       *time.Time     `json:"start_time,omitempty"`
-	EndTime         *time.Time     `json:"end_time,omitempty"`
-	LocationName    *string        `json:"location_name,omitempty"`
-	LocationAddress *string        `json:"location_address,omitempty"`
-	LocationLat     *float64       `json:"location_lat,omitempty" binding:"omitempty,min=-90,max=90"`
-	LocationLng     *float64       `json:"location_lng,omitempty" binding:"omitempty,min=-180,max=180"`
-	MaxAttendees    *int           `json:"max_attendees,omitempty" binding:"omitempty,min=3,max=100"`
-	Price           *float64       `json:"price,omitempty" binding:"omitempty,min=0"`
-	Privacy         *EventPrivacy  `json:"privacy,omitempty"`
-	Requirements    *string        `json:"requirements,omitempty"`
-	Status          *EventStatus   `json:"status,omitempty"`
-	ImageURLs       *[]string      `json:"image_urls,omitempty"` // If provided, replaces all existing images
+	Title            *string            `json:"title,omitempty" binding:"omitempty,min=3,max=100"`
+	Description      *string            `json:"description,omitempty" binding:"omitempty,min=10"`
+	Category         *EventCategory     `json:"category,omitempty"`
+	StartTime        *time.Time         `json:"start_time,omitempty"`
+	EndTime          *time.Time         `json:"end_time,omitempty"`
+	LocationName     *string            `json:"location_name,omitempty"`
+	LocationAddress  *string            `json:"location_address,omitempty"`
+	LocationLat      *float64           `json:"location_lat,omitempty" binding:"omitempty,min=-90,max=90"`
+	LocationLng      *float64           `json:"location_lng,omitempty" binding:"omitempty,min=-180,max=180"`
+	MaxAttendees     *int               `json:"max_attendees,omitempty" binding:"omitempty,min=3,max=100"`
+	Price            *float64           `json:"price,omitempty" binding:"omitempty,min=0"`
+	Privacy          *EventPrivacy      `json:"privacy,omitempty"`
+	Requirements     *string            `json:"requirements,omitempty"`
+	RequiresApproval *bool              `json:"requires_approval,omitempty"` // Pending requests stay pending when turned off
+	Status           *EventStatus       `json:"status,omitempty"`
+	ImageURLs        *[]string          `json:"image_urls,omitempty"` // If provided, replaces all existing images
+	ApplyTo          *SeriesScope       `json:"apply_to,omitempty"`   // Occurrences of a series: "occurrence" (default) or "series"
+	Recurrence       *RecurrenceRequest `json:"recurrence,omitempty"` // Replaces the series' recurrence; needs apply_to=series
+}
+
+// JoinEventRequest represents the optional data sent when joining an event
+type JoinEventRequest struct {
+	RequirementsAnswer *string `json:"requirements_answer,omitempty" binding:"omitempty,max=1000"`
+}
+
+// ReviewJoinRequestsRequest represents the users whose join requests the host approves or rejects
+type ReviewJoinRequestsRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=100"`
+}
+
+// SetStaffRoleRequest represents the role the host grants a user on their event
+type SetStaffRoleRequest struct {
+	Role StaffRole `json:"role" binding:"required,oneof=co_host check_in qna_moderator"`
 }
 
 // EventFilter represents event filtering options
 type EventFilter struct {
+	Query     *string        `form:"q"` // Full-text query over title, description and location
 	Category  *EventCategory `form:"category"`
 	StartDate *time.Time     `form:"start_date"`
 	EndDate   *time.Time     `form:"end_date"`
@@ -172,6 +327,10 @@ type EventFilter struct {
 	Mode      string         `form:"mode"`   // Discovery mode: "trending", "for_you", "chill"
 	Limit     int            `form:"limit"`
 	Offset    int            `form:"offset"`
+
+	// Keyset position for the default (newest first) order; set from the cursor
+	BeforeCreatedAt *time.Time `form:"-"`
+	BeforeID        *uuid.UUID `form:"-"`
 }
 
 // Business logic methods
@@ -197,3 +356,61 @@ func (e *Event) IsCompleted() bool {
 func (e *Event) SpotsLeft() int {
 	return e.MaxAttendees - e.TicketsSold
 }
+
+// Schedule returns when and where the event takes place, and its status
+func (e *Event) Schedule() Schedule {
+	return Schedule{
+		StartTime:       e.StartTime,
+		EndTime:         e.EndTime,
+		LocationName:    e.LocationName,
+		LocationAddress: e.LocationAddress,
+		LocationLat:     e.LocationLat,
+		LocationLng:     e.LocationLng,
+		Status:          e.Status,
+	}
+}
+
+// Access describes a user's relationship to an event, as needed to apply its privacy
+type Access struct {
+	IsHost           bool
+	IsAttendee       bool      // Joined the event or holds a ticket for it
+	IsMutualFollower bool      // The user and the host follow each other
+	IsInvited        bool      // The user accepted an invitation to the event
+	StaffRole        StaffRole // Role the host granted the user, empty if none
+}
+
+// CanCheckIn reports whether the user may check people in: the host, co-hosts and check-in staff
+func (a Access) CanCheckIn() bool {
+	return a.IsHost || a.StaffRole == StaffCoHost || a.StaffRole == StaffCheckIn
+}
+
+// CanModerateQnA reports whether the user may answer and remove questions: the
+// host, co-hosts and Q&A moderators
+func (a Access) CanModerateQnA() bool {
+	return a.IsHost || a.StaffRole == StaffCoHost || a.StaffRole == StaffQnAModerator
+}
+
+// IsCoHost reports whether the user helps run the whole event: the host and co-hosts
+func (a Access) IsCoHost() bool {
+	return a.IsHost || a.StaffRole == StaffCoHost
+}
+
+// CanView reports whether a user with the given access may see the event and
+// join it or buy tickets. Public events are open to everyone, friends_only events
+// to mutual followers of the host, and private events are invite-only. The host,
+// invited users, staff and people already attending always keep access.
+func (e *Event) CanView(a Access) bool {
+	if a.IsHost || a.IsAttendee || a.IsInvited || a.StaffRole != "" {
+		return true
+	}
+
+	switch e.Privacy {
+	case PrivacyPublic:
+		return true
+	case PrivacyFriendsOnly:
+		return a.IsMutualFollower
+	default:
+		// Private and unknown privacy levels are closed by default
+		return false
+	}
+}
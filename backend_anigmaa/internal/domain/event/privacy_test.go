@@ -0,0 +1,80 @@
+package event
+
+import "testing"
+
+func TestEvent_CanView(t *testing.T) {
+	host := Access{IsHost: true}
+	attendee := Access{IsAttendee: true}
+	mutual := Access{IsMutualFollower: true}
+	invited := Access{IsInvited: true}
+	staff := Access{StaffRole: StaffCheckIn}
+	stranger := Access{}
+
+	tests := []struct {
+		name    string
+		privacy EventPrivacy
+		access  Access
+		want    bool
+	}{
+		{"public - host", PrivacyPublic, host, true},
+		{"public - attendee", PrivacyPublic, attendee, true},
+		{"public - mutual follower", PrivacyPublic, mutual, true},
+		{"public - invited", PrivacyPublic, invited, true},
+		{"public - stranger", PrivacyPublic, stranger, true},
+
+		{"friends_only - host", PrivacyFriendsOnly, host, true},
+		{"friends_only - attendee", PrivacyFriendsOnly, attendee, true},
+		{"friends_only - mutual follower", PrivacyFriendsOnly, mutual, true},
+		{"friends_only - invited", PrivacyFriendsOnly, invited, true},
+		{"friends_only - stranger", PrivacyFriendsOnly, stranger, false},
+
+		{"private - host", PrivacyPrivate, host, true},
+		{"private - attendee", PrivacyPrivate, attendee, true},
+		{"private - mutual follower", PrivacyPrivate, mutual, false},
+		{"private - invited", PrivacyPrivate, invited, true},
+		{"private - staff", PrivacyPrivate, staff, true},
+		{"private - stranger", PrivacyPrivate, stranger, false},
+
+		{"unknown privacy - mutual follower", EventPrivacy("secret"), mutual, false},
+		{"unknown privacy - host", EventPrivacy("secret"), host, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Event{Privacy: tt.privacy}
+			if got := e.CanView(tt.access); got != tt.want {
+				t.Errorf("CanView(%+v) = %v, want %v", tt.access, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccess_StaffPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		access      Access
+		checkIn     bool
+		moderateQnA bool
+		coHost      bool
+	}{
+		{"host", Access{IsHost: true}, true, true, true},
+		{"co-host", Access{StaffRole: StaffCoHost}, true, true, true},
+		{"check-in staff", Access{StaffRole: StaffCheckIn}, true, false, false},
+		{"Q&A moderator", Access{StaffRole: StaffQnAModerator}, false, true, false},
+		{"attendee", Access{IsAttendee: true}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.access.CanCheckIn(); got != tt.checkIn {
+				t.Errorf("CanCheckIn() = %v, want %v", got, tt.checkIn)
+			}
+			if got := tt.access.CanModerateQnA(); got != tt.moderateQnA {
+				t.Errorf("CanModerateQnA() = %v, want %v", got, tt.moderateQnA)
+			}
+			if got := tt.access.IsCoHost(); got != tt.coHost {
+				t.Errorf("IsCoHost() = %v, want %v", got, tt.coHost)
+			}
+		})
+	}
+}
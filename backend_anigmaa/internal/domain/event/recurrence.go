@@ -0,0 +1,381 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRecurrence is returned for recurrence rules that can't be parsed or aren't supported
+var ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+
+// maxRecurrencePeriods bounds how many periods (days, weeks or months) are
+// walked to find occurrences, so rules that rarely match can't loop forever
+const maxRecurrencePeriods = 10000
+
+// Frequency is the FREQ of a recurrence rule
+type Frequency string
+
+const (
+	FreqDaily   Frequency = "DAILY"
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+)
+
+// WeekdayNum is a BYDAY entry: a weekday, optionally with its ordinal in the
+// month (2TU is the second Tuesday, -1FR the last Friday; 0 means every one)
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Recurrence is a parsed RFC 5545 RRULE. Event series support the DAILY,
+// WEEKLY and MONTHLY frequencies with INTERVAL, COUNT, UNTIL, BYDAY and
+// BYMONTHDAY, which covers weekly runs and "first Saturday of the month" style
+// workshops.
+type Recurrence struct {
+	Freq       Frequency
+	Interval   int
+	Count      int // 0 when the rule isn't limited by COUNT
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+
+	untilIsDate bool // UNTIL was a date, so it includes that whole local day
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// NormalizeRRule upper-cases a rule and strips its optional "RRULE:" prefix
+func NormalizeRRule(rule string) string {
+	rule = strings.ToUpper(strings.TrimSpace(rule))
+	return strings.TrimPrefix(rule, "RRULE:")
+}
+
+// ParseRecurrence parses an RRULE such as "FREQ=WEEKLY;BYDAY=TU,TH" or
+// "FREQ=MONTHLY;BYDAY=-1FR;COUNT=6"
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	r := &Recurrence{Interval: 1}
+
+	rule = NormalizeRRule(rule)
+	if rule == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRecurrence)
+	}
+
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRecurrence, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s given twice", ErrInvalidRecurrence, key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if r.Freq != FreqDaily && r.Freq != FreqWeekly && r.Freq != FreqMonthly {
+				err = fmt.Errorf("unsupported frequency %s", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = parseRecurrenceInt(value, 1, 365)
+		case "COUNT":
+			r.Count, err = parseRecurrenceInt(value, 1, 1000)
+		case "UNTIL":
+			err = r.parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(value)
+		case "WKST":
+			// Weeks start on Monday, the RFC 5545 default
+			if value != "MO" {
+				err = fmt.Errorf("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	return r, nil
+}
+
+func (r *Recurrence) validate() error {
+	if r.Freq == "" {
+		return errors.New("FREQ is required")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("COUNT and UNTIL can't be combined")
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != FreqMonthly {
+		return errors.New("BYMONTHDAY is only supported for monthly rules")
+	}
+	if len(r.ByMonthDay) > 0 && len(r.ByDay) > 0 {
+		return errors.New("BYDAY and BYMONTHDAY can't be combined")
+	}
+	if len(r.ByDay) > 0 && r.Freq == FreqDaily {
+		return errors.New("BYDAY is not supported for daily rules")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != FreqMonthly {
+			return errors.New("BYDAY ordinals are only supported for monthly rules")
+		}
+	}
+	return nil
+}
+
+func (r *Recurrence) parseUntil(value string) error {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			r.Until = &t
+			return nil
+		}
+	}
+
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return fmt.Errorf("invalid UNTIL %s", value)
+	}
+	r.Until = &t
+	r.untilIsDate = true
+	return nil
+}
+
+func parseRecurrenceInt(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s must be a number from %d to %d", value, min, max)
+	}
+	return n, nil
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	days := make([]WeekdayNum, 0)
+	for _, entry := range strings.Split(value, ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %s", entry)
+		}
+
+		code, ordinal := entry[len(entry)-2:], entry[:len(entry)-2]
+		weekday, ok := weekdayCodes[code]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %s", entry)
+		}
+
+		n := 0
+		if ordinal != "" {
+			var err error
+			n, err = strconv.Atoi(strings.TrimPrefix(ordinal, "+"))
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY %s", entry)
+			}
+		}
+
+		days = append(days, WeekdayNum{Weekday: weekday, N: n})
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	days := make([]int, 0)
+	for _, entry := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(entry)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %s", entry)
+		}
+		days = append(days, n)
+	}
+	return days, nil
+}
+
+// Occurrences returns the start times (in UTC) of the occurrences starting in
+// [from, until), in order. dtstart is the start of the first occurrence; every
+// occurrence keeps its wall clock time in loc, across DST changes. COUNT is
+// counted from dtstart, so occurrences before from still use it up.
+func (r *Recurrence) Occurrences(dtstart time.Time, loc *time.Location, from, until time.Time) []time.Time {
+	start := dtstart.In(loc)
+	occurrences := make([]time.Time, 0)
+
+	count := 0
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		for _, t := range r.periodStarts(start, period) {
+			if t.Before(start) {
+				continue
+			}
+			if r.Count > 0 && count >= r.Count {
+				return occurrences
+			}
+			if r.isAfterUntil(t) || !t.Before(until) {
+				return occurrences
+			}
+
+			count++
+			if !t.Before(from) {
+				occurrences = append(occurrences, t.UTC())
+			}
+		}
+	}
+
+	return occurrences
+}
+
+func (r *Recurrence) isAfterUntil(t time.Time) bool {
+	if r.Until == nil {
+		return false
+	}
+	if r.untilIsDate {
+		y, m, d := r.Until.Date()
+		return !t.Before(time.Date(y, m, d+1, 0, 0, 0, 0, t.Location()))
+	}
+	return t.After(*r.Until)
+}
+
+// periodStarts returns the candidate starts of the given period (day, week or
+// month) after the one of start, in order, at start's wall clock time
+func (r *Recurrence) periodStarts(start time.Time, period int) []time.Time {
+	y, m, d := start.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		return []time.Time{at(y, m, d+period*r.Interval)}
+
+	case FreqWeekly:
+		weekdays := []time.Weekday{start.Weekday()}
+		if len(r.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, wd := range r.ByDay {
+				weekdays = append(weekdays, wd.Weekday)
+			}
+		}
+
+		monday := d - daysSinceMonday(start.Weekday()) + period*r.Interval*7
+		days := make([]int, 0, len(weekdays))
+		for _, wd := range weekdays {
+			days = append(days, monday+daysSinceMonday(wd))
+		}
+		return datesAt(days, func(day int) time.Time { return at(y, m, day) })
+
+	case FreqMonthly:
+		first := time.Date(y, m+time.Month(period*r.Interval), 1, 0, 0, 0, 0, start.Location())
+		year, month := first.Year(), first.Month()
+		daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, start.Location()).Day()
+
+		days := make([]int, 0)
+		switch {
+		case len(r.ByMonthDay) > 0:
+			for _, n := range r.ByMonthDay {
+				if n < 0 {
+					n = daysInMonth + n + 1
+				}
+				if n >= 1 && n <= daysInMonth {
+					days = append(days, n)
+				}
+			}
+		case len(r.ByDay) > 0:
+			for _, wd := range r.ByDay {
+				days = append(days, monthWeekdays(first, daysInMonth, wd)...)
+			}
+		default:
+			// Months without the start's day of month (e.g. the 31st) are skipped
+			if d <= daysInMonth {
+				days = append(days, d)
+			}
+		}
+		return datesAt(days, func(day int) time.Time { return at(year, month, day) })
+	}
+
+	return nil
+}
+
+// monthWeekdays returns the days of the month matching a BYDAY entry
+func monthWeekdays(first time.Time, daysInMonth int, wd WeekdayNum) []int {
+	firstMatch := 1 + (int(wd.Weekday)-int(first.Weekday())+7)%7
+
+	matches := make([]int, 0, 5)
+	for day := firstMatch; day <= daysInMonth; day += 7 {
+		matches = append(matches, day)
+	}
+
+	switch {
+	case wd.N == 0:
+		return matches
+	case wd.N > 0 && wd.N <= len(matches):
+		return matches[wd.N-1 : wd.N]
+	case wd.N < 0 && -wd.N <= len(matches):
+		return matches[len(matches)+wd.N : len(matches)+wd.N+1]
+	}
+	return nil
+}
+
+// datesAt sorts and de-duplicates days and turns them into times
+func datesAt(days []int, at func(day int) time.Time) []time.Time {
+	sort.Ints(days)
+
+	times := make([]time.Time, 0, len(days))
+	for i, day := range days {
+		if i > 0 && day == days[i-1] {
+			continue
+		}
+		times = append(times, at(day))
+	}
+	return times
+}
+
+func daysSinceMonday(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+// Location returns the timezone the series' rule is evaluated in
+func (s *EventSeries) Location() (*time.Location, error) {
+	return time.LoadLocation(s.Timezone)
+}
+
+// OccurrenceStarts returns the starts of the series' occurrences in [from, until),
+// leaving out its exception dates
+func (s *EventSeries) OccurrenceStarts(from, until time.Time) ([]time.Time, error) {
+	rule, err := ParseRecurrence(s.RRule)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]bool, len(s.ExceptionDates))
+	for _, date := range s.ExceptionDates {
+		excluded[date] = true
+	}
+
+	starts := make([]time.Time, 0)
+	for _, t := range rule.Occurrences(s.StartsAt, loc, from, until) {
+		if !excluded[t.In(loc).Format(SeriesDateLayout)] {
+			starts = append(starts, t)
+		}
+	}
+	return starts, nil
+}
@@ -0,0 +1,131 @@
+package event
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYDAY=MO;BYMONTHDAY=1",
+		"FREQ=DAILY;COUNT=3;UNTIL=20250101",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYSETPOS=1",
+	}
+
+	for _, rule := range tests {
+		t.Run(rule, func(t *testing.T) {
+			if _, err := ParseRecurrence(rule); !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("ParseRecurrence(%q) error = %v, want ErrInvalidRecurrence", rule, err)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Occurrences(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	// Saturday 4 January 2025, 06:00 local
+	dtstart := time.Date(2025, 1, 4, 6, 0, 0, 0, jakarta)
+
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		want []string
+	}{
+		{
+			name: "weekly",
+			rule: "FREQ=WEEKLY;COUNT=3",
+			want: []string{"2025-01-04", "2025-01-11", "2025-01-18"},
+		},
+		{
+			name: "every other week on two days",
+			rule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=SA,SU;COUNT=4",
+			want: []string{"2025-01-04", "2025-01-05", "2025-01-18", "2025-01-19"},
+		},
+		{
+			name: "first Saturday of the month",
+			rule: "FREQ=MONTHLY;BYDAY=1SA;COUNT=3",
+			want: []string{"2025-01-04", "2025-02-01", "2025-03-01"},
+		},
+		{
+			name: "last Friday of the month",
+			rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=2",
+			want: []string{"2025-01-31", "2025-02-28"},
+		},
+		{
+			name: "31st skips short months",
+			rule: "FREQ=MONTHLY;BYMONTHDAY=31;COUNT=3",
+			want: []string{"2025-01-31", "2025-03-31", "2025-05-31"},
+		},
+		{
+			name: "until a date includes that day",
+			rule: "FREQ=DAILY;INTERVAL=3;UNTIL=20250110",
+			want: []string{"2025-01-04", "2025-01-07", "2025-01-10"},
+		},
+		{
+			name: "count is used up before from",
+			rule: "FREQ=WEEKLY;COUNT=3",
+			from: time.Date(2025, 1, 10, 0, 0, 0, 0, jakarta),
+			want: []string{"2025-01-11", "2025-01-18"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error = %v", tt.rule, err)
+			}
+
+			from := tt.from
+			if from.IsZero() {
+				from = dtstart
+			}
+			got := r.Occurrences(dtstart, jakarta, from, dtstart.AddDate(1, 0, 0))
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i, occurrence := range got {
+				local := occurrence.In(jakarta)
+				if local.Format("2006-01-02") != tt.want[i] || local.Hour() != 6 {
+					t.Errorf("Occurrences()[%d] = %v, want %s 06:00", i, local, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEventSeries_OccurrenceStarts(t *testing.T) {
+	series := &EventSeries{
+		RRule:          "FREQ=WEEKLY;COUNT=4",
+		Timezone:       "UTC",
+		StartsAt:       time.Date(2025, 1, 4, 6, 0, 0, 0, time.UTC),
+		ExceptionDates: []string{"2025-01-11"},
+	}
+
+	got, err := series.OccurrenceStarts(series.StartsAt, series.StartsAt.AddDate(0, 2, 0))
+	if err != nil {
+		t.Fatalf("OccurrenceStarts() error = %v", err)
+	}
+
+	want := []string{"2025-01-04", "2025-01-18", "2025-01-25"}
+	if len(got) != len(want) {
+		t.Fatalf("OccurrenceStarts() = %v, want %v", got, want)
+	}
+	for i, start := range got {
+		if start.Format(SeriesDateLayout) != want[i] {
+			t.Errorf("OccurrenceStarts()[%d] = %v, want %s", i, start, want[i])
+		}
+	}
+}
@@ -13,28 +13,79 @@ type Repository interface {
 	Create(ctx context.Context, event *Event) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Event, error)
 	GetWithDetails(ctx context.Context, eventID, userID uuid.UUID) (*EventWithDetails, error)
+	GetAccess(ctx context.Context, eventID, userID uuid.UUID) (*Access, error)
+	// Update saves an event. Returns sql.ErrNoRows if it doesn't exist, and
+	// ErrCapacityBelowSold if a changed MaxAttendees is below the tickets sold by then.
 	Update(ctx context.Context, event *Event) error
 	Delete(ctx context.Context, id uuid.UUID) error
 
-	// Event queries
+	// Event queries (only events the viewer may see, see Event.CanView)
 	List(ctx context.Context, filter *EventFilter, userID uuid.UUID) ([]EventWithDetails, error)
-	GetByHost(ctx context.Context, hostID uuid.UUID, limit, offset int) ([]EventWithDetails, error)
+	GetByHost(ctx context.Context, hostID, viewerID uuid.UUID, limit, offset int) ([]EventWithDetails, error)
 	GetJoinedEvents(ctx context.Context, userID uuid.UUID, limit, offset int) ([]EventWithDetails, error)
-	GetNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]EventWithDetails, error)
+	GetNearby(ctx context.Context, lat, lng, radiusKm float64, limit int, viewerID uuid.UUID) ([]EventWithDetails, error)
 
 	// Counting for pagination
-	CountEvents(ctx context.Context, filter *EventFilter) (int, error)
-	CountHostedEvents(ctx context.Context, hostID uuid.UUID) (int, error)
+	CountEvents(ctx context.Context, filter *EventFilter, viewerID uuid.UUID) (int, error)
+	CountHostedEvents(ctx context.Context, hostID, viewerID uuid.UUID) (int, error)
 	CountJoinedEvents(ctx context.Context, userID uuid.UUID) (int, error)
 	CountAttendees(ctx context.Context, eventID uuid.UUID) (int, error)
 
 	// Attendee management
 	Join(ctx context.Context, attendee *EventAttendee) error
 	Leave(ctx context.Context, eventID, userID uuid.UUID) error
+	// GetAttendee gets a user's attendee record of an event, whatever its status
+	GetAttendee(ctx context.Context, eventID, userID uuid.UUID) (*EventAttendee, error)
 	GetAttendees(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]EventAttendee, error)
+	// GetAttendeeIDs pages through the user IDs of an event's confirmed attendees
+	// in ID order, starting after the given ID (uuid.Nil for the first page)
+	GetAttendeeIDs(ctx context.Context, eventID, after uuid.UUID, limit int) ([]uuid.UUID, error)
 	IsAttending(ctx context.Context, eventID, userID uuid.UUID) (bool, error)
 	GetAttendeesCount(ctx context.Context, eventID uuid.UUID) (int, error)
 
+	// Join requests (events that require approval)
+	GetJoinRequests(ctx context.Context, eventID uuid.UUID, status AttendeeStatus, limit, offset int) ([]JoinRequest, error)
+	CountJoinRequests(ctx context.Context, eventID uuid.UUID, status AttendeeStatus) (int, error)
+	// ReviewJoinRequests moves the pending requests of the given users to status
+	// (confirmed or rejected) and returns the users whose request was updated.
+	// Confirmations are checked against the event's capacity under a lock of the
+	// event row; ErrEventFull is returned, and nothing updated, if they don't fit.
+	ReviewJoinRequests(ctx context.Context, eventID uuid.UUID, userIDs []uuid.UUID, status AttendeeStatus) ([]uuid.UUID, error)
+
+	// Staff
+	// SetStaff grants a user a role on an event, replacing the role they had
+	SetStaff(ctx context.Context, staff *Staff) error
+	// RemoveStaff takes a user's role on an event away; returns sql.ErrNoRows if they had none
+	RemoveStaff(ctx context.Context, eventID, userID uuid.UUID) error
+	GetStaff(ctx context.Context, eventID uuid.UUID) ([]StaffWithUser, error)
+
+	// Recurring series
+	CreateSeries(ctx context.Context, series *EventSeries) error
+	GetSeries(ctx context.Context, id uuid.UUID) (*EventSeries, error)
+	UpdateSeries(ctx context.Context, series *EventSeries) error
+	// GetSeriesToExtend gets the series whose occurrences are only generated up to before until
+	GetSeriesToExtend(ctx context.Context, until time.Time) ([]EventSeries, error)
+	// GetSeriesOccurrences gets the occurrences of a series starting from from, in order
+	GetSeriesOccurrences(ctx context.Context, seriesID uuid.UUID, from time.Time, limit int) ([]Event, error)
+	// GetSeriesTemplate gets the latest occurrence that isn't cancelled or edited on its
+	// own; new occurrences are copied from it
+	GetSeriesTemplate(ctx context.Context, seriesID uuid.UUID) (*Event, error)
+	// CreateOccurrences inserts occurrences of a series copied from template,
+	// skipping the slots (OccurrenceStart) that already have one, and returns the
+	// inserted ones. The template's ticket tiers and promo codes are copied to each
+	// occurrence, unsold and with their sale windows moved along with its start time.
+	CreateOccurrences(ctx context.Context, seriesID uuid.UUID, template *Event, occurrences []Event) ([]Event, error)
+	// UpdateSeriesEvent saves a series-wide edit of occurrence e in one transaction:
+	//   1. Saves e and the series (its recurrence and StartsAt)
+	//   2. Copies the series-wide fields of e to the upcoming occurrences that aren't
+	//      cancelled or edited on their own, moving them by shift
+	//   3. Moves the slots (OccurrenceStart) of all occurrences by shift
+	//   4. When keep isn't nil, removes the upcoming occurrences whose slot isn't in
+	//      keep. Ones with attendees or tickets are kept and detached (SeriesOverride).
+	// Returns the other occurrences updated, or ErrCapacityBelowSold (and saves nothing)
+	// if MaxAttendees is below the tickets sold of e or any of them.
+	UpdateSeriesEvent(ctx context.Context, e *Event, series *EventSeries, shift time.Duration, keep []time.Time) ([]uuid.UUID, error)
+
 	// Image management
 	AddImages(ctx context.Context, images []EventImage) error
 	GetImages(ctx context.Context, eventID uuid.UUID) ([]string, error)
@@ -43,6 +94,17 @@ type Repository interface {
 
 	// Status management
 	UpdateStatus(ctx context.Context, eventID uuid.UUID, status EventStatus) error
+	// TransitionStatuses moves every upcoming and ongoing event to the status its
+	// start and end time call for at now, and returns the events that changed
+	TransitionStatuses(ctx context.Context, now time.Time) ([]StatusTransition, error)
+	// ClaimReminders claims the reminder of the given kind for the upcoming events
+	// starting after from and up to until, and returns the events claimed. An event
+	// is claimed once per start time, before its reminder is sent, so reminders are
+	// delivered at most once.
+	ClaimReminders(ctx context.Context, kind ReminderKind, from, until time.Time) ([]Event, error)
+	// GetReminderRecipients gets the users holding an active ticket for an event or
+	// attending it
+	GetReminderRecipients(ctx context.Context, eventID uuid.UUID) ([]uuid.UUID, error)
 	GetUpcomingEvents(ctx context.Context, limit int) ([]EventWithDetails, error)
 	GetLiveEvents(ctx context.Context, limit int) ([]EventWithDetails, error)
 
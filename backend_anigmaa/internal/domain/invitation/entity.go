@@ -0,0 +1,86 @@
+package invitation
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInviteLinkExhausted is returned when an invite link reached its usage cap
+// while being redeemed
+var ErrInviteLinkExhausted = errors.New("invite link has reached its usage limit")
+
+// ErrInvitationRevoked is returned when the host revoked the user's invitation,
+// which an invite link can't undo
+var ErrInvitationRevoked = errors.New("your invitation to this event was revoked")
+
+// Status represents the status of an invitation
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusAccepted Status = "accepted"
+	StatusDeclined Status = "declined"
+	StatusRevoked  Status = "revoked"
+)
+
+// Invitation represents an invitation of a user to an event.
+// Accepted invitations grant access to friends_only and private events.
+type Invitation struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	EventID      uuid.UUID  `json:"event_id" db:"event_id"`
+	InviterID    uuid.UUID  `json:"inviter_id" db:"inviter_id"`
+	InviteeID    uuid.UUID  `json:"invitee_id" db:"invitee_id"`
+	InviteLinkID *uuid.UUID `json:"invite_link_id,omitempty" db:"invite_link_id"` // Set when accepted through an invite link
+	Status       Status     `json:"status" db:"status"`
+	InvitedAt    time.Time  `json:"invited_at" db:"invited_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty" db:"responded_at"`
+}
+
+// InvitationWithDetails includes the event and the people involved
+type InvitationWithDetails struct {
+	Invitation
+	EventTitle       string    `json:"event_title" db:"event_title"`
+	EventStartTime   time.Time `json:"event_start_time" db:"event_start_time"`
+	InviterName      string    `json:"inviter_name" db:"inviter_name"`
+	InviteeName      string    `json:"invitee_name" db:"invitee_name"`
+	InviteeUsername  *string   `json:"invitee_username,omitempty" db:"invitee_username"`
+	InviteeAvatarURL *string   `json:"invitee_avatar_url,omitempty" db:"invitee_avatar_url"`
+}
+
+// InviteLink represents a shareable invite token of an event
+type InviteLink struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	EventID   uuid.UUID  `json:"event_id" db:"event_id"`
+	CreatedBy uuid.UUID  `json:"created_by" db:"created_by"`
+	Token     string     `json:"token" db:"token"`
+	MaxUses   *int       `json:"max_uses,omitempty" db:"max_uses"` // Nil = unlimited
+	UsedCount int        `json:"used_count" db:"used_count"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActiveAt reports whether the link can still be redeemed at the given time,
+// ignoring its usage cap
+func (l *InviteLink) IsActiveAt(now time.Time) bool {
+	return l.RevokedAt == nil && now.Before(l.ExpiresAt)
+}
+
+// IsExhausted reports whether the link reached its usage cap
+func (l *InviteLink) IsExhausted() bool {
+	return l.MaxUses != nil && l.UsedCount >= *l.MaxUses
+}
+
+// CreateInvitationsRequest represents the request to invite users to an event
+type CreateInvitationsRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=50"`
+}
+
+// CreateInviteLinkRequest represents the request to create an invite link.
+// Links expire after 7 days by default and never outlive the event.
+type CreateInviteLinkRequest struct {
+	MaxUses   *int       `json:"max_uses,omitempty" binding:"omitempty,gt=0"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,36 @@
+package invitation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for event invitation data access
+type Repository interface {
+	// Invitations
+	// CreateInvitations stores pending invitations and returns the ones that were
+	// created or re-sent. Users with a pending or accepted invitation are skipped.
+	CreateInvitations(ctx context.Context, invitations []Invitation) ([]Invitation, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Invitation, error)
+	GetEventInvitations(ctx context.Context, eventID uuid.UUID, status *Status, limit, offset int) ([]InvitationWithDetails, error)
+	CountEventInvitations(ctx context.Context, eventID uuid.UUID, status *Status) (int, error)
+	GetUserInvitations(ctx context.Context, userID uuid.UUID, status *Status, limit, offset int) ([]InvitationWithDetails, error)
+	CountUserInvitations(ctx context.Context, userID uuid.UUID, status *Status) (int, error)
+	// Respond accepts or declines a pending invitation; returns sql.ErrNoRows if it isn't pending
+	Respond(ctx context.Context, id uuid.UUID, status Status) error
+	// Revoke revokes a pending or accepted invitation; returns sql.ErrNoRows otherwise
+	Revoke(ctx context.Context, id uuid.UUID) error
+
+	// Invite links
+	CreateLink(ctx context.Context, link *InviteLink) error
+	GetLinkByID(ctx context.Context, id uuid.UUID) (*InviteLink, error)
+	GetLinkByToken(ctx context.Context, token string) (*InviteLink, error)
+	GetEventLinks(ctx context.Context, eventID uuid.UUID) ([]InviteLink, error)
+	RevokeLink(ctx context.Context, id uuid.UUID) error
+	// RedeemLink uses up one redemption of a link and records an accepted invitation
+	// of the user from the link's creator. Returns ErrInviteLinkExhausted when the
+	// cap was reached concurrently, and ErrInvitationRevoked when the creator revoked
+	// the user's invitation (the link isn't used up then).
+	RedeemLink(ctx context.Context, link *InviteLink, inviteeID uuid.UUID) (*Invitation, error)
+}
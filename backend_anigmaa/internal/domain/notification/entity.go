@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType represents the type of notification (mirrors the notification_type enum)
+type NotificationType string
+
+const (
+	TypeLikePost            NotificationType = "like_post"
+	TypeCommentPost         NotificationType = "comment_post"
+	TypeMention             NotificationType = "mention"
+	TypeFollow              NotificationType = "follow"
+	TypeEventInvitation     NotificationType = "event_invitation"
+	TypeEventReminder       NotificationType = "event_reminder"
+	TypeEventUpdate         NotificationType = "event_update"
+	TypeCommunityInvitation NotificationType = "community_invitation"
+	TypeCommunityPost       NotificationType = "community_post"
+	TypeSystem              NotificationType = "system"
+)
+
+// Notification represents an in-app notification delivered to a user
+type Notification struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	UserID    uuid.UUID        `json:"user_id" db:"user_id"`
+	ActorID   *uuid.UUID       `json:"actor_id,omitempty" db:"actor_id"`
+	Type      NotificationType `json:"type" db:"type"`
+	Title     string           `json:"title" db:"title"`
+	Message   *string          `json:"message,omitempty" db:"message"`
+	Link      *string          `json:"link,omitempty" db:"link"`
+	Metadata  json.RawMessage  `json:"metadata,omitempty" db:"metadata"`
+	IsRead    bool             `json:"is_read" db:"is_read"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// NotificationWithActor includes basic info about the user who triggered the notification
+type NotificationWithActor struct {
+	Notification
+	ActorName      *string `json:"actor_name,omitempty" db:"actor_name"`
+	ActorUsername  *string `json:"actor_username,omitempty" db:"actor_username"`
+	ActorAvatarURL *string `json:"actor_avatar_url,omitempty" db:"actor_avatar_url"`
+}
+
+// NotificationFilter represents filters for listing notifications.
+// BeforeCreatedAt/BeforeID form a keyset cursor: only notifications strictly
+// older than that position are returned.
+type NotificationFilter struct {
+	UnreadOnly      bool
+	BeforeCreatedAt *time.Time
+	BeforeID        *uuid.UUID
+	Limit           int
+}
+
+// UnreadCountResponse represents the unread notification count
+type UnreadCountResponse struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+// MarkAllReadResponse represents the result of marking all notifications as read
+type MarkAllReadResponse struct {
+	Updated int `json:"updated"`
+}
@@ -0,0 +1,22 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for notification data access
+type Repository interface {
+	// Notification CRUD
+	Create(ctx context.Context, notification *Notification) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Notification, error)
+
+	// Listing (newest first, keyset paginated)
+	ListByUser(ctx context.Context, userID uuid.UUID, filter *NotificationFilter) ([]NotificationWithActor, error)
+
+	// Read status
+	MarkAsRead(ctx context.Context, id, userID uuid.UUID) error
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+}
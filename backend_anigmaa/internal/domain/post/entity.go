@@ -131,6 +131,20 @@ type RepostRequest struct {
 	QuoteContent *string   `json:"quote_content,omitempty" binding:"omitempty,max=280"`
 }
 
+// TrendingHashtag is a hashtag ranked by how many authors used it recently
+type TrendingHashtag struct {
+	Tag          string    `json:"tag" db:"tag"`
+	PostsCount   int       `json:"posts_count" db:"posts_count"`
+	AuthorsCount int       `json:"authors_count" db:"authors_count"`
+	LastUsedAt   time.Time `json:"last_used_at" db:"last_used_at"`
+}
+
+// TimelineEntry is a post reference in a home timeline, ordered by post creation time
+type TimelineEntry struct {
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
 // PostFilter represents post filtering options
 type PostFilter struct {
 	AuthorID   *uuid.UUID      `form:"author_id"`
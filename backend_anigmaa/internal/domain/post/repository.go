@@ -2,7 +2,9 @@ package post
 
 import (
 	"context"
+	"time"
 
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -11,18 +13,34 @@ type Repository interface {
 	// Post CRUD
 	Create(ctx context.Context, post *Post) error
 	GetByID(ctx context.Context, postID uuid.UUID) (*Post, error)
+	// GetVisible gets a post if the viewer may see it; returns sql.ErrNoRows otherwise
+	GetVisible(ctx context.Context, postID, viewerID uuid.UUID) (*Post, error)
 	GetWithDetails(ctx context.Context, postID, userID uuid.UUID) (*PostWithDetails, error)
 	Update(ctx context.Context, post *Post) error
 	Delete(ctx context.Context, postID uuid.UUID) error
 
 	// Post queries
 	List(ctx context.Context, filter *PostFilter, userID uuid.UUID) ([]PostWithDetails, error)
-	GetFeed(ctx context.Context, userID uuid.UUID, limit, offset int) ([]PostWithDetails, error)
+	// GetPublicPosts gets the public posts, newest first, after the cursor position (nil for the first page)
+	GetPublicPosts(ctx context.Context, before *utils.Cursor, limit, offset int) ([]PostWithDetails, error)
 	GetUserPosts(ctx context.Context, authorID, viewerID uuid.UUID, limit, offset int) ([]PostWithDetails, error)
+	GetByIDsWithDetails(ctx context.Context, postIDs []uuid.UUID, viewerID uuid.UUID) ([]PostWithDetails, error)
+	GetTimelineEntries(ctx context.Context, viewerID uuid.UUID, authorIDs []uuid.UUID, before *TimelineEntry, limit int) ([]TimelineEntry, error)
+	// GetDiscoveryEntries lists, newest first, the most engaging recent public posts
+	// by authors the viewer doesn't follow, strictly older than before (nil for the newest)
+	GetDiscoveryEntries(ctx context.Context, viewerID uuid.UUID, before *TimelineEntry, limit int) ([]TimelineEntry, error)
 
 	// Counting for pagination
-	CountFeed(ctx context.Context, userID uuid.UUID) (int, error)
-	CountUserPosts(ctx context.Context, authorID uuid.UUID) (int, error)
+	CountPublicPosts(ctx context.Context) (int, error)
+	CountUserPosts(ctx context.Context, authorID, viewerID uuid.UUID) (int, error)
+
+	// Hashtags and mentions
+	// SetTags replaces the hashtags and mentioned users of a post
+	SetTags(ctx context.Context, postID uuid.UUID, hashtags []string, mentionedIDs []uuid.UUID) error
+	GetMentionedUserIDs(ctx context.Context, postID uuid.UUID) ([]uuid.UUID, error)
+	GetHashtagPosts(ctx context.Context, tag string, viewerID uuid.UUID, before *utils.Cursor, limit, offset int) ([]PostWithDetails, error)
+	CountHashtagPosts(ctx context.Context, tag string, viewerID uuid.UUID) (int, error)
+	GetTrendingHashtags(ctx context.Context, since time.Time, limit int) ([]TrendingHashtag, error)
 
 	// Image management
 	AddImages(ctx context.Context, images []PostImage) error
@@ -37,3 +55,19 @@ type Repository interface {
 	DecrementReposts(ctx context.Context, postID uuid.UUID) error
 	IncrementShares(ctx context.Context, postID uuid.UUID) error
 }
+
+// TimelineRepository stores precomputed home timelines: per-user lists of
+// post references, newest first
+type TimelineRepository interface {
+	// Add pushes a post to the timelines of the given users that are currently cached
+	Add(ctx context.Context, userIDs []uuid.UUID, entry TimelineEntry) error
+	Remove(ctx context.Context, userIDs []uuid.UUID, postID uuid.UUID) error
+	// Get returns up to limit entries strictly older than before (nil for the newest).
+	// found is false when the user has no cached timeline.
+	Get(ctx context.Context, userID uuid.UUID, before *TimelineEntry, limit int) (entries []TimelineEntry, found bool, err error)
+	// Seed replaces a user's cached timeline, e.g. after it expired
+	Seed(ctx context.Context, userID uuid.UUID, entries []TimelineEntry) error
+	// Invalidate drops a user's cached timeline so the next read rebuilds it,
+	// e.g. after they follow or unfollow someone
+	Invalidate(ctx context.Context, userID uuid.UUID) error
+}
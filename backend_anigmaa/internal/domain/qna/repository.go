@@ -3,6 +3,7 @@ package qna
 import (
 	"context"
 
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -17,8 +18,8 @@ type Repository interface {
 	// GetByIDWithDetails retrieves a Q&A by ID with user details
 	GetByIDWithDetails(ctx context.Context, id, userID uuid.UUID) (*QnAWithDetails, error)
 
-	// GetByEvent retrieves all Q&A for an event
-	GetByEvent(ctx context.Context, eventID, userID uuid.UUID, limit, offset int) ([]QnAWithDetails, error)
+	// GetByEvent retrieves Q&A for an event, most upvoted first; before is a scored cursor (upvotes) or nil
+	GetByEvent(ctx context.Context, eventID, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]QnAWithDetails, error)
 
 	// Update updates a Q&A (for answering)
 	Update(ctx context.Context, qna *QnA) error
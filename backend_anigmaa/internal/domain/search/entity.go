@@ -0,0 +1,50 @@
+package search
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResultType represents the kind of item a search result points to
+type ResultType string
+
+const (
+	TypeEvent     ResultType = "event"
+	TypePost      ResultType = "post"
+	TypeCommunity ResultType = "community"
+	TypeUser      ResultType = "user"
+)
+
+// AllTypes lists every searchable result type
+var AllTypes = []ResultType{TypeEvent, TypePost, TypeCommunity, TypeUser}
+
+// IsValid reports whether t is a known result type
+func (t ResultType) IsValid() bool {
+	switch t {
+	case TypeEvent, TypePost, TypeCommunity, TypeUser:
+		return true
+	}
+	return false
+}
+
+// Result represents a single search hit
+type Result struct {
+	Type  ResultType `json:"type" db:"type"`
+	ID    uuid.UUID  `json:"id" db:"id"`
+	Title string     `json:"title" db:"title"` // Event title, community or user name, post author name
+	// Snippet is an excerpt of the matched text with matches wrapped in <b></b>
+	Snippet   string    `json:"snippet" db:"snippet"`
+	ImageURL  *string   `json:"image_url,omitempty" db:"image_url"`
+	Rank      float64   `json:"rank" db:"rank"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Query represents a search request
+type Query struct {
+	Text     string
+	Types    []ResultType
+	ViewerID uuid.UUID // uuid.Nil for anonymous searches
+	Limit    int
+	Offset   int
+}
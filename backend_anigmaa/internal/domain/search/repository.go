@@ -0,0 +1,10 @@
+package search
+
+import "context"
+
+// Repository defines the interface for full-text search
+type Repository interface {
+	// Search returns the items matching the query across the requested types,
+	// best match first
+	Search(ctx context.Context, query *Query) ([]Result, error)
+}
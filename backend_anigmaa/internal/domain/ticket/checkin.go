@@ -0,0 +1,178 @@
+package ticket
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckInOutcome is the result of a check-in scan
+type CheckInOutcome string
+
+const (
+	CheckInAdmitted  CheckInOutcome = "checked_in" // First scan of the ticket; let them in
+	CheckInDuplicate CheckInOutcome = "duplicate"  // The ticket was already checked in
+	CheckInRejected  CheckInOutcome = "rejected"   // A synced offline scan that wasn't valid; never logged
+)
+
+// CheckInScan is a logged check-in scan. The ID is chosen by the scanning device,
+// so a retried request gets the outcome of the original scan.
+type CheckInScan struct {
+	ID           uuid.UUID      `json:"id" db:"id"`
+	TicketID     uuid.UUID      `json:"ticket_id" db:"ticket_id"`
+	EventID      uuid.UUID      `json:"event_id" db:"event_id"`
+	Gate         string         `json:"gate" db:"gate"`
+	ScannedBy    uuid.UUID      `json:"scanned_by" db:"scanned_by"`                   // Staff member, or the issuer of the kiosk token
+	KioskTokenID *uuid.UUID     `json:"kiosk_token_id,omitempty" db:"kiosk_token_id"` // Set for scans made in kiosk mode
+	Outcome      CheckInOutcome `json:"outcome" db:"outcome"`
+	ScannedAt    time.Time      `json:"scanned_at" db:"scanned_at"`
+}
+
+// CheckInResult is what a scanner shows after a scan
+type CheckInResult struct {
+	ScanID    uuid.UUID          `json:"scan_id"`
+	Outcome   CheckInOutcome     `json:"outcome"`
+	Replayed  bool               `json:"replayed"` // The scan was already processed; this is its original outcome
+	Gate      string             `json:"gate"`
+	ScannedAt time.Time          `json:"scanned_at"`
+	Ticket    *TicketWithDetails `json:"ticket"`
+	// Where and when a duplicate's ticket was let in
+	FirstCheckedInAt *time.Time    `json:"first_checked_in_at,omitempty"`
+	FirstGate        *string       `json:"first_gate,omitempty"`
+	Stats            *CheckInStats `json:"stats,omitempty"`
+}
+
+// GateStats counts the scans at one gate of an event. Scans made before gates
+// existed count towards the gate named "".
+type GateStats struct {
+	Gate       string     `json:"gate" db:"gate"`
+	CheckedIn  int        `json:"checked_in" db:"checked_in"`
+	Duplicates int        `json:"duplicates" db:"duplicates"`
+	LastScanAt *time.Time `json:"last_scan_at,omitempty" db:"last_scan_at"`
+}
+
+// CheckInStats are the live check-in counts of an event
+type CheckInStats struct {
+	EventID   uuid.UUID   `json:"event_id"`
+	Total     int         `json:"total"`      // Tickets that can be checked in, including the ones that were
+	CheckedIn int         `json:"checked_in"` // Tickets checked in at any gate
+	Gates     []GateStats `json:"gates"`
+}
+
+// KioskToken lets a device check tickets in for one event at one gate, without
+// a user session. The token itself is only shown when it's issued.
+type KioskToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	EventID    uuid.UUID  `json:"event_id" db:"event_id"`
+	IssuedBy   uuid.UUID  `json:"issued_by" db:"issued_by"`
+	Gate       string     `json:"gate" db:"gate"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActive reports whether the token can still be used at now
+func (k *KioskToken) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// KioskTokenResponse is a newly issued kiosk token, the only time the token is shown
+type KioskTokenResponse struct {
+	KioskToken
+	Token string `json:"token"`
+}
+
+// KioskSession describes what a kiosk token is for, shown on the kiosk's screen
+type KioskSession struct {
+	EventID    uuid.UUID     `json:"event_id"`
+	EventTitle string        `json:"event_title"`
+	Gate       string        `json:"gate"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	Stats      *CheckInStats `json:"stats"`
+}
+
+// CreateKioskTokenRequest represents a kiosk token to issue for a gate of an event
+type CreateKioskTokenRequest struct {
+	Gate      string     `json:"gate" binding:"required,min=1,max=50"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Defaults to a while after the event ends
+}
+
+// KioskCheckInRequest represents a ticket scanned by a kiosk: its signed QR or its attendance code
+type KioskCheckInRequest struct {
+	ScanID         *uuid.UUID `json:"scan_id,omitempty"` // Generated by the kiosk per scan; a retry sends the same one
+	QRPayload      string     `json:"qr_payload,omitempty" binding:"required_without=AttendanceCode"`
+	AttendanceCode string     `json:"attendance_code,omitempty" binding:"required_without=QRPayload,omitempty,len=8"`
+}
+
+// CheckInManifest lists an event's tickets so scanners can check people in
+// while offline. Attendance codes are only included as hashes: the hex SHA-256
+// of "<event_id>:<attendance_code>".
+type CheckInManifest struct {
+	EventID     uuid.UUID        `json:"event_id"`
+	EventTitle  string           `json:"event_title"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	ExpiresAt   time.Time        `json:"expires_at"` // Check-in closes; the manifest must not be used after
+	Tickets     []ManifestTicket `json:"tickets"`
+}
+
+// ManifestTicket is a ticket as an offline scanner needs it
+type ManifestTicket struct {
+	TicketID           uuid.UUID    `json:"ticket_id"`
+	AttendanceCodeHash string       `json:"attendance_code_hash"`
+	Status             TicketStatus `json:"status"` // Only active tickets may be let in
+	IsCheckedIn        bool         `json:"is_checked_in"`
+	CheckedInAt        *time.Time   `json:"checked_in_at,omitempty"`
+	HolderName         string       `json:"holder_name"`
+	TierName           *string      `json:"tier_name,omitempty"`
+}
+
+// SignedCheckInManifest is a check-in manifest signed with the ticket QR key.
+// Scanners verify it with the key from GET /tickets/qr-public-key before trusting it.
+type SignedCheckInManifest struct {
+	// m1.<base64url(CheckInManifest JSON)>.<base64url(Ed25519 signature of the part before the last dot)>
+	Manifest    string    `json:"manifest"`
+	Algorithm   string    `json:"algorithm"`
+	TicketCount int       `json:"ticket_count"`
+	GeneratedAt time.Time `json:"generated_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// OfflineScan is a scan a scanner made while offline, uploaded once it's back online.
+// Scans are accepted from 12h before the event starts until the manifest expires.
+type OfflineScan struct {
+	ScanID    uuid.UUID `json:"scan_id" binding:"required"`
+	TicketID  uuid.UUID `json:"ticket_id" binding:"required"`
+	ScannedAt time.Time `json:"scanned_at" binding:"required"`
+	Gate      string    `json:"gate,omitempty" binding:"omitempty,max=50"` // Kiosks always scan at their token's gate
+}
+
+// SyncCheckInsRequest represents a batch of offline scans to replay
+type SyncCheckInsRequest struct {
+	Scans []OfflineScan `json:"scans" binding:"required,min=1,max=500,dive"`
+}
+
+// SyncedScan is the outcome of a replayed offline scan
+type SyncedScan struct {
+	ScanID   uuid.UUID      `json:"scan_id"`
+	TicketID uuid.UUID      `json:"ticket_id"`
+	Outcome  CheckInOutcome `json:"outcome"`
+	Replayed bool           `json:"replayed"` // The scan was already synced; this is its original outcome
+	// Conflict means another scanner let the same ticket in too: either this scan
+	// is a duplicate, or it was earlier than the check-in it took over
+	Conflict         bool       `json:"conflict"`
+	Error            string     `json:"error,omitempty"` // Why the scan was rejected
+	ScannedAt        time.Time  `json:"scanned_at"`
+	FirstCheckedInAt *time.Time `json:"first_checked_in_at,omitempty"`
+	FirstGate        *string    `json:"first_gate,omitempty"`
+}
+
+// SyncCheckInsResponse reports the outcome of every uploaded scan, in upload order
+type SyncCheckInsResponse struct {
+	Scans      []SyncedScan  `json:"scans"`
+	CheckedIn  int           `json:"checked_in"`
+	Duplicates int           `json:"duplicates"`
+	Rejected   int           `json:"rejected"`
+	Stats      *CheckInStats `json:"stats,omitempty"`
+}
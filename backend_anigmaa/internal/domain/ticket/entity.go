@@ -2,6 +2,7 @@ package ticket
 
 import (
 	"errors"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,10 +12,35 @@ import (
 // Defined here so both the repository and usecase layers share the same sentinel.
 var ErrEventFull = errors.New("event is full")
 
+// ErrAlreadyPurchased is returned by AtomicPurchase when the buyer already holds
+// a ticket for an event without tiers.
+var ErrAlreadyPurchased = errors.New("already purchased ticket for this event")
+
+// ErrTierSoldOut is returned by AtomicPurchase when the ticket tier's quota is used up.
+var ErrTierSoldOut = errors.New("ticket tier is sold out")
+
+// ErrTierLimitReached is returned by AtomicPurchase when the buyer already holds
+// the maximum number of tickets allowed per user for the tier.
+var ErrTierLimitReached = errors.New("ticket limit per user reached for this tier")
+
+// ErrPromoCodeExhausted is returned by AtomicPurchase when a promo code has reached its usage cap.
+var ErrPromoCodeExhausted = errors.New("promo code has been fully used")
+
+// ErrPromoCodeLimitReached is returned by AtomicPurchase when the buyer has
+// used a promo code the maximum number of times allowed per user.
+var ErrPromoCodeLimitReached = errors.New("promo code limit per user reached")
+
+// ErrAlreadyWaitlisted is returned by JoinWaitlist when the user is already in line for the event.
+var ErrAlreadyWaitlisted = errors.New("already on the waitlist for this event")
+
 // ErrAlreadyProcessed is returned by UpdateTransactionStatus when the DB row
 // was already in a terminal state (idempotency guard at the database level).
 var ErrAlreadyProcessed = errors.New("transaction already processed")
 
+// ErrTicketNotActive is returned by RecordCheckInScan when the ticket was
+// cancelled or refunded before its row was locked.
+var ErrTicketNotActive = errors.New("ticket is not active")
+
 // TransactionStatus represents the status of a payment transaction
 type TransactionStatus string
 
@@ -26,6 +52,33 @@ const (
 	TransactionExpired   TransactionStatus = "expired"
 )
 
+// RefundStatus represents the status of a provider refund
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "pending"
+	RefundSucceeded RefundStatus = "succeeded"
+	RefundFailed    RefundStatus = "failed"
+)
+
+// Discrepancy classifies a mismatch found by payment reconciliation
+type Discrepancy string
+
+const (
+	DiscrepancyMissedWebhook   Discrepancy = "missed_webhook"    // Provider outcome never reached us; applied by reconciliation
+	DiscrepancyPaidAfterExpiry Discrepancy = "paid_after_expiry" // Buyer paid after the pending ticket expired; needs a refund
+	DiscrepancyAmountMismatch  Discrepancy = "amount_mismatch"   // Provider amount differs from the stored amount; not applied
+	DiscrepancyApplyFailed     Discrepancy = "apply_failed"      // Provider outcome could not be applied
+)
+
+// DiscountType represents how a promo code discount is calculated
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage" // discount_value is a percentage of the price
+	DiscountFixed      DiscountType = "fixed"      // discount_value is an amount in IDR
+)
+
 // TicketStatus represents the status of a ticket
 type TicketStatus string
 
@@ -37,6 +90,17 @@ const (
 	StatusExpired   TicketStatus = "expired"   // Ticket expired
 )
 
+// WaitlistStatus represents the status of a waitlist entry
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting WaitlistStatus = "waiting" // In line for a spot
+	WaitlistOffered WaitlistStatus = "offered" // Holds a time-boxed claim on a freed spot
+	WaitlistClaimed WaitlistStatus = "claimed" // Got a ticket
+	WaitlistExpired WaitlistStatus = "expired" // Let the claim lapse
+	WaitlistLeft    WaitlistStatus = "left"    // Left the waitlist
+)
+
 // Ticket represents an event ticket
 type Ticket struct {
 	ID             uuid.UUID    `json:"id" db:"id"`
@@ -48,6 +112,44 @@ type Ticket struct {
 	IsCheckedIn    bool         `json:"is_checked_in" db:"is_checked_in"`
 	CheckedInAt    *time.Time   `json:"checked_in_at,omitempty" db:"checked_in_at"`
 	Status         TicketStatus `json:"status" db:"status"`
+	TierID         *uuid.UUID   `json:"tier_id,omitempty" db:"tier_id"` // Nil for events without tiers
+	PromoCodeID    *uuid.UUID   `json:"promo_code_id,omitempty" db:"promo_code_id"`
+	DiscountAmount float64      `json:"discount_amount" db:"discount_amount"` // Already deducted from PricePaid
+}
+
+// PromoCode is a host-managed discount code for an event's tickets
+type PromoCode struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	EventID        uuid.UUID    `json:"event_id" db:"event_id"`
+	TierID         *uuid.UUID   `json:"tier_id,omitempty" db:"tier_id"` // Nil = valid for any tier
+	Code           string       `json:"code" db:"code"`
+	DiscountType   DiscountType `json:"discount_type" db:"discount_type"`
+	DiscountValue  float64      `json:"discount_value" db:"discount_value"`
+	MaxUses        *int         `json:"max_uses,omitempty" db:"max_uses"` // Nil = unlimited
+	MaxUsesPerUser int          `json:"max_uses_per_user" db:"max_uses_per_user"`
+	UsedCount      int          `json:"used_count" db:"used_count"` // Pending + active tickets using the code
+	ValidFrom      *time.Time   `json:"valid_from,omitempty" db:"valid_from"`
+	ValidUntil     *time.Time   `json:"valid_until,omitempty" db:"valid_until"`
+	IsActive       bool         `json:"is_active" db:"is_active"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Tier is a ticket type of an event with its own price, quota, sale window and per-user limit
+type Tier struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	EventID     uuid.UUID  `json:"event_id" db:"event_id"`
+	Name        string     `json:"name" db:"name"`
+	Description *string    `json:"description,omitempty" db:"description"`
+	Price       float64    `json:"price" db:"price"`
+	Quota       int        `json:"quota" db:"quota"`
+	Sold        int        `json:"sold" db:"sold"`
+	SalesStart  *time.Time `json:"sales_start,omitempty" db:"sales_start"`
+	SalesEnd    *time.Time `json:"sales_end,omitempty" db:"sales_end"`
+	MaxPerUser  int        `json:"max_per_user" db:"max_per_user"`
+	SortOrder   int        `json:"sort_order" db:"sort_order"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // Order represents a payment transaction for tickets
@@ -68,7 +170,9 @@ type TicketWithDetails struct {
 	Ticket
 	EventTitle     string    `json:"event_title" db:"event_title"`
 	EventStartTime time.Time `json:"event_start_time" db:"event_start_time"`
+	EventEndTime   time.Time `json:"event_end_time" db:"event_end_time"`
 	EventLocation  string    `json:"event_location" db:"event_location"`
+	TierName       *string   `json:"tier_name,omitempty" db:"tier_name"`
 	UserName       string    `json:"user_name" db:"user_name"`
 	UserEmail      string    `json:"user_email" db:"user_email"`
 	UserAvatarURL  *string   `json:"user_avatar_url,omitempty" db:"user_avatar_url"`
@@ -77,14 +181,93 @@ type TicketWithDetails struct {
 
 // PurchaseTicketRequest represents ticket purchase data
 type PurchaseTicketRequest struct {
-	EventID       uuid.UUID `json:"event_id" binding:"required"`
-	PaymentMethod *string   `json:"payment_method,omitempty"` // null for free events
+	EventID       uuid.UUID  `json:"event_id" binding:"required"`
+	TierID        *uuid.UUID `json:"tier_id,omitempty"`        // Required when the event has ticket tiers
+	PromoCode     *string    `json:"promo_code,omitempty"`     // Optional discount code
+	PaymentMethod *string    `json:"payment_method,omitempty"` // null for free events
+}
+
+// CreatePromoCodeRequest represents the request to create a promo code for an event
+type CreatePromoCodeRequest struct {
+	Code           string       `json:"code" binding:"required,min=3,max=50"`
+	DiscountType   DiscountType `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	DiscountValue  float64      `json:"discount_value" binding:"required,gt=0"`
+	TierID         *uuid.UUID   `json:"tier_id,omitempty"`
+	MaxUses        *int         `json:"max_uses,omitempty" binding:"omitempty,gt=0"`
+	MaxUsesPerUser int          `json:"max_uses_per_user" binding:"omitempty,gt=0"` // Defaults to 1
+	ValidFrom      *time.Time   `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time   `json:"valid_until,omitempty"`
+}
+
+// UpdatePromoCodeRequest represents the request to update a promo code
+type UpdatePromoCodeRequest struct {
+	DiscountType   *DiscountType `json:"discount_type,omitempty" binding:"omitempty,oneof=percentage fixed"`
+	DiscountValue  *float64      `json:"discount_value,omitempty" binding:"omitempty,gt=0"`
+	MaxUses        *int          `json:"max_uses,omitempty" binding:"omitempty,gt=0"`
+	MaxUsesPerUser *int          `json:"max_uses_per_user,omitempty" binding:"omitempty,gt=0"`
+	ValidFrom      *time.Time    `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time    `json:"valid_until,omitempty"`
+	IsActive       *bool         `json:"is_active,omitempty"`
+}
+
+// ApplyPromoCodeRequest represents the request to preview a promo code before purchase
+type ApplyPromoCodeRequest struct {
+	Code   string     `json:"code" binding:"required"`
+	TierID *uuid.UUID `json:"tier_id,omitempty"`
+}
+
+// PromoCodeQuote is the price of a ticket after applying a promo code
+type PromoCodeQuote struct {
+	Code           string  `json:"code"`
+	OriginalPrice  float64 `json:"original_price"`
+	DiscountAmount float64 `json:"discount_amount"`
+	FinalPrice     float64 `json:"final_price"`
+}
+
+// CreateTierRequest represents the request to add a ticket tier to an event
+type CreateTierRequest struct {
+	Name        string     `json:"name" binding:"required,min=1,max=100"`
+	Description *string    `json:"description,omitempty"`
+	Price       float64    `json:"price" binding:"gte=0"`
+	Quota       int        `json:"quota" binding:"required,gt=0"`
+	SalesStart  *time.Time `json:"sales_start,omitempty"`
+	SalesEnd    *time.Time `json:"sales_end,omitempty"`
+	MaxPerUser  int        `json:"max_per_user" binding:"omitempty,gt=0"` // Defaults to 1
+	SortOrder   int        `json:"sort_order"`
+}
+
+// UpdateTierRequest represents the request to update a ticket tier
+type UpdateTierRequest struct {
+	Name        *string    `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Description *string    `json:"description,omitempty"`
+	Price       *float64   `json:"price,omitempty" binding:"omitempty,gte=0"`
+	Quota       *int       `json:"quota,omitempty" binding:"omitempty,gt=0"`
+	SalesStart  *time.Time `json:"sales_start,omitempty"`
+	SalesEnd    *time.Time `json:"sales_end,omitempty"`
+	MaxPerUser  *int       `json:"max_per_user,omitempty" binding:"omitempty,gt=0"`
+	SortOrder   *int       `json:"sort_order,omitempty"`
 }
 
 // CheckInRequest represents check-in data
 type CheckInRequest struct {
-	EventID        string `json:"event_id" binding:"required,uuid"`
-	AttendanceCode string `json:"attendance_code" binding:"required,len=8"`
+	EventID        string     `json:"event_id" binding:"required,uuid"`
+	AttendanceCode string     `json:"attendance_code" binding:"required,len=8"`
+	ScanID         *uuid.UUID `json:"scan_id,omitempty"` // Generated by the scanner per scan; a retry sends the same one
+	Gate           string     `json:"gate,omitempty" binding:"omitempty,max=50"`
+}
+
+// QRCheckInRequest represents check-in data from a scanned signed ticket QR
+type QRCheckInRequest struct {
+	EventID   string     `json:"event_id" binding:"required,uuid"`
+	QRPayload string     `json:"qr_payload" binding:"required"` // raw string read from the ticket QR
+	ScanID    *uuid.UUID `json:"scan_id,omitempty"`             // Generated by the scanner per scan; a retry sends the same one
+	Gate      string     `json:"gate,omitempty" binding:"omitempty,max=50"`
+}
+
+// QRPublicKeyResponse carries the key scanner apps use to verify ticket QRs offline
+type QRPublicKeyResponse struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"` // base64-encoded
 }
 
 // PurchaseTicketResponse represents the response after purchasing a ticket
@@ -95,6 +278,15 @@ type PurchaseTicketResponse struct {
 	QRCode       *string `json:"qr_code,omitempty"`       // Base64-encoded QR code PNG
 }
 
+// PaymentUpdate is pushed to the buyer's real-time stream when a payment settles or fails
+type PaymentUpdate struct {
+	TicketID          uuid.UUID         `json:"ticket_id"`
+	EventID           uuid.UUID         `json:"event_id"`
+	TransactionID     string            `json:"transaction_id"`
+	TransactionStatus TransactionStatus `json:"transaction_status"`
+	TicketStatus      TicketStatus      `json:"ticket_status"`
+}
+
 // TicketTransaction represents a payment transaction for a ticket
 type TicketTransaction struct {
 	ID             uuid.UUID        `json:"id" db:"id"`
@@ -107,6 +299,59 @@ type TicketTransaction struct {
 	CompletedAt    *time.Time       `json:"completed_at,omitempty" db:"completed_at"`
 }
 
+// Refund represents a refund of a ticket payment through the payment provider
+type Refund struct {
+	ID               uuid.UUID    `json:"id" db:"id"`
+	TicketID         uuid.UUID    `json:"ticket_id" db:"ticket_id"`
+	TransactionID    string       `json:"transaction_id" db:"transaction_id"` // Order ID of the original payment
+	RefundKey        string       `json:"refund_key" db:"refund_key"`
+	Amount           float64      `json:"amount" db:"amount"`
+	Reason           *string      `json:"reason,omitempty" db:"reason"`
+	Method           string       `json:"method" db:"method"` // refund or direct_refund
+	Status           RefundStatus `json:"status" db:"status"`
+	Partial          bool         `json:"partial" db:"partial"` // Part of the payment, refunded outside the app; the ticket stays active
+	ProviderRefundID *string      `json:"provider_refund_id,omitempty" db:"provider_refund_id"`
+	FailureReason    *string      `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at" db:"updated_at"`
+	CompletedAt      *time.Time   `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// ReconciliationEntry is a discrepancy between a local payment and the provider's record of it
+type ReconciliationEntry struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	TransactionID  string       `json:"transaction_id" db:"transaction_id"`
+	TicketID       uuid.UUID    `json:"ticket_id" db:"ticket_id"`
+	EventID        uuid.UUID    `json:"event_id" db:"event_id"`
+	Discrepancy    Discrepancy  `json:"discrepancy" db:"discrepancy"`
+	LocalStatus    TicketStatus `json:"local_status" db:"local_status"`
+	ProviderStatus string       `json:"provider_status" db:"provider_status"`
+	LocalAmount    float64      `json:"local_amount" db:"local_amount"`
+	ProviderAmount *string      `json:"provider_amount,omitempty" db:"provider_amount"`
+	Details        *string      `json:"details,omitempty" db:"details"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
+
+// WaitlistEntry is a user's place in the FIFO waitlist of a full event
+type WaitlistEntry struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	EventID        uuid.UUID      `json:"event_id" db:"event_id"`
+	UserID         uuid.UUID      `json:"user_id" db:"user_id"`
+	Status         WaitlistStatus `json:"status" db:"status"`
+	Position       int            `json:"position,omitempty" db:"position"` // 1-based place in line while waiting
+	JoinedAt       time.Time      `json:"joined_at" db:"joined_at"`
+	OfferedAt      *time.Time     `json:"offered_at,omitempty" db:"offered_at"`
+	OfferExpiresAt *time.Time     `json:"offer_expires_at,omitempty" db:"offer_expires_at"` // Deadline to buy the offered spot
+}
+
+// WaitlistEntryWithUser includes basic info about the waiting user
+type WaitlistEntryWithUser struct {
+	WaitlistEntry
+	UserName      string  `json:"user_name" db:"user_name"`
+	UserUsername  *string `json:"user_username,omitempty" db:"user_username"`
+	UserAvatarURL *string `json:"user_avatar_url,omitempty" db:"user_avatar_url"`
+}
+
 // Business logic methods
 func (t *Ticket) IsFree() bool {
 	return t.PricePaid == 0
@@ -116,6 +361,63 @@ func (t *Ticket) IsValid() bool {
 	return t.Status == StatusActive && !t.IsCheckedIn
 }
 
+// HoldsSpot reports whether the ticket takes up a spot, i.e. is paid or awaiting payment
+func (t *Ticket) HoldsSpot() bool {
+	return t.Status == StatusPending || t.Status == StatusActive
+}
+
 func (t *Ticket) CanBeRefunded() bool {
 	return t.Status == StatusActive && !t.IsCheckedIn
 }
+
+// HasOpenClaim reports whether the entry holds a spot the user can still buy at the given time
+func (w *WaitlistEntry) HasOpenClaim(now time.Time) bool {
+	return w.Status == WaitlistOffered && w.OfferExpiresAt != nil && now.Before(*w.OfferExpiresAt)
+}
+
+// IsOnSale reports whether the tier's sale window is open at the given time
+func (t *Tier) IsOnSale(now time.Time) bool {
+	if t.SalesStart != nil && now.Before(*t.SalesStart) {
+		return false
+	}
+	if t.SalesEnd != nil && !now.Before(*t.SalesEnd) {
+		return false
+	}
+	return true
+}
+
+func (t *Tier) IsSoldOut() bool {
+	return t.Sold >= t.Quota
+}
+
+// IsValidAt reports whether the promo code is active and inside its validity window
+func (p *PromoCode) IsValidAt(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.ValidFrom != nil && now.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && !now.Before(*p.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// AppliesTo reports whether the promo code can be used for a ticket of the given tier
+func (p *PromoCode) AppliesTo(tierID *uuid.UUID) bool {
+	return p.TierID == nil || (tierID != nil && *p.TierID == *tierID)
+}
+
+// DiscountFor returns the discount for a ticket price, rounded to whole rupiah
+// (Midtrans rejects fractional IDR amounts) and never more than the price.
+func (p *PromoCode) DiscountFor(price float64) float64 {
+	discount := p.DiscountValue
+	if p.DiscountType == DiscountPercentage {
+		discount = math.Round(price * p.DiscountValue / 100)
+	}
+	if discount > price {
+		return price
+	}
+	return discount
+}
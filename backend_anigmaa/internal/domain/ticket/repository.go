@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -11,15 +12,23 @@ import (
 type Repository interface {
 	// AtomicPurchase creates a ticket inside a DB transaction that:
 	//   1. Locks the event row (SELECT … FOR UPDATE)
-	//   2. Verifies capacity (returns ErrEventFull if sold out)
-	//   3. INSERTs the ticket
-	//   4. Atomically increments events.tickets_sold
+	//   2. Verifies capacity (returns ErrEventFull if sold out). Spots held by
+	//      other users' open waitlist claims count as taken.
+	//   3. For tier tickets, locks the tier row and verifies its quota and
+	//      per-user limit (returns ErrTierSoldOut / ErrTierLimitReached)
+	//   4. For discounted tickets, locks the promo code row and verifies its
+	//      usage caps (returns ErrPromoCodeExhausted / ErrPromoCodeLimitReached)
+	//   5. INSERTs the ticket (returns ErrAlreadyPurchased if the event has no
+	//      tiers and the buyer already holds a ticket for it)
+	//   6. Atomically increments events.tickets_sold (and ticket_tiers.sold)
+	//   7. Marks the buyer's waitlist entry of the event as claimed
 	// This prevents overselling under any concurrency level.
 	AtomicPurchase(ctx context.Context, ticket *Ticket) error
 
-	// DecrementTicketsSold decrements events.tickets_sold by 1 (floor 0).
+	// DecrementTicketsSold decrements events.tickets_sold by 1 (floor 0), and
+	// ticket_tiers.sold when tierID is set.
 	// Must be called whenever a pending ticket is cancelled or expired.
-	DecrementTicketsSold(ctx context.Context, eventID uuid.UUID) error
+	DecrementTicketsSold(ctx context.Context, eventID uuid.UUID, tierID *uuid.UUID) error
 
 	// ExpirePendingTickets sets status='expired' on all tickets whose status
 	// is still 'pending' and whose purchased_at is older than olderThan.
@@ -31,12 +40,23 @@ type Repository interface {
 	GetByID(ctx context.Context, ticketID uuid.UUID) (*Ticket, error)
 	GetWithDetails(ctx context.Context, ticketID uuid.UUID) (*TicketWithDetails, error)
 	Update(ctx context.Context, ticket *Ticket) error
+	// ClaimCancellation moves an active ticket that isn't checked in to cancelled.
+	// Returns sql.ErrNoRows if it isn't, so of concurrent cancellations of a
+	// ticket only one goes on to refund it and release its spot.
+	ClaimCancellation(ctx context.Context, ticketID uuid.UUID) error
+	// ReleaseCancellation moves a ticket claimed by ClaimCancellation back to active,
+	// when its refund failed
+	ReleaseCancellation(ctx context.Context, ticketID uuid.UUID) error
 	Delete(ctx context.Context, ticketID uuid.UUID) error
 
 	// Ticket queries
-	GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]TicketWithDetails, error)
+	// GetByUser lists a user's tickets newest first; before is a cursor on purchased_at or nil
+	GetByUser(ctx context.Context, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]TicketWithDetails, error)
 	GetByEvent(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]TicketWithDetails, error)
+	// GetManifestTickets gets all of an event's tickets in one query, oldest purchase first
+	GetManifestTickets(ctx context.Context, eventID uuid.UUID) ([]TicketWithDetails, error)
 	GetByAttendanceCode(ctx context.Context, code string) (*Ticket, error)
+	// GetUserTicketForEvent gets the latest of a user's tickets for an event
 	GetUserTicketForEvent(ctx context.Context, userID, eventID uuid.UUID) (*Ticket, error)
 
 	// Counting for pagination
@@ -44,14 +64,111 @@ type Repository interface {
 	CountEventTickets(ctx context.Context, eventID uuid.UUID) (int, error)
 
 	// Check-in
-	CheckIn(ctx context.Context, ticketID uuid.UUID) error
+	// CheckIn checks the ticket in as of at. If it was checked in later than at,
+	// e.g. by a scanner that was online while an offline one let the ticket in
+	// first, the check-in moves back to at. Returns sql.ErrNoRows if the ticket
+	// was already checked in at or before at.
+	CheckIn(ctx context.Context, ticketID uuid.UUID, at time.Time) error
 	GetCheckedInCount(ctx context.Context, eventID uuid.UUID) (int, error)
+	// RecordCheckInScan logs a scan of a ticket in one transaction, under a lock
+	// of the ticket row:
+	//   1. Checks the ticket in as of scan.ScannedAt (see CheckIn), or sets
+	//      scan.Outcome to duplicate if it was already checked in by then
+	//   2. When it checks the ticket in, relabels the ticket's admitting scans
+	//      after scan.ScannedAt as duplicates, as an earlier scan took over
+	//   3. Logs the scan
+	// Returns how many scans were relabelled. Returns false, and saves nothing, if
+	// a scan with the same ID was already logged; ErrTicketNotActive if the ticket
+	// isn't active, or sql.ErrNoRows if it doesn't exist.
+	RecordCheckInScan(ctx context.Context, scan *CheckInScan) (int, bool, error)
+	GetCheckInScan(ctx context.Context, scanID uuid.UUID) (*CheckInScan, error)
+	// GetAdmittingScan gets the scan that checked the ticket in, or sql.ErrNoRows
+	// if it was checked in before scans were logged
+	GetAdmittingScan(ctx context.Context, ticketID uuid.UUID) (*CheckInScan, error)
+	// GetCheckInStats counts the event's active and checked-in tickets, and the scans per gate
+	GetCheckInStats(ctx context.Context, eventID uuid.UUID) (*CheckInStats, error)
+
+	// Kiosk tokens
+	CreateKioskToken(ctx context.Context, token *KioskToken) error
+	GetKioskTokenByID(ctx context.Context, tokenID uuid.UUID) (*KioskToken, error)
+	GetKioskTokenByHash(ctx context.Context, tokenHash string) (*KioskToken, error)
+	// GetEventKioskTokens lists an event's kiosk tokens newest first; a nil issuedBy lists everyone's
+	GetEventKioskTokens(ctx context.Context, eventID uuid.UUID, issuedBy *uuid.UUID) ([]KioskToken, error)
+	// RevokeKioskToken returns sql.ErrNoRows if the token doesn't exist or was already revoked
+	RevokeKioskToken(ctx context.Context, tokenID uuid.UUID) error
+	TouchKioskToken(ctx context.Context, tokenID uuid.UUID) error
 
 	// Transaction
 	CreateTransaction(ctx context.Context, transaction *TicketTransaction) error
 	GetTransaction(ctx context.Context, transactionID string) (*TicketTransaction, error)
 	UpdateTransactionStatus(ctx context.Context, transactionID string, status TransactionStatus) error
 
+	// MarkTransactionRefunded moves a successful payment to 'refunded'.
+	// Returns ErrAlreadyProcessed if the transaction is not in 'success'.
+	MarkTransactionRefunded(ctx context.Context, transactionID string) error
+
+	// Refunds
+	CreateRefund(ctx context.Context, refund *Refund) error
+	GetRefundByKey(ctx context.Context, refundKey string) (*Refund, error)
+	GetRefundsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]Refund, error)
+	GetRefundsByTransactionID(ctx context.Context, transactionID string) ([]Refund, error)
+
+	// UpdateRefundStatus moves a refund to a new status. A succeeded refund is
+	// final: updating it returns ErrAlreadyProcessed, so webhooks are idempotent.
+	UpdateRefundStatus(ctx context.Context, refundKey string, status RefundStatus, providerRefundID, failureReason *string) error
+	// UpdateRefundAttempt records the provider's answer to a refund request: its
+	// method, status, provider refund ID and failure reason. Returns
+	// ErrAlreadyProcessed if the refund already succeeded, e.g. by webhook.
+	UpdateRefundAttempt(ctx context.Context, refund *Refund) error
+
+	// Ticket tiers
+	CreateTier(ctx context.Context, tier *Tier) error
+	GetTierByID(ctx context.Context, tierID uuid.UUID) (*Tier, error)
+	GetTiersByEvent(ctx context.Context, eventID uuid.UUID) ([]Tier, error)
+	UpdateTier(ctx context.Context, tier *Tier) error
+	DeleteTier(ctx context.Context, tierID uuid.UUID) error
+
+	// Promo codes
+	CreatePromoCode(ctx context.Context, promo *PromoCode) error
+	GetPromoCodeByID(ctx context.Context, promoID uuid.UUID) (*PromoCode, error)
+	GetPromoCodeByCode(ctx context.Context, eventID uuid.UUID, code string) (*PromoCode, error)
+	GetPromoCodesByEvent(ctx context.Context, eventID uuid.UUID) ([]PromoCode, error)
+	UpdatePromoCode(ctx context.Context, promo *PromoCode) error
+	DeletePromoCode(ctx context.Context, promoID uuid.UUID) error
+	// CountUserPromoCodeUses counts the user's pending and active tickets that used the promo code
+	CountUserPromoCodeUses(ctx context.Context, promoID, userID uuid.UUID) (int, error)
+
+	// Payment reconciliation
+	// ClaimPendingTransactions returns up to limit pending transactions created
+	// between createdAfter and createdBefore, least recently checked first, and
+	// marks them checked now
+	ClaimPendingTransactions(ctx context.Context, createdAfter, createdBefore time.Time, limit int) ([]TicketTransaction, error)
+	// CreateReconciliationEntry records a discrepancy; an entry with the same
+	// transaction and discrepancy is only recorded once.
+	CreateReconciliationEntry(ctx context.Context, entry *ReconciliationEntry) error
+	// GetReconciliationEntries lists entries newest first; a nil eventID lists all events
+	GetReconciliationEntries(ctx context.Context, eventID *uuid.UUID, limit, offset int) ([]ReconciliationEntry, error)
+	CountReconciliationEntries(ctx context.Context, eventID *uuid.UUID) (int, error)
+
+	// Waitlist
+	// JoinWaitlist puts the user at the end of the event's waitlist.
+	// Returns ErrAlreadyWaitlisted if the user is already waiting or holds a claim.
+	JoinWaitlist(ctx context.Context, entry *WaitlistEntry) error
+	// GetWaitlistEntry gets the user's waiting or offered entry of the event, with its position
+	GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*WaitlistEntry, error)
+	// LeaveWaitlist removes the user from the event's waitlist, giving up any open claim.
+	// Returns the entry as it was, or sql.ErrNoRows if the user wasn't in line.
+	LeaveWaitlist(ctx context.Context, eventID, userID uuid.UUID) (*WaitlistEntry, error)
+	// GetEventWaitlist lists the waiting and offered entries of an event in queue order
+	GetEventWaitlist(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]WaitlistEntryWithUser, error)
+	CountEventWaitlist(ctx context.Context, eventID uuid.UUID) (int, error)
+	// OfferWaitlistSpots locks the event row, expires its lapsed claims and offers
+	// every spot not taken by a ticket or an open claim to the next waiting users,
+	// oldest first. Returns the entries that received an offer.
+	OfferWaitlistSpots(ctx context.Context, eventID uuid.UUID, claimWindow time.Duration) ([]WaitlistEntry, error)
+	// GetWaitlistedEventIDs returns upcoming events with users waiting or claims that lapsed
+	GetWaitlistedEventIDs(ctx context.Context) ([]uuid.UUID, error)
+
 	// Analytics - get tickets and transactions for analytics
 	GetByEventID(ctx context.Context, eventID uuid.UUID) ([]Ticket, error)
 	GetTransactionsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]TicketTransaction, error)
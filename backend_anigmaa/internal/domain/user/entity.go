@@ -62,6 +62,12 @@ type User struct {
 	IsEmailVerified bool       `json:"is_email_verified" db:"is_email_verified"`
 }
 
+// Follower is a user in a followers list, with the time they started following
+type Follower struct {
+	User
+	FollowedAt time.Time `json:"followed_at" db:"followed_at"`
+}
+
 // UserSettings contains user preferences
 type UserSettings struct {
 	UserID             uuid.UUID `json:"user_id" db:"user_id"`
@@ -73,6 +79,16 @@ type UserSettings struct {
 	ShowOnlineStatus   bool      `json:"show_online_status" db:"show_online_status"`
 }
 
+// EmailRecipient is a user with the settings that decide the emails sent to them.
+// Users who never saved settings get the defaults.
+type EmailRecipient struct {
+	ID                 uuid.UUID `db:"id"`
+	Name               string    `db:"name"`
+	Email              string    `db:"email"`
+	EmailNotifications bool      `db:"email_notifications"`
+	Language           string    `db:"language"`
+}
+
 // UserStats contains user statistics
 type UserStats struct {
 	UserID                 uuid.UUID `json:"user_id" db:"user_id"`
@@ -168,7 +184,8 @@ type UpdateSettingsRequest struct {
 
 // GoogleAuthRequest represents Google authentication data
 type GoogleAuthRequest struct {
-	IDToken string `json:"idToken" binding:"required"`
+	IDToken    string `json:"idToken" binding:"required"`
+	DeviceName string `json:"deviceName,omitempty"` // Shown in the active sessions list, e.g. "Pixel 8"
 }
 
 // ProfileResponse represents public profile data for API response
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -19,15 +20,22 @@ type Repository interface {
 	// User Profile
 	GetProfile(ctx context.Context, userID uuid.UUID) (*UserProfile, error)
 	GetProfileByUsername(ctx context.Context, username string) (*UserProfile, error)
+	// GetSettings gets a user's settings; sql.ErrNoRows if they never saved any
+	GetSettings(ctx context.Context, userID uuid.UUID) (*UserSettings, error)
+	// GetEmailRecipients gets the given users with their email settings
+	GetEmailRecipients(ctx context.Context, userIDs []uuid.UUID) ([]EmailRecipient, error)
 	UpdateSettings(ctx context.Context, settings *UserSettings) error
 	UpdatePrivacy(ctx context.Context, privacy *UserPrivacy) error
 
 	// Follow system
 	Follow(ctx context.Context, followerID, followingID uuid.UUID) error
 	Unfollow(ctx context.Context, followerID, followingID uuid.UUID) error
-	GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]User, error)
+	// GetFollowers lists followers, most recent first; before is a cursor on the follow time or nil
+	GetFollowers(ctx context.Context, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]Follower, error)
 	GetFollowing(ctx context.Context, userID uuid.UUID, limit, offset int) ([]User, error)
 	IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error)
+	GetFollowerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	GetFollowingIDs(ctx context.Context, userID uuid.UUID, minFollowers int) ([]uuid.UUID, error)
 
 	// Counting for pagination
 	CountFollowers(ctx context.Context, userID uuid.UUID) (int, error)
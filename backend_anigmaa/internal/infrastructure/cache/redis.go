@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/anigmaa/backend/config"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -71,6 +72,41 @@ func (r *RedisClient) Decrement(ctx context.Context, key string) (int64, error)
 	return r.client.Decr(ctx, key).Result()
 }
 
+// Publish publishes a message to a pub/sub channel
+func (r *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// PSubscribe subscribes to every pub/sub channel matching the given patterns
+func (r *RedisClient) PSubscribe(ctx context.Context, patterns ...string) *redis.PubSub {
+	return r.client.PSubscribe(ctx, patterns...)
+}
+
+// releaseLockScript deletes a lock only if it still holds the caller's token,
+// so a lock that expired and was taken by someone else isn't released
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// AcquireLock takes a lock shared by every server instance for at most ttl.
+// Returns the token to release it with, or ok false if it's already held.
+func (r *RedisClient) AcquireLock(ctx context.Context, key string, ttl time.Duration) (token string, ok bool, err error) {
+	token = uuid.NewString()
+	ok, err = r.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// ReleaseLock releases a lock taken with AcquireLock
+func (r *RedisClient) ReleaseLock(ctx context.Context, key, token string) error {
+	return releaseLockScript.Run(ctx, r.client, []string{key}, token).Err()
+}
+
 // Close closes the Redis connection
 func (r *RedisClient) Close() error {
 	if r.client != nil {
@@ -0,0 +1,253 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anigmaa/backend/config"
+	"github.com/google/uuid"
+)
+
+// FakeOutcome is the result a FakeGateway simulates for a charge
+type FakeOutcome string
+
+const (
+	FakeOutcomeSettlement FakeOutcome = "settlement" // Buyer paid
+	FakeOutcomeExpire     FakeOutcome = "expire"     // Buyer never paid
+	FakeOutcomeFraud      FakeOutcome = "fraud"      // Payment denied by fraud detection
+)
+
+// fakeServerKey signs fake webhooks when no Midtrans server key is configured
+const fakeServerKey = "fake-server-key"
+
+var ErrFakeChargeNotFound = errors.New("fake gateway: charge not found")
+
+// Compile-time check that FakeGateway is a Gateway
+var _ Gateway = (*FakeGateway)(nil)
+
+// FakeGateway is an in-process payment provider for local development and
+// integration tests. Charges never leave the process; outcomes are simulated
+// by posting Midtrans-format webhooks, signed with the configured server key,
+// to the app's own webhook endpoint so the real callback path is exercised.
+type FakeGateway struct {
+	serverKey   string
+	webhookURL  string
+	autoOutcome FakeOutcome
+	autoDelay   time.Duration
+	httpClient  *http.Client
+
+	mu      sync.Mutex
+	charges map[string]*fakeCharge
+}
+
+type fakeCharge struct {
+	orderID       string
+	transactionID string
+	amount        float64
+	status        ChargeStatus
+	providerState string
+	fraudStatus   string
+	paymentType   string
+	createdAt     time.Time
+}
+
+// NewFakeGateway creates a fake gateway.
+// If cfg.FakeOutcome is set every charge resolves to it after cfg.FakeDelay;
+// otherwise outcomes are triggered with Simulate.
+func NewFakeGateway(cfg *config.MidtransConfig) *FakeGateway {
+	serverKey := cfg.ServerKey
+	if serverKey == "" {
+		serverKey = fakeServerKey
+	}
+
+	return &FakeGateway{
+		serverKey:   serverKey,
+		webhookURL:  cfg.FakeWebhookURL,
+		autoOutcome: FakeOutcome(cfg.FakeOutcome),
+		autoDelay:   cfg.FakeDelay,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		charges: make(map[string]*fakeCharge),
+	}
+}
+
+// Name identifies the provider
+func (f *FakeGateway) Name() string {
+	return "fake"
+}
+
+// ClientKey returns a placeholder client key
+func (f *FakeGateway) ClientKey() string {
+	return "fake-client-key"
+}
+
+// CreateCharge records a pending charge
+func (f *FakeGateway) CreateCharge(ctx context.Context, req *ChargeRequest) (*ChargeResponse, error) {
+	charge := &fakeCharge{
+		orderID:       req.OrderID,
+		transactionID: uuid.New().String(),
+		amount:        req.Amount,
+		status:        ChargePending,
+		providerState: "pending",
+		createdAt:     time.Now(),
+	}
+
+	f.mu.Lock()
+	f.charges[req.OrderID] = charge
+	f.mu.Unlock()
+
+	if f.autoOutcome != "" {
+		go func() {
+			time.Sleep(f.autoDelay)
+			if err := f.Simulate(context.Background(), req.OrderID, f.autoOutcome); err != nil {
+				log.Printf("[FakeGateway] failed to simulate %s for %s: %v", f.autoOutcome, req.OrderID, err)
+			}
+		}()
+	}
+
+	return &ChargeResponse{
+		Token:       "fake-" + charge.transactionID,
+		RedirectURL: "https://fake-gateway.local/pay/" + req.OrderID,
+	}, nil
+}
+
+// GetChargeStatus returns the simulated status of a charge
+func (f *FakeGateway) GetChargeStatus(ctx context.Context, orderID string) (*ChargeStatusResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[orderID]
+	if !ok {
+		return nil, ErrFakeChargeNotFound
+	}
+
+	return charge.result(), nil
+}
+
+// VerifyWebhook decodes a fake notification and verifies its signature
+func (f *FakeGateway) VerifyWebhook(body []byte) (*WebhookNotification, error) {
+	return parseMidtransNotification(body, func(orderID, statusCode, grossAmount, signatureKey string) bool {
+		return verifyMidtransSignature(f.serverKey, orderID, statusCode, grossAmount, signatureKey)
+	})
+}
+
+// RefundCharge refunds a settled charge immediately
+func (f *FakeGateway) RefundCharge(ctx context.Context, orderID string, req *RefundRequest) (*RefundResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[orderID]
+	if !ok {
+		return nil, &RefundError{Method: RefundMethodDirect, Err: ErrFakeChargeNotFound}
+	}
+	if charge.status != ChargeSettled {
+		return nil, &RefundError{Method: RefundMethodDirect, Err: fmt.Errorf("charge is %s, not settled", charge.status)}
+	}
+
+	charge.status = ChargeRefunded
+	charge.providerState = "refund"
+
+	return &RefundResult{
+		Method:           RefundMethodDirect,
+		ProviderRefundID: uuid.New().String(),
+	}, nil
+}
+
+// Simulate resolves a charge with the given outcome and delivers the matching webhook
+func (f *FakeGateway) Simulate(ctx context.Context, orderID string, outcome FakeOutcome) error {
+	f.mu.Lock()
+	charge, ok := f.charges[orderID]
+	if !ok {
+		f.mu.Unlock()
+		return ErrFakeChargeNotFound
+	}
+
+	var statusCode string
+	switch outcome {
+	case FakeOutcomeSettlement:
+		charge.status, charge.providerState, charge.fraudStatus, charge.paymentType = ChargeSettled, "settlement", "accept", "qris"
+		statusCode = "200"
+	case FakeOutcomeExpire:
+		charge.status, charge.providerState, charge.fraudStatus, charge.paymentType = ChargeExpired, "expire", "", "qris"
+		statusCode = "407"
+	case FakeOutcomeFraud:
+		charge.status, charge.providerState, charge.fraudStatus, charge.paymentType = ChargeFailed, "deny", "deny", "credit_card"
+		statusCode = "202"
+	default:
+		f.mu.Unlock()
+		return fmt.Errorf("fake gateway: unknown outcome %q", outcome)
+	}
+	notification := charge.notification(statusCode, f.serverKey)
+	f.mu.Unlock()
+
+	log.Printf("[FakeGateway] simulating %s for order %s", outcome, orderID)
+	return f.deliver(ctx, notification)
+}
+
+// deliver posts a notification to the configured webhook URL
+func (f *FakeGateway) deliver(ctx context.Context, notification *MidtransNotification) error {
+	if f.webhookURL == "" {
+		return errors.New("fake gateway: no webhook URL configured")
+	}
+
+	jsonData, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", f.webhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *fakeCharge) result() *ChargeStatusResult {
+	return &ChargeStatusResult{
+		OrderID:         c.orderID,
+		TransactionID:   c.transactionID,
+		Status:          c.status,
+		ProviderStatus:  c.providerState,
+		FraudStatus:     c.fraudStatus,
+		PaymentType:     c.paymentType,
+		GrossAmount:     fmt.Sprintf("%.2f", c.amount),
+		Currency:        "IDR",
+		TransactionTime: c.createdAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+func (c *fakeCharge) notification(statusCode, serverKey string) *MidtransNotification {
+	grossAmount := fmt.Sprintf("%.2f", c.amount)
+	return &MidtransNotification{
+		TransactionID:     c.transactionID,
+		OrderID:           c.orderID,
+		GrossAmount:       grossAmount,
+		Currency:          "IDR",
+		PaymentType:       c.paymentType,
+		TransactionTime:   c.createdAt.Format("2006-01-02 15:04:05"),
+		TransactionStatus: c.providerState,
+		FraudStatus:       c.fraudStatus,
+		StatusCode:        statusCode,
+		SignatureKey:      midtransSignature(serverKey, c.orderID, statusCode, grossAmount),
+	}
+}
@@ -0,0 +1,111 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anigmaa/backend/config"
+)
+
+// newFakeGatewayWithReceiver returns a fake gateway whose webhooks are verified
+// and collected by an httptest receiver.
+func newFakeGatewayWithReceiver(t *testing.T) (*FakeGateway, chan *WebhookNotification) {
+	t.Helper()
+
+	received := make(chan *WebhookNotification, 1)
+	var gateway *FakeGateway
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		notification, err := gateway.VerifyWebhook(body)
+		if err != nil {
+			t.Errorf("webhook failed verification: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- notification
+	}))
+	t.Cleanup(srv.Close)
+
+	gateway = NewFakeGateway(&config.MidtransConfig{FakeWebhookURL: srv.URL})
+	return gateway, received
+}
+
+func TestFakeGateway_Simulate(t *testing.T) {
+	tests := []struct {
+		outcome        FakeOutcome
+		wantStatus     ChargeStatus
+		wantFraud      string
+		wantRefundable bool
+	}{
+		{outcome: FakeOutcomeSettlement, wantStatus: ChargeSettled, wantFraud: "accept", wantRefundable: true},
+		{outcome: FakeOutcomeExpire, wantStatus: ChargeExpired},
+		{outcome: FakeOutcomeFraud, wantStatus: ChargeFailed, wantFraud: "deny"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.outcome), func(t *testing.T) {
+			gateway, received := newFakeGatewayWithReceiver(t)
+			ctx := context.Background()
+
+			if _, err := gateway.CreateCharge(ctx, &ChargeRequest{OrderID: "order-1", Amount: 150000}); err != nil {
+				t.Fatalf("CreateCharge: %v", err)
+			}
+
+			status, err := gateway.GetChargeStatus(ctx, "order-1")
+			if err != nil {
+				t.Fatalf("GetChargeStatus: %v", err)
+			}
+			if status.Status != ChargePending {
+				t.Errorf("initial status = %s, want pending", status.Status)
+			}
+
+			if err := gateway.Simulate(ctx, "order-1", tt.outcome); err != nil {
+				t.Fatalf("Simulate: %v", err)
+			}
+
+			notification := <-received
+			if notification.OrderID != "order-1" {
+				t.Errorf("OrderID = %s, want order-1", notification.OrderID)
+			}
+			if notification.Status != tt.wantStatus {
+				t.Errorf("webhook status = %s, want %s", notification.Status, tt.wantStatus)
+			}
+			if notification.FraudStatus != tt.wantFraud {
+				t.Errorf("fraud status = %q, want %q", notification.FraudStatus, tt.wantFraud)
+			}
+			if notification.GrossAmount != "150000.00" {
+				t.Errorf("GrossAmount = %s, want 150000.00", notification.GrossAmount)
+			}
+
+			status, _ = gateway.GetChargeStatus(ctx, "order-1")
+			if status.Status != tt.wantStatus {
+				t.Errorf("status after simulate = %s, want %s", status.Status, tt.wantStatus)
+			}
+
+			_, err = gateway.RefundCharge(ctx, "order-1", &RefundRequest{RefundKey: "refund-1", Amount: 150000})
+			if tt.wantRefundable && err != nil {
+				t.Errorf("RefundCharge: %v", err)
+			}
+			if !tt.wantRefundable {
+				var refundErr *RefundError
+				if !errors.As(err, &refundErr) {
+					t.Errorf("RefundCharge error = %v, want *RefundError", err)
+				}
+			}
+		})
+	}
+}
+
+func TestFakeGateway_VerifyWebhookRejectsForgery(t *testing.T) {
+	gateway := NewFakeGateway(&config.MidtransConfig{})
+
+	body := []byte(`{"order_id":"order-1","status_code":"200","gross_amount":"150000.00","transaction_status":"settlement","signature_key":"forged"}`)
+	if _, err := gateway.VerifyWebhook(body); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyWebhook error = %v, want ErrInvalidSignature", err)
+	}
+}
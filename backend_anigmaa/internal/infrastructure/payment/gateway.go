@@ -0,0 +1,115 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidSignature is returned by VerifyWebhook when a notification is not authentic
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ChargeStatus is a provider-neutral payment status
+type ChargeStatus string
+
+const (
+	ChargePending  ChargeStatus = "pending"  // Awaiting payment or under fraud review
+	ChargeSettled  ChargeStatus = "settled"  // Paid
+	ChargeFailed   ChargeStatus = "failed"   // Denied, cancelled or rejected as fraud
+	ChargeExpired  ChargeStatus = "expired"  // Not paid before the deadline
+	ChargeRefunded ChargeStatus = "refunded" // Fully or partially refunded
+)
+
+// Refund methods reported by gateways
+const (
+	RefundMethodStandard = "refund"        // Asynchronous refund, may complete by webhook
+	RefundMethodDirect   = "direct_refund" // Synchronous refund through the payment channel
+)
+
+// Gateway is a payment provider.
+// MidtransClient is the production implementation; FakeGateway simulates one locally.
+type Gateway interface {
+	// Name identifies the provider, e.g. for logs and stored payment methods
+	Name() string
+
+	// ClientKey returns the public key the frontend uses to open the payment page
+	ClientKey() string
+
+	// CreateCharge starts a payment and returns the token/URL the buyer pays with
+	CreateCharge(ctx context.Context, req *ChargeRequest) (*ChargeResponse, error)
+
+	// GetChargeStatus fetches the current status of a payment from the provider
+	GetChargeStatus(ctx context.Context, orderID string) (*ChargeStatusResult, error)
+
+	// VerifyWebhook authenticates a raw webhook body and decodes it.
+	// Returns ErrInvalidSignature if the notification is not authentic.
+	VerifyWebhook(body []byte) (*WebhookNotification, error)
+
+	// RefundCharge refunds a settled payment in full.
+	// Errors from the provider are returned as *RefundError.
+	RefundCharge(ctx context.Context, orderID string, req *RefundRequest) (*RefundResult, error)
+}
+
+// ChargeRequest describes a payment to create
+type ChargeRequest struct {
+	OrderID         string
+	Amount          float64
+	Customer        CustomerDetails
+	Items           []ItemDetail
+	EnabledPayments []string
+	Callbacks       *Callbacks
+	Expiry          *Expiry
+}
+
+// ChargeResponse is what the buyer needs to complete a payment
+type ChargeResponse struct {
+	Token       string `json:"token"`
+	RedirectURL string `json:"redirect_url"`
+}
+
+// ChargeStatusResult is the provider's view of a payment
+type ChargeStatusResult struct {
+	OrderID         string       `json:"order_id"`
+	TransactionID   string       `json:"transaction_id"`
+	Status          ChargeStatus `json:"status"`
+	ProviderStatus  string       `json:"provider_status"` // Raw provider status, e.g. Midtrans "settlement"
+	FraudStatus     string       `json:"fraud_status,omitempty"`
+	PaymentType     string       `json:"payment_type"`
+	GrossAmount     string       `json:"gross_amount"`
+	Currency        string       `json:"currency"`
+	TransactionTime string       `json:"transaction_time"`
+}
+
+// WebhookNotification is an authenticated payment notification
+type WebhookNotification struct {
+	ChargeStatusResult
+	Refunds       []NotifiedRefund // Refunds covered by a refund notification
+	PartialRefund bool             // Only part of the payment has been refunded
+}
+
+// NotifiedRefund is a refund listed in a refund notification
+type NotifiedRefund struct {
+	RefundKey string
+	Amount    float64
+}
+
+// RefundResult describes an accepted refund
+type RefundResult struct {
+	Method           string
+	ProviderRefundID string
+	Pending          bool // Accepted but completes later, usually by webhook
+}
+
+// RefundError is a refund rejected or failed at the provider
+type RefundError struct {
+	Method string
+	Err    error
+}
+
+func (e *RefundError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.Method, e.Err)
+}
+
+func (e *RefundError) Unwrap() error {
+	return e.Err
+}
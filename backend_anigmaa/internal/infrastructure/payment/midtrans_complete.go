@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/anigmaa/backend/config"
@@ -21,6 +22,7 @@ type MidtransClient struct {
 	serverKey  string
 	clientKey  string
 	isSandbox  bool
+	baseURL    string
 	httpClient *http.Client
 }
 
@@ -30,22 +32,29 @@ func NewMidtransClient(cfg *config.MidtransConfig) *MidtransClient {
 		serverKey: cfg.ServerKey,
 		clientKey: cfg.ClientKey,
 		isSandbox: !cfg.IsProduction,
+		baseURL:   strings.TrimRight(cfg.BaseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
-// getBaseURL returns the appropriate Midtrans API URL
+// getBaseURL returns the appropriate Midtrans Core API URL
 func (m *MidtransClient) getBaseURL() string {
+	if m.baseURL != "" {
+		return m.baseURL
+	}
 	if m.isSandbox {
-		return "https://app.sandbox.midtrans.com"
+		return "https://api.sandbox.midtrans.com"
 	}
-	return "https://app.midtrans.com"
+	return "https://api.midtrans.com"
 }
 
 // getSnapBaseURL returns Snap API URL (always uses app domain)
 func (m *MidtransClient) getSnapBaseURL() string {
+	if m.baseURL != "" {
+		return m.baseURL + "/snap/v1"
+	}
 	if m.isSandbox {
 		return "https://app.sandbox.midtrans.com/snap/v1"
 	}
@@ -207,13 +216,125 @@ func (m *MidtransClient) GetTransactionStatus(ctx context.Context, orderID strin
 	return &status, nil
 }
 
+// Refund API status codes returned in the response body
+const (
+	refundStatusSucceeded = "200"
+	refundStatusPending   = "201"
+)
+
+// RefundRequest represents a refund or direct refund request
+type RefundRequest struct {
+	RefundKey string  `json:"refund_key"` // Idempotency key; retrying with the same key never refunds twice
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+// RefundResponse represents the refund API response
+type RefundResponse struct {
+	StatusCode         string `json:"status_code"`
+	StatusMessage      string `json:"status_message"`
+	TransactionID      string `json:"transaction_id"`
+	OrderID            string `json:"order_id"`
+	GrossAmount        string `json:"gross_amount"`
+	PaymentType        string `json:"payment_type"`
+	TransactionStatus  string `json:"transaction_status"`
+	RefundChargebackID int64  `json:"refund_chargeback_id"`
+	RefundAmount       string `json:"refund_amount"`
+	RefundKey          string `json:"refund_key"`
+}
+
+// IsPending reports whether Midtrans accepted the refund but has not completed it yet
+func (r *RefundResponse) IsPending() bool {
+	return r.StatusCode == refundStatusPending
+}
+
+// Refund requests a refund of a settled transaction.
+// Card refunds complete asynchronously; the final result arrives by webhook.
+func (m *MidtransClient) Refund(ctx context.Context, orderID string, req *RefundRequest) (*RefundResponse, error) {
+	return m.refund(ctx, fmt.Sprintf("%s/v2/%s/refund", m.getBaseURL(), orderID), req)
+}
+
+// DirectRefund requests an immediate refund through the payment channel.
+// Supported for e-wallet and QRIS payments.
+func (m *MidtransClient) DirectRefund(ctx context.Context, orderID string, req *RefundRequest) (*RefundResponse, error) {
+	return m.refund(ctx, fmt.Sprintf("%s/v2/%s/refund/online/direct", m.getBaseURL(), orderID), req)
+}
+
+// SupportsDirectRefund reports whether a payment type is refunded through the direct refund API
+func SupportsDirectRefund(paymentType string) bool {
+	switch paymentType {
+	case "gopay", "qris", "shopeepay":
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *MidtransClient) refund(ctx context.Context, url string, req *RefundRequest) (*RefundResponse, error) {
+	// Marshal request
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers - Basic Auth using Server Key
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.SetBasicAuth(m.serverKey, "")
+
+	// Send request
+	resp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Check status code
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("midtrans API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var refundResp RefundResponse
+	if err := json.Unmarshal(body, &refundResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	// Midtrans reports business errors (e.g. 412 not refundable) in the body with HTTP 200
+	if refundResp.StatusCode != refundStatusSucceeded && refundResp.StatusCode != refundStatusPending {
+		return nil, fmt.Errorf("midtrans refund rejected (status %s): %s", refundResp.StatusCode, refundResp.StatusMessage)
+	}
+
+	return &refundResp, nil
+}
+
 // VerifySignature verifies the webhook signature from Midtrans.
 // Formula: SHA512(order_id + status_code + gross_amount + server_key)
 // Uses constant-time comparison to prevent timing-based signature oracle attacks.
 func (m *MidtransClient) VerifySignature(orderID, statusCode, grossAmount, signatureKey string) bool {
-	payload := orderID + statusCode + grossAmount + m.serverKey
-	hash := sha512.Sum512([]byte(payload))
-	expectedSig := fmt.Sprintf("%x", hash)
+	return verifyMidtransSignature(m.serverKey, orderID, statusCode, grossAmount, signatureKey)
+}
+
+// midtransSignature computes the notification signature for a server key
+func midtransSignature(serverKey, orderID, statusCode, grossAmount string) string {
+	hash := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	return fmt.Sprintf("%x", hash)
+}
+
+func verifyMidtransSignature(serverKey, orderID, statusCode, grossAmount, signatureKey string) bool {
+	expectedSig := midtransSignature(serverKey, orderID, statusCode, grossAmount)
 	// subtle.ConstantTimeCompare prevents timing attacks where an attacker
 	// could infer bytes of the expected signature by measuring response time.
 	return subtle.ConstantTimeCompare([]byte(expectedSig), []byte(signatureKey)) == 1
@@ -232,8 +353,3 @@ func GenerateOrderID(prefix string) string {
 	}
 	return fmt.Sprintf("%s-%s%s", prefix, nano, hex.EncodeToString(b))
 }
-
-// GetClientKey returns the client key for frontend
-func (m *MidtransClient) GetClientKey() string {
-	return m.clientKey
-}
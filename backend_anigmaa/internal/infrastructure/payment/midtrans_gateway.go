@@ -0,0 +1,194 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// Compile-time check that MidtransClient is a Gateway
+var _ Gateway = (*MidtransClient)(nil)
+
+// MidtransNotification represents the webhook notification from Midtrans
+type MidtransNotification struct {
+	TransactionID     string `json:"transaction_id"`
+	OrderID           string `json:"order_id"`
+	GrossAmount       string `json:"gross_amount"`
+	Currency          string `json:"currency"`
+	PaymentType       string `json:"payment_type"`
+	TransactionTime   string `json:"transaction_time"`
+	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status,omitempty"`
+	StatusCode        string `json:"status_code"`
+	SignatureKey      string `json:"signature_key"`
+	// Refunds is present on refund and partial_refund notifications
+	Refunds []MidtransNotificationRefund `json:"refunds,omitempty"`
+}
+
+// MidtransNotificationRefund is a single refund listed in a refund notification
+type MidtransNotificationRefund struct {
+	RefundChargebackID int64  `json:"refund_chargeback_id"`
+	RefundAmount       string `json:"refund_amount"`
+	RefundKey          string `json:"refund_key"`
+	Reason             string `json:"reason,omitempty"`
+}
+
+// mapMidtransStatus converts a Midtrans transaction_status/fraud_status pair
+// to a provider-neutral ChargeStatus.
+func mapMidtransStatus(transactionStatus, fraudStatus string) ChargeStatus {
+	switch transactionStatus {
+	case "capture":
+		// Card payments flagged for review stay pending until Midtrans decides
+		if fraudStatus == "challenge" {
+			return ChargePending
+		}
+		if fraudStatus == "deny" {
+			return ChargeFailed
+		}
+		return ChargeSettled
+	case "settlement":
+		return ChargeSettled
+	case "deny", "cancel", "failure":
+		return ChargeFailed
+	case "expire":
+		return ChargeExpired
+	case "refund", "partial_refund":
+		return ChargeRefunded
+	default:
+		// "pending", "authorize" and unknown statuses
+		return ChargePending
+	}
+}
+
+// Name identifies the provider
+func (m *MidtransClient) Name() string {
+	return "midtrans"
+}
+
+// ClientKey returns the Midtrans client key for frontend initialization
+func (m *MidtransClient) ClientKey() string {
+	return m.clientKey
+}
+
+// CreateCharge creates a Snap payment
+func (m *MidtransClient) CreateCharge(ctx context.Context, req *ChargeRequest) (*ChargeResponse, error) {
+	snapResp, err := m.CreateSnapToken(ctx, &SnapRequest{
+		TransactionDetails: TransactionDetails{
+			OrderID:     req.OrderID,
+			GrossAmount: req.Amount,
+		},
+		CustomerDetails: req.Customer,
+		ItemDetails:     req.Items,
+		EnabledPayments: req.EnabledPayments,
+		Callbacks:       req.Callbacks,
+		Expiry:          req.Expiry,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChargeResponse{
+		Token:       snapResp.Token,
+		RedirectURL: snapResp.RedirectURL,
+	}, nil
+}
+
+// GetChargeStatus fetches the transaction status from Midtrans
+func (m *MidtransClient) GetChargeStatus(ctx context.Context, orderID string) (*ChargeStatusResult, error) {
+	status, err := m.GetTransactionStatus(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChargeStatusResult{
+		OrderID:         status.OrderID,
+		TransactionID:   status.TransactionID,
+		Status:          mapMidtransStatus(status.TransactionStatus, status.FraudStatus),
+		ProviderStatus:  status.TransactionStatus,
+		FraudStatus:     status.FraudStatus,
+		PaymentType:     status.PaymentType,
+		GrossAmount:     status.GrossAmount,
+		Currency:        status.Currency,
+		TransactionTime: status.TransactionTime,
+	}, nil
+}
+
+// VerifyWebhook decodes a Midtrans notification and verifies its SHA-512 signature
+func (m *MidtransClient) VerifyWebhook(body []byte) (*WebhookNotification, error) {
+	return parseMidtransNotification(body, m.VerifySignature)
+}
+
+// parseMidtransNotification decodes a Midtrans-format notification and checks it with verify
+func parseMidtransNotification(body []byte, verify func(orderID, statusCode, grossAmount, signatureKey string) bool) (*WebhookNotification, error) {
+	var notification MidtransNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode notification: %w", err)
+	}
+
+	// Signature: SHA512(order_id + status_code + gross_amount + server_key)
+	if !verify(notification.OrderID, notification.StatusCode, notification.GrossAmount, notification.SignatureKey) {
+		return nil, ErrInvalidSignature
+	}
+
+	result := &WebhookNotification{
+		ChargeStatusResult: ChargeStatusResult{
+			OrderID:         notification.OrderID,
+			TransactionID:   notification.TransactionID,
+			Status:          mapMidtransStatus(notification.TransactionStatus, notification.FraudStatus),
+			ProviderStatus:  notification.TransactionStatus,
+			FraudStatus:     notification.FraudStatus,
+			PaymentType:     notification.PaymentType,
+			GrossAmount:     notification.GrossAmount,
+			Currency:        notification.Currency,
+			TransactionTime: notification.TransactionTime,
+		},
+	}
+	result.PartialRefund = notification.TransactionStatus == "partial_refund"
+	for _, refund := range notification.Refunds {
+		if refund.RefundKey == "" {
+			continue
+		}
+		amount, err := strconv.ParseFloat(refund.RefundAmount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid refund_amount %q: %w", refund.RefundAmount, err)
+		}
+		result.Refunds = append(result.Refunds, NotifiedRefund{RefundKey: refund.RefundKey, Amount: amount})
+	}
+
+	return result, nil
+}
+
+// RefundCharge refunds a settled transaction.
+// E-wallet and QRIS payments use the direct refund API; everything else uses
+// the standard refund API.
+func (m *MidtransClient) RefundCharge(ctx context.Context, orderID string, req *RefundRequest) (*RefundResult, error) {
+	method := RefundMethodStandard
+	status, err := m.GetTransactionStatus(ctx, orderID)
+	if err != nil {
+		log.Printf("[Midtrans] failed to get payment type for %s, using standard refund: %v", orderID, err)
+	} else if SupportsDirectRefund(status.PaymentType) {
+		method = RefundMethodDirect
+	}
+
+	var refundResp *RefundResponse
+	if method == RefundMethodDirect {
+		refundResp, err = m.DirectRefund(ctx, orderID, req)
+	} else {
+		refundResp, err = m.Refund(ctx, orderID, req)
+	}
+	if err != nil {
+		return nil, &RefundError{Method: method, Err: err}
+	}
+
+	result := &RefundResult{
+		Method:  method,
+		Pending: refundResp.IsPending(),
+	}
+	if refundResp.RefundChargebackID != 0 {
+		result.ProviderRefundID = strconv.FormatInt(refundResp.RefundChargebackID, 10)
+	}
+
+	return result, nil
+}
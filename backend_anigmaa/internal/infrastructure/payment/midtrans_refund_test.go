@@ -0,0 +1,219 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anigmaa/backend/config"
+)
+
+const testServerKey = "SB-Mid-server-test"
+
+// newMidtransStandIn starts an httptest server that answers refund calls with the given body
+// and records the last request it received.
+func newMidtransStandIn(t *testing.T, statusCode int, body interface{}) (*MidtransClient, *http.Request, *RefundRequest) {
+	t.Helper()
+
+	var gotReq http.Request
+	var gotBody RefundRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReq = *r
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Errorf("failed to decode refund request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewMidtransClient(&config.MidtransConfig{
+		ServerKey: testServerKey,
+		BaseURL:   srv.URL,
+	})
+
+	return client, &gotReq, &gotBody
+}
+
+func TestMidtransClient_Refund(t *testing.T) {
+	tests := []struct {
+		name        string
+		direct      bool
+		statusCode  int
+		body        map[string]interface{}
+		wantPath    string
+		wantErr     bool
+		wantPending bool
+	}{
+		{
+			name:       "refund succeeded",
+			statusCode: http.StatusOK,
+			body: map[string]interface{}{
+				"status_code":          "200",
+				"status_message":       "Success, refund request is approved",
+				"order_id":             "order-1",
+				"transaction_status":   "refund",
+				"refund_chargeback_id": 4321,
+				"refund_amount":        "150000.00",
+				"refund_key":           "refund-key-1",
+			},
+			wantPath: "/v2/order-1/refund",
+		},
+		{
+			name:       "direct refund succeeded",
+			direct:     true,
+			statusCode: http.StatusOK,
+			body: map[string]interface{}{
+				"status_code":        "200",
+				"status_message":     "Success, refund request is approved",
+				"order_id":           "order-1",
+				"transaction_status": "refund",
+				"refund_key":         "refund-key-1",
+			},
+			wantPath: "/v2/order-1/refund/online/direct",
+		},
+		{
+			name:       "refund pending",
+			statusCode: http.StatusOK,
+			body: map[string]interface{}{
+				"status_code":    "201",
+				"status_message": "Refund request is being processed",
+				"order_id":       "order-1",
+				"refund_key":     "refund-key-1",
+			},
+			wantPath:    "/v2/order-1/refund",
+			wantPending: true,
+		},
+		{
+			name:       "refund rejected in body",
+			statusCode: http.StatusOK,
+			body: map[string]interface{}{
+				"status_code":    "412",
+				"status_message": "Merchant cannot modify the status of the transaction",
+			},
+			wantPath: "/v2/order-1/refund",
+			wantErr:  true,
+		},
+		{
+			name:       "http error",
+			direct:     true,
+			statusCode: http.StatusInternalServerError,
+			body:       map[string]interface{}{"status_message": "internal error"},
+			wantPath:   "/v2/order-1/refund/online/direct",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, gotReq, gotBody := newMidtransStandIn(t, tt.statusCode, tt.body)
+
+			req := &RefundRequest{RefundKey: "refund-key-1", Amount: 150000, Reason: "cancelled"}
+
+			var resp *RefundResponse
+			var err error
+			if tt.direct {
+				resp, err = client.DirectRefund(context.Background(), "order-1", req)
+			} else {
+				resp, err = client.Refund(context.Background(), "order-1", req)
+			}
+
+			if gotReq.Method != http.MethodPost {
+				t.Errorf("method = %s, want POST", gotReq.Method)
+			}
+			if gotReq.URL.Path != tt.wantPath {
+				t.Errorf("path = %s, want %s", gotReq.URL.Path, tt.wantPath)
+			}
+			if user, _, ok := gotReq.BasicAuth(); !ok || user != testServerKey {
+				t.Errorf("basic auth user = %q, want server key", user)
+			}
+			if *gotBody != *req {
+				t.Errorf("request body = %+v, want %+v", *gotBody, *req)
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got response %+v", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.IsPending() != tt.wantPending {
+				t.Errorf("IsPending() = %v, want %v", resp.IsPending(), tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestMidtransClient_RefundChargebackID(t *testing.T) {
+	client, _, _ := newMidtransStandIn(t, http.StatusOK, map[string]interface{}{
+		"status_code":          "200",
+		"refund_chargeback_id": 4321,
+		"refund_key":           "refund-key-1",
+	})
+
+	resp, err := client.Refund(context.Background(), "order-1", &RefundRequest{RefundKey: "refund-key-1", Amount: 1000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.RefundChargebackID != 4321 {
+		t.Errorf("RefundChargebackID = %d, want 4321", resp.RefundChargebackID)
+	}
+	if resp.RefundKey != "refund-key-1" {
+		t.Errorf("RefundKey = %q, want refund-key-1", resp.RefundKey)
+	}
+}
+
+func TestParseMidtransNotification_PartialRefund(t *testing.T) {
+	body := []byte(`{
+		"order_id": "order-1",
+		"status_code": "200",
+		"gross_amount": "150000.00",
+		"transaction_status": "partial_refund",
+		"refunds": [
+			{"refund_chargeback_id": 1, "refund_amount": "50000.00", "refund_key": "refund-key-1"},
+			{"refund_chargeback_id": 2, "refund_amount": "25000.00", "refund_key": "refund-key-2"}
+		]
+	}`)
+	accept := func(orderID, statusCode, grossAmount, signatureKey string) bool { return true }
+
+	notification, err := parseMidtransNotification(body, accept)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if notification.Status != ChargeRefunded || !notification.PartialRefund {
+		t.Errorf("status = %s, partial = %v, want a partial refund", notification.Status, notification.PartialRefund)
+	}
+	want := []NotifiedRefund{{RefundKey: "refund-key-1", Amount: 50000}, {RefundKey: "refund-key-2", Amount: 25000}}
+	if len(notification.Refunds) != len(want) {
+		t.Fatalf("Refunds = %+v, want %+v", notification.Refunds, want)
+	}
+	for i := range want {
+		if notification.Refunds[i] != want[i] {
+			t.Errorf("Refunds[%d] = %+v, want %+v", i, notification.Refunds[i], want[i])
+		}
+	}
+}
+
+func TestSupportsDirectRefund(t *testing.T) {
+	tests := map[string]bool{
+		"gopay":         true,
+		"qris":          true,
+		"shopeepay":     true,
+		"credit_card":   false,
+		"bank_transfer": false,
+		"":              false,
+	}
+
+	for paymentType, want := range tests {
+		if got := SupportsDirectRefund(paymentType); got != want {
+			t.Errorf("SupportsDirectRefund(%q) = %v, want %v", paymentType, got, want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anigmaa/backend/internal/infrastructure/cache"
+	"github.com/google/uuid"
+)
+
+// Stream event types pushed to connected clients
+const (
+	EventNotification  = "notification"
+	EventQnAAnswered   = "qna_answered"
+	EventPaymentUpdate = "payment_update"
+	EventStatusChanged = "event_status_changed"
+	EventCheckInStats  = "check_in_stats"
+)
+
+const (
+	// userChannelPrefix is the Redis pub/sub channel prefix; the full channel is prefix + user ID
+	userChannelPrefix = "stream:user:"
+	// subscriberBuffer is how many messages a slow client may fall behind before messages are dropped
+	subscriberBuffer = 32
+)
+
+// Message is a single event delivered to a user's stream
+type Message struct {
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+	SentAt time.Time       `json:"sent_at"`
+}
+
+// Hub fans out stream messages to locally connected users.
+// Messages are always published through Redis so that a user connected to
+// another server instance still receives them; every instance runs one
+// pattern subscription and dispatches to its own local subscribers.
+type Hub struct {
+	redis       *cache.RedisClient
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan Message]struct{}
+}
+
+// NewHub creates a new realtime hub
+func NewHub(redis *cache.RedisClient) *Hub {
+	return &Hub{
+		redis:       redis,
+		subscribers: make(map[uuid.UUID]map[chan Message]struct{}),
+	}
+}
+
+// Run listens on Redis pub/sub and dispatches messages until ctx is cancelled
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := h.redis.PSubscribe(ctx, userChannelPrefix+"*")
+	defer pubsub.Close()
+
+	log.Println("[RealtimeHub] Subscribed to Redis stream channels")
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[RealtimeHub] Stopped")
+			return
+		case redisMsg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			userID, err := uuid.Parse(strings.TrimPrefix(redisMsg.Channel, userChannelPrefix))
+			if err != nil {
+				continue
+			}
+
+			var msg Message
+			if err := json.Unmarshal([]byte(redisMsg.Payload), &msg); err != nil {
+				log.Printf("[RealtimeHub] Dropping malformed message on %s: %v", redisMsg.Channel, err)
+				continue
+			}
+
+			h.dispatch(userID, msg)
+		}
+	}
+}
+
+// dispatch delivers a message to every local connection of a user.
+// A connection whose buffer is full misses the message rather than blocking the hub.
+func (h *Hub) dispatch(userID uuid.UUID, msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers[userID] {
+		select {
+		case sub <- msg:
+		default:
+			log.Printf("[RealtimeHub] Subscriber buffer full for user %s, dropping %s", userID, msg.Type)
+		}
+	}
+}
+
+// Subscribe registers a local connection for a user.
+// The returned function must be called when the connection closes.
+func (h *Hub) Subscribe(userID uuid.UUID) (<-chan Message, func()) {
+	sub := make(chan Message, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan Message]struct{})
+	}
+	h.subscribers[userID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[userID], sub)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+	}
+
+	return sub, unsubscribe
+}
+
+// Publish sends an event to a user's stream on every server instance.
+// Safe to call on a nil hub (realtime delivery disabled).
+func (h *Hub) Publish(ctx context.Context, userID uuid.UUID, eventType string, data interface{}) error {
+	if h == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	msg, err := json.Marshal(Message{
+		Type:   eventType,
+		Data:   payload,
+		SentAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.redis.Publish(ctx, userChannelPrefix+userID.String(), msg)
+}
+
+// PublishToUsers sends the same event to several users, logging individual failures
+func (h *Hub) PublishToUsers(ctx context.Context, userIDs []uuid.UUID, eventType string, data interface{}) {
+	for _, userID := range userIDs {
+		if err := h.Publish(ctx, userID, eventType, data); err != nil {
+			log.Printf("[RealtimeHub] Failed to publish %s to user %s: %v", eventType, userID, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/auth"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type authSessionRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuthSessionRepository creates a new auth session repository
+func NewAuthSessionRepository(db *sqlx.DB) auth.SessionRepository {
+	return &authSessionRepository{db: db}
+}
+
+// CreateSession creates a new device session
+func (r *authSessionRepository) CreateSession(ctx context.Context, session *auth.Session) error {
+	// Generate UUID if not provided
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+
+	// Set timestamps if not provided
+	now := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	if session.LastUsedAt.IsZero() {
+		session.LastUsedAt = now
+	}
+
+	query := `
+		INSERT INTO auth_sessions (
+			id, user_id, refresh_token_id, access_token_id, device_name, user_agent, ip_address,
+			created_at, last_used_at, expires_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID, session.UserID, session.RefreshTokenID, session.AccessTokenID,
+		session.DeviceName, session.UserAgent, session.IPAddress,
+		session.CreatedAt, session.LastUsedAt, session.ExpiresAt,
+	)
+
+	return err
+}
+
+// GetSessionByID gets a session by ID
+func (r *authSessionRepository) GetSessionByID(ctx context.Context, id uuid.UUID) (*auth.Session, error) {
+	query := `
+		SELECT id, user_id, refresh_token_id, access_token_id, device_name, user_agent, ip_address,
+			created_at, last_used_at, expires_at, revoked_at, revoked_reason
+		FROM auth_sessions
+		WHERE id = $1
+	`
+
+	var session auth.Session
+	err := r.db.GetContext(ctx, &session, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// RotateSession swaps in a new refresh token. The WHERE clause on the old
+// refresh_token_id makes rotation a compare-and-swap, so two concurrent
+// refreshes with the same token cannot both succeed.
+func (r *authSessionRepository) RotateSession(ctx context.Context, id uuid.UUID, oldRefreshTokenID, newRefreshTokenID, newAccessTokenID string, expiresAt time.Time) error {
+	query := `
+		UPDATE auth_sessions
+		SET refresh_token_id = $1, access_token_id = $2, expires_at = $3, last_used_at = $4
+		WHERE id = $5 AND refresh_token_id = $6 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		newRefreshTokenID, newAccessTokenID, expiresAt, time.Now(), id, oldRefreshTokenID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ListActiveSessions lists a user's active sessions, most recently used first
+func (r *authSessionRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]auth.Session, error) {
+	query := `
+		SELECT id, user_id, refresh_token_id, access_token_id, device_name, user_agent, ip_address,
+			created_at, last_used_at, expires_at, revoked_at, revoked_reason
+		FROM auth_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC
+	`
+
+	var sessions []auth.Session
+	err := r.db.SelectContext(ctx, &sessions, query, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if sessions == nil {
+		sessions = []auth.Session{}
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes a single session
+func (r *authSessionRepository) RevokeSession(ctx context.Context, id uuid.UUID, reason string) error {
+	query := `
+		UPDATE auth_sessions
+		SET revoked_at = $1, revoked_reason = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), reason, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RevokeUserSessions revokes all active sessions of a user except one and returns the revoked sessions
+func (r *authSessionRepository) RevokeUserSessions(ctx context.Context, userID, exceptSessionID uuid.UUID, reason string) ([]auth.Session, error) {
+	query := `
+		UPDATE auth_sessions
+		SET revoked_at = $1, revoked_reason = $2
+		WHERE user_id = $3 AND id <> $4 AND revoked_at IS NULL
+		RETURNING id, user_id, refresh_token_id, access_token_id, device_name, user_agent, ip_address,
+			created_at, last_used_at, expires_at, revoked_at, revoked_reason
+	`
+
+	var sessions []auth.Session
+	err := r.db.SelectContext(ctx, &sessions, query, time.Now(), reason, userID, exceptSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sessions == nil {
+		sessions = []auth.Session{}
+	}
+
+	return sessions, nil
+}
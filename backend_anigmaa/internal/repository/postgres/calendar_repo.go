@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/calendar"
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type calendarRepository struct {
+	db *sqlx.DB
+}
+
+// NewCalendarRepository creates a new calendar feed repository
+func NewCalendarRepository(db *sqlx.DB) calendar.Repository {
+	return &calendarRepository{db: db}
+}
+
+func (r *calendarRepository) GetFeedToken(ctx context.Context, userID uuid.UUID) (*calendar.FeedToken, error) {
+	var token calendar.FeedToken
+	err := r.db.GetContext(ctx, &token, `SELECT user_id, token_hash, created_at FROM calendar_feed_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *calendarRepository) GetFeedTokenByHash(ctx context.Context, tokenHash string) (*calendar.FeedToken, error) {
+	var feedToken calendar.FeedToken
+	err := r.db.GetContext(ctx, &feedToken, `SELECT user_id, token_hash, created_at FROM calendar_feed_tokens WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return &feedToken, nil
+}
+
+func (r *calendarRepository) SaveFeedToken(ctx context.Context, token *calendar.FeedToken) error {
+	query := `
+		INSERT INTO calendar_feed_tokens (user_id, token_hash, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at
+	`
+
+	token.CreatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, query, token.UserID, token.TokenHash, token.CreatedAt)
+	return err
+}
+
+func (r *calendarRepository) GetFeedEvents(ctx context.Context, userID uuid.UUID, from time.Time, hostedLimit, ticketLimit int) ([]event.Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE id IN (
+			(SELECT id FROM events
+			WHERE host_id = $1 AND NOT is_archived
+			ORDER BY created_at DESC
+			LIMIT $2)
+			UNION
+			(SELECT e.id FROM events e
+			WHERE e.start_time > $3
+				AND EXISTS(SELECT 1 FROM tickets t
+					WHERE t.event_id = e.id AND t.user_id = $1 AND t.status = 'active')
+			ORDER BY e.start_time
+			LIMIT $4)
+		)
+		ORDER BY start_time, id
+	`
+
+	events := make([]event.Event, 0)
+	if err := r.db.SelectContext(ctx, &events, query, userID, hostedLimit, from, ticketLimit); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/comment"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
@@ -151,7 +152,7 @@ func (r *commentRepository) Delete(ctx context.Context, commentID uuid.UUID) err
 }
 
 // GetByPost gets comments for a post
-func (r *commentRepository) GetByPost(ctx context.Context, postID, userID uuid.UUID, limit, offset int) ([]comment.CommentWithDetails, error) {
+func (r *commentRepository) GetByPost(ctx context.Context, postID, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]comment.CommentWithDetails, error) {
 	query := `
 		SELECT
 			c.id,
@@ -175,11 +176,19 @@ func (r *commentRepository) GetByPost(ctx context.Context, postID, userID uuid.U
 		FROM comments c
 		INNER JOIN users u ON c.author_id = u.id
 		WHERE c.post_id = $2
-		ORDER BY c.likes_count DESC, c.created_at DESC
+			AND ($5::timestamptz IS NULL OR (c.likes_count, c.created_at, c.id) < ($7::int, $5::timestamptz, $6::uuid))
+		ORDER BY c.likes_count DESC, c.created_at DESC, c.id DESC
 		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, userID, postID, limit, offset)
+	var beforeCreatedAt *time.Time
+	var beforeID *uuid.UUID
+	var beforeScore *int
+	if before != nil {
+		beforeCreatedAt, beforeID, beforeScore = &before.CreatedAt, &before.ID, &before.Score
+	}
+
+	rows, err := r.db.QueryxContext(ctx, query, userID, postID, limit, offset, beforeCreatedAt, beforeID, beforeScore)
 	if err != nil {
 		return nil, err
 	}
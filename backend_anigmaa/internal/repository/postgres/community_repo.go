@@ -21,6 +21,11 @@ func NewCommunityRepository(db *sqlx.DB) community.Repository {
 	return &communityRepository{db: db}
 }
 
+// communityColumns lists the columns of community.Community. The table also has a
+// search_vector column, so SELECT * can't be scanned into it.
+const communityColumns = `id, name, slug, description, category, avatar_url, cover_url,
+	creator_id, privacy, members_count, posts_count, created_at, updated_at`
+
 // Create creates a new community
 func (r *communityRepository) Create(ctx context.Context, comm *community.Community) error {
 	query := `
@@ -47,7 +52,7 @@ func (r *communityRepository) Create(ctx context.Context, comm *community.Commun
 // GetByID gets a community by ID
 func (r *communityRepository) GetByID(ctx context.Context, id uuid.UUID) (*community.Community, error) {
 	var comm community.Community
-	query := `SELECT * FROM communities WHERE id = $1`
+	query := `SELECT ` + communityColumns + ` FROM communities WHERE id = $1`
 
 	err := r.db.GetContext(ctx, &comm, query, id)
 	if err == sql.ErrNoRows {
@@ -59,7 +64,7 @@ func (r *communityRepository) GetByID(ctx context.Context, id uuid.UUID) (*commu
 // GetBySlug gets a community by slug
 func (r *communityRepository) GetBySlug(ctx context.Context, slug string) (*community.Community, error) {
 	var comm community.Community
-	query := `SELECT * FROM communities WHERE slug = $1`
+	query := `SELECT ` + communityColumns + ` FROM communities WHERE slug = $1`
 
 	err := r.db.GetContext(ctx, &comm, query, slug)
 	if err == sql.ErrNoRows {
@@ -145,7 +150,7 @@ func (r *communityRepository) GetAll(ctx context.Context, filter *community.Comm
 func (r *communityRepository) GetByCreator(ctx context.Context, creatorID uuid.UUID, limit, offset int) ([]community.Community, error) {
 	var communities []community.Community
 	query := `
-		SELECT * FROM communities
+		SELECT ` + communityColumns + ` FROM communities
 		WHERE creator_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
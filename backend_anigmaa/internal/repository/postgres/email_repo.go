@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/email"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type emailRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailRepository creates a new email outbox repository
+func NewEmailRepository(db *sqlx.DB) email.Repository {
+	return &emailRepository{db: db}
+}
+
+const emailColumns = `id, user_id, recipient, template, language, subject, html_body,
+		status, attempts, last_error, next_attempt_at, sent_at, created_at`
+
+func (r *emailRepository) Enqueue(ctx context.Context, e *email.Email) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	e.Status = email.StatusPending
+	e.CreatedAt = time.Now()
+	e.NextAttemptAt = e.CreatedAt
+
+	query := `
+		INSERT INTO email_outbox (id, user_id, recipient, template, language, subject, html_body,
+			status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err = tx.ExecContext(ctx, query, e.ID, e.UserID, e.Recipient, e.Template, e.Language,
+		e.Subject, e.HTMLBody, e.Status, e.NextAttemptAt, e.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range e.Attachments {
+		a := &e.Attachments[i]
+		a.EmailID = e.ID
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO email_attachments (email_id, filename, content_type, content)
+			VALUES ($1, $2, $3, $4)
+		`, a.EmailID, a.Filename, a.ContentType, a.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *emailRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]email.Email, error) {
+	query := `
+		UPDATE email_outbox SET attempts = attempts + 1,
+			next_attempt_at = NOW() + $2::float8 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id FROM email_outbox
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + emailColumns
+
+	emails := make([]email.Email, 0)
+	if err := r.db.SelectContext(ctx, &emails, query, limit, lease.Seconds()); err != nil {
+		return nil, err
+	}
+	if len(emails) == 0 {
+		return emails, nil
+	}
+
+	ids := make([]uuid.UUID, len(emails))
+	byID := make(map[uuid.UUID]*email.Email, len(emails))
+	for i := range emails {
+		ids[i] = emails[i].ID
+		byID[emails[i].ID] = &emails[i]
+	}
+
+	var attachments []email.Attachment
+	err := r.db.SelectContext(ctx, &attachments, `
+		SELECT email_id, filename, content_type, content
+		FROM email_attachments
+		WHERE email_id = ANY($1)
+		ORDER BY filename
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		e := byID[a.EmailID]
+		e.Attachments = append(e.Attachments, a)
+	}
+
+	return emails, nil
+}
+
+func (r *emailRepository) MarkSent(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE email_outbox SET status = 'sent', sent_at = NOW(), last_error = NULL WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *emailRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error {
+	query := `
+		UPDATE email_outbox SET last_error = $2,
+			status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($3::timestamptz, next_attempt_at)
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, lastError, retryAt)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/anigmaa/backend/internal/domain/event"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type eventRepository struct {
@@ -19,18 +20,39 @@ func NewEventRepository(db *sqlx.DB) event.Repository {
 	return &eventRepository{db: db}
 }
 
-func (r *eventRepository) Create(ctx context.Context, e *event.Event) error {
-	query := `
-		INSERT INTO events (id, host_id, title, description, category, start_time, end_time,
-			location_name, location_address, location_lat, location_lng, location_geom,
-			max_attendees, price, is_free, status, privacy, requirements, ticketing_enabled,
-			tickets_sold, is_archived, created_at, updated_at)
-		VALUES ($1::uuid, $2::uuid, $3, $4, $5::event_category, $6::timestamp with time zone, $7::timestamp with time zone,
-			$8, $9, $10::numeric, $11::numeric, ST_SetSRID(ST_MakePoint($11::numeric, $10::numeric), 4326),
-			$12::integer, $13::numeric, $14, $15::event_status, $16::event_privacy, $17,
-			$18, $19::integer, $20, $21::timestamp with time zone, $22::timestamp with time zone)
-	`
+// eventVisibleTo returns a WHERE condition (on alias e) that passes only the events
+// the viewer bound to param may see. It mirrors event.Event.CanView: public events,
+// the viewer's own events, events they attend, hold a ticket for or accepted an
+// invitation to, and friends_only events of hosts they mutually follow.
+func eventVisibleTo(param string) string {
+	return `(e.privacy = 'public' OR e.host_id = ` + param + `
+		OR EXISTS(SELECT 1 FROM invitations i
+			WHERE i.event_id = e.id AND i.invitee_id = ` + param + ` AND i.status = 'accepted')
+		OR EXISTS(SELECT 1 FROM event_attendees ea
+			WHERE ea.event_id = e.id AND ea.user_id = ` + param + ` AND ea.status IN ('pending', 'confirmed'))
+		OR EXISTS(SELECT 1 FROM tickets t
+			WHERE t.event_id = e.id AND t.user_id = ` + param + ` AND t.status IN ('pending', 'active'))
+		OR (e.privacy = 'friends_only'
+			AND EXISTS(SELECT 1 FROM follows f WHERE f.follower_id = ` + param + ` AND f.following_id = e.host_id)
+			AND EXISTS(SELECT 1 FROM follows f WHERE f.follower_id = e.host_id AND f.following_id = ` + param + `)))`
+}
 
+// eventInsertQuery inserts an event with the arguments of eventInsertArgs
+const eventInsertQuery = `
+	INSERT INTO events (id, host_id, title, description, category, start_time, end_time,
+		location_name, location_address, location_lat, location_lng, location_geom,
+		max_attendees, price, is_free, status, privacy, requirements, ticketing_enabled,
+		tickets_sold, is_archived, created_at, updated_at, requires_approval,
+		series_id, occurrence_start, series_override)
+	VALUES ($1::uuid, $2::uuid, $3, $4, $5::event_category, $6::timestamp with time zone, $7::timestamp with time zone,
+		$8, $9, $10::numeric, $11::numeric, ST_SetSRID(ST_MakePoint($11::numeric, $10::numeric), 4326),
+		$12::integer, $13::numeric, $14, $15::event_status, $16::event_privacy, $17,
+		$18, $19::integer, $20, $21::timestamp with time zone, $22::timestamp with time zone, $23,
+		$24, $25, $26)
+`
+
+// prepareEventInsert sets the fields of a new event and returns the arguments of eventInsertQuery
+func prepareEventInsert(e *event.Event) []interface{} {
 	e.ID = uuid.New()
 	e.CreatedAt = time.Now().UTC()
 	e.UpdatedAt = time.Now().UTC()
@@ -38,22 +60,29 @@ func (r *eventRepository) Create(ctx context.Context, e *event.Event) error {
 	e.TicketsSold = 0
 	e.IsArchived = false
 
-	_, err := r.db.ExecContext(ctx, query,
+	return []interface{}{
 		e.ID, e.HostID, e.Title, e.Description, e.Category, e.StartTime, e.EndTime,
 		e.LocationName, e.LocationAddress, e.LocationLat, e.LocationLng,
 		e.MaxAttendees, e.Price, e.IsFree, e.Status, e.Privacy, e.Requirements,
-		e.TicketingEnabled, e.TicketsSold, e.IsArchived, e.CreatedAt, e.UpdatedAt,
-	)
+		e.TicketingEnabled, e.TicketsSold, e.IsArchived, e.CreatedAt, e.UpdatedAt, e.RequiresApproval,
+		e.SeriesID, e.OccurrenceStart, e.SeriesOverride,
+	}
+}
 
+func (r *eventRepository) Create(ctx context.Context, e *event.Event) error {
+	_, err := r.db.ExecContext(ctx, eventInsertQuery, prepareEventInsert(e)...)
 	return err
 }
 
+// eventColumns are the columns of event.Event
+const eventColumns = `id, host_id, title, description, category, start_time, end_time,
+		location_name, location_address, ST_Y(location_geom::geometry) as location_lat, ST_X(location_geom::geometry) as location_lng, max_attendees,
+		price, is_free, status, privacy, requirements, ticketing_enabled, requires_approval, series_id, occurrence_start, series_override, calendar_sequence, tickets_sold, is_archived,
+		created_at, updated_at`
+
 func (r *eventRepository) GetByID(ctx context.Context, id uuid.UUID) (*event.Event, error) {
 	var e event.Event
-	query := `SELECT id, host_id, title, description, category, start_time, end_time,
-		location_name, location_address, ST_Y(location_geom::geometry) as location_lat, ST_X(location_geom::geometry) as location_lng, max_attendees,
-		price, is_free, status, privacy, requirements, ticketing_enabled, tickets_sold, is_archived,
-		created_at, updated_at FROM events WHERE id = $1`
+	query := `SELECT ` + eventColumns + ` FROM events WHERE id = $1`
 
 	err := r.db.GetContext(ctx, &e, query, id)
 	if err == sql.ErrNoRows {
@@ -68,13 +97,14 @@ func (r *eventRepository) GetWithDetails(ctx context.Context, eventID, userID uu
 		SELECT e.id, e.host_id, e.title, e.description, e.category, e.start_time, e.end_time,
 			e.location_name, e.location_address, ST_Y(e.location_geom::geometry) as location_lat, ST_X(e.location_geom::geometry) as location_lng,
 			e.max_attendees, e.price, e.is_free, e.status, e.privacy, e.requirements,
-			e.ticketing_enabled, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
+			e.ticketing_enabled, e.requires_approval, e.series_id, e.occurrence_start, e.series_override, e.calendar_sequence, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
 			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
 			u.name as host_name, u.avatar_url as host_avatar_url,
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as attendees_count,
 			EXISTS(SELECT 1 FROM event_attendees WHERE event_id = e.id AND user_id = $2 AND status = 'confirmed') as is_user_attending,
 			EXISTS(SELECT 1 FROM event_interests WHERE event_id = e.id AND user_id = $2) as is_user_interested,
-			(e.host_id = $2) as is_user_host
+			(e.host_id = $2) as is_user_host,
+			(SELECT status FROM event_attendees WHERE event_id = e.id AND user_id = $2) as user_attendance_status
 		FROM events e
 		INNER JOIN users u ON e.host_id = u.id
 		WHERE e.id = $1
@@ -96,25 +126,75 @@ func (r *eventRepository) GetWithDetails(ctx context.Context, eventID, userID uu
 	return &details, nil
 }
 
-func (r *eventRepository) Update(ctx context.Context, e *event.Event) error {
+// GetAccess gets a user's relationship to an event, used to apply its privacy
+func (r *eventRepository) GetAccess(ctx context.Context, eventID, userID uuid.UUID) (*event.Access, error) {
 	query := `
-		UPDATE events SET title = $1, description = $2, category = $3, start_time = $4,
-			end_time = $5, location_name = $6, location_address = $7, location_lat = $8,
-			location_lng = $9, location_geom = ST_SetSRID(ST_MakePoint($9, $8), 4326),
-			max_attendees = $10, price = $11, privacy = $12, requirements = $13,
-			status = $14, is_archived = $15, updated_at = $16
-		WHERE id = $17
+		SELECT
+			e.host_id = $2 as is_host,
+			(EXISTS(SELECT 1 FROM event_attendees ea
+				WHERE ea.event_id = e.id AND ea.user_id = $2 AND ea.status IN ('pending', 'confirmed'))
+			OR EXISTS(SELECT 1 FROM tickets t
+				WHERE t.event_id = e.id AND t.user_id = $2 AND t.status IN ('pending', 'active'))) as is_attendee,
+			(EXISTS(SELECT 1 FROM follows f WHERE f.follower_id = $2 AND f.following_id = e.host_id)
+			AND EXISTS(SELECT 1 FROM follows f WHERE f.follower_id = e.host_id AND f.following_id = $2)) as is_mutual_follower,
+			EXISTS(SELECT 1 FROM invitations i
+				WHERE i.event_id = e.id AND i.invitee_id = $2 AND i.status = 'accepted') as is_invited,
+			COALESCE((SELECT s.role FROM event_staff s WHERE s.event_id = e.id AND s.user_id = $2), '') as staff_role
+		FROM events e
+		WHERE e.id = $1
 	`
 
+	var access event.Access
+	err := r.db.QueryRowContext(ctx, query, eventID, userID).Scan(&access.IsHost, &access.IsAttendee, &access.IsMutualFollower, &access.IsInvited, &access.StaffRole)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("event not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &access, nil
+}
+
+// eventUpdateQuery saves an event. A changed capacity must still hold the
+// tickets sold, so a lowered max_attendees can't race a purchase.
+const eventUpdateQuery = `
+	UPDATE events SET title = $1, description = $2, category = $3, start_time = $4,
+		end_time = $5, location_name = $6, location_address = $7, location_lat = $8,
+		location_lng = $9, location_geom = ST_SetSRID(ST_MakePoint($9, $8), 4326),
+		max_attendees = $10, price = $11, privacy = $12, requirements = $13,
+		status = $14, is_archived = $15, updated_at = $16, requires_approval = $18,
+		series_override = $19
+	WHERE id = $17 AND (max_attendees = $10 OR tickets_sold <= $10)
+	RETURNING id
+`
+
+func prepareEventUpdate(e *event.Event) []interface{} {
 	e.UpdatedAt = time.Now()
-	_, err := r.db.ExecContext(ctx, query,
+	return []interface{}{
 		e.Title, e.Description, e.Category, e.StartTime, e.EndTime,
 		e.LocationName, e.LocationAddress, e.LocationLat, e.LocationLng,
 		e.MaxAttendees, e.Price, e.Privacy, e.Requirements, e.Status,
-		e.IsArchived, e.UpdatedAt, e.ID,
-	)
+		e.IsArchived, e.UpdatedAt, e.ID, e.RequiresApproval, e.SeriesOverride,
+	}
+}
 
-	return err
+func (r *eventRepository) Update(ctx context.Context, e *event.Event) error {
+	var id uuid.UUID
+	err := r.db.QueryRowxContext(ctx, eventUpdateQuery, prepareEventUpdate(e)...).Scan(&id)
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	// Nothing was saved: either the event is gone or the capacity guard held the edit back
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM events WHERE id = $1)`, e.ID); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	return event.ErrCapacityBelowSold
 }
 
 func (r *eventRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -123,12 +203,18 @@ func (r *eventRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// eventSearchQuery returns a tsquery expression for the search text bound to
+// parameter n, matched like the search endpoint does (see searchQueryCTE)
+func eventSearchQuery(n int) string {
+	return fmt.Sprintf(`websearch_to_tsquery('simple', $%[1]d) || websearch_to_tsquery('indonesian', $%[1]d) || websearch_to_tsquery('english', $%[1]d)`, n)
+}
+
 func (r *eventRepository) List(ctx context.Context, filter *event.EventFilter, userID uuid.UUID) ([]event.EventWithDetails, error) {
 	query := `
 		SELECT e.id, e.host_id, e.title, e.description, e.category, e.start_time, e.end_time,
 			e.location_name, e.location_address, ST_Y(e.location_geom::geometry) as location_lat, ST_X(e.location_geom::geometry) as location_lng,
 			e.max_attendees, e.price, e.is_free, e.status, e.privacy, e.requirements,
-			e.ticketing_enabled, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
+			e.ticketing_enabled, e.requires_approval, e.series_id, e.occurrence_start, e.series_override, e.calendar_sequence, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
 			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
 			u.name as host_name, u.avatar_url as host_avatar_url,
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as attendees_count,
@@ -137,7 +223,7 @@ func (r *eventRepository) List(ctx context.Context, filter *event.EventFilter, u
 		(e.host_id = $1) as is_user_host
 		FROM events e
 		INNER JOIN users u ON e.host_id = u.id
-		WHERE 1=1
+		WHERE ` + eventVisibleTo("$1") + `
 	`
 
 	args := []interface{}{userID}
@@ -159,6 +245,12 @@ func (r *eventRepository) List(ctx context.Context, filter *event.EventFilter, u
 		argCount++
 	}
 
+	if filter.Query != nil && *filter.Query != "" {
+		query += fmt.Sprintf(" AND e.search_vector @@ (%s)", eventSearchQuery(argCount))
+		args = append(args, *filter.Query)
+		argCount++
+	}
+
 	if filter.Category != nil {
 		query += fmt.Sprintf(" AND e.category = $%d", argCount)
 		args = append(args, *filter.Category)
@@ -177,6 +269,13 @@ func (r *eventRepository) List(ctx context.Context, filter *event.EventFilter, u
 		argCount++
 	}
 
+	// Keyset pagination, only meaningful for the default chronological order
+	if filter.Mode == "" && filter.BeforeCreatedAt != nil && filter.BeforeID != nil {
+		query += fmt.Sprintf(" AND (e.created_at, e.id) < ($%d, $%d)", argCount, argCount+1)
+		args = append(args, *filter.BeforeCreatedAt, *filter.BeforeID)
+		argCount += 2
+	}
+
 	// CTO REVIEW: Discovery mode algorithms need improvement
 	// All modes are missing the completed event filter (see line 126 comment)
 	// "chill" mode should also filter by price/free status and max_attendees < 30
@@ -215,10 +314,10 @@ func (r *eventRepository) List(ctx context.Context, filter *event.EventFilter, u
 			random()`
 
 	default:
-		// Default: Simple chronological with some randomness
+		// Default: Newest first, with a stable order so pages can be keyed by cursor
 		query += ` ORDER BY
 			e.created_at DESC,
-			random()`
+			e.id DESC`
 	}
 
 	if filter.Limit > 0 {
@@ -250,24 +349,24 @@ func (r *eventRepository) List(ctx context.Context, filter *event.EventFilter, u
 	return events, nil
 }
 
-func (r *eventRepository) GetByHost(ctx context.Context, hostID uuid.UUID, limit, offset int) ([]event.EventWithDetails, error) {
+func (r *eventRepository) GetByHost(ctx context.Context, hostID, viewerID uuid.UUID, limit, offset int) ([]event.EventWithDetails, error) {
 	query := `
 		SELECT e.id, e.host_id, e.title, e.description, e.category, e.start_time, e.end_time,
 			e.location_name, e.location_address, ST_Y(e.location_geom::geometry) as location_lat, ST_X(e.location_geom::geometry) as location_lng,
 			e.max_attendees, e.price, e.is_free, e.status, e.privacy, e.requirements,
-			e.ticketing_enabled, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
+			e.ticketing_enabled, e.requires_approval, e.series_id, e.occurrence_start, e.series_override, e.calendar_sequence, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
 			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
 			u.name as host_name, u.avatar_url as host_avatar_url,
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as attendees_count
 		FROM events e
 		INNER JOIN users u ON e.host_id = u.id
-		WHERE e.host_id = $1
+		WHERE e.host_id = $1 AND ` + eventVisibleTo("$4") + `
 		ORDER BY e.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
 	var events []event.EventWithDetails
-	err := r.db.SelectContext(ctx, &events, query, hostID, limit, offset)
+	err := r.db.SelectContext(ctx, &events, query, hostID, limit, offset, viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +387,7 @@ func (r *eventRepository) GetJoinedEvents(ctx context.Context, userID uuid.UUID,
 		SELECT e.id, e.host_id, e.title, e.description, e.category, e.start_time, e.end_time,
 			e.location_name, e.location_address, ST_Y(e.location_geom::geometry) as location_lat, ST_X(e.location_geom::geometry) as location_lng,
 			e.max_attendees, e.price, e.is_free, e.status, e.privacy, e.requirements,
-			e.ticketing_enabled, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
+			e.ticketing_enabled, e.requires_approval, e.series_id, e.occurrence_start, e.series_override, e.calendar_sequence, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
 			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
 			u.name as host_name, u.avatar_url as host_avatar_url,
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as attendees_count,
@@ -316,12 +415,12 @@ func (r *eventRepository) GetJoinedEvents(ctx context.Context, userID uuid.UUID,
 	return events, nil
 }
 
-func (r *eventRepository) GetNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]event.EventWithDetails, error) {
+func (r *eventRepository) GetNearby(ctx context.Context, lat, lng, radiusKm float64, limit int, viewerID uuid.UUID) ([]event.EventWithDetails, error) {
 	query := `
 		SELECT e.id, e.host_id, e.title, e.description, e.category, e.start_time, e.end_time,
 			e.location_name, e.location_address, ST_Y(e.location_geom::geometry) as location_lat, ST_X(e.location_geom::geometry) as location_lng,
 			e.max_attendees, e.price, e.is_free, e.status, e.privacy, e.requirements,
-			e.ticketing_enabled, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
+			e.ticketing_enabled, e.requires_approval, e.series_id, e.occurrence_start, e.series_override, e.calendar_sequence, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
 			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
 			u.name as host_name, u.avatar_url as host_avatar_url,
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as attendees_count,
@@ -332,6 +431,7 @@ func (r *eventRepository) GetNearby(ctx context.Context, lat, lng, radiusKm floa
 			AND e.status IN ('upcoming', 'ongoing')
 			AND e.end_time >= NOW()
 			AND e.created_at >= $5
+			AND ` + eventVisibleTo("$6") + `
 		ORDER BY (
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') + 1
 		) * random() DESC
@@ -339,7 +439,7 @@ func (r *eventRepository) GetNearby(ctx context.Context, lat, lng, radiusKm floa
 	`
 
 	var events []event.EventWithDetails
-	err := r.db.SelectContext(ctx, &events, query, lat, lng, radiusKm, limit, time.Now().AddDate(0, -3, 0))
+	err := r.db.SelectContext(ctx, &events, query, lat, lng, radiusKm, limit, time.Now().AddDate(0, -3, 0), viewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -353,17 +453,24 @@ func (r *eventRepository) GetNearby(ctx context.Context, lat, lng, radiusKm floa
 	return events, nil
 }
 
+// Join adds an attendee, confirmed unless a status is given. Joining again
+// (e.g. an approved attendee buying a ticket) updates the existing record.
 func (r *eventRepository) Join(ctx context.Context, attendee *event.EventAttendee) error {
 	query := `
-		INSERT INTO event_attendees (id, event_id, user_id, joined_at, status)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO event_attendees (id, event_id, user_id, joined_at, status, requirements_answer)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (event_id, user_id) DO UPDATE
+			SET status = EXCLUDED.status,
+				requirements_answer = COALESCE(EXCLUDED.requirements_answer, event_attendees.requirements_answer)
 	`
 
 	attendee.ID = uuid.New()
 	attendee.JoinedAt = time.Now()
-	attendee.Status = event.AttendeeConfirmed
+	if attendee.Status == "" {
+		attendee.Status = event.AttendeeConfirmed
+	}
 
-	_, err := r.db.ExecContext(ctx, query, attendee.ID, attendee.EventID, attendee.UserID, attendee.JoinedAt, attendee.Status)
+	_, err := r.db.ExecContext(ctx, query, attendee.ID, attendee.EventID, attendee.UserID, attendee.JoinedAt, attendee.Status, attendee.RequirementsAnswer)
 	return err
 }
 
@@ -373,6 +480,21 @@ func (r *eventRepository) Leave(ctx context.Context, eventID, userID uuid.UUID)
 	return err
 }
 
+func (r *eventRepository) GetAttendee(ctx context.Context, eventID, userID uuid.UUID) (*event.EventAttendee, error) {
+	query := `SELECT * FROM event_attendees WHERE event_id = $1 AND user_id = $2`
+
+	var attendee event.EventAttendee
+	err := r.db.GetContext(ctx, &attendee, query, eventID, userID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("attendee not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &attendee, nil
+}
+
 func (r *eventRepository) GetAttendees(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]event.EventAttendee, error) {
 	query := `
 		SELECT * FROM event_attendees
@@ -386,6 +508,19 @@ func (r *eventRepository) GetAttendees(ctx context.Context, eventID uuid.UUID, l
 	return attendees, err
 }
 
+func (r *eventRepository) GetAttendeeIDs(ctx context.Context, eventID, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT user_id FROM event_attendees
+		WHERE event_id = $1 AND status = 'confirmed' AND user_id > $2
+		ORDER BY user_id
+		LIMIT $3
+	`
+
+	var userIDs []uuid.UUID
+	err := r.db.SelectContext(ctx, &userIDs, query, eventID, after, limit)
+	return userIDs, err
+}
+
 func (r *eventRepository) IsAttending(ctx context.Context, eventID, userID uuid.UUID) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM event_attendees WHERE event_id = $1 AND user_id = $2 AND status = 'confirmed')`
 	var exists bool
@@ -400,6 +535,407 @@ func (r *eventRepository) GetAttendeesCount(ctx context.Context, eventID uuid.UU
 	return count, err
 }
 
+// GetJoinRequests gets the join requests of an event with the given status, oldest first
+// so hosts review them in the order they arrived
+func (r *eventRepository) GetJoinRequests(ctx context.Context, eventID uuid.UUID, status event.AttendeeStatus, limit, offset int) ([]event.JoinRequest, error) {
+	query := `
+		SELECT ea.id, ea.event_id, ea.user_id, ea.joined_at, ea.status, ea.requirements_answer, ea.responded_at,
+			u.name as user_name, u.username as user_username, u.avatar_url as user_avatar_url
+		FROM event_attendees ea
+		INNER JOIN users u ON ea.user_id = u.id
+		WHERE ea.event_id = $1 AND ea.status = $2
+		ORDER BY ea.joined_at ASC, ea.id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	requests := make([]event.JoinRequest, 0)
+	err := r.db.SelectContext(ctx, &requests, query, eventID, status, limit, offset)
+	return requests, err
+}
+
+func (r *eventRepository) CountJoinRequests(ctx context.Context, eventID uuid.UUID, status event.AttendeeStatus) (int, error) {
+	query := `SELECT COUNT(*) FROM event_attendees WHERE event_id = $1 AND status = $2`
+	var count int
+	err := r.db.GetContext(ctx, &count, query, eventID, status)
+	return count, err
+}
+
+// ReviewJoinRequests approves or rejects pending join requests in one statement
+func (r *eventRepository) ReviewJoinRequests(ctx context.Context, eventID uuid.UUID, userIDs []uuid.UUID, status event.AttendeeStatus) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Concurrent reviews of the same event wait here, so the capacity check
+	// below sees the confirmations made before it
+	var maxAttendees int
+	if err := tx.GetContext(ctx, &maxAttendees, `SELECT max_attendees FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return nil, err
+	}
+
+	if status == event.AttendeeConfirmed && maxAttendees > 0 {
+		// Only the requests still pending take a spot; confirmed or unknown users are skipped
+		var confirmed, pending int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FILTER (WHERE status = 'confirmed'),
+				COUNT(*) FILTER (WHERE status = 'pending' AND user_id = ANY($2))
+			FROM event_attendees
+			WHERE event_id = $1
+		`, eventID, pq.Array(userIDs)).Scan(&confirmed, &pending)
+		if err != nil {
+			return nil, err
+		}
+		if confirmed+pending > maxAttendees {
+			return nil, event.ErrEventFull
+		}
+	}
+
+	query := `
+		UPDATE event_attendees SET status = $3, responded_at = NOW()
+		WHERE event_id = $1 AND user_id = ANY($2) AND status = 'pending'
+		RETURNING user_id
+	`
+
+	reviewed := make([]uuid.UUID, 0)
+	if err := tx.SelectContext(ctx, &reviewed, query, eventID, pq.Array(userIDs), status); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reviewed, nil
+}
+
+func (r *eventRepository) SetStaff(ctx context.Context, s *event.Staff) error {
+	query := `
+		INSERT INTO event_staff (event_id, user_id, role, added_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (event_id, user_id) DO UPDATE SET role = EXCLUDED.role, added_by = EXCLUDED.added_by
+		RETURNING created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query, s.EventID, s.UserID, s.Role, s.AddedBy, time.Now()).Scan(&s.CreatedAt)
+}
+
+func (r *eventRepository) RemoveStaff(ctx context.Context, eventID, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM event_staff WHERE event_id = $1 AND user_id = $2`, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetStaff gets the staff of an event, in the order they were added
+func (r *eventRepository) GetStaff(ctx context.Context, eventID uuid.UUID) ([]event.StaffWithUser, error) {
+	query := `
+		SELECT s.event_id, s.user_id, s.role, s.added_by, s.created_at,
+			u.name as user_name, u.username as user_username, u.avatar_url as user_avatar_url
+		FROM event_staff s
+		INNER JOIN users u ON s.user_id = u.id
+		WHERE s.event_id = $1
+		ORDER BY s.created_at ASC
+	`
+
+	staff := make([]event.StaffWithUser, 0)
+	err := r.db.SelectContext(ctx, &staff, query, eventID)
+	return staff, err
+}
+
+func (r *eventRepository) CreateSeries(ctx context.Context, s *event.EventSeries) error {
+	query := `
+		INSERT INTO event_series (id, host_id, rrule, timezone, starts_at, exception_dates, generated_until, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+	`
+
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now().UTC()
+	s.UpdatedAt = s.CreatedAt
+
+	_, err := r.db.ExecContext(ctx, query, s.ID, s.HostID, s.RRule, s.Timezone, s.StartsAt,
+		pq.Array(s.ExceptionDates), s.GeneratedUntil, s.CreatedAt)
+	return err
+}
+
+const seriesColumns = `id, host_id, rrule, timezone, starts_at, exception_dates, generated_until, created_at, updated_at`
+
+func scanSeries(row interface{ Scan(...interface{}) error }, s *event.EventSeries) error {
+	return row.Scan(&s.ID, &s.HostID, &s.RRule, &s.Timezone, &s.StartsAt,
+		pq.Array(&s.ExceptionDates), &s.GeneratedUntil, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (r *eventRepository) GetSeries(ctx context.Context, id uuid.UUID) (*event.EventSeries, error) {
+	query := `SELECT ` + seriesColumns + ` FROM event_series WHERE id = $1`
+
+	var s event.EventSeries
+	err := scanSeries(r.db.QueryRowContext(ctx, query, id), &s)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("event series not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+const seriesUpdateQuery = `
+	UPDATE event_series SET rrule = $2, timezone = $3, starts_at = $4, exception_dates = $5,
+		generated_until = $6, updated_at = $7
+	WHERE id = $1
+`
+
+func prepareSeriesUpdate(s *event.EventSeries) []interface{} {
+	s.UpdatedAt = time.Now().UTC()
+	return []interface{}{s.ID, s.RRule, s.Timezone, s.StartsAt, pq.Array(s.ExceptionDates), s.GeneratedUntil, s.UpdatedAt}
+}
+
+func (r *eventRepository) UpdateSeries(ctx context.Context, s *event.EventSeries) error {
+	_, err := r.db.ExecContext(ctx, seriesUpdateQuery, prepareSeriesUpdate(s)...)
+	return err
+}
+
+func (r *eventRepository) GetSeriesToExtend(ctx context.Context, until time.Time) ([]event.EventSeries, error) {
+	query := `SELECT ` + seriesColumns + ` FROM event_series WHERE generated_until < $1 ORDER BY generated_until`
+
+	rows, err := r.db.QueryContext(ctx, query, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	series := make([]event.EventSeries, 0)
+	for rows.Next() {
+		var s event.EventSeries
+		if err := scanSeries(rows, &s); err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+
+	return series, rows.Err()
+}
+
+func (r *eventRepository) GetSeriesOccurrences(ctx context.Context, seriesID uuid.UUID, from time.Time, limit int) ([]event.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events
+		WHERE series_id = $1 AND end_time >= $2
+		ORDER BY start_time, id
+		LIMIT $3`
+
+	occurrences := make([]event.Event, 0)
+	err := r.db.SelectContext(ctx, &occurrences, query, seriesID, from, limit)
+	return occurrences, err
+}
+
+func (r *eventRepository) GetSeriesTemplate(ctx context.Context, seriesID uuid.UUID) (*event.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events
+		WHERE series_id = $1 AND NOT series_override AND status <> 'cancelled'
+		ORDER BY occurrence_start DESC
+		LIMIT 1`
+
+	var e event.Event
+	err := r.db.GetContext(ctx, &e, query, seriesID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("event series has no occurrence to copy")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// occurrenceTicketingQuery copies the ticket tiers and promo codes of event $1 to
+// event $2, moving their sale and validity windows by $3 seconds. The tiers get
+// new IDs up front so promo codes restricted to a tier follow it.
+const occurrenceTicketingQuery = `
+	WITH tiers AS (
+		SELECT uuid_generate_v4() AS new_id, t.* FROM ticket_tiers t WHERE t.event_id = $1
+	), copied_tiers AS (
+		INSERT INTO ticket_tiers (id, event_id, name, description, price, quota, sold,
+			sales_start, sales_end, max_per_user, sort_order)
+		SELECT new_id, $2, name, description, price, quota, 0,
+			sales_start + $3::float8 * INTERVAL '1 second', sales_end + $3::float8 * INTERVAL '1 second',
+			max_per_user, sort_order
+		FROM tiers
+	)
+	INSERT INTO promo_codes (event_id, tier_id, code, discount_type, discount_value,
+		max_uses, max_uses_per_user, valid_from, valid_until, is_active)
+	SELECT $2, tiers.new_id, p.code, p.discount_type, p.discount_value,
+		p.max_uses, p.max_uses_per_user,
+		p.valid_from + $3::float8 * INTERVAL '1 second', p.valid_until + $3::float8 * INTERVAL '1 second', p.is_active
+	FROM promo_codes p
+	LEFT JOIN tiers ON tiers.id = p.tier_id
+	WHERE p.event_id = $1
+`
+
+// CreateOccurrences inserts the occurrences, with the template's ticket tiers and
+// promo codes, in one transaction. The series row is locked so concurrent
+// generation runs can't insert the same slot twice.
+func (r *eventRepository) CreateOccurrences(ctx context.Context, seriesID uuid.UUID, template *event.Event, occurrences []event.Event) ([]event.Event, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM event_series WHERE id = $1 FOR UPDATE`, seriesID); err != nil {
+		return nil, err
+	}
+
+	var existing []time.Time
+	if err := tx.SelectContext(ctx, &existing, `SELECT occurrence_start FROM events WHERE series_id = $1`, seriesID); err != nil {
+		return nil, err
+	}
+	taken := make(map[int64]bool, len(existing))
+	for _, t := range existing {
+		taken[t.Unix()] = true
+	}
+
+	created := make([]event.Event, 0, len(occurrences))
+	for _, e := range occurrences {
+		if e.OccurrenceStart == nil || taken[e.OccurrenceStart.Unix()] {
+			continue
+		}
+		taken[e.OccurrenceStart.Unix()] = true
+
+		e.SeriesID = &seriesID
+		if _, err := tx.ExecContext(ctx, eventInsertQuery, prepareEventInsert(&e)...); err != nil {
+			return nil, err
+		}
+
+		// Without its tiers, an occurrence of a tiered event would sell free tickets
+		offset := e.StartTime.Sub(template.StartTime).Seconds()
+		if _, err := tx.ExecContext(ctx, occurrenceTicketingQuery, template.ID, e.ID, offset); err != nil {
+			return nil, err
+		}
+		created = append(created, e)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *eventRepository) UpdateSeriesEvent(ctx context.Context, e *event.Event, series *event.EventSeries, shift time.Duration, keep []time.Time) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// The series row serializes series-wide edits and occurrence generation
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM event_series WHERE id = $1 FOR UPDATE`, series.ID); err != nil {
+		return nil, err
+	}
+
+	// Locking the occurrences about to change makes purchases wait, so tickets_sold
+	// can't grow past the new capacity after this check
+	var oversold bool
+	err = tx.GetContext(ctx, &oversold, `
+		SELECT EXISTS(
+			SELECT 1 FROM (
+				SELECT tickets_sold FROM events
+				WHERE id = $2
+					OR (series_id = $1 AND NOT series_override AND status <> 'cancelled' AND start_time > NOW())
+				FOR UPDATE
+			) locked
+			WHERE tickets_sold > $3
+		)
+	`, series.ID, e.ID, e.MaxAttendees)
+	if err != nil {
+		return nil, err
+	}
+	if oversold {
+		return nil, event.ErrCapacityBelowSold
+	}
+
+	if _, err := tx.ExecContext(ctx, eventUpdateQuery, prepareEventUpdate(e)...); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, seriesUpdateQuery, prepareSeriesUpdate(series)...); err != nil {
+		return nil, err
+	}
+
+	// Occurrences keep their own date; only the time of day and length change
+	query := `
+		UPDATE events SET title = $3, description = $4, category = $5,
+			location_name = $6, location_address = $7, location_lat = $8, location_lng = $9,
+			location_geom = ST_SetSRID(ST_MakePoint($9, $8), 4326),
+			max_attendees = $10, price = $11, privacy = $12, requirements = $13, requires_approval = $14,
+			start_time = start_time + $15::float8 * INTERVAL '1 second',
+			end_time = start_time + ($15::float8 + $16::float8) * INTERVAL '1 second',
+			updated_at = NOW()
+		WHERE series_id = $1 AND id <> $2 AND NOT series_override AND status <> 'cancelled' AND start_time > NOW()
+		RETURNING id
+	`
+
+	updated := make([]uuid.UUID, 0)
+	err = tx.SelectContext(ctx, &updated, query, e.SeriesID, e.ID,
+		e.Title, e.Description, e.Category, e.LocationName, e.LocationAddress, e.LocationLat, e.LocationLng,
+		e.MaxAttendees, e.Price, e.Privacy, e.Requirements, e.RequiresApproval,
+		shift.Seconds(), e.EndTime.Sub(e.StartTime).Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if shift != 0 {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE events SET occurrence_start = occurrence_start + $2::float8 * INTERVAL '1 second'
+			WHERE series_id = $1
+		`, e.SeriesID, shift.Seconds())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if keep != nil {
+		if err := pruneSeriesOccurrences(ctx, tx, series.ID, keep); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// pruneSeriesOccurrences removes the upcoming occurrences of a series whose slot
+// isn't in keep. Ones with attendees or tickets are detached (series_override) instead.
+func pruneSeriesOccurrences(ctx context.Context, tx *sqlx.Tx, seriesID uuid.UUID, keep []time.Time) error {
+	// Occurrences nobody signed up for yet are removed
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM events e
+		WHERE e.series_id = $1 AND NOT e.series_override AND e.start_time > NOW()
+			AND NOT (e.occurrence_start = ANY($2::timestamptz[]))
+			AND e.tickets_sold = 0
+			AND NOT EXISTS(SELECT 1 FROM event_attendees ea WHERE ea.event_id = e.id AND ea.status IN ('pending', 'confirmed'))
+			AND NOT EXISTS(SELECT 1 FROM tickets t WHERE t.event_id = e.id)
+	`, seriesID, pq.Array(keep))
+	if err != nil {
+		return err
+	}
+
+	// The others are left to the host
+	_, err = tx.ExecContext(ctx, `
+		UPDATE events SET series_override = TRUE, updated_at = NOW()
+		WHERE series_id = $1 AND NOT series_override AND start_time > NOW()
+			AND NOT (occurrence_start = ANY($2::timestamptz[]))
+	`, seriesID, pq.Array(keep))
+	return err
+}
+
 func (r *eventRepository) AddImages(ctx context.Context, images []event.EventImage) error {
 	query := `INSERT INTO event_images (id, event_id, image_url, order_index) VALUES ($1, $2, $3, $4)`
 
@@ -440,12 +976,80 @@ func (r *eventRepository) UpdateStatus(ctx context.Context, eventID uuid.UUID, s
 	return err
 }
 
+func (r *eventRepository) TransitionStatuses(ctx context.Context, now time.Time) ([]event.StatusTransition, error) {
+	// Cancelled and ended events are never moved; an event can go from upcoming
+	// straight to ended if it was missed while it was ongoing
+	query := `
+		WITH due AS (
+			SELECT id AS event_id, status AS previous_status,
+				CASE
+					WHEN end_time <= $1 THEN 'ended'::event_status
+					WHEN start_time <= $1 THEN 'ongoing'::event_status
+					ELSE 'upcoming'::event_status
+				END AS new_status
+			FROM events
+			WHERE status IN ('upcoming', 'ongoing')
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE events SET status = due.new_status, updated_at = $1
+		FROM due
+		WHERE events.id = due.event_id AND due.new_status <> due.previous_status
+		RETURNING ` + eventColumns + `, due.previous_status
+	`
+
+	transitions := make([]event.StatusTransition, 0)
+	if err := r.db.SelectContext(ctx, &transitions, query, now); err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
+
+func (r *eventRepository) ClaimReminders(ctx context.Context, kind event.ReminderKind, from, until time.Time) ([]event.Event, error) {
+	query := `
+		WITH claimed AS (
+			INSERT INTO event_reminders (event_id, kind, start_time)
+			SELECT id, $1, start_time
+			FROM events
+			WHERE status = 'upcoming' AND start_time > $2 AND start_time <= $3
+			ON CONFLICT DO NOTHING
+			RETURNING event_id
+		)
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE id IN (SELECT event_id FROM claimed)
+		ORDER BY start_time
+	`
+
+	events := make([]event.Event, 0)
+	if err := r.db.SelectContext(ctx, &events, query, kind, from, until); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *eventRepository) GetReminderRecipients(ctx context.Context, eventID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT user_id FROM tickets WHERE event_id = $1 AND status = 'active'
+		UNION
+		SELECT user_id FROM event_attendees WHERE event_id = $1 AND status = 'confirmed'
+	`
+
+	userIDs := make([]uuid.UUID, 0)
+	if err := r.db.SelectContext(ctx, &userIDs, query, eventID); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
 func (r *eventRepository) GetUpcomingEvents(ctx context.Context, limit int) ([]event.EventWithDetails, error) {
 	query := `
 		SELECT e.id, e.host_id, e.title, e.description, e.category, e.start_time, e.end_time,
 			e.location_name, e.location_address, ST_Y(e.location_geom::geometry) as location_lat, ST_X(e.location_geom::geometry) as location_lng,
 			e.max_attendees, e.price, e.is_free, e.status, e.privacy, e.requirements,
-			e.ticketing_enabled, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
+			e.ticketing_enabled, e.requires_approval, e.series_id, e.occurrence_start, e.series_override, e.calendar_sequence, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
 			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
 			u.name as host_name, u.avatar_url as host_avatar_url,
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as attendees_count
@@ -454,6 +1058,7 @@ func (r *eventRepository) GetUpcomingEvents(ctx context.Context, limit int) ([]e
 		WHERE e.status IN ('upcoming', 'ongoing')
 			AND e.end_time >= NOW()
 			AND e.created_at >= $2
+			AND e.privacy = 'public'
 		ORDER BY (
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') + 1
 		) * random() DESC
@@ -480,7 +1085,7 @@ func (r *eventRepository) GetLiveEvents(ctx context.Context, limit int) ([]event
 		SELECT e.id, e.host_id, e.title, e.description, e.category, e.start_time, e.end_time,
 			e.location_name, e.location_address, ST_Y(e.location_geom::geometry) as location_lat, ST_X(e.location_geom::geometry) as location_lng,
 			e.max_attendees, e.price, e.is_free, e.status, e.privacy, e.requirements,
-			e.ticketing_enabled, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
+			e.ticketing_enabled, e.requires_approval, e.series_id, e.occurrence_start, e.series_override, e.calendar_sequence, e.tickets_sold, e.is_archived, e.created_at, e.updated_at,
 			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
 			u.name as host_name, u.avatar_url as host_avatar_url,
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as attendees_count
@@ -490,6 +1095,7 @@ func (r *eventRepository) GetLiveEvents(ctx context.Context, limit int) ([]event
 			AND e.start_time <= NOW()
 			AND e.end_time >= NOW()
 			AND e.created_at >= $2
+			AND e.privacy = 'public'
 		ORDER BY (
 			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') + 1
 		) * random() DESC
@@ -517,7 +1123,7 @@ func (r *eventRepository) GetByHostID(ctx context.Context, hostID uuid.UUID) ([]
 		SELECT id, host_id, title, description, category, start_time, end_time,
 			location_name, location_address, ST_Y(location_geom::geometry) as location_lat, ST_X(location_geom::geometry) as location_lng,
 			max_attendees, price, is_free, status, privacy, requirements,
-			ticketing_enabled, tickets_sold, is_archived, created_at, updated_at
+			ticketing_enabled, requires_approval, series_id, occurrence_start, series_override, calendar_sequence, tickets_sold, is_archived, created_at, updated_at
 		FROM events
 		WHERE host_id = $1
 		ORDER BY start_time DESC
@@ -532,11 +1138,11 @@ func (r *eventRepository) GetByHostID(ctx context.Context, hostID uuid.UUID) ([]
 	return events, nil
 }
 
-// CountEvents counts total events matching filter
-func (r *eventRepository) CountEvents(ctx context.Context, filter *event.EventFilter) (int, error) {
-	query := `SELECT COUNT(*) FROM events WHERE 1=1`
-	args := []interface{}{}
-	argCount := 1
+// CountEvents counts total events matching filter that the viewer may see
+func (r *eventRepository) CountEvents(ctx context.Context, filter *event.EventFilter, viewerID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM events e WHERE ` + eventVisibleTo("$1")
+	args := []interface{}{viewerID}
+	argCount := 2
 
 	// Strict filtering: Only show active events
 	// Exclude events that are: ended, cancelled, or have passed their end time
@@ -554,6 +1160,12 @@ func (r *eventRepository) CountEvents(ctx context.Context, filter *event.EventFi
 		argCount++
 	}
 
+	if filter.Query != nil && *filter.Query != "" {
+		query += fmt.Sprintf(" AND search_vector @@ (%s)", eventSearchQuery(argCount))
+		args = append(args, *filter.Query)
+		argCount++
+	}
+
 	if filter.Category != nil {
 		query += fmt.Sprintf(" AND category = $%d", argCount)
 		args = append(args, *filter.Category)
@@ -585,11 +1197,11 @@ func (r *eventRepository) CountEvents(ctx context.Context, filter *event.EventFi
 	return count, err
 }
 
-// CountHostedEvents counts total events by a host
-func (r *eventRepository) CountHostedEvents(ctx context.Context, hostID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM events WHERE host_id = $1`
+// CountHostedEvents counts total events by a host that the viewer may see
+func (r *eventRepository) CountHostedEvents(ctx context.Context, hostID, viewerID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM events e WHERE e.host_id = $1 AND ` + eventVisibleTo("$2")
 	var count int
-	err := r.db.QueryRowContext(ctx, query, hostID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, hostID, viewerID).Scan(&count)
 	return count, err
 }
 
@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/invitation"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type invitationRepository struct {
+	db *sqlx.DB
+}
+
+// NewInvitationRepository creates a new event invitation repository
+func NewInvitationRepository(db *sqlx.DB) invitation.Repository {
+	return &invitationRepository{db: db}
+}
+
+const invitationColumns = `i.id, i.event_id, i.inviter_id, i.invitee_id, i.invite_link_id, i.status, i.invited_at, i.responded_at`
+
+const invitationDetailsQuery = `
+	SELECT ` + invitationColumns + `,
+		e.title as event_title, e.start_time as event_start_time,
+		inviter.name as inviter_name,
+		invitee.name as invitee_name, invitee.username as invitee_username, invitee.avatar_url as invitee_avatar_url
+	FROM invitations i
+	INNER JOIN events e ON i.event_id = e.id
+	INNER JOIN users inviter ON i.inviter_id = inviter.id
+	INNER JOIN users invitee ON i.invitee_id = invitee.id
+`
+
+// CreateInvitations inserts pending invitations. Declined and revoked invitations
+// are re-sent; pending and accepted ones are left untouched and not returned.
+func (r *invitationRepository) CreateInvitations(ctx context.Context, invitations []invitation.Invitation) ([]invitation.Invitation, error) {
+	query := `
+		INSERT INTO invitations (id, inviter_id, invitee_id, event_id, status, invited_at)
+		VALUES ($1, $2, $3, $4, 'pending', $5)
+		ON CONFLICT (inviter_id, invitee_id, event_id) DO UPDATE
+			SET status = 'pending', invited_at = EXCLUDED.invited_at, responded_at = NULL, invite_link_id = NULL
+			WHERE invitations.status IN ('declined', 'revoked')
+		RETURNING id, event_id, inviter_id, invitee_id, invite_link_id, status, invited_at, responded_at
+	`
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now()
+	created := make([]invitation.Invitation, 0, len(invitations))
+	for _, inv := range invitations {
+		var stored invitation.Invitation
+		err := tx.GetContext(ctx, &stored, query, uuid.New(), inv.InviterID, inv.InviteeID, inv.EventID, now)
+		if err == sql.ErrNoRows {
+			// Already invited
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, stored)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*invitation.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitations i WHERE i.id = $1`
+
+	var inv invitation.Invitation
+	err := r.db.GetContext(ctx, &inv, query, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invitation not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+// GetEventInvitations gets the invitations of an event, newest first
+func (r *invitationRepository) GetEventInvitations(ctx context.Context, eventID uuid.UUID, status *invitation.Status, limit, offset int) ([]invitation.InvitationWithDetails, error) {
+	query := invitationDetailsQuery + `
+		WHERE i.event_id = $1 AND ($2::varchar IS NULL OR i.status = $2)
+		ORDER BY i.invited_at DESC, i.id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	invitations := make([]invitation.InvitationWithDetails, 0)
+	err := r.db.SelectContext(ctx, &invitations, query, eventID, status, limit, offset)
+	return invitations, err
+}
+
+func (r *invitationRepository) CountEventInvitations(ctx context.Context, eventID uuid.UUID, status *invitation.Status) (int, error) {
+	query := `SELECT COUNT(*) FROM invitations WHERE event_id = $1 AND ($2::varchar IS NULL OR status = $2)`
+	var count int
+	err := r.db.QueryRowContext(ctx, query, eventID, status).Scan(&count)
+	return count, err
+}
+
+// GetUserInvitations gets the invitations a user received, newest first
+func (r *invitationRepository) GetUserInvitations(ctx context.Context, userID uuid.UUID, status *invitation.Status, limit, offset int) ([]invitation.InvitationWithDetails, error) {
+	query := invitationDetailsQuery + `
+		WHERE i.invitee_id = $1 AND ($2::varchar IS NULL OR i.status = $2)
+		ORDER BY i.invited_at DESC, i.id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	invitations := make([]invitation.InvitationWithDetails, 0)
+	err := r.db.SelectContext(ctx, &invitations, query, userID, status, limit, offset)
+	return invitations, err
+}
+
+func (r *invitationRepository) CountUserInvitations(ctx context.Context, userID uuid.UUID, status *invitation.Status) (int, error) {
+	query := `SELECT COUNT(*) FROM invitations WHERE invitee_id = $1 AND event_id IS NOT NULL AND ($2::varchar IS NULL OR status = $2)`
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID, status).Scan(&count)
+	return count, err
+}
+
+func (r *invitationRepository) Respond(ctx context.Context, id uuid.UUID, status invitation.Status) error {
+	query := `UPDATE invitations SET status = $2, responded_at = $3 WHERE id = $1 AND status = 'pending'`
+	return r.execUpdate(ctx, query, id, status, time.Now())
+}
+
+func (r *invitationRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE invitations SET status = 'revoked' WHERE id = $1 AND status IN ('pending', 'accepted')`
+	return r.execUpdate(ctx, query, id)
+}
+
+func (r *invitationRepository) CreateLink(ctx context.Context, link *invitation.InviteLink) error {
+	query := `
+		INSERT INTO event_invite_links (id, event_id, created_by, token, max_uses, used_count, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $7)
+	`
+
+	link.ID = uuid.New()
+	link.UsedCount = 0
+	link.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, query, link.ID, link.EventID, link.CreatedBy, link.Token, link.MaxUses, link.ExpiresAt, link.CreatedAt)
+	return err
+}
+
+func (r *invitationRepository) GetLinkByID(ctx context.Context, id uuid.UUID) (*invitation.InviteLink, error) {
+	var link invitation.InviteLink
+	err := r.db.GetContext(ctx, &link, `SELECT * FROM event_invite_links WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invite link not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *invitationRepository) GetLinkByToken(ctx context.Context, token string) (*invitation.InviteLink, error) {
+	var link invitation.InviteLink
+	err := r.db.GetContext(ctx, &link, `SELECT * FROM event_invite_links WHERE token = $1`, token)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("invite link not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetEventLinks gets all invite links of an event, newest first
+func (r *invitationRepository) GetEventLinks(ctx context.Context, eventID uuid.UUID) ([]invitation.InviteLink, error) {
+	links := make([]invitation.InviteLink, 0)
+	err := r.db.SelectContext(ctx, &links, `SELECT * FROM event_invite_links WHERE event_id = $1 ORDER BY created_at DESC`, eventID)
+	return links, err
+}
+
+func (r *invitationRepository) RevokeLink(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE event_invite_links SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`
+	return r.execUpdate(ctx, query, id, time.Now())
+}
+
+// RedeemLink takes one use of the link and upserts the user's invitation as accepted,
+// in one transaction so a use is never consumed without granting access.
+func (r *invitationRepository) RedeemLink(ctx context.Context, link *invitation.InviteLink, inviteeID uuid.UUID) (*invitation.Invitation, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// The usage cap is checked under the row lock taken by the update
+	result, err := tx.ExecContext(ctx, `
+		UPDATE event_invite_links SET used_count = used_count + 1
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+			AND (max_uses IS NULL OR used_count < max_uses)
+	`, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return nil, invitation.ErrInviteLinkExhausted
+	}
+
+	// A revoked invitation stays revoked: the update is skipped and nothing is
+	// returned, and rolling back gives the redemption back to the link
+	var inv invitation.Invitation
+	now := time.Now()
+	err = tx.GetContext(ctx, &inv, `
+		INSERT INTO invitations (id, inviter_id, invitee_id, event_id, status, invite_link_id, invited_at, responded_at)
+		VALUES ($1, $2, $3, $4, 'accepted', $5, $6, $6)
+		ON CONFLICT (inviter_id, invitee_id, event_id) DO UPDATE
+			SET status = 'accepted', invite_link_id = EXCLUDED.invite_link_id, responded_at = EXCLUDED.responded_at
+			WHERE invitations.status <> 'revoked'
+		RETURNING id, event_id, inviter_id, invitee_id, invite_link_id, status, invited_at, responded_at
+	`, uuid.New(), link.CreatedBy, inviteeID, link.EventID, link.ID, now)
+	if err == sql.ErrNoRows {
+		return nil, invitation.ErrInvitationRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	link.UsedCount++
+	return &inv, nil
+}
+
+// execUpdate runs an update and returns sql.ErrNoRows when it matched no row
+func (r *invitationRepository) execUpdate(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/notification"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type notificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sqlx.DB) notification.Repository {
+	return &notificationRepository{db: db}
+}
+
+// Create creates a new notification
+func (r *notificationRepository) Create(ctx context.Context, n *notification.Notification) error {
+	// Generate UUID if not provided
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+
+	// Set created_at if not provided
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+
+	// JSONB must be sent as text; a raw []byte would be encoded as bytea
+	var metadata interface{}
+	if len(n.Metadata) > 0 {
+		metadata = string(n.Metadata)
+	}
+
+	query := `
+		INSERT INTO notifications (id, user_id, actor_id, type, title, message, link, metadata, is_read, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		n.ID, n.UserID, n.ActorID, n.Type, n.Title, n.Message, n.Link, metadata, n.IsRead, n.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByID gets a notification by ID
+func (r *notificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*notification.Notification, error) {
+	query := `
+		SELECT id, user_id, actor_id, type, title, message, link,
+			COALESCE(metadata, '{}'::jsonb) as metadata,
+			COALESCE(is_read, FALSE) as is_read,
+			created_at
+		FROM notifications
+		WHERE id = $1
+	`
+
+	var n notification.Notification
+	err := r.db.GetContext(ctx, &n, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// ListByUser lists a user's notifications, newest first.
+// Pagination uses (created_at, id) as a keyset so new notifications arriving
+// between requests never shift or duplicate items on the next page.
+func (r *notificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, filter *notification.NotificationFilter) ([]notification.NotificationWithActor, error) {
+	query := `
+		SELECT
+			n.id, n.user_id, n.actor_id, n.type, n.title, n.message, n.link,
+			COALESCE(n.metadata, '{}'::jsonb) as metadata,
+			COALESCE(n.is_read, FALSE) as is_read,
+			n.created_at,
+			u.name as actor_name,
+			u.username as actor_username,
+			u.avatar_url as actor_avatar_url
+		FROM notifications n
+		LEFT JOIN users u ON n.actor_id = u.id
+		WHERE n.user_id = $1
+			AND ($2::boolean = FALSE OR COALESCE(n.is_read, FALSE) = FALSE)
+			AND ($3::timestamptz IS NULL OR (n.created_at, n.id) < ($3::timestamptz, $4::uuid))
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $5
+	`
+
+	var notifications []notification.NotificationWithActor
+	err := r.db.SelectContext(ctx, &notifications, query,
+		userID, filter.UnreadOnly, filter.BeforeCreatedAt, filter.BeforeID, filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if notifications == nil {
+		notifications = []notification.NotificationWithActor{}
+	}
+
+	return notifications, nil
+}
+
+// MarkAsRead marks a single notification as read for its recipient
+func (r *notificationRepository) MarkAsRead(ctx context.Context, id, userID uuid.UUID) error {
+	query := `UPDATE notifications SET is_read = TRUE WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// MarkAllAsRead marks every unread notification of a user as read
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `UPDATE notifications SET is_read = TRUE WHERE user_id = $1 AND is_read = FALSE`
+
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(rowsAffected), nil
+}
+
+// CountUnread counts unread notifications for a user
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE`
+
+	var count int
+	err := r.db.GetContext(ctx, &count, query, userID)
+	return count, err
+}
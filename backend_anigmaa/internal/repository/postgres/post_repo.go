@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type postRepository struct {
@@ -20,6 +22,53 @@ func NewPostRepository(db *sqlx.DB) post.Repository {
 	return &postRepository{db: db}
 }
 
+// postVisibleTo returns a WHERE condition (on alias p) that passes only the posts
+// the viewer bound to param may see: public posts, followers-only posts of
+// authors the viewer follows, and all of the viewer's own posts
+func postVisibleTo(param string) string {
+	return `(p.visibility = 'public' OR p.author_id = ` + param + `
+		OR (p.visibility = 'followers' AND EXISTS(
+			SELECT 1 FROM follows f WHERE f.follower_id = ` + param + ` AND f.following_id = p.author_id)))`
+}
+
+// discoveryPoolSize caps the discovery posts mixed into a home timeline, so they
+// never crowd out the posts of followed authors
+const discoveryPoolSize = 100
+
+// feedPostColumns is the post, author and attached event column list read by
+// scanFeedPosts, for the viewer bound to $1
+const feedPostColumns = `
+			p.id, p.author_id, p.content, p.type, p.attached_event_id,
+			p.original_post_id, p.visibility, p.created_at, p.updated_at,
+			p.likes_count, p.comments_count, p.reposts_count, p.shares_count, p.is_archived,
+			u.name as author_name, u.avatar_url as author_avatar_url, u.is_verified as author_is_verified,
+			EXISTS(SELECT 1 FROM likes WHERE user_id = $1 AND likeable_type = 'post' AND likeable_id = p.id) as is_liked_by_user,
+			EXISTS(SELECT 1 FROM bookmarks WHERE user_id = $1 AND post_id = p.id) as is_bookmarked_by_user,
+			EXISTS(SELECT 1 FROM reposts WHERE user_id = $1 AND post_id = p.id) as is_reposted_by_user,
+			COALESCE(
+				(SELECT json_agg(image_url ORDER BY order_index)
+				 FROM post_images WHERE post_id = p.id), '[]'::json
+			) as image_urls,
+			ARRAY(SELECT h.tag FROM post_hashtags ph INNER JOIN hashtags h ON ph.hashtag_id = h.id
+				WHERE ph.post_id = p.id ORDER BY h.tag) as hashtags,
+			ARRAY(SELECT mu.username FROM post_mentions pm INNER JOIN users mu ON pm.user_id = mu.id
+				WHERE pm.post_id = p.id AND mu.username IS NOT NULL ORDER BY mu.username) as mentions,
+			e.id as event_id, e.title as event_title, e.description as event_description,
+			e.category as event_category, e.start_time as event_start_time, e.end_time as event_end_time,
+			e.location_name as event_location_name, e.location_address as event_location_address,
+			e.location_lat as event_location_lat, e.location_lng as event_location_lng,
+			e.host_id as event_host_id, eh.name as event_host_name, eh.avatar_url as event_host_avatar_url,
+			e.max_attendees as event_max_attendees,
+			(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as event_attendees_count,
+			(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
+			EXISTS(SELECT 1 FROM event_interests WHERE event_id = e.id AND user_id = $1) as event_is_interested,
+			e.price as event_price, e.is_free as event_is_free,
+			e.status as event_status, e.privacy as event_privacy,
+			COALESCE(
+				(SELECT json_agg(image_url ORDER BY order_index)
+				 FROM event_images WHERE event_id = e.id), '[]'::json
+			) as event_image_urls`
+
 // Create creates a new post
 func (r *postRepository) Create(ctx context.Context, p *post.Post) error {
 	// Generate UUID if not provided
@@ -67,6 +116,25 @@ func (r *postRepository) GetByID(ctx context.Context, postID uuid.UUID) (*post.P
 	return &p, nil
 }
 
+// GetVisible gets a post by ID if the viewer may see it
+func (r *postRepository) GetVisible(ctx context.Context, postID, viewerID uuid.UUID) (*post.Post, error) {
+	query := `
+		SELECT p.id, p.author_id, p.content, p.type, p.attached_event_id, p.original_post_id,
+		       p.visibility, p.created_at, p.updated_at, p.likes_count, p.comments_count,
+		       p.reposts_count, p.shares_count, p.is_archived
+		FROM posts p
+		WHERE p.id = $1 AND ` + postVisibleTo("$2") + `
+	`
+
+	var p post.Post
+	err := r.db.GetContext(ctx, &p, query, postID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
 // GetWithDetails gets a post with full details
 func (r *postRepository) GetWithDetails(ctx context.Context, postID, userID uuid.UUID) (*post.PostWithDetails, error) {
 	query := `
@@ -82,6 +150,10 @@ func (r *postRepository) GetWithDetails(ctx context.Context, postID, userID uuid
 				(SELECT json_agg(image_url ORDER BY order_index)
 				 FROM post_images WHERE post_id = p.id), '[]'::json
 			) as image_urls,
+			ARRAY(SELECT h.tag FROM post_hashtags ph INNER JOIN hashtags h ON ph.hashtag_id = h.id
+				WHERE ph.post_id = p.id ORDER BY h.tag) as hashtags,
+			ARRAY(SELECT mu.username FROM post_mentions pm INNER JOIN users mu ON pm.user_id = mu.id
+				WHERE pm.post_id = p.id AND mu.username IS NOT NULL ORDER BY mu.username) as mentions,
 			e.id as event_id, e.title as event_title, e.description as event_description,
 			e.category as event_category, e.start_time as event_start_time, e.end_time as event_end_time,
 			e.location_name as event_location_name, e.location_address as event_location_address,
@@ -100,7 +172,7 @@ func (r *postRepository) GetWithDetails(ctx context.Context, postID, userID uuid
 		INNER JOIN users u ON p.author_id = u.id
 		LEFT JOIN events e ON p.attached_event_id = e.id
 		LEFT JOIN users eh ON e.host_id = eh.id
-		WHERE p.id = $1
+		WHERE p.id = $1 AND ` + postVisibleTo("$2") + `
 	`
 
 	var p post.PostWithDetails
@@ -127,6 +199,7 @@ func (r *postRepository) GetWithDetails(ctx context.Context, postID, userID uuid
 		&p.AuthorName, &p.AuthorAvatarURL, &p.AuthorIsVerified,
 		&p.IsLikedByUser, &p.IsBookmarkedByUser, &p.IsRepostedByUser,
 		&imageURLs,
+		pq.Array(&p.Hashtags), pq.Array(&p.Mentions),
 		&eventID, &eventTitle, &eventDescription, &eventCategory,
 		&eventStartTime, &eventEndTime,
 		&eventLocationName, &eventLocationAddress, &eventLocationLat, &eventLocationLng,
@@ -284,71 +357,39 @@ func (r *postRepository) List(ctx context.Context, filter *post.PostFilter, user
 	return nil, nil
 }
 
-// GetFeed gets the feed for a user with random + engagement bias algorithm
-// Algorithm: Random selection from top N recent posts, weighted by engagement score
-// Engagement score = likes + (comments * 2) + (reposts * 3)
-//
-// CTO REVIEW: PERFORMANCE - Missing database index
-// Issue: Query uses "WHERE p.visibility = 'public' ORDER BY p.created_at DESC"
-// Without composite index on (visibility, created_at), this will be slow with many posts
-// Recommendation: CREATE INDEX idx_posts_visibility_created ON posts(visibility, created_at DESC);
-// Also: random() can be expensive on large datasets - consider pre-computed trending score
-// Priority: MEDIUM - Will need optimization as post count grows
-func (r *postRepository) GetFeed(ctx context.Context, userID uuid.UUID, limit, offset int) ([]post.PostWithDetails, error) {
+// GetPublicPosts gets the public posts, newest first, strictly older than before
+// (nil for the newest). It backs the feed of signed-out visitors.
+func (r *postRepository) GetPublicPosts(ctx context.Context, before *utils.Cursor, limit, offset int) ([]post.PostWithDetails, error) {
 	query := `
-		WITH recent_posts AS (
-			-- Get top 100 most recent public posts as candidate pool
-			SELECT
-				p.id, p.author_id, p.content, p.type, p.attached_event_id,
-				p.original_post_id, p.visibility, p.created_at, p.updated_at,
-				p.likes_count, p.comments_count, p.reposts_count, p.shares_count, p.is_archived,
-				u.name as author_name, u.avatar_url as author_avatar_url, u.is_verified as author_is_verified,
-				EXISTS(SELECT 1 FROM likes WHERE user_id = $1 AND likeable_type = 'post' AND likeable_id = p.id) as is_liked_by_user,
-				EXISTS(SELECT 1 FROM bookmarks WHERE user_id = $1 AND post_id = p.id) as is_bookmarked_by_user,
-				EXISTS(SELECT 1 FROM reposts WHERE user_id = $1 AND post_id = p.id) as is_reposted_by_user,
-				COALESCE(
-					(SELECT json_agg(image_url ORDER BY order_index)
-					 FROM post_images WHERE post_id = p.id), '[]'::json
-				) as image_urls,
-				e.id as event_id, e.title as event_title, e.description as event_description,
-				e.category as event_category, e.start_time as event_start_time, e.end_time as event_end_time,
-				e.location_name as event_location_name, e.location_address as event_location_address,
-				e.location_lat as event_location_lat, e.location_lng as event_location_lng,
-				e.host_id as event_host_id, eh.name as event_host_name, eh.avatar_url as event_host_avatar_url,
-				e.max_attendees as event_max_attendees,
-				(SELECT COUNT(*) FROM event_attendees WHERE event_id = e.id AND status = 'confirmed') as event_attendees_count,
-				(SELECT COUNT(*) FROM event_interests WHERE event_id = e.id) as interests_count,
-				EXISTS(SELECT 1 FROM event_interests WHERE event_id = e.id AND user_id = $1) as event_is_interested,
-				e.price as event_price, e.is_free as event_is_free,
-				e.status as event_status, e.privacy as event_privacy,
-				COALESCE(
-					(SELECT json_agg(image_url ORDER BY order_index)
-					 FROM event_images WHERE event_id = e.id), '[]'::json
-				) as event_image_urls
-			FROM posts p
-			INNER JOIN users u ON p.author_id = u.id
-			LEFT JOIN events e ON p.attached_event_id = e.id
-			LEFT JOIN users eh ON e.host_id = eh.id
-			WHERE p.visibility = 'public'
-			AND p.created_at >= NOW() - INTERVAL '7 days'
-			ORDER BY p.created_at DESC
-			LIMIT 100
-		)
-		SELECT *
-		FROM recent_posts
-		-- Random weighted by engagement score
-		-- Formula: (likes + comments*2 + reposts*3 + 1) ensures minimum weight of 1
-		-- Multiply by random() to add randomness with engagement bias
-		ORDER BY (likes_count + comments_count * 2 + reposts_count * 3 + 1) * random() DESC
+		SELECT ` + feedPostColumns + `
+		FROM posts p
+		INNER JOIN users u ON p.author_id = u.id
+		LEFT JOIN events e ON p.attached_event_id = e.id
+		LEFT JOIN users eh ON e.host_id = eh.id
+		WHERE p.visibility = 'public'
+			AND ($4::timestamptz IS NULL OR (p.created_at, p.id) < ($4::timestamptz, $5::uuid))
+		ORDER BY p.created_at DESC, p.id DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryxContext(ctx, query, userID, limit, offset)
+	var beforeCreatedAt *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforeCreatedAt, beforeID = &before.CreatedAt, &before.ID
+	}
+
+	// No viewer: $1 is uuid.Nil, so the interaction flags are all false
+	rows, err := r.db.QueryxContext(ctx, query, uuid.Nil, limit, offset, beforeCreatedAt, beforeID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanFeedPosts(rows)
+}
+
+// scanFeedPosts scans rows selected with feedPostColumns
+func scanFeedPosts(rows *sqlx.Rows) ([]post.PostWithDetails, error) {
 	var posts []post.PostWithDetails
 	for rows.Next() {
 		var p post.PostWithDetails
@@ -377,6 +418,7 @@ func (r *postRepository) GetFeed(ctx context.Context, userID uuid.UUID, limit, o
 			&p.AuthorName, &p.AuthorAvatarURL, &p.AuthorIsVerified,
 			&p.IsLikedByUser, &p.IsBookmarkedByUser, &p.IsRepostedByUser,
 			&imageURLs,
+			pq.Array(&p.Hashtags), pq.Array(&p.Mentions),
 			&eventID, &eventTitle, &eventDescription, &eventCategory,
 			&eventStartTime, &eventEndTime,
 			&eventLocationName, &eventLocationAddress, &eventLocationLat, &eventLocationLng,
@@ -478,13 +520,143 @@ func (r *postRepository) GetFeed(ctx context.Context, userID uuid.UUID, limit, o
 		posts = append(posts, p)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// GetByIDsWithDetails hydrates a page of posts in one query, in the order of postIDs.
+// Posts that no longer exist or that the viewer may not see are left out.
+func (r *postRepository) GetByIDsWithDetails(ctx context.Context, postIDs []uuid.UUID, viewerID uuid.UUID) ([]post.PostWithDetails, error) {
+	if len(postIDs) == 0 {
+		return []post.PostWithDetails{}, nil
+	}
+
+	query := `
+		SELECT ` + feedPostColumns + `
+		FROM posts p
+		INNER JOIN users u ON p.author_id = u.id
+		LEFT JOIN events e ON p.attached_event_id = e.id
+		LEFT JOIN users eh ON e.host_id = eh.id
+		WHERE p.id = ANY($2) AND ` + postVisibleTo("$1") + `
+	`
+
+	rows, err := r.db.QueryxContext(ctx, query, viewerID, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found, err := scanFeedPosts(rows)
+	if err != nil {
 		return nil, err
 	}
 
+	byID := make(map[uuid.UUID]post.PostWithDetails, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+
+	posts := make([]post.PostWithDetails, 0, len(found))
+	for _, id := range postIDs {
+		if p, ok := byID[id]; ok {
+			posts = append(posts, p)
+		}
+	}
+
 	return posts, nil
 }
 
+// GetTimelineEntries lists the posts by the given authors that the viewer may see,
+// newest first, strictly older than before (nil for the newest)
+func (r *postRepository) GetTimelineEntries(ctx context.Context, viewerID uuid.UUID, authorIDs []uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, error) {
+	if len(authorIDs) == 0 {
+		return []post.TimelineEntry{}, nil
+	}
+
+	query := `
+		SELECT p.id, p.created_at
+		FROM posts p
+		WHERE p.author_id = ANY($2) AND ` + postVisibleTo("$1") + `
+			AND ($3::timestamptz IS NULL OR (p.created_at, p.id) < ($3::timestamptz, $4::uuid))
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $5
+	`
+
+	var beforeCreatedAt *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforeCreatedAt = &before.CreatedAt
+		beforeID = &before.PostID
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, viewerID, pq.Array(authorIDs), beforeCreatedAt, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []post.TimelineEntry{}
+	for rows.Next() {
+		var entry post.TimelineEntry
+		if err := rows.Scan(&entry.PostID, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetDiscoveryEntries lists the discovery posts mixed into the viewer's home timeline:
+// of the public posts from the last 7 days by authors the viewer doesn't follow,
+// the discoveryPoolSize with the highest engagement score, newest first
+func (r *postRepository) GetDiscoveryEntries(ctx context.Context, viewerID uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, error) {
+	query := `
+		WITH pool AS (
+			SELECT p.id, p.created_at
+			FROM posts p
+			WHERE p.visibility = 'public'
+			AND p.author_id <> $1
+			AND NOT EXISTS(SELECT 1 FROM follows f WHERE f.follower_id = $1 AND f.following_id = p.author_id)
+			AND p.created_at >= NOW() - INTERVAL '7 days'
+			ORDER BY p.likes_count + p.comments_count * 2 + p.reposts_count * 3 DESC, p.created_at DESC
+			LIMIT $2
+		)
+		SELECT id, created_at
+		FROM pool
+		WHERE $3::timestamptz IS NULL OR (created_at, id) < ($3::timestamptz, $4::uuid)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5
+	`
+
+	var beforeCreatedAt *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforeCreatedAt = &before.CreatedAt
+		beforeID = &before.PostID
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, viewerID, discoveryPoolSize, beforeCreatedAt, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []post.TimelineEntry{}
+	for rows.Next() {
+		var entry post.TimelineEntry
+		if err := rows.Scan(&entry.PostID, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
 // GetUserPosts gets posts by a specific user
 func (r *postRepository) GetUserPosts(ctx context.Context, authorID, viewerID uuid.UUID, limit, offset int) ([]post.PostWithDetails, error) {
 	query := `
@@ -500,6 +672,10 @@ func (r *postRepository) GetUserPosts(ctx context.Context, authorID, viewerID uu
 				(SELECT json_agg(image_url ORDER BY order_index)
 				 FROM post_images WHERE post_id = p.id), '[]'::json
 			) as image_urls,
+			ARRAY(SELECT h.tag FROM post_hashtags ph INNER JOIN hashtags h ON ph.hashtag_id = h.id
+				WHERE ph.post_id = p.id ORDER BY h.tag) as hashtags,
+			ARRAY(SELECT mu.username FROM post_mentions pm INNER JOIN users mu ON pm.user_id = mu.id
+				WHERE pm.post_id = p.id AND mu.username IS NOT NULL ORDER BY mu.username) as mentions,
 			e.id as event_id, e.title as event_title, e.description as event_description,
 			e.category as event_category, e.start_time as event_start_time, e.end_time as event_end_time,
 			e.location_name as event_location_name, e.location_address as event_location_address,
@@ -519,7 +695,7 @@ func (r *postRepository) GetUserPosts(ctx context.Context, authorID, viewerID uu
 		INNER JOIN users u ON p.author_id = u.id
 		LEFT JOIN events e ON p.attached_event_id = e.id
 		LEFT JOIN users eh ON e.host_id = eh.id
-		WHERE p.author_id = $1 AND p.visibility IN ('public', 'followers')
+		WHERE p.author_id = $1 AND ` + postVisibleTo("$2") + `
 		ORDER BY p.created_at DESC
 		LIMIT $3 OFFSET $4
 	`
@@ -558,6 +734,7 @@ func (r *postRepository) GetUserPosts(ctx context.Context, authorID, viewerID uu
 			&p.AuthorName, &p.AuthorAvatarURL, &p.AuthorIsVerified,
 			&p.IsLikedByUser, &p.IsBookmarkedByUser, &p.IsRepostedByUser,
 			&imageURLs,
+			pq.Array(&p.Hashtags), pq.Array(&p.Mentions),
 			&eventID, &eventTitle, &eventDescription, &eventCategory,
 			&eventStartTime, &eventEndTime,
 			&eventLocationName, &eventLocationAddress, &eventLocationLat, &eventLocationLng,
@@ -771,27 +948,161 @@ func (r *postRepository) IncrementShares(ctx context.Context, postID uuid.UUID)
 	return err
 }
 
-// CountFeed counts the total number of posts in a user's feed
-func (r *postRepository) CountFeed(ctx context.Context, userID uuid.UUID) (int, error) {
+// CountPublicPosts counts the public posts
+func (r *postRepository) CountPublicPosts(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM posts WHERE visibility = 'public'`
+	var count int
+	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	return count, err
+}
+
+// CountUserPosts counts the posts by a specific author that the viewer can see
+func (r *postRepository) CountUserPosts(ctx context.Context, authorID, viewerID uuid.UUID) (int, error) {
 	query := `
 		SELECT COUNT(*)
 		FROM posts p
-		WHERE p.visibility = 'public'
-		AND p.created_at >= NOW() - INTERVAL '7 days'
+		WHERE p.author_id = $1 AND ` + postVisibleTo("$2") + `
 	`
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, authorID, viewerID).Scan(&count)
 	return count, err
 }
 
-// CountUserPosts counts the total number of posts by a specific author
-func (r *postRepository) CountUserPosts(ctx context.Context, authorID uuid.UUID) (int, error) {
+// SetTags replaces the hashtags and mentioned users of a post.
+// Hashtags must already be normalized; new ones are created on the fly.
+func (r *postRepository) SetTags(ctx context.Context, postID uuid.UUID, hashtags []string, mentionedIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_hashtags WHERE post_id = $1`, postID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_mentions WHERE post_id = $1`, postID); err != nil {
+		return err
+	}
+
+	if len(hashtags) > 0 {
+		query := `
+			INSERT INTO hashtags (tag)
+			SELECT UNNEST($1::varchar[])
+			ON CONFLICT (tag) DO NOTHING
+		`
+		if _, err := tx.ExecContext(ctx, query, pq.Array(hashtags)); err != nil {
+			return err
+		}
+
+		// Keep the post's creation time so trending windows need no join with posts
+		query = `
+			INSERT INTO post_hashtags (post_id, hashtag_id, created_at)
+			SELECT p.id, h.id, p.created_at
+			FROM posts p, hashtags h
+			WHERE p.id = $1 AND h.tag = ANY($2)
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := tx.ExecContext(ctx, query, postID, pq.Array(hashtags)); err != nil {
+			return err
+		}
+	}
+
+	if len(mentionedIDs) > 0 {
+		query := `
+			INSERT INTO post_mentions (post_id, user_id)
+			SELECT $1, UNNEST($2::uuid[])
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := tx.ExecContext(ctx, query, postID, pq.Array(mentionedIDs)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMentionedUserIDs gets the IDs of the users mentioned by a post
+func (r *postRepository) GetMentionedUserIDs(ctx context.Context, postID uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT user_id FROM post_mentions WHERE post_id = $1`
+
+	userIDs := []uuid.UUID{}
+	err := r.db.SelectContext(ctx, &userIDs, query, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// GetHashtagPosts gets the posts using a hashtag that the viewer may see, newest first,
+// strictly older than before (nil for the newest)
+func (r *postRepository) GetHashtagPosts(ctx context.Context, tag string, viewerID uuid.UUID, before *utils.Cursor, limit, offset int) ([]post.PostWithDetails, error) {
+	query := `
+		SELECT ` + feedPostColumns + `
+		FROM post_hashtags ph
+		INNER JOIN hashtags h ON ph.hashtag_id = h.id
+		INNER JOIN posts p ON ph.post_id = p.id
+		INNER JOIN users u ON p.author_id = u.id
+		LEFT JOIN events e ON p.attached_event_id = e.id
+		LEFT JOIN users eh ON e.host_id = eh.id
+		WHERE h.tag = $2 AND ` + postVisibleTo("$1") + `
+			AND ($5::timestamptz IS NULL OR (ph.created_at, ph.post_id) < ($5::timestamptz, $6::uuid))
+		ORDER BY ph.created_at DESC, ph.post_id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	var beforeCreatedAt *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforeCreatedAt, beforeID = &before.CreatedAt, &before.ID
+	}
+
+	rows, err := r.db.QueryxContext(ctx, query, viewerID, tag, limit, offset, beforeCreatedAt, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanFeedPosts(rows)
+}
+
+// CountHashtagPosts counts the posts using a hashtag that the viewer may see
+func (r *postRepository) CountHashtagPosts(ctx context.Context, tag string, viewerID uuid.UUID) (int, error) {
 	query := `
 		SELECT COUNT(*)
-		FROM posts p
-		WHERE p.author_id = $1
+		FROM post_hashtags ph
+		INNER JOIN hashtags h ON ph.hashtag_id = h.id
+		INNER JOIN posts p ON ph.post_id = p.id
+		WHERE h.tag = $2 AND ` + postVisibleTo("$1") + `
 	`
 	var count int
-	err := r.db.QueryRowContext(ctx, query, authorID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, viewerID, tag).Scan(&count)
 	return count, err
 }
+
+// GetTrendingHashtags ranks the hashtags of public posts created since the given time.
+// Hashtags used by more distinct authors rank first, so one account repeating a
+// tag can't push it up on its own.
+func (r *postRepository) GetTrendingHashtags(ctx context.Context, since time.Time, limit int) ([]post.TrendingHashtag, error) {
+	query := `
+		SELECT h.tag,
+			COUNT(*) as posts_count,
+			COUNT(DISTINCT p.author_id) as authors_count,
+			MAX(ph.created_at) as last_used_at
+		FROM post_hashtags ph
+		INNER JOIN hashtags h ON ph.hashtag_id = h.id
+		INNER JOIN posts p ON ph.post_id = p.id
+		WHERE ph.created_at >= $1 AND p.visibility = 'public'
+		GROUP BY h.tag
+		ORDER BY authors_count DESC, posts_count DESC, last_used_at DESC
+		LIMIT $2
+	`
+
+	hashtags := []post.TrendingHashtag{}
+	err := r.db.SelectContext(ctx, &hashtags, query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashtags, nil
+}
@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/anigmaa/backend/internal/domain/qna"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
@@ -155,7 +157,7 @@ func (r *QnARepository) GetByIDWithDetails(ctx context.Context, id, userID uuid.
 }
 
 // GetByEvent retrieves all Q&A for an event
-func (r *QnARepository) GetByEvent(ctx context.Context, eventID, userID uuid.UUID, limit, offset int) ([]qna.QnAWithDetails, error) {
+func (r *QnARepository) GetByEvent(ctx context.Context, eventID, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]qna.QnAWithDetails, error) {
 	query := `
 		SELECT
 			q.id, q.event_id, q.question, q.answer,
@@ -181,11 +183,19 @@ func (r *QnARepository) GetByEvent(ctx context.Context, eventID, userID uuid.UUI
 		INNER JOIN users u1 ON q.asked_by_id = u1.id
 		LEFT JOIN users u2 ON q.answered_by_id = u2.id
 		WHERE q.event_id = $1
-		ORDER BY q.upvotes DESC, q.asked_at DESC
+			AND ($5::timestamptz IS NULL OR (q.upvotes, q.asked_at, q.id) < ($7::int, $5::timestamptz, $6::uuid))
+		ORDER BY q.upvotes DESC, q.asked_at DESC, q.id DESC
 		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, eventID, userID, limit, offset)
+	var beforeAskedAt *time.Time
+	var beforeID *uuid.UUID
+	var beforeScore *int
+	if before != nil {
+		beforeAskedAt, beforeID, beforeScore = &before.CreatedAt, &before.ID, &before.Score
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, eventID, userID, limit, offset, beforeAskedAt, beforeID, beforeScore)
 	if err != nil {
 		return nil, err
 	}
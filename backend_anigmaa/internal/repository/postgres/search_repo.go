@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/anigmaa/backend/internal/domain/search"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type searchRepository struct {
+	db *sqlx.DB
+}
+
+// NewSearchRepository creates a new full-text search repository
+func NewSearchRepository(db *sqlx.DB) search.Repository {
+	return &searchRepository{db: db}
+}
+
+// searchQueryCTE parses the search text bound to $1 into search_query(query).
+// The text is read as web search syntax ("quoted phrases", -exclusions, OR) and
+// matched unstemmed as well as with Indonesian and English stemming, the same
+// configurations the search_vector columns are built with.
+const searchQueryCTE = `search_query AS (
+			SELECT websearch_to_tsquery('simple', $1)
+				|| websearch_to_tsquery('indonesian', $1)
+				|| websearch_to_tsquery('english', $1) AS query
+		)`
+
+// searchHeadlineOptions configures the snippets returned with each result
+const searchHeadlineOptions = `StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" ... "`
+
+// Search ranks events, posts, communities and users matching the query.
+// Each type contributes at most offset+limit candidates, ranked with ts_rank_cd
+// normalized to 0..1 so ranks are comparable across types. Snippets are only
+// built for the returned page since ts_headline re-parses the text.
+// Only items the viewer may see are returned: events open to them (see eventVisibleTo),
+// visible posts, non-secret communities (and the ones they belong to).
+func (r *searchRepository) Search(ctx context.Context, q *search.Query) ([]search.Result, error) {
+	query := `
+		WITH ` + searchQueryCTE + `,
+		candidates AS (
+			(
+				SELECT 'event' as type, e.id, e.title, coalesce(e.description, '') as body,
+					(SELECT image_url FROM event_images WHERE event_id = e.id ORDER BY order_index LIMIT 1) as image_url,
+					ts_rank_cd(e.search_vector, sq.query, 32) as rank, e.created_at
+				FROM events e, search_query sq
+				WHERE 'event' = ANY($3) AND e.search_vector @@ sq.query
+					AND e.is_archived = false AND ` + eventVisibleTo("$2") + `
+				ORDER BY rank DESC
+				LIMIT $4
+			)
+			UNION ALL
+			(
+				SELECT 'post' as type, p.id, u.name as title, p.content as body,
+					(SELECT image_url FROM post_images WHERE post_id = p.id ORDER BY order_index LIMIT 1) as image_url,
+					ts_rank_cd(p.search_vector, sq.query, 32) as rank, p.created_at
+				FROM posts p
+				INNER JOIN users u ON p.author_id = u.id, search_query sq
+				WHERE 'post' = ANY($3) AND p.search_vector @@ sq.query
+					AND p.is_archived = false AND ` + postVisibleTo("$2") + `
+				ORDER BY rank DESC
+				LIMIT $4
+			)
+			UNION ALL
+			(
+				SELECT 'community' as type, c.id, c.name as title, coalesce(c.description, '') as body,
+					c.avatar_url as image_url,
+					ts_rank_cd(c.search_vector, sq.query, 32) as rank, c.created_at
+				FROM communities c, search_query sq
+				WHERE 'community' = ANY($3) AND c.search_vector @@ sq.query
+					AND (c.privacy <> 'secret' OR EXISTS(
+						SELECT 1 FROM community_members cm WHERE cm.community_id = c.id AND cm.user_id = $2))
+				ORDER BY rank DESC
+				LIMIT $4
+			)
+			UNION ALL
+			(
+				SELECT 'user' as type, u.id, u.name as title,
+					concat_ws(' ', '@' || u.username, u.bio) as body,
+					u.avatar_url as image_url,
+					ts_rank_cd(u.search_vector, sq.query, 32) as rank, u.created_at
+				FROM users u, search_query sq
+				WHERE 'user' = ANY($3) AND u.search_vector @@ sq.query
+				ORDER BY rank DESC
+				LIMIT $4
+			)
+		),
+		page AS (
+			SELECT * FROM candidates
+			ORDER BY rank DESC, created_at DESC, id
+			LIMIT $5 OFFSET $6
+		)
+		SELECT pg.type, pg.id, pg.title,
+			ts_headline('simple', pg.body, sq.query, '` + searchHeadlineOptions + `') as snippet,
+			pg.image_url, pg.rank, pg.created_at
+		FROM page pg, search_query sq
+		ORDER BY pg.rank DESC, pg.created_at DESC, pg.id
+	`
+
+	types := make([]string, len(q.Types))
+	for i, t := range q.Types {
+		types[i] = string(t)
+	}
+
+	results := []search.Result{}
+	err := r.db.SelectContext(ctx, &results, query,
+		q.Text, q.ViewerID, pq.Array(types), q.Offset+q.Limit, q.Limit, q.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
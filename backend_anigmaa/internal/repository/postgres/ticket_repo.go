@@ -9,10 +9,15 @@ import (
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// uniqueUntieredTicket is the index allowing one ticket per user for events without tiers
+const uniqueUntieredTicket = "idx_tickets_user_event_untiered"
+
 type ticketRepository struct {
 	db *sqlx.DB
 }
@@ -70,12 +75,13 @@ func (r *ticketRepository) Create(ctx context.Context, t *ticket.Ticket) error {
 	t.PurchasedAt = time.Now()
 
 	query := `
-		INSERT INTO tickets (id, user_id, event_id, attendance_code, price_paid, purchased_at, is_checked_in, status)
-		VALUES ($1, $2, $3, $4, $5, $6, FALSE, $7)
+		INSERT INTO tickets (id, user_id, event_id, attendance_code, price_paid, purchased_at, is_checked_in, status, tier_id, promo_code_id, discount_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, FALSE, $7, $8, $9, $10)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		t.ID, t.UserID, t.EventID, t.AttendanceCode, t.PricePaid, t.PurchasedAt, t.Status,
+		t.ID, t.UserID, t.EventID, t.AttendanceCode, t.PricePaid, t.PurchasedAt, t.Status, t.TierID,
+		t.PromoCodeID, t.DiscountAmount,
 	)
 
 	return err
@@ -85,7 +91,7 @@ func (r *ticketRepository) Create(ctx context.Context, t *ticket.Ticket) error {
 func (r *ticketRepository) GetByID(ctx context.Context, ticketID uuid.UUID) (*ticket.Ticket, error) {
 	query := `
 		SELECT id, user_id, event_id, attendance_code, price_paid, purchased_at,
-		       is_checked_in, checked_in_at, status
+		       is_checked_in, checked_in_at, status, tier_id, promo_code_id, discount_amount
 		FROM tickets
 		WHERE id = $1
 	`
@@ -104,12 +110,14 @@ func (r *ticketRepository) GetWithDetails(ctx context.Context, ticketID uuid.UUI
 	query := `
 		SELECT
 			t.id, t.user_id, t.event_id, t.attendance_code, t.price_paid, t.purchased_at,
-			t.is_checked_in, t.checked_in_at, t.status,
+			t.is_checked_in, t.checked_in_at, t.status, t.tier_id, t.promo_code_id, t.discount_amount,
 			u.name as user_name, u.email as user_email, u.avatar_url as user_avatar_url,
-			e.title as event_title, e.start_time as event_start_time, e.location_name as event_location
+			e.title as event_title, e.start_time as event_start_time, e.end_time as event_end_time, e.location_name as event_location,
+			tt.name as tier_name
 		FROM tickets t
 		INNER JOIN users u ON t.user_id = u.id
 		INNER JOIN events e ON t.event_id = e.id
+		LEFT JOIN ticket_tiers tt ON t.tier_id = tt.id
 		WHERE t.id = $1
 	`
 
@@ -147,6 +155,35 @@ func (r *ticketRepository) Update(ctx context.Context, t *ticket.Ticket) error {
 	return nil
 }
 
+// ClaimCancellation moves an active ticket that isn't checked in to cancelled
+func (r *ticketRepository) ClaimCancellation(ctx context.Context, ticketID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tickets
+		SET status = 'cancelled'
+		WHERE id = $1 AND status = 'active' AND is_checked_in = FALSE
+	`, ticketID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // Not active anymore, e.g. claimed by a concurrent cancellation
+	}
+
+	return nil
+}
+
+// ReleaseCancellation moves a cancelled ticket back to active
+func (r *ticketRepository) ReleaseCancellation(ctx context.Context, ticketID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE tickets SET status = 'active' WHERE id = $1 AND status = 'cancelled'`, ticketID)
+	return err
+}
+
 // Delete deletes a ticket
 func (r *ticketRepository) Delete(ctx context.Context, ticketID uuid.UUID) error {
 	query := `DELETE FROM tickets WHERE id = $1`
@@ -169,23 +206,32 @@ func (r *ticketRepository) Delete(ctx context.Context, ticketID uuid.UUID) error
 }
 
 // GetByUser gets tickets for a user
-func (r *ticketRepository) GetByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]ticket.TicketWithDetails, error) {
+func (r *ticketRepository) GetByUser(ctx context.Context, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]ticket.TicketWithDetails, error) {
 	query := `
 		SELECT
 			t.id, t.user_id, t.event_id, t.attendance_code, t.price_paid, t.purchased_at,
-			t.is_checked_in, t.checked_in_at, t.status,
+			t.is_checked_in, t.checked_in_at, t.status, t.tier_id, t.promo_code_id, t.discount_amount,
 			u.name as user_name, u.email as user_email, u.avatar_url as user_avatar_url,
-			e.title as event_title, e.start_time as event_start_time, e.location_name as event_location
+			e.title as event_title, e.start_time as event_start_time, e.end_time as event_end_time, e.location_name as event_location,
+			tt.name as tier_name
 		FROM tickets t
 		INNER JOIN users u ON t.user_id = u.id
 		INNER JOIN events e ON t.event_id = e.id
+		LEFT JOIN ticket_tiers tt ON t.tier_id = tt.id
 		WHERE t.user_id = $1
-		ORDER BY t.purchased_at DESC
+			AND ($4::timestamptz IS NULL OR (t.purchased_at, t.id) < ($4::timestamptz, $5::uuid))
+		ORDER BY t.purchased_at DESC, t.id DESC
 		LIMIT $2 OFFSET $3
 	`
 
+	var beforePurchasedAt *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforePurchasedAt, beforeID = &before.CreatedAt, &before.ID
+	}
+
 	var tickets []ticket.TicketWithDetails
-	err := r.db.SelectContext(ctx, &tickets, query, userID, limit, offset)
+	err := r.db.SelectContext(ctx, &tickets, query, userID, limit, offset, beforePurchasedAt, beforeID)
 	if err != nil {
 		return nil, err
 	}
@@ -202,12 +248,14 @@ func (r *ticketRepository) GetByEvent(ctx context.Context, eventID uuid.UUID, li
 	query := `
 		SELECT
 			t.id, t.user_id, t.event_id, t.attendance_code, t.price_paid, t.purchased_at,
-			t.is_checked_in, t.checked_in_at, t.status,
+			t.is_checked_in, t.checked_in_at, t.status, t.tier_id, t.promo_code_id, t.discount_amount,
 			u.name as user_name, u.email as user_email, u.avatar_url as user_avatar_url,
-			e.title as event_title, e.start_time as event_start_time, e.location_name as event_location
+			e.title as event_title, e.start_time as event_start_time, e.end_time as event_end_time, e.location_name as event_location,
+			tt.name as tier_name
 		FROM tickets t
 		INNER JOIN users u ON t.user_id = u.id
 		INNER JOIN events e ON t.event_id = e.id
+		LEFT JOIN ticket_tiers tt ON t.tier_id = tt.id
 		WHERE t.event_id = $1
 		ORDER BY t.purchased_at DESC
 		LIMIT $2 OFFSET $3
@@ -226,11 +274,33 @@ func (r *ticketRepository) GetByEvent(ctx context.Context, eventID uuid.UUID, li
 	return tickets, nil
 }
 
+// GetManifestTickets gets all of an event's tickets, oldest purchase first
+func (r *ticketRepository) GetManifestTickets(ctx context.Context, eventID uuid.UUID) ([]ticket.TicketWithDetails, error) {
+	query := `
+		SELECT
+			t.id, t.user_id, t.event_id, t.attendance_code, t.price_paid, t.purchased_at,
+			t.is_checked_in, t.checked_in_at, t.status, t.tier_id, t.promo_code_id, t.discount_amount,
+			u.name as user_name, tt.name as tier_name
+		FROM tickets t
+		INNER JOIN users u ON t.user_id = u.id
+		LEFT JOIN ticket_tiers tt ON t.tier_id = tt.id
+		WHERE t.event_id = $1
+		ORDER BY t.purchased_at, t.id
+	`
+
+	tickets := make([]ticket.TicketWithDetails, 0)
+	if err := r.db.SelectContext(ctx, &tickets, query, eventID); err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
 // GetByAttendanceCode gets a ticket by attendance code
 func (r *ticketRepository) GetByAttendanceCode(ctx context.Context, code string) (*ticket.Ticket, error) {
 	query := `
 		SELECT id, user_id, event_id, attendance_code, price_paid, purchased_at,
-		       is_checked_in, checked_in_at, status
+		       is_checked_in, checked_in_at, status, tier_id, promo_code_id, discount_amount
 		FROM tickets
 		WHERE attendance_code = $1
 	`
@@ -244,13 +314,15 @@ func (r *ticketRepository) GetByAttendanceCode(ctx context.Context, code string)
 	return &t, nil
 }
 
-// GetUserTicketForEvent gets a user's ticket for a specific event
+// GetUserTicketForEvent gets a user's latest ticket for a specific event
 func (r *ticketRepository) GetUserTicketForEvent(ctx context.Context, userID, eventID uuid.UUID) (*ticket.Ticket, error) {
 	query := `
 		SELECT id, user_id, event_id, attendance_code, price_paid, purchased_at,
-		       is_checked_in, checked_in_at, status
+		       is_checked_in, checked_in_at, status, tier_id, promo_code_id, discount_amount
 		FROM tickets
 		WHERE user_id = $1 AND event_id = $2
+		ORDER BY purchased_at DESC
+		LIMIT 1
 	`
 
 	var t ticket.Ticket
@@ -262,16 +334,15 @@ func (r *ticketRepository) GetUserTicketForEvent(ctx context.Context, userID, ev
 	return &t, nil
 }
 
-// CheckIn checks in a ticket
-func (r *ticketRepository) CheckIn(ctx context.Context, ticketID uuid.UUID) error {
-	now := time.Now()
+// CheckIn checks in a ticket as of at, moving a later check-in back to at
+func (r *ticketRepository) CheckIn(ctx context.Context, ticketID uuid.UUID, at time.Time) error {
 	query := `
 		UPDATE tickets
 		SET is_checked_in = TRUE, checked_in_at = $1
-		WHERE id = $2 AND is_checked_in = FALSE
+		WHERE id = $2 AND (is_checked_in = FALSE OR checked_in_at > $1)
 	`
 
-	result, err := r.db.ExecContext(ctx, query, now, ticketID)
+	result, err := r.db.ExecContext(ctx, query, at, ticketID)
 	if err != nil {
 		return err
 	}
@@ -282,7 +353,7 @@ func (r *ticketRepository) CheckIn(ctx context.Context, ticketID uuid.UUID) erro
 	}
 
 	if rowsAffected == 0 {
-		return sql.ErrNoRows // Already checked in or ticket not found
+		return sql.ErrNoRows // Already checked in by then or ticket not found
 	}
 
 	return nil
@@ -301,6 +372,223 @@ func (r *ticketRepository) GetCheckedInCount(ctx context.Context, eventID uuid.U
 	return count, nil
 }
 
+// RecordCheckInScan checks the ticket in, takes the check-in over from later
+// scans and logs the scan in one transaction
+func (r *ticketRepository) RecordCheckInScan(ctx context.Context, scan *ticket.CheckInScan) (int, bool, error) {
+	if scan.ID == uuid.Nil {
+		scan.ID = uuid.New()
+	}
+	if scan.ScannedAt.IsZero() {
+		scan.ScannedAt = time.Now()
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// The ticket row serializes scans of the same ticket, and refunds of it
+	var status ticket.TicketStatus
+	err = tx.GetContext(ctx, &status, `SELECT status FROM tickets WHERE id = $1 FOR UPDATE`, scan.TicketID)
+	if err != nil {
+		return 0, false, err
+	}
+	if status != ticket.StatusActive {
+		return 0, false, ticket.ErrTicketNotActive
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tickets
+		SET is_checked_in = TRUE, checked_in_at = $1
+		WHERE id = $2 AND (is_checked_in = FALSE OR checked_in_at > $1)
+	`, scan.ScannedAt, scan.TicketID)
+	if err != nil {
+		return 0, false, err
+	}
+	checkedIn, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+
+	demoted := int64(0)
+	scan.Outcome = ticket.CheckInDuplicate
+	if checkedIn > 0 {
+		scan.Outcome = ticket.CheckInAdmitted
+
+		// Only an offline scan can be earlier than the check-in it finds
+		result, err := tx.ExecContext(ctx, `
+			UPDATE ticket_check_ins
+			SET outcome = 'duplicate'
+			WHERE ticket_id = $1 AND outcome = 'checked_in' AND scanned_at > $2
+		`, scan.TicketID, scan.ScannedAt)
+		if err != nil {
+			return 0, false, err
+		}
+		if demoted, err = result.RowsAffected(); err != nil {
+			return 0, false, err
+		}
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		INSERT INTO ticket_check_ins (id, ticket_id, event_id, gate, scanned_by, kiosk_token_id, outcome, scanned_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO NOTHING
+	`, scan.ID, scan.TicketID, scan.EventID, scan.Gate, scan.ScannedBy, scan.KioskTokenID, scan.Outcome, scan.ScannedAt)
+	if err != nil {
+		return 0, false, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+	if inserted == 0 {
+		// A retry of the same scan got there first; roll this one back
+		return 0, false, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return int(demoted), true, nil
+}
+
+const checkInScanColumns = `id, ticket_id, event_id, gate, scanned_by, kiosk_token_id, outcome, scanned_at`
+
+// GetCheckInScan gets a logged scan by ID
+func (r *ticketRepository) GetCheckInScan(ctx context.Context, scanID uuid.UUID) (*ticket.CheckInScan, error) {
+	var scan ticket.CheckInScan
+	err := r.db.GetContext(ctx, &scan, `SELECT `+checkInScanColumns+` FROM ticket_check_ins WHERE id = $1`, scanID)
+	if err != nil {
+		return nil, err
+	}
+	return &scan, nil
+}
+
+// GetAdmittingScan gets the scan that checked the ticket in
+func (r *ticketRepository) GetAdmittingScan(ctx context.Context, ticketID uuid.UUID) (*ticket.CheckInScan, error) {
+	query := `SELECT ` + checkInScanColumns + `
+		FROM ticket_check_ins
+		WHERE ticket_id = $1 AND outcome = 'checked_in'
+		ORDER BY scanned_at
+		LIMIT 1
+	`
+
+	var scan ticket.CheckInScan
+	if err := r.db.GetContext(ctx, &scan, query, ticketID); err != nil {
+		return nil, err
+	}
+	return &scan, nil
+}
+
+// GetCheckInStats counts the event's active and checked-in tickets, and the scans per gate
+func (r *ticketRepository) GetCheckInStats(ctx context.Context, eventID uuid.UUID) (*ticket.CheckInStats, error) {
+	stats := &ticket.CheckInStats{EventID: eventID}
+
+	err := r.db.QueryRowxContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'active' OR is_checked_in),
+			COUNT(*) FILTER (WHERE is_checked_in)
+		FROM tickets
+		WHERE event_id = $1
+	`, eventID).Scan(&stats.Total, &stats.CheckedIn)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Gates = make([]ticket.GateStats, 0)
+	err = r.db.SelectContext(ctx, &stats.Gates, `
+		SELECT
+			gate,
+			COUNT(*) FILTER (WHERE outcome = 'checked_in') as checked_in,
+			COUNT(*) FILTER (WHERE outcome = 'duplicate') as duplicates,
+			MAX(scanned_at) as last_scan_at
+		FROM ticket_check_ins
+		WHERE event_id = $1
+		GROUP BY gate
+		ORDER BY gate
+	`, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// CreateKioskToken stores a kiosk token
+func (r *ticketRepository) CreateKioskToken(ctx context.Context, token *ticket.KioskToken) error {
+	query := `
+		INSERT INTO event_kiosk_tokens (event_id, issued_by, gate, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRowxContext(ctx, query,
+		token.EventID, token.IssuedBy, token.Gate, token.TokenHash, token.ExpiresAt,
+	).Scan(&token.ID, &token.CreatedAt)
+}
+
+const kioskTokenColumns = `id, event_id, issued_by, gate, token_hash, expires_at, revoked_at, last_used_at, created_at`
+
+// GetKioskTokenByID gets a kiosk token by ID
+func (r *ticketRepository) GetKioskTokenByID(ctx context.Context, tokenID uuid.UUID) (*ticket.KioskToken, error) {
+	var token ticket.KioskToken
+	err := r.db.GetContext(ctx, &token, `SELECT `+kioskTokenColumns+` FROM event_kiosk_tokens WHERE id = $1`, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetKioskTokenByHash gets a kiosk token by the hash of its secret
+func (r *ticketRepository) GetKioskTokenByHash(ctx context.Context, tokenHash string) (*ticket.KioskToken, error) {
+	var token ticket.KioskToken
+	err := r.db.GetContext(ctx, &token, `SELECT `+kioskTokenColumns+` FROM event_kiosk_tokens WHERE token_hash = $1`, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetEventKioskTokens lists an event's kiosk tokens newest first, optionally only the ones a user issued
+func (r *ticketRepository) GetEventKioskTokens(ctx context.Context, eventID uuid.UUID, issuedBy *uuid.UUID) ([]ticket.KioskToken, error) {
+	query := `SELECT ` + kioskTokenColumns + `
+		FROM event_kiosk_tokens
+		WHERE event_id = $1 AND ($2::uuid IS NULL OR issued_by = $2)
+		ORDER BY created_at DESC
+	`
+
+	tokens := make([]ticket.KioskToken, 0)
+	if err := r.db.SelectContext(ctx, &tokens, query, eventID, issuedBy); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeKioskToken revokes a kiosk token
+func (r *ticketRepository) RevokeKioskToken(ctx context.Context, tokenID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE event_kiosk_tokens SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, tokenID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TouchKioskToken records that a kiosk token was just used
+func (r *ticketRepository) TouchKioskToken(ctx context.Context, tokenID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE event_kiosk_tokens SET last_used_at = NOW() WHERE id = $1`, tokenID)
+	return err
+}
+
 // CreateTransaction creates a new ticket transaction
 func (r *ticketRepository) CreateTransaction(ctx context.Context, transaction *ticket.TicketTransaction) error {
 	// Generate UUID if not provided
@@ -380,86 +668,600 @@ func (r *ticketRepository) UpdateTransactionStatus(ctx context.Context, transact
 	return nil
 }
 
-// GetByEventID gets all tickets for an event (for analytics)
-func (r *ticketRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]ticket.Ticket, error) {
+// MarkTransactionRefunded moves a successful payment transaction to 'refunded'
+func (r *ticketRepository) MarkTransactionRefunded(ctx context.Context, transactionID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE ticket_transactions
+		SET status = 'refunded', completed_at = NOW()
+		WHERE transaction_id = $1
+		  AND status = 'success'
+	`, transactionID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ticket.ErrAlreadyProcessed
+	}
+
+	return nil
+}
+
+// CreateRefund creates a new refund record
+func (r *ticketRepository) CreateRefund(ctx context.Context, refund *ticket.Refund) error {
+	// Generate UUID if not provided
+	if refund.ID == uuid.Nil {
+		refund.ID = uuid.New()
+	}
+
+	// Set timestamps
+	now := time.Now()
+	refund.CreatedAt = now
+	refund.UpdatedAt = now
+
 	query := `
-		SELECT id, user_id, event_id, attendance_code, price_paid, purchased_at,
-		       is_checked_in, checked_in_at, status
-		FROM tickets
-		WHERE event_id = $1
-		ORDER BY purchased_at DESC
+		INSERT INTO ticket_refunds (id, ticket_id, transaction_id, refund_key, amount, reason, method, status, partial,
+		                            provider_refund_id, failure_reason, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
-	var tickets []ticket.Ticket
-	err := r.db.SelectContext(ctx, &tickets, query, eventID)
+	_, err := r.db.ExecContext(ctx, query,
+		refund.ID, refund.TicketID, refund.TransactionID, refund.RefundKey, refund.Amount,
+		refund.Reason, refund.Method, refund.Status, refund.Partial, refund.ProviderRefundID, refund.FailureReason,
+		refund.CreatedAt, refund.UpdatedAt,
+	)
+
+	return err
+}
+
+// UpdateRefundAttempt records the provider's answer to a refund request
+func (r *ticketRepository) UpdateRefundAttempt(ctx context.Context, refund *ticket.Refund) error {
+	now := time.Now()
+	var completedAt *time.Time
+	if refund.Status == ticket.RefundFailed {
+		completedAt = &now
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE ticket_refunds
+		SET method = $1,
+		    status = $2,
+		    provider_refund_id = COALESCE($3, provider_refund_id),
+		    failure_reason = $4,
+		    completed_at = $5,
+		    updated_at = $6
+		WHERE refund_key = $7
+		  AND status <> 'succeeded'
+	`, refund.Method, refund.Status, refund.ProviderRefundID, refund.FailureReason, completedAt, now, refund.RefundKey)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ticket.ErrAlreadyProcessed
+	}
+
+	refund.UpdatedAt = now
+	refund.CompletedAt = completedAt
+	return nil
+}
+
+// GetRefundByKey gets a refund by its provider refund key
+func (r *ticketRepository) GetRefundByKey(ctx context.Context, refundKey string) (*ticket.Refund, error) {
+	query := `
+		SELECT id, ticket_id, transaction_id, refund_key, amount, reason, method, status, partial,
+		       provider_refund_id, failure_reason, created_at, updated_at, completed_at
+		FROM ticket_refunds
+		WHERE refund_key = $1
+	`
+
+	var refund ticket.Refund
+	if err := r.db.GetContext(ctx, &refund, query, refundKey); err != nil {
 		return nil, err
 	}
 
-	return tickets, nil
+	return &refund, nil
 }
 
-// GetTransactionsByTicketID gets all transactions for a ticket
-func (r *ticketRepository) GetTransactionsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]ticket.TicketTransaction, error) {
+// GetRefundsByTicketID gets all refunds for a ticket, newest first
+func (r *ticketRepository) GetRefundsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]ticket.Refund, error) {
 	query := `
-		SELECT id, ticket_id, transaction_id, amount, payment_method,
-		       status, created_at, completed_at
-		FROM ticket_transactions
+		SELECT id, ticket_id, transaction_id, refund_key, amount, reason, method, status, partial,
+		       provider_refund_id, failure_reason, created_at, updated_at, completed_at
+		FROM ticket_refunds
 		WHERE ticket_id = $1
 		ORDER BY created_at DESC
 	`
 
-	var transactions []ticket.TicketTransaction
-	err := r.db.SelectContext(ctx, &transactions, query, ticketID)
-	if err != nil {
+	refunds := []ticket.Refund{}
+	if err := r.db.SelectContext(ctx, &refunds, query, ticketID); err != nil {
 		return nil, err
 	}
 
-	return transactions, nil
+	return refunds, nil
 }
 
-// CountUserTickets counts total tickets for a user
-func (r *ticketRepository) CountUserTickets(ctx context.Context, userID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM tickets WHERE user_id = $1`
-	var count int
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
-	return count, err
-}
+// GetRefundsByTransactionID gets all refunds for a payment order, newest first
+func (r *ticketRepository) GetRefundsByTransactionID(ctx context.Context, transactionID string) ([]ticket.Refund, error) {
+	query := `
+		SELECT id, ticket_id, transaction_id, refund_key, amount, reason, method, status, partial,
+		       provider_refund_id, failure_reason, created_at, updated_at, completed_at
+		FROM ticket_refunds
+		WHERE transaction_id = $1
+		ORDER BY created_at DESC
+	`
 
-// CountEventTickets counts total tickets for an event
-func (r *ticketRepository) CountEventTickets(ctx context.Context, eventID uuid.UUID) (int, error) {
-	query := `SELECT COUNT(*) FROM tickets WHERE event_id = $1`
-	var count int
-	err := r.db.QueryRowContext(ctx, query, eventID).Scan(&count)
-	return count, err
+	refunds := []ticket.Refund{}
+	if err := r.db.SelectContext(ctx, &refunds, query, transactionID); err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
 }
 
-// AtomicPurchase creates a ticket inside a serialised DB transaction:
-//  1. Locks the event row with SELECT … FOR UPDATE (blocks concurrent purchasers)
-//  2. Checks capacity — returns ticket.ErrEventFull if sold out
-//  3. INSERTs the ticket
-//  4. Increments events.tickets_sold atomically in the same transaction
+// UpdateRefundStatus updates a refund's status.
 //
-// This is the only correct way to sell tickets; the non-transactional Create
-// must not be used for ticket purchases.
-func (r *ticketRepository) AtomicPurchase(ctx context.Context, t *ticket.Ticket) error {
-	if t.ID == uuid.Nil {
-		t.ID = uuid.New()
+// The WHERE clause excludes already-succeeded refunds so that repeated or
+// late webhook deliveries cannot move a completed refund backwards.
+func (r *ticketRepository) UpdateRefundStatus(ctx context.Context, refundKey string, status ticket.RefundStatus, providerRefundID, failureReason *string) error {
+	now := time.Now()
+	var completedAt *time.Time
+	if status == ticket.RefundSucceeded || status == ticket.RefundFailed {
+		completedAt = &now
 	}
 
-	if t.AttendanceCode == "" {
-		code, err := generateAttendanceCode()
-		if err != nil {
-			return fmt.Errorf("failed to generate attendance code: %w", err)
-		}
-		t.AttendanceCode = code
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE ticket_refunds
+		SET status = $1,
+		    provider_refund_id = COALESCE($2, provider_refund_id),
+		    failure_reason = $3,
+		    completed_at = $4,
+		    updated_at = $5
+		WHERE refund_key = $6
+		  AND status <> 'succeeded'
+	`, status, providerRefundID, failureReason, completedAt, now, refundKey)
+	if err != nil {
+		return err
 	}
 
-	t.PurchasedAt = time.Now()
-
-	tx, err := r.db.BeginTxx(ctx, nil)
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ticket.ErrAlreadyProcessed
+	}
+
+	return nil
+}
+
+// CreateTier creates a new ticket tier
+func (r *ticketRepository) CreateTier(ctx context.Context, tier *ticket.Tier) error {
+	// Generate UUID if not provided
+	if tier.ID == uuid.Nil {
+		tier.ID = uuid.New()
+	}
+
+	// Set timestamps
+	now := time.Now()
+	tier.CreatedAt = now
+	tier.UpdatedAt = now
+
+	query := `
+		INSERT INTO ticket_tiers (
+			id, event_id, name, description, price, quota, sold,
+			sales_start, sales_end, max_per_user, sort_order, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, 0, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		tier.ID, tier.EventID, tier.Name, tier.Description, tier.Price, tier.Quota,
+		tier.SalesStart, tier.SalesEnd, tier.MaxPerUser, tier.SortOrder, tier.CreatedAt, tier.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetTierByID gets a ticket tier by ID
+func (r *ticketRepository) GetTierByID(ctx context.Context, tierID uuid.UUID) (*ticket.Tier, error) {
+	query := `
+		SELECT id, event_id, name, description, price, quota, sold, sales_start, sales_end,
+		       max_per_user, sort_order, created_at, updated_at
+		FROM ticket_tiers
+		WHERE id = $1
+	`
+
+	var tier ticket.Tier
+	if err := r.db.GetContext(ctx, &tier, query, tierID); err != nil {
+		return nil, err
+	}
+
+	return &tier, nil
+}
+
+// GetTiersByEvent gets the ticket tiers of an event in display order
+func (r *ticketRepository) GetTiersByEvent(ctx context.Context, eventID uuid.UUID) ([]ticket.Tier, error) {
+	query := `
+		SELECT id, event_id, name, description, price, quota, sold, sales_start, sales_end,
+		       max_per_user, sort_order, created_at, updated_at
+		FROM ticket_tiers
+		WHERE event_id = $1
+		ORDER BY sort_order ASC, created_at ASC
+	`
+
+	tiers := []ticket.Tier{}
+	if err := r.db.SelectContext(ctx, &tiers, query, eventID); err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+// UpdateTier updates a ticket tier. The sold counter is only changed by purchases.
+func (r *ticketRepository) UpdateTier(ctx context.Context, tier *ticket.Tier) error {
+	tier.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE ticket_tiers
+		SET name = $1, description = $2, price = $3, quota = $4, sales_start = $5,
+		    sales_end = $6, max_per_user = $7, sort_order = $8, updated_at = $9
+		WHERE id = $10
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		tier.Name, tier.Description, tier.Price, tier.Quota, tier.SalesStart,
+		tier.SalesEnd, tier.MaxPerUser, tier.SortOrder, tier.UpdatedAt, tier.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteTier deletes a ticket tier that has no tickets sold
+func (r *ticketRepository) DeleteTier(ctx context.Context, tierID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ticket_tiers WHERE id = $1 AND sold = 0`, tierID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// promoCodeColumns selects a promo code with its live usage count
+const promoCodeColumns = `
+	p.id, p.event_id, p.tier_id, p.code, p.discount_type, p.discount_value, p.max_uses,
+	p.max_uses_per_user, p.valid_from, p.valid_until, p.is_active, p.created_at, p.updated_at,
+	(SELECT COUNT(*) FROM tickets t WHERE t.promo_code_id = p.id AND t.status IN ('pending', 'active')) as used_count
+`
+
+// CreatePromoCode creates a new promo code
+func (r *ticketRepository) CreatePromoCode(ctx context.Context, promo *ticket.PromoCode) error {
+	// Generate UUID if not provided
+	if promo.ID == uuid.Nil {
+		promo.ID = uuid.New()
+	}
+
+	// Set timestamps
+	now := time.Now()
+	promo.CreatedAt = now
+	promo.UpdatedAt = now
+
+	query := `
+		INSERT INTO promo_codes (
+			id, event_id, tier_id, code, discount_type, discount_value, max_uses,
+			max_uses_per_user, valid_from, valid_until, is_active, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		promo.ID, promo.EventID, promo.TierID, promo.Code, promo.DiscountType, promo.DiscountValue, promo.MaxUses,
+		promo.MaxUsesPerUser, promo.ValidFrom, promo.ValidUntil, promo.IsActive, promo.CreatedAt, promo.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetPromoCodeByID gets a promo code by ID
+func (r *ticketRepository) GetPromoCodeByID(ctx context.Context, promoID uuid.UUID) (*ticket.PromoCode, error) {
+	query := `SELECT ` + promoCodeColumns + ` FROM promo_codes p WHERE p.id = $1`
+
+	var promo ticket.PromoCode
+	if err := r.db.GetContext(ctx, &promo, query, promoID); err != nil {
+		return nil, err
+	}
+
+	return &promo, nil
+}
+
+// GetPromoCodeByCode gets an event's promo code by its (upper-case) code
+func (r *ticketRepository) GetPromoCodeByCode(ctx context.Context, eventID uuid.UUID, code string) (*ticket.PromoCode, error) {
+	query := `SELECT ` + promoCodeColumns + ` FROM promo_codes p WHERE p.event_id = $1 AND p.code = $2`
+
+	var promo ticket.PromoCode
+	if err := r.db.GetContext(ctx, &promo, query, eventID, code); err != nil {
+		return nil, err
+	}
+
+	return &promo, nil
+}
+
+// GetPromoCodesByEvent gets all promo codes of an event, newest first
+func (r *ticketRepository) GetPromoCodesByEvent(ctx context.Context, eventID uuid.UUID) ([]ticket.PromoCode, error) {
+	query := `SELECT ` + promoCodeColumns + ` FROM promo_codes p WHERE p.event_id = $1 ORDER BY p.created_at DESC`
+
+	promos := []ticket.PromoCode{}
+	if err := r.db.SelectContext(ctx, &promos, query, eventID); err != nil {
+		return nil, err
+	}
+
+	return promos, nil
+}
+
+// UpdatePromoCode updates a promo code. The code itself and its event can't change.
+func (r *ticketRepository) UpdatePromoCode(ctx context.Context, promo *ticket.PromoCode) error {
+	promo.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE promo_codes
+		SET discount_type = $1, discount_value = $2, max_uses = $3, max_uses_per_user = $4,
+		    valid_from = $5, valid_until = $6, is_active = $7, updated_at = $8
+		WHERE id = $9
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		promo.DiscountType, promo.DiscountValue, promo.MaxUses, promo.MaxUsesPerUser,
+		promo.ValidFrom, promo.ValidUntil, promo.IsActive, promo.UpdatedAt, promo.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeletePromoCode deletes a promo code. Tickets that used it keep their discount_amount.
+func (r *ticketRepository) DeletePromoCode(ctx context.Context, promoID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM promo_codes WHERE id = $1`, promoID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// CountUserPromoCodeUses counts a user's pending and active tickets that used a promo code
+func (r *ticketRepository) CountUserPromoCodeUses(ctx context.Context, promoID, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM tickets WHERE promo_code_id = $1 AND user_id = $2 AND status IN ('pending', 'active')`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, promoID, userID); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ClaimPendingTransactions gets the least recently checked pending transactions
+// created within a time window and marks them checked. SKIP LOCKED keeps
+// concurrent runs from checking the same transactions.
+func (r *ticketRepository) ClaimPendingTransactions(ctx context.Context, createdAfter, createdBefore time.Time, limit int) ([]ticket.TicketTransaction, error) {
+	query := `
+		UPDATE ticket_transactions
+		SET last_checked_at = NOW()
+		WHERE id IN (
+			SELECT id
+			FROM ticket_transactions
+			WHERE status = 'pending'
+			  AND created_at > $1
+			  AND created_at < $2
+			ORDER BY last_checked_at ASC NULLS FIRST, created_at ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, ticket_id, transaction_id, amount, payment_method, status, created_at, completed_at
+	`
+
+	transactions := []ticket.TicketTransaction{}
+	if err := r.db.SelectContext(ctx, &transactions, query, createdAfter, createdBefore, limit); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// CreateReconciliationEntry records a payment discrepancy.
+// ON CONFLICT DO NOTHING keeps repeated worker runs from duplicating an entry.
+func (r *ticketRepository) CreateReconciliationEntry(ctx context.Context, entry *ticket.ReconciliationEntry) error {
+	// Generate UUID if not provided
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	// Set timestamp
+	entry.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO payment_reconciliation_reports (
+			id, transaction_id, ticket_id, event_id, discrepancy, local_status,
+			provider_status, local_amount, provider_amount, details, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (transaction_id, discrepancy) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		entry.ID, entry.TransactionID, entry.TicketID, entry.EventID, entry.Discrepancy, entry.LocalStatus,
+		entry.ProviderStatus, entry.LocalAmount, entry.ProviderAmount, entry.Details, entry.CreatedAt,
+	)
+
+	return err
+}
+
+// GetReconciliationEntries lists reconciliation entries, optionally for one event
+func (r *ticketRepository) GetReconciliationEntries(ctx context.Context, eventID *uuid.UUID, limit, offset int) ([]ticket.ReconciliationEntry, error) {
+	query := `
+		SELECT id, transaction_id, ticket_id, event_id, discrepancy, local_status,
+		       provider_status, local_amount, provider_amount, details, created_at
+		FROM payment_reconciliation_reports
+		WHERE ($1::uuid IS NULL OR event_id = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	entries := []ticket.ReconciliationEntry{}
+	if err := r.db.SelectContext(ctx, &entries, query, eventID, limit, offset); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CountReconciliationEntries counts reconciliation entries, optionally for one event
+func (r *ticketRepository) CountReconciliationEntries(ctx context.Context, eventID *uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM payment_reconciliation_reports WHERE ($1::uuid IS NULL OR event_id = $1)`
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, eventID); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetByEventID gets all tickets for an event (for analytics)
+func (r *ticketRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) ([]ticket.Ticket, error) {
+	query := `
+		SELECT id, user_id, event_id, attendance_code, price_paid, purchased_at,
+		       is_checked_in, checked_in_at, status, tier_id, promo_code_id, discount_amount
+		FROM tickets
+		WHERE event_id = $1
+		ORDER BY purchased_at DESC
+	`
+
+	var tickets []ticket.Ticket
+	err := r.db.SelectContext(ctx, &tickets, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// GetTransactionsByTicketID gets all transactions for a ticket
+func (r *ticketRepository) GetTransactionsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]ticket.TicketTransaction, error) {
+	query := `
+		SELECT id, ticket_id, transaction_id, amount, payment_method,
+		       status, created_at, completed_at
+		FROM ticket_transactions
+		WHERE ticket_id = $1
+		ORDER BY created_at DESC
+	`
+
+	var transactions []ticket.TicketTransaction
+	err := r.db.SelectContext(ctx, &transactions, query, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// CountUserTickets counts total tickets for a user
+func (r *ticketRepository) CountUserTickets(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM tickets WHERE user_id = $1`
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+// CountEventTickets counts total tickets for an event
+func (r *ticketRepository) CountEventTickets(ctx context.Context, eventID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM tickets WHERE event_id = $1`
+	var count int
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(&count)
+	return count, err
+}
+
+// AtomicPurchase creates a ticket inside a serialised DB transaction:
+//  1. Locks the event row with SELECT … FOR UPDATE (blocks concurrent purchasers)
+//  2. Checks capacity — returns ticket.ErrEventFull if sold out or the
+//     remaining spots are held by other users' open waitlist claims
+//  3. INSERTs the ticket — returns ticket.ErrAlreadyPurchased if the event has
+//     no tiers and the buyer already holds a ticket for it
+//  4. Increments events.tickets_sold atomically in the same transaction
+//  5. Marks the buyer's waitlist entry as claimed
+//
+// This is the only correct way to sell tickets; the non-transactional Create
+// must not be used for ticket purchases.
+func (r *ticketRepository) AtomicPurchase(ctx context.Context, t *ticket.Ticket) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+
+	if t.AttendanceCode == "" {
+		code, err := generateAttendanceCode()
+		if err != nil {
+			return fmt.Errorf("failed to generate attendance code: %w", err)
+		}
+		t.AttendanceCode = code
+	}
+
+	t.PurchasedAt = time.Now()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback() //nolint:errcheck
 
@@ -474,16 +1276,100 @@ func (r *ticketRepository) AtomicPurchase(ctx context.Context, t *ticket.Ticket)
 		return fmt.Errorf("failed to lock event row: %w", err)
 	}
 
-	if ticketsSold >= maxAttendees {
+	// Spots offered to other waitlisted users are reserved until their claim
+	// lapses. Claims are only made under this same lock (OfferWaitlistSpots).
+	var heldByClaims int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM event_waitlist
+		WHERE event_id = $1 AND user_id <> $2 AND status = 'offered' AND offer_expires_at > NOW()
+	`, t.EventID, t.UserID).Scan(&heldByClaims)
+	if err != nil {
+		return fmt.Errorf("failed to count waitlist claims: %w", err)
+	}
+
+	if ticketsSold+heldByClaims >= maxAttendees {
 		return ticket.ErrEventFull
 	}
 
+	if t.TierID != nil {
+		// Lock the tier row as well (always after the event row, so concurrent
+		// purchases take the locks in the same order).
+		var quota, sold, maxPerUser int
+		err = tx.QueryRowContext(ctx,
+			`SELECT quota, sold, max_per_user FROM ticket_tiers WHERE id = $1 AND event_id = $2 FOR UPDATE`,
+			t.TierID, t.EventID,
+		).Scan(&quota, &sold, &maxPerUser)
+		if err != nil {
+			return fmt.Errorf("failed to lock tier row: %w", err)
+		}
+
+		if sold >= quota {
+			return ticket.ErrTierSoldOut
+		}
+
+		// The tier lock serialises purchases of this tier, so the count is stable.
+		var held int
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM tickets
+			WHERE tier_id = $1 AND user_id = $2 AND status IN ('pending', 'active')
+		`, t.TierID, t.UserID).Scan(&held)
+		if err != nil {
+			return fmt.Errorf("failed to count tier tickets: %w", err)
+		}
+
+		if held >= maxPerUser {
+			return ticket.ErrTierLimitReached
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`UPDATE ticket_tiers SET sold = sold + 1, updated_at = NOW() WHERE id = $1`,
+			t.TierID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to increment tier sold: %w", err)
+		}
+	}
+
+	if t.PromoCodeID != nil {
+		// Lock the promo code row last; usage is counted from the tickets that
+		// hold the code, so the lock makes the count-then-insert atomic.
+		var maxUses sql.NullInt64
+		var maxUsesPerUser int
+		err = tx.QueryRowContext(ctx,
+			`SELECT max_uses, max_uses_per_user FROM promo_codes WHERE id = $1 AND event_id = $2 FOR UPDATE`,
+			t.PromoCodeID, t.EventID,
+		).Scan(&maxUses, &maxUsesPerUser)
+		if err != nil {
+			return fmt.Errorf("failed to lock promo code row: %w", err)
+		}
+
+		var used, usedByUser int
+		err = tx.QueryRowContext(ctx, `
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $2)
+			FROM tickets
+			WHERE promo_code_id = $1 AND status IN ('pending', 'active')
+		`, t.PromoCodeID, t.UserID).Scan(&used, &usedByUser)
+		if err != nil {
+			return fmt.Errorf("failed to count promo code uses: %w", err)
+		}
+
+		if maxUses.Valid && int64(used) >= maxUses.Int64 {
+			return ticket.ErrPromoCodeExhausted
+		}
+		if usedByUser >= maxUsesPerUser {
+			return ticket.ErrPromoCodeLimitReached
+		}
+	}
+
 	// Insert the ticket.
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO tickets (id, user_id, event_id, attendance_code, price_paid, purchased_at, is_checked_in, status)
-		VALUES ($1, $2, $3, $4, $5, $6, FALSE, $7)
-	`, t.ID, t.UserID, t.EventID, t.AttendanceCode, t.PricePaid, t.PurchasedAt, t.Status)
+		INSERT INTO tickets (id, user_id, event_id, attendance_code, price_paid, purchased_at, is_checked_in, status, tier_id, promo_code_id, discount_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, FALSE, $7, $8, $9, $10)
+	`, t.ID, t.UserID, t.EventID, t.AttendanceCode, t.PricePaid, t.PurchasedAt, t.Status, t.TierID, t.PromoCodeID, t.DiscountAmount)
 	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == uniqueUntieredTicket {
+			return ticket.ErrAlreadyPurchased
+		}
 		return fmt.Errorf("failed to insert ticket: %w", err)
 	}
 
@@ -496,17 +1382,34 @@ func (r *ticketRepository) AtomicPurchase(ctx context.Context, t *ticket.Ticket)
 		return fmt.Errorf("failed to increment tickets_sold: %w", err)
 	}
 
+	// The buyer no longer needs a place in line (or the claim they just used).
+	_, err = tx.ExecContext(ctx, `
+		UPDATE event_waitlist SET status = 'claimed', updated_at = NOW()
+		WHERE event_id = $1 AND user_id = $2 AND status IN ('waiting', 'offered')
+	`, t.EventID, t.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to claim waitlist entry: %w", err)
+	}
+
 	return tx.Commit()
 }
 
 // DecrementTicketsSold decrements events.tickets_sold by 1 (floor 0).
 // Call this whenever a pending ticket is cancelled, failed, or expired so
 // the capacity counter stays in sync with actual sellable spots.
-func (r *ticketRepository) DecrementTicketsSold(ctx context.Context, eventID uuid.UUID) error {
+func (r *ticketRepository) DecrementTicketsSold(ctx context.Context, eventID uuid.UUID, tierID *uuid.UUID) error {
 	_, err := r.db.ExecContext(ctx,
 		`UPDATE events SET tickets_sold = GREATEST(tickets_sold - 1, 0) WHERE id = $1`,
 		eventID,
 	)
+	if err != nil || tierID == nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`UPDATE ticket_tiers SET sold = GREATEST(sold - 1, 0), updated_at = NOW() WHERE id = $1`,
+		tierID,
+	)
 	return err
 }
 
@@ -520,7 +1423,7 @@ func (r *ticketRepository) ExpirePendingTickets(ctx context.Context, olderThan t
 		WHERE status = 'pending'
 		  AND purchased_at < $1
 		RETURNING id, user_id, event_id, attendance_code, price_paid, purchased_at,
-		          is_checked_in, checked_in_at, status
+		          is_checked_in, checked_in_at, status, tier_id, promo_code_id, discount_amount
 	`, olderThan)
 	if err != nil {
 		return nil, err
@@ -532,7 +1435,8 @@ func (r *ticketRepository) ExpirePendingTickets(ctx context.Context, olderThan t
 		var t ticket.Ticket
 		if err := rows.Scan(
 			&t.ID, &t.UserID, &t.EventID, &t.AttendanceCode,
-			&t.PricePaid, &t.PurchasedAt, &t.IsCheckedIn, &t.CheckedInAt, &t.Status,
+			&t.PricePaid, &t.PurchasedAt, &t.IsCheckedIn, &t.CheckedInAt, &t.Status, &t.TierID,
+			&t.PromoCodeID, &t.DiscountAmount,
 		); err != nil {
 			return nil, err
 		}
@@ -540,3 +1444,177 @@ func (r *ticketRepository) ExpirePendingTickets(ctx context.Context, olderThan t
 	}
 	return tickets, rows.Err()
 }
+
+// JoinWaitlist puts the user at the end of the event's waitlist
+func (r *ticketRepository) JoinWaitlist(ctx context.Context, entry *ticket.WaitlistEntry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	entry.Status = ticket.WaitlistWaiting
+	entry.JoinedAt = time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO event_waitlist (id, event_id, user_id, status, joined_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (event_id, user_id) WHERE status IN ('waiting', 'offered') DO NOTHING
+	`, entry.ID, entry.EventID, entry.UserID, entry.Status, entry.JoinedAt)
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ticket.ErrAlreadyWaitlisted
+	}
+	return nil
+}
+
+// GetWaitlistEntry gets the user's waiting or offered entry of the event.
+// Position counts the waiting users who joined earlier; it is 0 for offered entries.
+func (r *ticketRepository) GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*ticket.WaitlistEntry, error) {
+	query := `
+		SELECT w.id, w.event_id, w.user_id, w.status, w.joined_at, w.offered_at, w.offer_expires_at,
+			CASE WHEN w.status = 'waiting' THEN (
+				SELECT COUNT(*) FROM event_waitlist ahead
+				WHERE ahead.event_id = w.event_id AND ahead.status = 'waiting'
+					AND (ahead.joined_at, ahead.id) <= (w.joined_at, w.id)
+			) ELSE 0 END as position
+		FROM event_waitlist w
+		WHERE w.event_id = $1 AND w.user_id = $2 AND w.status IN ('waiting', 'offered')
+	`
+
+	var entry ticket.WaitlistEntry
+	err := r.db.GetContext(ctx, &entry, query, eventID, userID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("waitlist entry not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// LeaveWaitlist removes the user from the event's waitlist
+func (r *ticketRepository) LeaveWaitlist(ctx context.Context, eventID, userID uuid.UUID) (*ticket.WaitlistEntry, error) {
+	// The returned status is the one before the update, so callers know whether a claim was given up
+	query := `
+		UPDATE event_waitlist w SET status = 'left', updated_at = NOW()
+		FROM event_waitlist prev
+		WHERE prev.id = w.id AND w.event_id = $1 AND w.user_id = $2 AND w.status IN ('waiting', 'offered')
+		RETURNING w.id, w.event_id, w.user_id, prev.status, w.joined_at, w.offered_at, w.offer_expires_at
+	`
+
+	var entry ticket.WaitlistEntry
+	err := r.db.GetContext(ctx, &entry, query, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetEventWaitlist lists the waiting and offered entries of an event in queue order
+func (r *ticketRepository) GetEventWaitlist(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]ticket.WaitlistEntryWithUser, error) {
+	query := `
+		SELECT w.id, w.event_id, w.user_id, w.status, w.joined_at, w.offered_at, w.offer_expires_at,
+			CASE WHEN w.status = 'waiting'
+				THEN ROW_NUMBER() OVER (PARTITION BY w.status ORDER BY w.joined_at, w.id)
+				ELSE 0 END as position,
+			u.name as user_name, u.username as user_username, u.avatar_url as user_avatar_url
+		FROM event_waitlist w
+		INNER JOIN users u ON w.user_id = u.id
+		WHERE w.event_id = $1 AND w.status IN ('waiting', 'offered')
+		ORDER BY w.status = 'waiting', w.joined_at, w.id
+		LIMIT $2 OFFSET $3
+	`
+
+	entries := make([]ticket.WaitlistEntryWithUser, 0)
+	err := r.db.SelectContext(ctx, &entries, query, eventID, limit, offset)
+	return entries, err
+}
+
+// CountEventWaitlist counts the waiting and offered entries of an event
+func (r *ticketRepository) CountEventWaitlist(ctx context.Context, eventID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM event_waitlist WHERE event_id = $1 AND status IN ('waiting', 'offered')`
+	var count int
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(&count)
+	return count, err
+}
+
+// OfferWaitlistSpots offers the event's free spots to the next waiting users.
+// It takes the same event row lock as AtomicPurchase, so a spot is never both
+// sold and offered.
+func (r *ticketRepository) OfferWaitlistSpots(ctx context.Context, eventID uuid.UUID, claimWindow time.Duration) ([]ticket.WaitlistEntry, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var maxAttendees, ticketsSold int
+	err = tx.QueryRowContext(ctx,
+		`SELECT max_attendees, tickets_sold FROM events WHERE id = $1 FOR UPDATE`,
+		eventID,
+	).Scan(&maxAttendees, &ticketsSold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock event row: %w", err)
+	}
+
+	// Lapsed claims pass to the next user in line
+	_, err = tx.ExecContext(ctx, `
+		UPDATE event_waitlist SET status = 'expired', updated_at = NOW()
+		WHERE event_id = $1 AND status = 'offered' AND offer_expires_at <= NOW()
+	`, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire waitlist claims: %w", err)
+	}
+
+	var openClaims int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM event_waitlist WHERE event_id = $1 AND status = 'offered'`,
+		eventID,
+	).Scan(&openClaims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count waitlist claims: %w", err)
+	}
+
+	offered := make([]ticket.WaitlistEntry, 0)
+	free := maxAttendees - ticketsSold - openClaims
+	if free > 0 {
+		err = tx.SelectContext(ctx, &offered, `
+			UPDATE event_waitlist
+			SET status = 'offered', offered_at = NOW(), offer_expires_at = NOW() + make_interval(secs => $3), updated_at = NOW()
+			WHERE id IN (
+				SELECT id FROM event_waitlist
+				WHERE event_id = $1 AND status = 'waiting'
+				ORDER BY joined_at, id
+				LIMIT $2
+			)
+			RETURNING id, event_id, user_id, status, joined_at, offered_at, offer_expires_at
+		`, eventID, free, int(claimWindow.Seconds()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to offer waitlist spots: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return offered, nil
+}
+
+// GetWaitlistedEventIDs returns upcoming events with users waiting or claims that lapsed
+func (r *ticketRepository) GetWaitlistedEventIDs(ctx context.Context) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT w.event_id
+		FROM event_waitlist w
+		INNER JOIN events e ON w.event_id = e.id
+		WHERE e.start_time > NOW()
+			AND (w.status = 'waiting' OR (w.status = 'offered' AND w.offer_expires_at <= NOW()))
+	`
+
+	eventIDs := make([]uuid.UUID, 0)
+	err := r.db.SelectContext(ctx, &eventIDs, query)
+	return eventIDs, err
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -198,6 +199,40 @@ func (r *userRepository) GetProfileByUsername(ctx context.Context, username stri
 	return nil, fmt.Errorf("user not found")
 }
 
+// GetSettings gets user settings
+func (r *userRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*user.UserSettings, error) {
+	query := `
+		SELECT user_id, push_notifications, email_notifications, dark_mode, language, location_enabled, show_online_status
+		FROM user_settings WHERE user_id = $1
+	`
+
+	var settings user.UserSettings
+	if err := r.db.GetContext(ctx, &settings, query, userID); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// GetEmailRecipients gets users with their email settings, defaulting the ones without settings
+func (r *userRepository) GetEmailRecipients(ctx context.Context, userIDs []uuid.UUID) ([]user.EmailRecipient, error) {
+	query := `
+		SELECT u.id, u.name, u.email,
+			COALESCE(s.email_notifications, TRUE) AS email_notifications,
+			COALESCE(s.language, 'en') AS language
+		FROM users u
+		LEFT JOIN user_settings s ON s.user_id = u.id
+		WHERE u.id = ANY($1)
+	`
+
+	recipients := make([]user.EmailRecipient, 0)
+	if err := r.db.SelectContext(ctx, &recipients, query, pq.Array(userIDs)); err != nil {
+		return nil, err
+	}
+
+	return recipients, nil
+}
+
 // UpdateSettings updates user settings
 func (r *userRepository) UpdateSettings(ctx context.Context, settings *user.UserSettings) error {
 	query := `
@@ -276,43 +311,52 @@ func (r *userRepository) Unfollow(ctx context.Context, followerID, followingID u
 }
 
 // GetFollowers gets users following a specific user
-func (r *userRepository) GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]user.User, error) {
+func (r *userRepository) GetFollowers(ctx context.Context, userID uuid.UUID, before *utils.Cursor, limit, offset int) ([]user.Follower, error) {
 	query := `
 		SELECT u.id, u.email, u.username, u.role, u.name as name, u.bio, u.avatar_url,
 		       u.phone, u.date_of_birth, u.gender, u.location, u.interests,
-		       u.created_at, u.updated_at, u.last_login_at, u.is_verified, u.is_email_verified
+		       u.created_at, u.updated_at, u.last_login_at, u.is_verified, u.is_email_verified,
+		       f.created_at as followed_at
 		FROM users u
 		INNER JOIN follows f ON u.id = f.follower_id
 		WHERE f.following_id = $1
-		ORDER BY f.created_at DESC
+			AND ($4::timestamptz IS NULL OR (f.created_at, u.id) < ($4::timestamptz, $5::uuid))
+		ORDER BY f.created_at DESC, u.id DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	var beforeFollowedAt *time.Time
+	var beforeID *uuid.UUID
+	if before != nil {
+		beforeFollowedAt, beforeID = &before.CreatedAt, &before.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset, beforeFollowedAt, beforeID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []user.User
+	var followers []user.Follower
 	for rows.Next() {
-		var u user.User
+		var u user.Follower
 		err := rows.Scan(
 			&u.ID, &u.Email, &u.Username, &u.Role, &u.Name, &u.Bio, &u.AvatarURL,
 			&u.Phone, &u.DateOfBirth, &u.Gender, &u.Location, pq.Array(&u.Interests),
 			&u.CreatedAt, &u.UpdatedAt, &u.LastLoginAt, &u.IsVerified, &u.IsEmailVerified,
+			&u.FollowedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		users = append(users, u)
+		followers = append(followers, u)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return users, nil
+	return followers, nil
 }
 
 // GetFollowing gets users that a specific user is following
@@ -364,6 +408,29 @@ func (r *userRepository) IsFollowing(ctx context.Context, followerID, followingI
 	return exists, err
 }
 
+// GetFollowerIDs gets the IDs of all users following a user
+func (r *userRepository) GetFollowerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `SELECT follower_id FROM follows WHERE following_id = $1`
+
+	ids := []uuid.UUID{}
+	err := r.db.SelectContext(ctx, &ids, query, userID)
+	return ids, err
+}
+
+// GetFollowingIDs gets the IDs of the users a user follows that have at least minFollowers followers
+func (r *userRepository) GetFollowingIDs(ctx context.Context, userID uuid.UUID, minFollowers int) ([]uuid.UUID, error) {
+	query := `
+		SELECT f.following_id
+		FROM follows f
+		LEFT JOIN user_stats us ON us.user_id = f.following_id
+		WHERE f.follower_id = $1 AND COALESCE(us.followers_count, 0) >= $2
+	`
+
+	ids := []uuid.UUID{}
+	err := r.db.SelectContext(ctx, &ids, query, userID, minFollowers)
+	return ids, err
+}
+
 // GetStats gets user statistics
 func (r *userRepository) GetStats(ctx context.Context, userID uuid.UUID) (*user.UserStats, error) {
 	var stats user.UserStats
@@ -0,0 +1,176 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	timelineKeyPrefix = "timeline:home:"
+	// Keep the newest entries only; older pages are not served from the cache
+	timelineMaxEntries = 800
+	// Timelines expire this long after they are built, whether or not they are read,
+	// and are rebuilt from the database on the next read. This bounds how long a
+	// missed fan-out or removal can linger.
+	timelineTTL = 7 * 24 * time.Hour
+	// Keys per script call when fanning out to many followers
+	timelineFanOutBatch = 500
+)
+
+// addToTimelinesScript adds a post to every given timeline that is currently cached
+// and trims it. Timelines that are not cached are skipped so they are never left
+// holding only the posts created after they expired.
+// ARGV: score, post ID, max entries
+var addToTimelinesScript = redis.NewScript(`
+for _, key in ipairs(KEYS) do
+	if redis.call('EXISTS', key) == 1 then
+		redis.call('ZADD', key, ARGV[1], ARGV[2])
+		redis.call('ZREMRANGEBYRANK', key, 0, -(tonumber(ARGV[3]) + 1))
+	end
+end
+return 0
+`)
+
+type timelineRepository struct {
+	client *redis.Client
+}
+
+// NewTimelineRepository creates a new Redis-backed home timeline repository.
+// Each timeline is a sorted set of post IDs scored by creation time in microseconds.
+func NewTimelineRepository(client *redis.Client) post.TimelineRepository {
+	return &timelineRepository{client: client}
+}
+
+func timelineKey(userID uuid.UUID) string {
+	return timelineKeyPrefix + userID.String()
+}
+
+func timelineScore(createdAt time.Time) float64 {
+	return float64(createdAt.UnixMicro())
+}
+
+// Add pushes a post to the cached timelines of the given users
+func (r *timelineRepository) Add(ctx context.Context, userIDs []uuid.UUID, entry post.TimelineEntry) error {
+	score := strconv.FormatInt(entry.CreatedAt.UnixMicro(), 10)
+
+	for start := 0; start < len(userIDs); start += timelineFanOutBatch {
+		end := start + timelineFanOutBatch
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		keys := make([]string, 0, end-start)
+		for _, userID := range userIDs[start:end] {
+			keys = append(keys, timelineKey(userID))
+		}
+
+		err := addToTimelinesScript.Run(ctx, r.client, keys, score, entry.PostID.String(), timelineMaxEntries).Err()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove deletes a post from the timelines of the given users
+func (r *timelineRepository) Remove(ctx context.Context, userIDs []uuid.UUID, postID uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, userID := range userIDs {
+			pipe.ZRem(ctx, timelineKey(userID), postID.String())
+		}
+		return nil
+	})
+	return err
+}
+
+// Get returns up to limit entries strictly older than before, newest first.
+// Entries with the same timestamp are ordered by post ID, descending.
+func (r *timelineRepository) Get(ctx context.Context, userID uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, bool, error) {
+	key := timelineKey(userID)
+
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil || exists == 0 {
+		return nil, false, err
+	}
+
+	max := "+inf"
+	if before != nil {
+		// Inclusive, so entries sharing the cursor's timestamp are filtered below
+		max = strconv.FormatInt(before.CreatedAt.UnixMicro(), 10)
+	}
+
+	var entries []post.TimelineEntry
+	var offset int64
+	for len(entries) < limit {
+		batch, err := r.client.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+			Min:    "-inf",
+			Max:    max,
+			Offset: offset,
+			Count:  int64(limit),
+		}).Result()
+		if err != nil {
+			return nil, true, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		offset += int64(len(batch))
+
+		for _, z := range batch {
+			member, _ := z.Member.(string)
+			postID, err := uuid.Parse(member)
+			if err != nil {
+				continue
+			}
+
+			createdAt := time.UnixMicro(int64(z.Score))
+			if before != nil && createdAt.Equal(before.CreatedAt) && member >= before.PostID.String() {
+				continue
+			}
+
+			entries = append(entries, post.TimelineEntry{PostID: postID, CreatedAt: createdAt})
+			if len(entries) == limit {
+				break
+			}
+		}
+	}
+
+	return entries, true, nil
+}
+
+// Invalidate deletes a user's cached timeline
+func (r *timelineRepository) Invalidate(ctx context.Context, userID uuid.UUID) error {
+	return r.client.Del(ctx, timelineKey(userID)).Err()
+}
+
+// Seed replaces a user's cached timeline with the given entries
+func (r *timelineRepository) Seed(ctx context.Context, userID uuid.UUID, entries []post.TimelineEntry) error {
+	key := timelineKey(userID)
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		if len(entries) == 0 {
+			return nil
+		}
+
+		members := make([]redis.Z, len(entries))
+		for i, entry := range entries {
+			members[i] = redis.Z{Score: timelineScore(entry.CreatedAt), Member: entry.PostID.String()}
+		}
+		pipe.ZAdd(ctx, key, members...)
+		pipe.ZRemRangeByRank(ctx, key, 0, -(timelineMaxEntries + 1))
+		pipe.Expire(ctx, key, timelineTTL)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/auth"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	revokedTokenKeyPrefix = "auth:revoked:jti:"
+	revokedUserKeyPrefix  = "auth:revoked:user:"
+)
+
+type tokenRevocationRepository struct {
+	client *redis.Client
+}
+
+// NewTokenRevocationRepository creates a new Redis-backed token revocation repository
+func NewTokenRevocationRepository(client *redis.Client) auth.RevocationRepository {
+	return &tokenRevocationRepository{client: client}
+}
+
+// RevokeToken stores the jti until the token would have expired on its own
+func (r *tokenRevocationRepository) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	// Already-expired tokens are rejected by signature validation; nothing to store
+	if tokenID == "" || ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, revokedTokenKeyPrefix+tokenID, 1, ttl).Err()
+}
+
+// IsTokenRevoked checks whether a jti is on the revocation list
+func (r *tokenRevocationRepository) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	if tokenID == "" {
+		return false, nil
+	}
+	n, err := r.client.Exists(ctx, revokedTokenKeyPrefix+tokenID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RevokeUserTokens records a cut-off time; every token of the user issued at or before it is rejected
+func (r *tokenRevocationRepository) RevokeUserTokens(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, revokedUserKeyPrefix+userID.String(), issuedBefore.Unix(), ttl).Err()
+}
+
+// IsUserTokenRevoked checks a token's issue time against the user's revocation cut-off
+func (r *tokenRevocationRepository) IsUserTokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	value, err := r.client.Get(ctx, revokedUserKeyPrefix+userID.String()).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	cutoff, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	// JWT iat has second precision, so compare in whole seconds
+	return issuedAt.Unix() <= cutoff, nil
+}
@@ -1,40 +1,51 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"html"
 	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
 	"strings"
+	"time"
 
+	"github.com/anigmaa/backend/config"
+	"github.com/anigmaa/backend/internal/domain/email"
 	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/google/uuid"
 )
 
-// EmailConfig holds email service configuration
-type EmailConfig struct {
-	SMTPHost     string
-	SMTPPort     int
-	SMTPUsername string
-	SMTPPassword string
-	FromEmail    string
-	FromName     string
-}
+// smtpTimeout bounds a whole SMTP conversation
+const smtpTimeout = 30 * time.Second
 
-// EmailService handles email sending functionality
+// EmailService delivers rendered emails over SMTP. Emails are queued in the
+// outbox, by the email usecase or the Send* helpers below; the outbox worker
+// hands them to Send.
 type EmailService struct {
-	config *EmailConfig
+	config *config.EmailConfig
+	outbox email.Repository
 }
 
 // NewEmailService creates a new email service
-func NewEmailService(config *EmailConfig) *EmailService {
+func NewEmailService(cfg *config.EmailConfig, outbox email.Repository) *EmailService {
 	return &EmailService{
-		config: config,
+		config: cfg,
+		outbox: outbox,
 	}
 }
 
 // SendEmailVerification sends verification email to user
 func (es *EmailService) SendEmailVerification(ctx context.Context, user *user.User, token string) error {
-	if es.config == nil || es.config.SMTPHost == "" {
+	if !es.IsConfigured() {
 		log.Printf("Email service not configured, skipping verification email to %s", user.Email)
 		return nil
 	}
@@ -55,12 +66,12 @@ Best regards,
 The Anigmaa Team
 `, user.Name, token)
 
-	return es.sendEmail(user.Email, subject, body)
+	return es.queue(ctx, &user.ID, user.Email, email.TemplateEmailVerification, subject, body)
 }
 
 // SendPasswordReset sends password reset email to user
 func (es *EmailService) SendPasswordReset(ctx context.Context, user *user.User, token string) error {
-	if es.config == nil || es.config.SMTPHost == "" {
+	if !es.IsConfigured() {
 		log.Printf("Email service not configured, skipping password reset email to %s", user.Email)
 		return nil
 	}
@@ -81,12 +92,12 @@ Best regards,
 The Anigmaa Team
 `, user.Name, token)
 
-	return es.sendEmail(user.Email, subject, body)
+	return es.queue(ctx, &user.ID, user.Email, email.TemplatePasswordReset, subject, body)
 }
 
 // SendWelcomeEmail sends welcome email to newly verified user
 func (es *EmailService) SendWelcomeEmail(ctx context.Context, user *user.User) error {
-	if es.config == nil || es.config.SMTPHost == "" {
+	if !es.IsConfigured() {
 		log.Printf("Email service not configured, skipping welcome email to %s", user.Email)
 		return nil
 	}
@@ -108,58 +119,204 @@ Best regards,
 The Anigmaa Team
 `, user.Name)
 
-	return es.sendEmail(user.Email, subject, body)
+	return es.queue(ctx, &user.ID, user.Email, email.TemplateWelcome, subject, body)
 }
 
-// sendEmail sends an email using SMTP
-func (es *EmailService) sendEmail(to, subject, body string) error {
-	if es.config == nil {
-		return fmt.Errorf("email service not configured")
+// SendBulkEmail sends email to multiple recipients (for newsletters, announcements, etc.)
+func (es *EmailService) SendBulkEmail(ctx context.Context, recipients []string, subject, body string) error {
+	if !es.IsConfigured() {
+		log.Printf("Email service not configured, skipping bulk email to %d recipients", len(recipients))
+		return nil
+	}
+
+	var errors []string
+
+	for _, recipient := range recipients {
+		if err := es.queue(ctx, nil, recipient, email.TemplateBulk, subject, body); err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to send to %s: %v", recipient, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("bulk email completed with errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+// queue stores a plain-text email in the outbox for the outbox worker to send
+func (es *EmailService) queue(ctx context.Context, userID *uuid.UUID, to string, tmpl email.Template, subject, body string) error {
+	if es.outbox == nil {
+		return fmt.Errorf("email outbox not configured")
 	}
 
-	// Create message
-	message := fmt.Sprintf("From: %s <%s>\r\n", es.config.FromName, es.config.FromEmail)
-	message += fmt.Sprintf("To: %s\r\n", to)
-	message += fmt.Sprintf("Subject: %s\r\n", subject)
-	message += "MIME-version: 1.0;\nContent-Type: text/plain; charset=\"UTF-8\"\r\n\r\n"
-	message += body
+	return es.outbox.Enqueue(ctx, &email.Email{
+		UserID:    userID,
+		Recipient: to,
+		Template:  tmpl,
+		Language:  "en",
+		Subject:   subject,
+		HTMLBody:  plainTextHTML(body),
+	})
+}
+
+// IsConfigured returns true if an SMTP server and sender address are set.
+// Credentials are optional, e.g. for a local SMTP stand-in.
+func (es *EmailService) IsConfigured() bool {
+	return es.config != nil &&
+		es.config.SMTPHost != "" &&
+		es.config.FromEmail != ""
+}
 
-	// SMTP authentication
-	auth := smtp.PlainAuth("", es.config.SMTPUsername, es.config.SMTPPassword, es.config.SMTPHost)
+// Send sends an email with its attachments
+func (es *EmailService) Send(ctx context.Context, e *email.Email) error {
+	if !es.IsConfigured() {
+		return fmt.Errorf("email service not configured")
+	}
 
-	// Send email
-	addr := fmt.Sprintf("%s:%d", es.config.SMTPHost, es.config.SMTPPort)
-	err := smtp.SendMail(addr, auth, es.config.FromEmail, []string{to}, []byte(message))
+	message, err := es.buildMessage(e)
 	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	if err := es.sendMail(ctx, e.Recipient, message); err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 
-	log.Printf("Email sent successfully to %s", to)
+	log.Printf("Email %s sent successfully to %s", e.Template, e.Recipient)
 	return nil
 }
 
-// IsConfigured returns true if email service is properly configured
-func (es *EmailService) IsConfigured() bool {
-	return es.config != nil && 
-		   es.config.SMTPHost != "" && 
-		   es.config.SMTPUsername != "" && 
-		   es.config.SMTPPassword != "" && 
-		   es.config.FromEmail != ""
+// buildMessage builds the MIME message of an email: the HTML body followed by
+// its attachments
+func (es *EmailService) buildMessage(e *email.Email) ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", (&mail.Address{Name: es.config.FromName, Address: es.config.FromEmail}).String())
+	header("To", e.Recipient)
+	header("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", e.ID, domainOf(es.config.FromEmail)))
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf("multipart/mixed; boundary=%q", body.Boundary()))
+	buf.WriteString("\r\n")
+
+	htmlPart, err := body.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(htmlPart)
+	if _, err := qp.Write([]byte(e.HTMLBody)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, a := range e.Attachments {
+		part, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", a.ContentType, a.Filename)},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", a.Filename)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(wrapBase64(a.Content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-// SendBulkEmail sends email to multiple recipients (for newsletters, announcements, etc.)
-func (es *EmailService) SendBulkEmail(ctx context.Context, recipients []string, subject, body string) error {
-	var errors []string
-	
-	for _, recipient := range recipients {
-		if err := es.sendEmail(recipient, subject, body); err != nil {
-			errors = append(errors, fmt.Sprintf("Failed to send to %s: %v", recipient, err))
+// sendMail runs the SMTP conversation, upgrading to TLS when the server offers it
+func (es *EmailService) sendMail(ctx context.Context, to string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(es.config.SMTPHost, fmt.Sprint(es.config.SMTPPort))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, es.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: es.config.SMTPHost}); err != nil {
+			return err
 		}
 	}
-	
-	if len(errors) > 0 {
-		return fmt.Errorf("bulk email completed with errors: %s", strings.Join(errors, "; "))
+	if es.config.SMTPUsername != "" {
+		auth := smtp.PlainAuth("", es.config.SMTPUsername, es.config.SMTPPassword, es.config.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
 	}
-	
-	return nil
+
+	if err := client.Mail(es.config.FromEmail); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// plainTextHTML wraps a plain-text body in HTML, keeping its line breaks
+func plainTextHTML(body string) string {
+	return `<pre style="font-family: inherit; white-space: pre-wrap">` + html.EscapeString(strings.TrimSpace(body)) + `</pre>`
+}
+
+// domainOf returns the domain of an email address, used for Message-IDs
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
+
+// wrapBase64 encodes content as base64 in lines of 76 characters (RFC 2045)
+func wrapBase64(content []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(content)
+
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76])
+		buf.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
 }
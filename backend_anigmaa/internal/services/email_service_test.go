@@ -0,0 +1,233 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/anigmaa/backend/config"
+	"github.com/anigmaa/backend/internal/domain/email"
+	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// smtpMessage is a message received by the SMTP stand-in
+type smtpMessage struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// startSMTPStandIn runs a minimal SMTP server on localhost that accepts one
+// message, without TLS or authentication like a local development stand-in
+func startSMTPStandIn(t *testing.T) (host string, port int, received <-chan smtpMessage) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		var msg smtpMessage
+		_ = tp.PrintfLine("220 localhost ESMTP stand-in")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch verb {
+			case "EHLO", "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case "MAIL":
+				msg.from = strings.TrimSuffix(strings.TrimPrefix(line[len("MAIL FROM:"):], "<"), ">")
+				_ = tp.PrintfLine("250 OK")
+			case "RCPT":
+				msg.to = append(msg.to, strings.TrimSuffix(strings.TrimPrefix(line[len("RCPT TO:"):], "<"), ">"))
+				_ = tp.PrintfLine("250 OK")
+			case "DATA":
+				_ = tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				msg.data, err = tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				_ = tp.PrintfLine("250 OK")
+				messages <- msg
+			case "QUIT":
+				_ = tp.PrintfLine("221 Bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 Command not implemented")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, messages
+}
+
+func TestEmailService_Send(t *testing.T) {
+	host, port, received := startSMTPStandIn(t)
+
+	svc := NewEmailService(&config.EmailConfig{
+		SMTPHost:  host,
+		SMTPPort:  port,
+		FromEmail: "noreply@anigmaa.com",
+		FromName:  "Anigmaa",
+	}, nil)
+	if !svc.IsConfigured() {
+		t.Fatal("IsConfigured() = false, want true without credentials")
+	}
+
+	qr := bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 64)
+	e := &email.Email{
+		ID:        uuid.New(),
+		Recipient: "budi@example.com",
+		Template:  email.TemplateTicketConfirmation,
+		Subject:   "Tiketmu untuk Jazz & Kopi",
+		HTMLBody:  "<p>Hai Budi, sampai jumpa di <strong>Jazz &amp; Kopi</strong>!</p>",
+		Attachments: []email.Attachment{
+			{Filename: "ticket-qr.png", ContentType: "image/png", Content: qr},
+		},
+	}
+	if err := svc.Send(context.Background(), e); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	msg := <-received
+	if msg.from != "noreply@anigmaa.com" {
+		t.Errorf("MAIL FROM = %q, want noreply@anigmaa.com", msg.from)
+	}
+	if len(msg.to) != 1 || msg.to[0] != "budi@example.com" {
+		t.Errorf("RCPT TO = %v, want [budi@example.com]", msg.to)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(msg.data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != e.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, e.Subject)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q, want multipart/mixed", parsed.Header.Get("Content-Type"))
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	htmlPart, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("failed to read HTML part: %v", err)
+	}
+	html, _ := io.ReadAll(htmlPart)
+	if string(html) != e.HTMLBody {
+		t.Errorf("HTML body = %q, want %q", html, e.HTMLBody)
+	}
+
+	attachment, err := reader.NextPart()
+	if err != nil {
+		t.Fatalf("failed to read attachment: %v", err)
+	}
+	if attachment.FileName() != "ticket-qr.png" {
+		t.Errorf("attachment filename = %q, want ticket-qr.png", attachment.FileName())
+	}
+	encoded, _ := io.ReadAll(attachment)
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if err != nil || !bytes.Equal(content, qr) {
+		t.Errorf("attachment content doesn't match (err = %v)", err)
+	}
+}
+
+func TestEmailService_SendUnreachable(t *testing.T) {
+	// Nothing listens on the port once the listener is closed
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	svc := NewEmailService(&config.EmailConfig{
+		SMTPHost:  "127.0.0.1",
+		SMTPPort:  port,
+		FromEmail: "noreply@anigmaa.com",
+	}, nil)
+	err = svc.Send(context.Background(), &email.Email{ID: uuid.New(), Recipient: "budi@example.com"})
+	if err == nil {
+		t.Fatalf("Send() to 127.0.0.1:%d error = nil, want an error", port)
+	}
+}
+
+// fakeOutbox records the emails queued in the outbox
+type fakeOutbox struct {
+	email.Repository
+	enqueued []*email.Email
+}
+
+func (o *fakeOutbox) Enqueue(ctx context.Context, e *email.Email) error {
+	o.enqueued = append(o.enqueued, e)
+	return nil
+}
+
+func TestEmailService_Queue(t *testing.T) {
+	u := &user.User{ID: uuid.New(), Name: "Budi <admin>", Email: "budi@example.com"}
+	configured := &config.EmailConfig{SMTPHost: "127.0.0.1", SMTPPort: 25, FromEmail: "noreply@anigmaa.com"}
+
+	t.Run("queues in the outbox", func(t *testing.T) {
+		outbox := &fakeOutbox{}
+		svc := NewEmailService(configured, outbox)
+
+		if err := svc.SendPasswordReset(context.Background(), u, "tok123"); err != nil {
+			t.Fatalf("SendPasswordReset() error = %v", err)
+		}
+		if err := svc.SendBulkEmail(context.Background(), []string{"a@example.com", "b@example.com"}, "News", "Hello"); err != nil {
+			t.Fatalf("SendBulkEmail() error = %v", err)
+		}
+
+		if len(outbox.enqueued) != 3 {
+			t.Fatalf("enqueued %d emails, want 3", len(outbox.enqueued))
+		}
+		reset := outbox.enqueued[0]
+		if reset.Template != email.TemplatePasswordReset || reset.Recipient != u.Email || reset.UserID == nil || *reset.UserID != u.ID {
+			t.Errorf("enqueued %s email to %s, want password reset to %s", reset.Template, reset.Recipient, u.Email)
+		}
+		if !strings.Contains(reset.HTMLBody, "token=tok123") || !strings.Contains(reset.HTMLBody, "Budi &lt;admin&gt;") {
+			t.Errorf("body = %q, want the reset link and the escaped name", reset.HTMLBody)
+		}
+		if bulk := outbox.enqueued[2]; bulk.Recipient != "b@example.com" || bulk.UserID != nil {
+			t.Errorf("bulk email = %+v, want one to b@example.com without a user", bulk)
+		}
+	})
+
+	t.Run("skipped without SMTP", func(t *testing.T) {
+		outbox := &fakeOutbox{}
+		svc := NewEmailService(&config.EmailConfig{}, outbox)
+
+		if err := svc.SendWelcomeEmail(context.Background(), u); err != nil {
+			t.Fatalf("SendWelcomeEmail() error = %v", err)
+		}
+		if len(outbox.enqueued) != 0 {
+			t.Errorf("enqueued %d emails, want none", len(outbox.enqueued))
+		}
+	})
+}
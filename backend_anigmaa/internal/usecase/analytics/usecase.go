@@ -48,6 +48,7 @@ type EventAnalytics struct {
 	CheckInRate      float64              `json:"check_in_rate"`   // Percentage of checked in vs tickets sold
 	PaymentMethods   []PaymentMethodStats `json:"payment_methods"`
 	TimelineStats    []TimelineStats      `json:"timeline_stats"` // Sales over time (daily)
+	RevenueByTier    []TierRevenueStats   `json:"revenue_by_tier"` // Empty for events without ticket tiers
 }
 
 // RevenueStats represents revenue statistics
@@ -59,6 +60,20 @@ type RevenueStats struct {
 	NetRevenue      float64 `json:"net_revenue"`      // Total - Refunded
 }
 
+// TierRevenueStats represents revenue statistics for a ticket tier
+type TierRevenueStats struct {
+	TierID          uuid.UUID `json:"tier_id"`
+	Name            string    `json:"name"`
+	Price           float64   `json:"price"`
+	Quota           int       `json:"quota"`
+	TicketsSold     int       `json:"tickets_sold"` // Pending + active, as held against the quota
+	TotalRevenue    float64   `json:"total_revenue"`
+	PendingRevenue  float64   `json:"pending_revenue"`
+	RefundedRevenue float64   `json:"refunded_revenue"`
+	NetRevenue      float64   `json:"net_revenue"`
+	SellThroughRate float64   `json:"sell_through_rate"` // Percentage of quota sold
+}
+
 // TransactionStats represents transaction statistics
 type TransactionStats struct {
 	TotalTransactions      int `json:"total_transactions"`
@@ -182,12 +197,28 @@ func (uc *Usecase) GetEventAnalytics(ctx context.Context, eventID, hostID uuid.U
 		Transactions:   TransactionStats{},
 		PaymentMethods: []PaymentMethodStats{},
 		TimelineStats:  []TimelineStats{},
+		RevenueByTier:  []TierRevenueStats{},
+	}
+
+	tiers, err := uc.ticketRepo.GetTiersByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate statistics
 	var checkedInCount int
 	paymentMethodMap := make(map[string]*PaymentMethodStats)
 	timelineMap := make(map[string]*TimelineStats)
+	tierMap := make(map[uuid.UUID]*TierRevenueStats, len(tiers))
+	for _, tier := range tiers {
+		tierMap[tier.ID] = &TierRevenueStats{
+			TierID:      tier.ID,
+			Name:        tier.Name,
+			Price:       tier.Price,
+			Quota:       tier.Quota,
+			TicketsSold: tier.Sold,
+		}
+	}
 
 	for _, tkt := range tickets {
 		if tkt.IsCheckedIn {
@@ -200,6 +231,11 @@ func (uc *Usecase) GetEventAnalytics(ctx context.Context, eventID, hostID uuid.U
 			continue
 		}
 
+		var tierStats *TierRevenueStats
+		if tkt.TierID != nil {
+			tierStats = tierMap[*tkt.TierID]
+		}
+
 		for _, txn := range transactions {
 			analytics.Transactions.TotalTransactions++
 
@@ -217,6 +253,17 @@ func (uc *Usecase) GetEventAnalytics(ctx context.Context, eventID, hostID uuid.U
 				analytics.Revenue.RefundedRevenue += txn.Amount
 			}
 
+			if tierStats != nil {
+				switch txn.Status {
+				case ticket.TransactionSuccess:
+					tierStats.TotalRevenue += txn.Amount
+				case ticket.TransactionPending:
+					tierStats.PendingRevenue += txn.Amount
+				case ticket.TransactionRefunded:
+					tierStats.RefundedRevenue += txn.Amount
+				}
+			}
+
 			// Track payment methods (only for successful transactions)
 			if txn.Status == ticket.TransactionSuccess {
 				if _, exists := paymentMethodMap[txn.PaymentMethod]; !exists {
@@ -257,10 +304,24 @@ func (uc *Usecase) GetEventAnalytics(ctx context.Context, eventID, hostID uuid.U
 	analytics.Revenue.NetRevenue = analytics.Revenue.TotalRevenue - analytics.Revenue.RefundedRevenue
 
 	// Calculate expected revenue
-	if evt.Price != nil && evt.MaxAttendees > 0 {
+	if len(tiers) > 0 {
+		for _, tier := range tiers {
+			analytics.Revenue.ExpectedRevenue += tier.Price * float64(tier.Quota)
+		}
+	} else if evt.Price != nil && evt.MaxAttendees > 0 {
 		analytics.Revenue.ExpectedRevenue = *evt.Price * float64(evt.MaxAttendees)
 	}
 
+	// Tier breakdown, in the tiers' display order
+	for _, tier := range tiers {
+		ts := tierMap[tier.ID]
+		ts.NetRevenue = ts.TotalRevenue - ts.RefundedRevenue
+		if ts.Quota > 0 {
+			ts.SellThroughRate = float64(ts.TicketsSold) / float64(ts.Quota) * 100
+		}
+		analytics.RevenueByTier = append(analytics.RevenueByTier, *ts)
+	}
+
 	// Convert payment method map to slice and calculate percentages
 	totalSuccessful := analytics.Transactions.SuccessfulTransactions
 	for _, pm := range paymentMethodMap {
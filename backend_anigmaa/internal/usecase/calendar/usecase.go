@@ -0,0 +1,193 @@
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/calendar"
+	"github.com/anigmaa/backend/internal/domain/event"
+	eventUsecase "github.com/anigmaa/backend/internal/usecase/event"
+	"github.com/anigmaa/backend/pkg/ical"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEventNotFound  = eventUsecase.ErrEventNotFound  // proxy to event usecase sentinel
+	ErrEventForbidden = eventUsecase.ErrEventForbidden // proxy to event usecase sentinel
+	ErrFeedNotFound   = errors.New("calendar feed not found")
+)
+
+const (
+	prodID = "-//Anigmaa//Events//EN"
+	// appURL is where event pages live; exported events link there
+	appURL = "https://anigmaa.com"
+	// uidDomain makes event UIDs globally unique (RFC 5545 3.8.4.7)
+	uidDomain = "anigmaa.com"
+
+	// feedTicketLimit caps the upcoming ticketed events in a feed, soonest first
+	feedTicketLimit = 50
+	// feedHostedLimit caps the hosted events in a feed, most recently created first
+	feedHostedLimit = 100
+	// feedRefreshInterval is how often subscribed calendar apps are asked to fetch the feed
+	feedRefreshInterval = time.Hour
+)
+
+// jakarta is the timezone exported events are shown in. Events don't store a
+// timezone and most take place in WIB.
+var jakarta = &ical.Timezone{ID: "Asia/Jakarta", Name: "WIB", Offset: 7 * time.Hour}
+
+// Usecase exports events as iCalendar files and serves users' calendar feeds
+type Usecase struct {
+	calendarRepo calendar.Repository
+	eventUC      *eventUsecase.Usecase
+	baseURL      string
+}
+
+// NewUsecase creates a new calendar usecase. baseURL is the public URL of the
+// API that feed URLs are built on.
+func NewUsecase(calendarRepo calendar.Repository, eventUC *eventUsecase.Usecase, baseURL string) *Usecase {
+	return &Usecase{
+		calendarRepo: calendarRepo,
+		eventUC:      eventUC,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// GetEventCalendar exports a single event the viewer may see as an .ics file.
+// viewerID is uuid.Nil for anonymous viewers.
+func (uc *Usecase) GetEventCalendar(ctx context.Context, eventID, viewerID uuid.UUID) ([]byte, error) {
+	evt, err := uc.eventUC.GetEventWithDetails(ctx, eventID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &ical.Calendar{
+		ProdID:   prodID,
+		Timezone: jakarta,
+		Events:   []ical.Event{toICalEvent(&evt.Event)},
+	}
+	return cal.Marshal(), nil
+}
+
+// GetFeedURL gets a user's calendar feed subscription, creating the feed on first
+// use. The URL is only returned when the feed is created: afterwards only its
+// hash is known, and ResetFeedURL gives a new one.
+func (uc *Usecase) GetFeedURL(ctx context.Context, userID uuid.UUID) (*calendar.FeedResponse, error) {
+	token, err := uc.calendarRepo.GetFeedToken(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uc.ResetFeedURL(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &calendar.FeedResponse{CreatedAt: token.CreatedAt}, nil
+}
+
+// ResetFeedURL replaces the token of a user's calendar feed, so subscriptions
+// to the old URL stop working, and returns the new URL
+func (uc *Usecase) ResetFeedURL(ctx context.Context, userID uuid.UUID) (*calendar.FeedResponse, error) {
+	secret, err := generateFeedToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &calendar.FeedToken{UserID: userID, TokenHash: hashFeedToken(secret)}
+	if err := uc.calendarRepo.SaveFeedToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	// Calendar apps subscribe to webcal:// URLs; the feed itself is served over http(s)
+	url := fmt.Sprintf("%s/api/v1/calendar/feeds/%s.ics", uc.baseURL, secret)
+	webcal := "webcal://" + strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+
+	return &calendar.FeedResponse{URL: url, WebcalURL: webcal, CreatedAt: token.CreatedAt}, nil
+}
+
+// GetFeed exports the calendar feed of the user a token belongs to: the upcoming
+// events they hold tickets for and the events they host
+func (uc *Usecase) GetFeed(ctx context.Context, token string) ([]byte, error) {
+	feedToken, err := uc.calendarRepo.GetFeedTokenByHash(ctx, hashFeedToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.calendarRepo.GetFeedEvents(ctx, feedToken.UserID, time.Now(), feedHostedLimit, feedTicketLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed events: %w", err)
+	}
+
+	cal := &ical.Calendar{
+		ProdID:          prodID,
+		Name:            "Anigmaa",
+		Timezone:        jakarta,
+		RefreshInterval: feedRefreshInterval,
+		Events:          make([]ical.Event, 0, len(events)),
+	}
+	for i := range events {
+		cal.Events = append(cal.Events, toICalEvent(&events[i]))
+	}
+
+	return cal.Marshal(), nil
+}
+
+// toICalEvent exports an event. The UID stays the same across exports and
+// updates, so calendar apps replace the event instead of adding a copy.
+func toICalEvent(e *event.Event) ical.Event {
+	url := fmt.Sprintf("%s/events/%s", appURL, e.ID)
+
+	// Not every calendar app shows the URL property, so the link ends the description too
+	description := url
+	if e.Description != "" {
+		description = e.Description + "\n\n" + url
+	}
+
+	status := ical.StatusConfirmed
+	if e.Status == event.StatusCancelled {
+		status = ical.StatusCancelled
+	}
+
+	var geo *ical.Geo
+	if e.LocationLat != 0 || e.LocationLng != 0 {
+		geo = &ical.Geo{Lat: e.LocationLat, Lng: e.LocationLng}
+	}
+
+	return ical.Event{
+		UID:          fmt.Sprintf("%s@%s", e.ID, uidDomain),
+		Sequence:     e.CalendarSequence,
+		Summary:      e.Title,
+		Description:  description,
+		Start:        e.StartTime,
+		End:          e.EndTime,
+		Location:     e.LocationName,
+		Address:      e.LocationAddress,
+		Geo:          geo,
+		URL:          url,
+		Status:       status,
+		Created:      e.CreatedAt,
+		LastModified: e.UpdatedAt,
+	}
+}
+
+func generateFeedToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashFeedToken is how feed tokens are stored, so a leaked database doesn't leak working feed URLs
+func hashFeedToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
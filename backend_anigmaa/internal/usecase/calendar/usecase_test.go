@@ -0,0 +1,170 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/calendar"
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/google/uuid"
+)
+
+// fakeCalendarRepo stores feed tokens by user and serves one feed of events
+type fakeCalendarRepo struct {
+	calendar.Repository
+	tokens     map[uuid.UUID]*calendar.FeedToken
+	events     []event.Event
+	feedCalls  int
+	feedUserID uuid.UUID
+}
+
+func (r *fakeCalendarRepo) GetFeedToken(ctx context.Context, userID uuid.UUID) (*calendar.FeedToken, error) {
+	token, ok := r.tokens[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *fakeCalendarRepo) GetFeedTokenByHash(ctx context.Context, tokenHash string) (*calendar.FeedToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeCalendarRepo) SaveFeedToken(ctx context.Context, token *calendar.FeedToken) error {
+	token.CreatedAt = time.Now()
+	copied := *token
+	r.tokens[token.UserID] = &copied
+	return nil
+}
+
+func (r *fakeCalendarRepo) GetFeedEvents(ctx context.Context, userID uuid.UUID, from time.Time, hostedLimit, ticketLimit int) ([]event.Event, error) {
+	r.feedCalls++
+	r.feedUserID = userID
+	return r.events, nil
+}
+
+func newFakeCalendarRepo() *fakeCalendarRepo {
+	return &fakeCalendarRepo{tokens: make(map[uuid.UUID]*calendar.FeedToken)}
+}
+
+// feedSecret gets the secret token out of a feed URL
+func feedSecret(t *testing.T, url string) string {
+	t.Helper()
+	i := strings.LastIndex(url, "/")
+	if i < 0 || !strings.HasSuffix(url, ".ics") {
+		t.Fatalf("feed URL %q doesn't end in a token", url)
+	}
+	return strings.TrimSuffix(url[i+1:], ".ics")
+}
+
+func TestGetFeedURL(t *testing.T) {
+	repo := newFakeCalendarRepo()
+	uc := NewUsecase(repo, nil, "https://api.anigmaa.com/")
+	userID := uuid.New()
+
+	created, err := uc.GetFeedURL(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetFeedURL() error = %v", err)
+	}
+	if !strings.HasPrefix(created.URL, "https://api.anigmaa.com/api/v1/calendar/feeds/") {
+		t.Errorf("URL = %q, want it on the configured base URL", created.URL)
+	}
+	if want := "webcal://" + strings.TrimPrefix(created.URL, "https://"); created.WebcalURL != want {
+		t.Errorf("webcal URL = %q, want %q", created.WebcalURL, want)
+	}
+
+	secret := feedSecret(t, created.URL)
+	stored := repo.tokens[userID]
+	if stored.TokenHash == secret || stored.TokenHash != hashFeedToken(secret) {
+		t.Errorf("stored token = %q, want the SHA-256 hash of the secret", stored.TokenHash)
+	}
+
+	// The secret can't be shown again once it's only stored hashed
+	again, err := uc.GetFeedURL(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetFeedURL() again error = %v", err)
+	}
+	if again.URL != "" || again.WebcalURL != "" || !again.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("GetFeedURL() again = %+v, want only the creation time", again)
+	}
+}
+
+func TestResetFeedURL(t *testing.T) {
+	repo := newFakeCalendarRepo()
+	uc := NewUsecase(repo, nil, "http://localhost:8080")
+	userID := uuid.New()
+
+	old, err := uc.GetFeedURL(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetFeedURL() error = %v", err)
+	}
+	reset, err := uc.ResetFeedURL(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ResetFeedURL() error = %v", err)
+	}
+	if reset.URL == old.URL || !strings.HasPrefix(reset.WebcalURL, "webcal://localhost:8080/") {
+		t.Errorf("ResetFeedURL() = %+v, want a new URL on the base URL", reset)
+	}
+
+	if _, err := uc.GetFeed(context.Background(), feedSecret(t, old.URL)); !errors.Is(err, ErrFeedNotFound) {
+		t.Errorf("GetFeed() with the old token error = %v, want %v", err, ErrFeedNotFound)
+	}
+	if _, err := uc.GetFeed(context.Background(), feedSecret(t, reset.URL)); err != nil {
+		t.Errorf("GetFeed() with the new token error = %v", err)
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	start := time.Date(2025, time.January, 4, 12, 0, 0, 0, time.UTC)
+	hosted := event.Event{ID: uuid.New(), Title: "Jazz Senja", StartTime: start, EndTime: start.Add(2 * time.Hour), Status: event.StatusUpcoming}
+	cancelled := event.Event{ID: uuid.New(), Title: "Kopi Pagi", StartTime: start.Add(24 * time.Hour), EndTime: start.Add(25 * time.Hour), Status: event.StatusCancelled, CalendarSequence: 3}
+
+	repo := newFakeCalendarRepo()
+	repo.events = []event.Event{hosted, cancelled}
+	uc := NewUsecase(repo, nil, "https://api.anigmaa.com")
+	userID := uuid.New()
+
+	feed, err := uc.ResetFeedURL(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("ResetFeedURL() error = %v", err)
+	}
+
+	ics, err := uc.GetFeed(context.Background(), feedSecret(t, feed.URL))
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if repo.feedCalls != 1 || repo.feedUserID != userID {
+		t.Errorf("feed events fetched %d times for %s, want once for %s", repo.feedCalls, repo.feedUserID, userID)
+	}
+
+	out := string(ics)
+	for _, want := range []string{
+		"UID:" + hosted.ID.String() + "@anigmaa.com\r\n",
+		"UID:" + cancelled.ID.String() + "@anigmaa.com\r\n",
+		"SEQUENCE:3\r\n",
+		"STATUS:CANCELLED\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("feed doesn't contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "SUMMARY:Jazz Senja") > strings.Index(out, "SUMMARY:Kopi Pagi") {
+		t.Errorf("feed events aren't in start order:\n%s", out)
+	}
+
+	if _, err := uc.GetFeed(context.Background(), "not-a-token"); !errors.Is(err, ErrFeedNotFound) {
+		t.Errorf("GetFeed() with an unknown token error = %v, want %v", err, ErrFeedNotFound)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/community"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
 	"github.com/google/uuid"
 )
 
@@ -22,13 +23,15 @@ var (
 
 // Usecase handles community business logic
 type Usecase struct {
-	communityRepo community.Repository
+	communityRepo  community.Repository
+	notificationUC *notificationUsecase.Usecase
 }
 
 // NewUsecase creates a new community usecase
-func NewUsecase(communityRepo community.Repository) *Usecase {
+func NewUsecase(communityRepo community.Repository, notificationUC *notificationUsecase.Usecase) *Usecase {
 	return &Usecase{
-		communityRepo: communityRepo,
+		communityRepo:  communityRepo,
+		notificationUC: notificationUC,
 	}
 }
 
@@ -188,7 +191,12 @@ func (uc *Usecase) JoinCommunity(ctx context.Context, communityID, userID uuid.U
 		JoinedAt:    time.Now(),
 	}
 
-	return uc.communityRepo.Join(ctx, member)
+	if err := uc.communityRepo.Join(ctx, member); err != nil {
+		return err
+	}
+
+	uc.notificationUC.NotifyCommunityJoined(ctx, userID, comm.CreatorID, communityID, comm.Name)
+	return nil
 }
 
 // LeaveCommunity removes a user from a community
@@ -323,5 +323,5 @@ func (m *Matcher) GetEventsNearby(ctx context.Context, userID uuid.UUID, lat, ln
 		limit = 100
 	}
 
-	return m.eventRepo.GetNearby(ctx, lat, lng, radiusKm, limit)
+	return m.eventRepo.GetNearby(ctx, lat, lng, radiusKm, limit, userID)
 }
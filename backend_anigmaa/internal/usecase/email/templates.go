@@ -0,0 +1,182 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/email"
+	"github.com/anigmaa/backend/internal/domain/event"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// defaultLanguage is used for users without settings or with a language
+// emails aren't written in (matches the user_settings default)
+const defaultLanguage = "en"
+
+// languages are the languages every email template is written in
+var languages = []string{"en", "id"}
+
+// appURL is where links in emails point to
+const appURL = "https://anigmaa.com"
+
+// wib is the timezone event times are shown in (Western Indonesia Time)
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// parsedTemplates holds the layout combined with every template, by template and language
+var parsedTemplates = mustParseTemplates()
+
+func mustParseTemplates() map[string]*template.Template {
+	all := []email.Template{
+		email.TemplateWelcome,
+		email.TemplateTicketConfirmation,
+		email.TemplatePaymentReceipt,
+		email.TemplateTicketCancelled,
+		email.TemplateRefundIssued,
+		email.TemplateEventUpdated,
+		email.TemplateEventCancelled,
+	}
+
+	parsed := make(map[string]*template.Template, len(all)*len(languages))
+	for _, tmpl := range all {
+		for _, lang := range languages {
+			name := fmt.Sprintf("%s.%s", tmpl, lang)
+			parsed[name] = template.Must(template.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html"))
+		}
+	}
+	return parsed
+}
+
+// templateData is what email templates are rendered with. Only the parts an
+// email is about are set.
+type templateData struct {
+	Lang     string
+	Name     string
+	EventURL string
+	Event    *eventDetails
+	Ticket   *ticketDetails
+	Payment  *paymentDetails
+	Refund   *refundDetails
+}
+
+type eventDetails struct {
+	Title     string
+	StartTime string
+	EndTime   string
+	Location  string
+}
+
+type ticketDetails struct {
+	TierName       string
+	AttendanceCode string
+}
+
+type paymentDetails struct {
+	TransactionID string
+	Amount        string
+	Discount      string
+	Method        string
+	PaidAt        string
+}
+
+type refundDetails struct {
+	Amount  string
+	Pending bool
+	Partial bool // Only part of the payment was refunded; the ticket stays valid
+}
+
+// render renders the subject and HTML body of an email in lang
+func render(tmpl email.Template, lang string, data *templateData) (subject, body string, err error) {
+	t, ok := parsedTemplates[fmt.Sprintf("%s.%s", tmpl, lang)]
+	if !ok {
+		return "", "", fmt.Errorf("unknown email template %s in %s", tmpl, lang)
+	}
+	data.Lang = lang
+
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	// The subject is a header, not HTML, so the escaping is undone
+	subject = html.UnescapeString(strings.TrimSpace(buf.String()))
+
+	buf.Reset()
+	if err := t.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return "", "", err
+	}
+
+	return subject, buf.String(), nil
+}
+
+// language picks the language of a user's emails from their settings, e.g. "id-ID" → "id"
+func language(setting string) string {
+	lang := strings.ToLower(strings.TrimSpace(setting))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	for _, supported := range languages {
+		if lang == supported {
+			return lang
+		}
+	}
+	return defaultLanguage
+}
+
+func newEventDetails(e *event.Event, lang string) *eventDetails {
+	location := e.LocationName
+	if e.LocationAddress != "" {
+		location += ", " + e.LocationAddress
+	}
+	return &eventDetails{
+		Title:     e.Title,
+		StartTime: formatTime(e.StartTime, lang),
+		EndTime:   formatTime(e.EndTime, lang),
+		Location:  location,
+	}
+}
+
+func eventURL(eventID fmt.Stringer) string {
+	return fmt.Sprintf("%s/events/%s", appURL, eventID)
+}
+
+var (
+	indonesianDays   = []string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+	indonesianMonths = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+)
+
+// formatTime formats a time in WIB, e.g. "Saturday, 4 January 2025 19:00 WIB"
+// or "Sabtu, 4 Januari 2025 19.00 WIB"
+func formatTime(t time.Time, lang string) string {
+	t = t.In(wib)
+	if lang == "id" {
+		return fmt.Sprintf("%s, %d %s %d %02d.%02d WIB",
+			indonesianDays[t.Weekday()], t.Day(), indonesianMonths[t.Month()-1], t.Year(), t.Hour(), t.Minute())
+	}
+	return t.Format("Monday, 2 January 2006 15:04") + " WIB"
+}
+
+// formatRupiah formats an amount in rupiah, e.g. 150000 → "Rp150.000"
+func formatRupiah(amount float64) string {
+	digits := fmt.Sprintf("%.0f", amount)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+
+	if negative {
+		return "-Rp" + b.String()
+	}
+	return "Rp" + b.String()
+}
@@ -0,0 +1,290 @@
+package email
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/email"
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+const (
+	// outboxBatchSize is how many emails a delivery run claims at a time
+	outboxBatchSize = 50
+	// outboxLease hides a claimed email from other instances while it's being sent
+	outboxLease = 5 * time.Minute
+	// maxAttempts is how often an email is tried before it's marked failed
+	maxAttempts = 8
+	// retryBaseDelay doubles after every failed attempt, up to retryMaxDelay
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+)
+
+// Sender delivers a rendered email (see services.EmailService)
+type Sender interface {
+	// IsConfigured reports whether emails can be sent at all
+	IsConfigured() bool
+	Send(ctx context.Context, e *email.Email) error
+}
+
+// Usecase renders transactional emails into the outbox and delivers them
+type Usecase struct {
+	emailRepo email.Repository
+	userRepo  user.Repository
+	sender    Sender
+}
+
+// NewUsecase creates a new email usecase
+func NewUsecase(emailRepo email.Repository, userRepo user.Repository, sender Sender) *Usecase {
+	return &Usecase{
+		emailRepo: emailRepo,
+		userRepo:  userRepo,
+		sender:    sender,
+	}
+}
+
+// SendWelcome welcomes a user who just created their account
+func (uc *Usecase) SendWelcome(ctx context.Context, u *user.User) {
+	uc.queue(ctx, u.ID, email.TemplateWelcome, false, func(lang string) *templateData {
+		return &templateData{}
+	})
+}
+
+// SendTicketConfirmation sends an active ticket to its holder with its QR code attached.
+// tierName is empty for events without tiers.
+func (uc *Usecase) SendTicketConfirmation(ctx context.Context, t *ticket.Ticket, e *event.Event, tierName string, qrPNG []byte) {
+	var attachments []email.Attachment
+	if len(qrPNG) > 0 {
+		attachments = append(attachments, email.Attachment{
+			Filename:    "ticket-qr.png",
+			ContentType: "image/png",
+			Content:     qrPNG,
+		})
+	}
+
+	uc.queue(ctx, t.UserID, email.TemplateTicketConfirmation, false, func(lang string) *templateData {
+		return &templateData{
+			EventURL: eventURL(e.ID),
+			Event:    newEventDetails(e, lang),
+			Ticket:   &ticketDetails{TierName: tierName, AttendanceCode: t.AttendanceCode},
+		}
+	}, attachments...)
+}
+
+// SendPaymentReceipt sends the receipt of a settled ticket payment
+func (uc *Usecase) SendPaymentReceipt(ctx context.Context, t *ticket.Ticket, tx *ticket.TicketTransaction, e *event.Event, tierName string) {
+	paidAt := time.Now()
+	if tx.CompletedAt != nil {
+		paidAt = *tx.CompletedAt
+	}
+
+	uc.queue(ctx, t.UserID, email.TemplatePaymentReceipt, false, func(lang string) *templateData {
+		data := &templateData{
+			EventURL: eventURL(e.ID),
+			Event:    newEventDetails(e, lang),
+			Ticket:   &ticketDetails{TierName: tierName, AttendanceCode: t.AttendanceCode},
+			Payment: &paymentDetails{
+				TransactionID: tx.TransactionID,
+				Amount:        formatRupiah(tx.Amount),
+				Method:        tx.PaymentMethod,
+				PaidAt:        formatTime(paidAt, lang),
+			},
+		}
+		if t.DiscountAmount > 0 {
+			data.Payment.Discount = formatRupiah(t.DiscountAmount)
+		}
+		return data
+	})
+}
+
+// SendTicketCancelled tells a user their ticket was cancelled, with the refund
+// it was given (nil for free tickets)
+func (uc *Usecase) SendTicketCancelled(ctx context.Context, t *ticket.Ticket, e *event.Event, refund *ticket.Refund) {
+	uc.queue(ctx, t.UserID, email.TemplateTicketCancelled, false, func(lang string) *templateData {
+		data := &templateData{
+			EventURL: eventURL(e.ID),
+			Event:    newEventDetails(e, lang),
+		}
+		if refund != nil {
+			data.Refund = &refundDetails{
+				Amount:  formatRupiah(refund.Amount),
+				Pending: refund.Status != ticket.RefundSucceeded,
+			}
+		}
+		return data
+	})
+}
+
+// SendRefundIssued tells a user amount of the payment of their ticket was
+// refunded. After a partial refund the ticket stays valid.
+func (uc *Usecase) SendRefundIssued(ctx context.Context, t *ticket.Ticket, e *event.Event, amount float64, partial bool) {
+	uc.queue(ctx, t.UserID, email.TemplateRefundIssued, false, func(lang string) *templateData {
+		return &templateData{
+			EventURL: eventURL(e.ID),
+			Event:    newEventDetails(e, lang),
+			Refund:   &refundDetails{Amount: formatRupiah(amount), Partial: partial},
+		}
+	})
+}
+
+// SendEventChanged tells attendees that the host moved or cancelled an event,
+// i.e. that its schedule changed from previous; other edits aren't emailed.
+// Users who turned email notifications off are skipped.
+func (uc *Usecase) SendEventChanged(ctx context.Context, e *event.Event, previous event.Schedule, userIDs []uuid.UUID) {
+	if !e.Schedule().ChangedFrom(previous) {
+		return
+	}
+	tmpl := email.TemplateEventUpdated
+	if e.Status == event.StatusCancelled {
+		tmpl = email.TemplateEventCancelled
+	}
+	if len(userIDs) == 0 || !uc.canSend(tmpl) {
+		return
+	}
+
+	recipients, err := uc.userRepo.GetEmailRecipients(ctx, userIDs)
+	if err != nil {
+		log.Printf("[Email] failed to get recipients of %s email for event %s: %v", tmpl, e.ID, err)
+		return
+	}
+
+	for i := range recipients {
+		uc.enqueue(ctx, &recipients[i], tmpl, true, func(lang string) *templateData {
+			return &templateData{
+				EventURL: eventURL(e.ID),
+				Event:    newEventDetails(e, lang),
+			}
+		})
+	}
+}
+
+// canSend reports whether emails can be sent. Without SMTP they're dropped
+// rather than piling up in the outbox with no worker to send them.
+func (uc *Usecase) canSend(tmpl email.Template) bool {
+	if uc == nil {
+		return false
+	}
+	if !uc.sender.IsConfigured() {
+		log.Printf("[Email] SMTP not configured, dropping %s email", tmpl)
+		return false
+	}
+	return true
+}
+
+// queue renders an email in the user's language and stores it in the outbox.
+// optional emails respect the user's email notification setting; transactional
+// ones (tickets, payments) are always sent.
+//
+// Failures are logged, never returned: an email must not fail the action it's about.
+func (uc *Usecase) queue(ctx context.Context, userID uuid.UUID, tmpl email.Template, optional bool, build func(lang string) *templateData, attachments ...email.Attachment) {
+	if !uc.canSend(tmpl) {
+		return
+	}
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("[Email] failed to get recipient %s of %s email: %v", userID, tmpl, err)
+		return
+	}
+
+	recipient := &user.EmailRecipient{ID: u.ID, Name: u.Name, Email: u.Email, EmailNotifications: true}
+	settings, err := uc.userRepo.GetSettings(ctx, userID)
+	switch {
+	case err == nil:
+		recipient.EmailNotifications = settings.EmailNotifications
+		recipient.Language = settings.Language
+	case !errors.Is(err, sql.ErrNoRows):
+		log.Printf("[Email] failed to get settings of user %s: %v", userID, err)
+	}
+
+	uc.enqueue(ctx, recipient, tmpl, optional, build, attachments...)
+}
+
+// enqueue renders an email for a recipient and stores it in the outbox
+func (uc *Usecase) enqueue(ctx context.Context, r *user.EmailRecipient, tmpl email.Template, optional bool, build func(lang string) *templateData, attachments ...email.Attachment) {
+	if r.Email == "" || (optional && !r.EmailNotifications) {
+		return
+	}
+
+	lang := language(r.Language)
+	data := build(lang)
+	data.Name = r.Name
+	subject, body, err := render(tmpl, lang, data)
+	if err != nil {
+		log.Printf("[Email] failed to render %s email for user %s: %v", tmpl, r.ID, err)
+		return
+	}
+
+	userID := r.ID
+	e := &email.Email{
+		UserID:      &userID,
+		Recipient:   r.Email,
+		Template:    tmpl,
+		Language:    lang,
+		Subject:     subject,
+		HTMLBody:    body,
+		Attachments: attachments,
+	}
+	if err := uc.emailRepo.Enqueue(ctx, e); err != nil {
+		log.Printf("[Email] failed to queue %s email for user %s: %v", tmpl, r.ID, err)
+	}
+}
+
+// ProcessOutbox is called by the background outbox worker. It sends the emails
+// that are due, retrying failed ones with exponential backoff.
+func (uc *Usecase) ProcessOutbox(ctx context.Context) error {
+	for {
+		emails, err := uc.emailRepo.ClaimDue(ctx, outboxBatchSize, outboxLease)
+		if err != nil {
+			return err
+		}
+
+		for i := range emails {
+			uc.deliver(ctx, &emails[i])
+		}
+
+		if len(emails) < outboxBatchSize {
+			return nil
+		}
+	}
+}
+
+func (uc *Usecase) deliver(ctx context.Context, e *email.Email) {
+	sendErr := uc.sender.Send(ctx, e)
+	if sendErr == nil {
+		if err := uc.emailRepo.MarkSent(ctx, e.ID); err != nil {
+			log.Printf("[Email] failed to mark email %s as sent: %v", e.ID, err)
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if e.Attempts < maxAttempts {
+		at := time.Now().Add(retryDelay(e.Attempts))
+		retryAt = &at
+		log.Printf("[Email] failed to send %s email %s (attempt %d), retrying at %s: %v", e.Template, e.ID, e.Attempts, at.Format(time.RFC3339), sendErr)
+	} else {
+		log.Printf("[Email] giving up on %s email %s after %d attempts: %v", e.Template, e.ID, e.Attempts, sendErr)
+	}
+
+	if err := uc.emailRepo.MarkFailed(ctx, e.ID, sendErr.Error(), retryAt); err != nil {
+		log.Printf("[Email] failed to record failed attempt of email %s: %v", e.ID, err)
+	}
+}
+
+// retryDelay is how long to wait after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,340 @@
+package email
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/email"
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// fakeEmailRepo records the outbox calls the usecase makes
+type fakeEmailRepo struct {
+	email.Repository
+	due      []email.Email
+	enqueued []*email.Email
+	sent     []uuid.UUID
+	failed   map[uuid.UUID]*time.Time
+}
+
+func (r *fakeEmailRepo) Enqueue(ctx context.Context, e *email.Email) error {
+	r.enqueued = append(r.enqueued, e)
+	return nil
+}
+
+func (r *fakeEmailRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]email.Email, error) {
+	due := r.due
+	r.due = nil
+	return due, nil
+}
+
+func (r *fakeEmailRepo) MarkSent(ctx context.Context, id uuid.UUID) error {
+	r.sent = append(r.sent, id)
+	return nil
+}
+
+func (r *fakeEmailRepo) MarkFailed(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error {
+	if r.failed == nil {
+		r.failed = make(map[uuid.UUID]*time.Time)
+	}
+	r.failed[id] = retryAt
+	return nil
+}
+
+// fakeUserRepo serves a single user and their settings
+type fakeUserRepo struct {
+	user.Repository
+	user       *user.User
+	settings   *user.UserSettings
+	recipients []user.EmailRecipient
+	lookups    int
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	r.lookups++
+	return r.user, nil
+}
+
+func (r *fakeUserRepo) GetEmailRecipients(ctx context.Context, userIDs []uuid.UUID) ([]user.EmailRecipient, error) {
+	r.lookups++
+	return r.recipients, nil
+}
+
+func (r *fakeUserRepo) GetSettings(ctx context.Context, userID uuid.UUID) (*user.UserSettings, error) {
+	if r.settings == nil {
+		return nil, sql.ErrNoRows
+	}
+	return r.settings, nil
+}
+
+// fakeSender fails the emails whose recipient is in fail
+type fakeSender struct {
+	fail         map[string]bool
+	unconfigured bool
+}
+
+func (s *fakeSender) IsConfigured() bool {
+	return !s.unconfigured
+}
+
+func (s *fakeSender) Send(ctx context.Context, e *email.Email) error {
+	if s.fail[e.Recipient] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// moved returns e's schedule an hour earlier, as it was before the host
+// rescheduled it
+func moved(e *event.Event) event.Schedule {
+	previous := e.Schedule()
+	previous.StartTime = previous.StartTime.Add(-time.Hour)
+	return previous
+}
+
+func testEvent() *event.Event {
+	start := time.Date(2025, time.January, 4, 12, 0, 0, 0, time.UTC)
+	return &event.Event{
+		ID:              uuid.New(),
+		Title:           "Jazz & Kopi",
+		StartTime:       start,
+		EndTime:         start.Add(3 * time.Hour),
+		LocationName:    "Kopi Kenangan",
+		LocationAddress: "Jl. Sudirman 1, Jakarta",
+		Status:          event.StatusUpcoming,
+	}
+}
+
+func TestRender(t *testing.T) {
+	e := testEvent()
+
+	for name := range parsedTemplates {
+		dot := strings.LastIndex(name, ".")
+		tmpl, lang := email.Template(name[:dot]), name[dot+1:]
+
+		t.Run(name, func(t *testing.T) {
+			data := &templateData{
+				Name:     "Budi",
+				EventURL: eventURL(e.ID),
+				Event:    newEventDetails(e, lang),
+				Ticket:   &ticketDetails{TierName: "VIP", AttendanceCode: "ABCD1234"},
+				Payment: &paymentDetails{
+					TransactionID: "TRX-1",
+					Amount:        formatRupiah(150000),
+					Method:        "qris",
+					PaidAt:        formatTime(e.StartTime, lang),
+				},
+				Refund: &refundDetails{Amount: formatRupiah(150000)},
+			}
+
+			subject, body, err := render(tmpl, lang, data)
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if subject == "" || strings.Contains(subject, "&amp;") {
+				t.Errorf("subject = %q, want a non-empty unescaped subject", subject)
+			}
+			if !strings.Contains(body, "Budi") {
+				t.Errorf("body doesn't greet the recipient")
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    string
+	}{
+		{"id", "id"},
+		{"id-ID", "id"},
+		{"EN_us", "en"},
+		{"", "en"},
+		{"fr", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.setting, func(t *testing.T) {
+			if got := language(tt.setting); got != tt.want {
+				t.Errorf("language(%q) = %q, want %q", tt.setting, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRupiah(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		{0, "Rp0"},
+		{999, "Rp999"},
+		{150000, "Rp150.000"},
+		{1250000, "Rp1.250.000"},
+		{-5000, "-Rp5.000"},
+	}
+
+	for _, tt := range tests {
+		if got := formatRupiah(tt.amount); got != tt.want {
+			t.Errorf("formatRupiah(%v) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestQueue(t *testing.T) {
+	u := &user.User{ID: uuid.New(), Name: "Budi", Email: "budi@example.com"}
+	e := testEvent()
+
+	t.Run("uses the user's language", func(t *testing.T) {
+		repo := &fakeEmailRepo{}
+		uc := NewUsecase(repo, &fakeUserRepo{user: u, settings: &user.UserSettings{Language: "id", EmailNotifications: true}}, &fakeSender{})
+
+		uc.SendTicketConfirmation(context.Background(), &ticket.Ticket{UserID: u.ID, AttendanceCode: "ABCD1234"}, e, "", []byte("png"))
+
+		if len(repo.enqueued) != 1 {
+			t.Fatalf("enqueued %d emails, want 1", len(repo.enqueued))
+		}
+		got := repo.enqueued[0]
+		if got.Language != "id" || got.Recipient != u.Email {
+			t.Errorf("enqueued %s email to %s, want id email to %s", got.Language, got.Recipient, u.Email)
+		}
+		if len(got.Attachments) != 1 || got.Attachments[0].Filename != "ticket-qr.png" {
+			t.Errorf("attachments = %v, want ticket-qr.png", got.Attachments)
+		}
+	})
+
+	t.Run("event changes to opted-in users, fetched at once", func(t *testing.T) {
+		repo := &fakeEmailRepo{}
+		users := &fakeUserRepo{recipients: []user.EmailRecipient{
+			{ID: u.ID, Name: u.Name, Email: u.Email, EmailNotifications: true, Language: "id"},
+			{ID: uuid.New(), Name: "Siti", Email: "siti@example.com"},
+			{ID: uuid.New(), Name: "Andi", EmailNotifications: true},
+		}}
+		uc := NewUsecase(repo, users, &fakeSender{})
+
+		uc.SendEventChanged(context.Background(), e, moved(e), []uuid.UUID{u.ID, users.recipients[1].ID, users.recipients[2].ID})
+
+		if users.lookups != 1 {
+			t.Errorf("looked up recipients %d times, want once", users.lookups)
+		}
+		if len(repo.enqueued) != 1 || repo.enqueued[0].Recipient != u.Email || repo.enqueued[0].Language != "id" {
+			t.Errorf("enqueued %d emails, want one id email to %s", len(repo.enqueued), u.Email)
+		}
+	})
+
+	t.Run("drops emails when SMTP isn't configured", func(t *testing.T) {
+		repo := &fakeEmailRepo{}
+		users := &fakeUserRepo{user: u}
+		uc := NewUsecase(repo, users, &fakeSender{unconfigured: true})
+
+		uc.SendWelcome(context.Background(), u)
+		uc.SendEventChanged(context.Background(), e, moved(e), []uuid.UUID{u.ID})
+
+		if len(repo.enqueued) != 0 || users.lookups != 0 {
+			t.Errorf("enqueued %d emails after %d lookups, want none", len(repo.enqueued), users.lookups)
+		}
+	})
+
+	t.Run("sends receipts regardless of settings", func(t *testing.T) {
+		repo := &fakeEmailRepo{}
+		uc := NewUsecase(repo, &fakeUserRepo{user: u, settings: &user.UserSettings{Language: "en"}}, &fakeSender{})
+
+		tx := &ticket.TicketTransaction{TransactionID: "TRX-1", Amount: 150000, PaymentMethod: "qris"}
+		uc.SendPaymentReceipt(context.Background(), &ticket.Ticket{UserID: u.ID}, tx, e, "VIP")
+
+		if len(repo.enqueued) != 1 {
+			t.Errorf("enqueued %d emails, want 1", len(repo.enqueued))
+		}
+	})
+}
+
+func TestSendEventChanged_OnlyScheduleChanges(t *testing.T) {
+	u := &user.User{ID: uuid.New(), Name: "Budi", Email: "budi@example.com"}
+
+	tests := []struct {
+		name   string
+		change func(e *event.Event)
+		want   int
+	}{
+		{"description edited", func(e *event.Event) { e.Description = "Now with pastries" }, 0},
+		{"capacity raised", func(e *event.Event) { e.MaxAttendees += 10 }, 0},
+		{"start moved", func(e *event.Event) { e.StartTime = e.StartTime.Add(time.Hour) }, 1},
+		{"end moved", func(e *event.Event) { e.EndTime = e.EndTime.Add(time.Hour) }, 1},
+		{"venue changed", func(e *event.Event) { e.LocationName = "Kopi Tuku" }, 1},
+		{"pin moved", func(e *event.Event) { e.LocationLat = -6.2 }, 1},
+		{"cancelled", func(e *event.Event) { e.Status = event.StatusCancelled }, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeEmailRepo{}
+			users := &fakeUserRepo{recipients: []user.EmailRecipient{
+				{ID: u.ID, Name: u.Name, Email: u.Email, EmailNotifications: true, Language: "en"},
+			}}
+			uc := NewUsecase(repo, users, &fakeSender{})
+			e := testEvent()
+			previous := e.Schedule()
+			tt.change(e)
+
+			uc.SendEventChanged(context.Background(), e, previous, []uuid.UUID{u.ID})
+
+			if len(repo.enqueued) != tt.want {
+				t.Errorf("enqueued %d emails, want %d", len(repo.enqueued), tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessOutbox(t *testing.T) {
+	delivered := email.Email{ID: uuid.New(), Recipient: "budi@example.com", Attempts: 1}
+	retried := email.Email{ID: uuid.New(), Recipient: "siti@example.com", Attempts: 2}
+	exhausted := email.Email{ID: uuid.New(), Recipient: "siti@example.com", Attempts: maxAttempts}
+
+	repo := &fakeEmailRepo{due: []email.Email{delivered, retried, exhausted}}
+	uc := NewUsecase(repo, nil, &fakeSender{fail: map[string]bool{"siti@example.com": true}})
+
+	before := time.Now()
+	if err := uc.ProcessOutbox(context.Background()); err != nil {
+		t.Fatalf("ProcessOutbox() error = %v", err)
+	}
+
+	if len(repo.sent) != 1 || repo.sent[0] != delivered.ID {
+		t.Errorf("sent = %v, want [%s]", repo.sent, delivered.ID)
+	}
+
+	retryAt, ok := repo.failed[retried.ID]
+	if !ok || retryAt == nil {
+		t.Fatalf("email after 2 attempts wasn't scheduled for a retry")
+	}
+	if wait := retryAt.Sub(before); wait < 2*retryBaseDelay || wait > 2*retryBaseDelay+time.Minute {
+		t.Errorf("retry after 2 attempts in %s, want %s", wait, 2*retryBaseDelay)
+	}
+
+	if retryAt, ok := repo.failed[exhausted.ID]; !ok || retryAt != nil {
+		t.Errorf("email after %d attempts wasn't marked failed", maxAttempts)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{4, 8 * time.Minute},
+		{7, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
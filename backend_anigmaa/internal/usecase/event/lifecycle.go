@@ -0,0 +1,85 @@
+package event
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+)
+
+// eventReminders are the reminders sent before an event starts, longest lead
+// first. Each one is due once the event is closer than its lead, until the
+// next reminder takes over.
+var eventReminders = []struct {
+	kind event.ReminderKind
+	lead time.Duration
+}{
+	{event.Reminder24h, 24 * time.Hour},
+	{event.Reminder1h, time.Hour},
+}
+
+// AdvanceEventStatuses is called by the background lifecycle worker. It moves
+// events from upcoming to ongoing to ended as their start and end times pass,
+// and pushes each change to the host and attendees.
+func (uc *Usecase) AdvanceEventStatuses(ctx context.Context) error {
+	transitions, err := uc.eventRepo.TransitionStatuses(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range transitions {
+		t := &transitions[i]
+		uc.publishStatusChange(ctx, &t.Event, t.PreviousStatus, nil)
+	}
+	if len(transitions) > 0 {
+		log.Printf("[EventLifecycle] moved %d events to a new status", len(transitions))
+	}
+
+	return nil
+}
+
+// SendEventReminders is called by the background lifecycle worker. It reminds
+// the ticket holders and attendees of upcoming events 24 hours and 1 hour
+// before they start. An event created or rescheduled inside a reminder's window
+// gets only the reminders still ahead of it.
+//
+// Reminders are at most once: an event is claimed before its reminder goes out,
+// so a pass that fails halfway never sends a reminder twice but may skip some
+// recipients. A missed reminder is better than a duplicate one.
+func (uc *Usecase) SendEventReminders(ctx context.Context) error {
+	now := time.Now()
+
+	for i, reminder := range eventReminders {
+		from := now
+		if i+1 < len(eventReminders) {
+			from = now.Add(eventReminders[i+1].lead)
+		}
+
+		events, err := uc.eventRepo.ClaimReminders(ctx, reminder.kind, from, now.Add(reminder.lead))
+		if err != nil {
+			return err
+		}
+
+		for j := range events {
+			uc.sendEventReminder(ctx, &events[j])
+		}
+		if len(events) > 0 {
+			log.Printf("[EventLifecycle] sent %s reminders for %d events", reminder.kind, len(events))
+		}
+	}
+
+	return nil
+}
+
+func (uc *Usecase) sendEventReminder(ctx context.Context, e *event.Event) {
+	userIDs, err := uc.eventRepo.GetReminderRecipients(ctx, e.ID)
+	if err != nil {
+		log.Printf("[EventLifecycle] failed to get reminder recipients of event %s: %v", e.ID, err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		uc.notificationUC.NotifyEventReminder(ctx, userID, e.ID, e.Title, e.StartTime)
+	}
+}
@@ -0,0 +1,149 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/notification"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
+	"github.com/google/uuid"
+)
+
+// reminderEventRepo claims reminders of upcoming events the way the database
+// does: once per event and kind, for the events starting inside the window
+type reminderEventRepo struct {
+	event.Repository
+	events      []event.Event
+	recipients  []uuid.UUID
+	claimed     map[event.ReminderKind]map[uuid.UUID]bool
+	transitions []event.StatusTransition
+	statusErr   error
+	claimErr    error
+}
+
+func (r *reminderEventRepo) TransitionStatuses(ctx context.Context, now time.Time) ([]event.StatusTransition, error) {
+	return r.transitions, r.statusErr
+}
+
+func (r *reminderEventRepo) ClaimReminders(ctx context.Context, kind event.ReminderKind, from, until time.Time) ([]event.Event, error) {
+	if r.claimErr != nil {
+		return nil, r.claimErr
+	}
+	if r.claimed[kind] == nil {
+		r.claimed[kind] = make(map[uuid.UUID]bool)
+	}
+
+	var claimed []event.Event
+	for _, e := range r.events {
+		if e.StartTime.After(from) && !e.StartTime.After(until) && !r.claimed[kind][e.ID] {
+			r.claimed[kind][e.ID] = true
+			claimed = append(claimed, e)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *reminderEventRepo) GetReminderRecipients(ctx context.Context, eventID uuid.UUID) ([]uuid.UUID, error) {
+	return r.recipients, nil
+}
+
+func (r *reminderEventRepo) GetAttendeeIDs(ctx context.Context, eventID, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+// reminderNotificationRepo records the reminders sent, by recipient
+type reminderNotificationRepo struct {
+	notification.Repository
+	sent map[uuid.UUID]int
+}
+
+func (r *reminderNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
+	if n.Type == notification.TypeEventReminder {
+		r.sent[n.UserID]++
+	}
+	return nil
+}
+
+func TestSendEventReminders(t *testing.T) {
+	tests := []struct {
+		name        string
+		startsIn    time.Duration
+		wantClaimed []event.ReminderKind
+	}{
+		{"tomorrow", 20 * time.Hour, []event.ReminderKind{event.Reminder24h}},
+		{"within the hour", 30 * time.Minute, []event.ReminderKind{event.Reminder1h}},
+		{"next week", 7 * 24 * time.Hour, nil},
+		{"already started", -time.Minute, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := event.Event{ID: uuid.New(), Title: "Jazz Senja", StartTime: time.Now().Add(tt.startsIn)}
+			recipient := uuid.New()
+			repo := &reminderEventRepo{
+				events:     []event.Event{evt},
+				recipients: []uuid.UUID{recipient},
+				claimed:    make(map[event.ReminderKind]map[uuid.UUID]bool),
+			}
+			notifications := &reminderNotificationRepo{sent: make(map[uuid.UUID]int)}
+			uc := NewUsecase(repo, nil, notificationUsecase.NewUsecase(notifications, nil), nil, nil, nil)
+
+			// A second pass finds the reminders already claimed
+			for i := 0; i < 2; i++ {
+				if err := uc.SendEventReminders(context.Background()); err != nil {
+					t.Fatalf("SendEventReminders() error = %v", err)
+				}
+			}
+
+			for _, kind := range []event.ReminderKind{event.Reminder24h, event.Reminder1h} {
+				want := false
+				for _, k := range tt.wantClaimed {
+					want = want || k == kind
+				}
+				if got := repo.claimed[kind][evt.ID]; got != want {
+					t.Errorf("%s reminder claimed = %v, want %v", kind, got, want)
+				}
+			}
+			if got := notifications.sent[recipient]; got != len(tt.wantClaimed) {
+				t.Errorf("sent %d reminders, want %d", got, len(tt.wantClaimed))
+			}
+		})
+	}
+}
+
+func TestSendEventReminders_ClaimFails(t *testing.T) {
+	repo := &reminderEventRepo{claimErr: errors.New("connection reset")}
+	uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+	if err := uc.SendEventReminders(context.Background()); !errors.Is(err, repo.claimErr) {
+		t.Errorf("SendEventReminders() error = %v, want %v", err, repo.claimErr)
+	}
+}
+
+func TestAdvanceEventStatuses(t *testing.T) {
+	transitions := []event.StatusTransition{
+		{Event: event.Event{ID: uuid.New(), Status: event.StatusOngoing}, PreviousStatus: event.StatusUpcoming},
+	}
+
+	tests := []struct {
+		name      string
+		statusErr error
+	}{
+		{"events moved", nil},
+		{"transition fails", errors.New("connection reset")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &reminderEventRepo{transitions: transitions, statusErr: tt.statusErr}
+			uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+			if err := uc.AdvanceEventStatuses(context.Background()); !errors.Is(err, tt.statusErr) {
+				t.Errorf("AdvanceEventStatuses() error = %v, want %v", err, tt.statusErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/google/uuid"
+)
+
+// privacyEventRepo serves a single event and a fixed access for every user.
+// Methods the privacy checks don't reach are left to the embedded nil interface.
+type privacyEventRepo struct {
+	event.Repository
+	evt    *event.Event
+	access event.Access
+	joined bool
+}
+
+func (r *privacyEventRepo) GetByID(ctx context.Context, id uuid.UUID) (*event.Event, error) {
+	return r.evt, nil
+}
+
+func (r *privacyEventRepo) GetWithDetails(ctx context.Context, eventID, userID uuid.UUID) (*event.EventWithDetails, error) {
+	return &event.EventWithDetails{Event: *r.evt}, nil
+}
+
+func (r *privacyEventRepo) GetAccess(ctx context.Context, eventID, userID uuid.UUID) (*event.Access, error) {
+	access := r.access
+	return &access, nil
+}
+
+func (r *privacyEventRepo) GetAttendee(ctx context.Context, eventID, userID uuid.UUID) (*event.EventAttendee, error) {
+	return nil, errors.New("attendee not found")
+}
+
+func (r *privacyEventRepo) Join(ctx context.Context, attendee *event.EventAttendee) error {
+	r.joined = true
+	return nil
+}
+
+func TestUsecase_EventPrivacy(t *testing.T) {
+	tests := []struct {
+		name    string
+		privacy event.EventPrivacy
+		access  event.Access
+		wantErr error
+	}{
+		{"public event, stranger", event.PrivacyPublic, event.Access{}, nil},
+		{"friends_only event, stranger", event.PrivacyFriendsOnly, event.Access{}, ErrEventForbidden},
+		{"friends_only event, mutual follower", event.PrivacyFriendsOnly, event.Access{IsMutualFollower: true}, nil},
+		{"private event, mutual follower", event.PrivacyPrivate, event.Access{IsMutualFollower: true}, ErrEventForbidden},
+		{"private event, attendee", event.PrivacyPrivate, event.Access{IsAttendee: true}, nil},
+		{"private event, invited", event.PrivacyPrivate, event.Access{IsInvited: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &privacyEventRepo{
+				evt:    &event.Event{ID: uuid.New(), HostID: uuid.New(), Privacy: tt.privacy, MaxAttendees: 10},
+				access: tt.access,
+			}
+			uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+			userID := uuid.New()
+
+			if _, err := uc.GetEventWithDetails(context.Background(), repo.evt.ID, userID); err != tt.wantErr {
+				t.Errorf("GetEventWithDetails() error = %v, want %v", err, tt.wantErr)
+			}
+
+			_, err := uc.JoinEvent(context.Background(), repo.evt.ID, userID, nil)
+			if err != tt.wantErr {
+				t.Errorf("JoinEvent() error = %v, want %v", err, tt.wantErr)
+			}
+			if joined := tt.wantErr == nil; repo.joined != joined {
+				t.Errorf("JoinEvent() joined = %v, want %v", repo.joined, joined)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/google/uuid"
+)
+
+const (
+	// seriesHorizon is how far ahead the occurrences of a series are generated
+	seriesHorizon = 90 * 24 * time.Hour
+	// seriesExtendEvery is how often a series' horizon is moved forward
+	seriesExtendEvery = 24 * time.Hour
+	// seriesOccurrencesLimit caps the occurrences listed with a series
+	seriesOccurrencesLimit = 100
+)
+
+// newSeries validates a recurrence and creates the (unsaved) series it describes,
+// starting with the occurrence at start
+func newSeries(hostID uuid.UUID, start time.Time, req *event.RecurrenceRequest) (*event.EventSeries, error) {
+	series := &event.EventSeries{
+		HostID:   hostID,
+		StartsAt: start.UTC(),
+	}
+	if err := applyRecurrence(series, req); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// applyRecurrence validates a recurrence and sets it on the series
+func applyRecurrence(series *event.EventSeries, req *event.RecurrenceRequest) error {
+	if _, err := event.ParseRecurrence(req.RRule); err != nil {
+		return err
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return ErrInvalidTimezone
+	}
+
+	exceptionDates := make([]string, 0, len(req.ExceptionDates))
+	for _, date := range req.ExceptionDates {
+		if _, err := time.Parse(event.SeriesDateLayout, date); err != nil {
+			return fmt.Errorf("%w: invalid exception date %s", ErrInvalidRecurrence, date)
+		}
+		exceptionDates = append(exceptionDates, date)
+	}
+
+	series.RRule = event.NormalizeRRule(req.RRule)
+	series.Timezone = timezone
+	series.ExceptionDates = exceptionDates
+	return nil
+}
+
+// GetEventSeries gets the series an event is an occurrence of, with its upcoming
+// occurrences the user may view
+func (uc *Usecase) GetEventSeries(ctx context.Context, eventID, userID uuid.UUID) (*event.SeriesWithOccurrences, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if err := event.CheckAccess(ctx, uc.eventRepo, evt, userID); err != nil {
+		return nil, err
+	}
+	if evt.SeriesID == nil {
+		return nil, ErrNotSeriesEvent
+	}
+
+	series, err := uc.eventRepo.GetSeries(ctx, *evt.SeriesID)
+	if err != nil {
+		return nil, ErrNotSeriesEvent
+	}
+
+	occurrences, err := uc.eventRepo.GetSeriesOccurrences(ctx, series.ID, time.Now(), seriesOccurrencesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invitations are per occurrence, so private occurrences are checked one by one
+	visible := make([]event.Event, 0, len(occurrences))
+	for i := range occurrences {
+		if occurrences[i].ID != evt.ID && event.CheckAccess(ctx, uc.eventRepo, &occurrences[i], userID) != nil {
+			continue
+		}
+		visible = append(visible, occurrences[i])
+	}
+
+	return &event.SeriesWithOccurrences{EventSeries: *series, Occurrences: visible}, nil
+}
+
+// GenerateSeriesOccurrences is called by the background series worker. It moves
+// the horizon of every series forward, generating the occurrences that come into it.
+func (uc *Usecase) GenerateSeriesOccurrences(ctx context.Context) error {
+	series, err := uc.eventRepo.GetSeriesToExtend(ctx, time.Now().Add(seriesHorizon-seriesExtendEvery))
+	if err != nil {
+		return err
+	}
+
+	for i := range series {
+		if err := uc.extendSeries(ctx, &series[i]); err != nil {
+			log.Printf("[EventSeries] failed to generate occurrences of series %s: %v", series[i].ID, err)
+		}
+	}
+
+	return nil
+}
+
+// extendSeries generates the missing occurrences of a series from now up to the
+// horizon, copying the latest occurrence that wasn't edited on its own, along
+// with its images, ticket tiers and promo codes
+func (uc *Usecase) extendSeries(ctx context.Context, series *event.EventSeries) error {
+	now := time.Now()
+	until := now.Add(seriesHorizon)
+
+	starts, err := series.OccurrenceStarts(now, until)
+	if err != nil {
+		return err
+	}
+
+	template, err := uc.eventRepo.GetSeriesTemplate(ctx, series.ID)
+	if err != nil {
+		// Every occurrence was cancelled or edited on its own; there is nothing to copy
+		log.Printf("[EventSeries] series %s has no occurrence to copy: %v", series.ID, err)
+		series.GeneratedUntil = until
+		return uc.eventRepo.UpdateSeries(ctx, series)
+	}
+
+	duration := template.EndTime.Sub(template.StartTime)
+	occurrences := make([]event.Event, 0, len(starts))
+	for _, start := range starts {
+		start := start
+		occurrence := *template
+		occurrence.StartTime = start
+		occurrence.EndTime = start.Add(duration)
+		occurrence.OccurrenceStart = &start
+		occurrence.SeriesOverride = false
+		occurrences = append(occurrences, occurrence)
+	}
+
+	created, err := uc.eventRepo.CreateOccurrences(ctx, series.ID, template, occurrences)
+	if err != nil {
+		return err
+	}
+
+	if len(created) > 0 {
+		imageURLs, err := uc.eventRepo.GetImages(ctx, template.ID)
+		if err != nil {
+			log.Printf("[EventSeries] failed to get images of event %s: %v", template.ID, err)
+		}
+		for _, occurrence := range created {
+			uc.copyImages(ctx, occurrence.ID, imageURLs)
+		}
+		log.Printf("[EventSeries] generated %d occurrences of series %s", len(created), series.ID)
+	}
+
+	series.GeneratedUntil = until
+	return uc.eventRepo.UpdateSeries(ctx, series)
+}
+
+func (uc *Usecase) copyImages(ctx context.Context, eventID uuid.UUID, imageURLs []string) {
+	if len(imageURLs) == 0 {
+		return
+	}
+
+	images := make([]event.EventImage, len(imageURLs))
+	for i, url := range imageURLs {
+		images[i] = event.EventImage{
+			ID:       uuid.New(),
+			EventID:  eventID,
+			ImageURL: url,
+			Order:    i,
+		}
+	}
+	if err := uc.eventRepo.AddImages(ctx, images); err != nil {
+		log.Printf("[EventSeries] failed to copy images to event %s: %v", eventID, err)
+	}
+}
+
+// updateSeries saves a series-wide edit of occurrence e and applies it to the
+// upcoming occurrences of its series, all in one transaction. A changed start
+// time moves them all by the same amount; a new recurrence drops the occurrences
+// that no longer fit it. Returns the other occurrences that were updated.
+func (uc *Usecase) updateSeries(ctx context.Context, e *event.Event, series *event.EventSeries, previous event.Schedule, recurrence *event.RecurrenceRequest) ([]uuid.UUID, error) {
+	shift := e.StartTime.Sub(previous.StartTime)
+	series.StartsAt = series.StartsAt.Add(shift)
+
+	var keep []time.Time
+	if recurrence != nil {
+		if err := applyRecurrence(series, recurrence); err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		starts, err := series.OccurrenceStarts(now, now.Add(seriesHorizon))
+		if err != nil {
+			return nil, err
+		}
+		keep = append(make([]time.Time, 0, len(starts)), starts...)
+	}
+
+	// Remember the other occurrences' schedules, so only the ones that moved are announced
+	before := make(map[uuid.UUID]event.Schedule)
+	occurrences, err := uc.eventRepo.GetSeriesOccurrences(ctx, series.ID, time.Now(), seriesOccurrencesLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range occurrences {
+		before[occurrences[i].ID] = occurrences[i].Schedule()
+	}
+
+	updatedIDs, err := uc.eventRepo.UpdateSeriesEvent(ctx, e, series, shift, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	// The slots a new recurrence adds are generated once the edit is saved; the
+	// series worker catches up if this fails
+	if err := uc.extendSeries(ctx, series); err != nil {
+		log.Printf("[EventSeries] failed to extend series %s: %v", series.ID, err)
+	}
+
+	// Let the attendees of the other occurrences know they moved. Occurrences past
+	// the listing limit weren't remembered, so they're announced regardless.
+	for _, id := range updatedIDs {
+		occurrence, err := uc.eventRepo.GetByID(ctx, id)
+		if err != nil {
+			continue
+		}
+		uc.announceEventChange(ctx, occurrence, before[id], true)
+	}
+
+	return updatedIDs, nil
+}
+
+// excludeOccurrence adds the slot of a deleted occurrence to its series'
+// exception dates, so it isn't generated again
+func (uc *Usecase) excludeOccurrence(ctx context.Context, e *event.Event) error {
+	if e.SeriesID == nil || e.OccurrenceStart == nil {
+		return nil
+	}
+
+	series, err := uc.eventRepo.GetSeries(ctx, *e.SeriesID)
+	if err != nil {
+		return nil
+	}
+	loc, err := series.Location()
+	if err != nil {
+		return err
+	}
+
+	date := e.OccurrenceStart.In(loc).Format(event.SeriesDateLayout)
+	for _, excluded := range series.ExceptionDates {
+		if excluded == date {
+			return nil
+		}
+	}
+	series.ExceptionDates = append(series.ExceptionDates, date)
+
+	return uc.eventRepo.UpdateSeries(ctx, series)
+}
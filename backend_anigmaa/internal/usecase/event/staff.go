@@ -0,0 +1,82 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/google/uuid"
+)
+
+// staffRoleNames describe roles in notifications
+var staffRoleNames = map[event.StaffRole]string{
+	event.StaffCoHost:       "a co-host",
+	event.StaffCheckIn:      "check-in staff",
+	event.StaffQnAModerator: "a Q&A moderator",
+}
+
+// SetStaffRole grants a user a role on an event, replacing the role they had (host only)
+func (uc *Usecase) SetStaffRole(ctx context.Context, eventID, hostID, userID uuid.UUID, req *event.SetStaffRoleRequest) (*event.Staff, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if evt.HostID != hostID {
+		return nil, ErrUnauthorized
+	}
+	if userID == hostID {
+		return nil, ErrCannotStaffHost
+	}
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrStaffUserNotFound
+	}
+
+	staff := &event.Staff{
+		EventID: eventID,
+		UserID:  userID,
+		Role:    req.Role,
+		AddedBy: hostID,
+	}
+	if err := uc.eventRepo.SetStaff(ctx, staff); err != nil {
+		return nil, err
+	}
+
+	uc.notificationUC.NotifyStaffRoleGranted(ctx, hostID, userID, eventID, evt.Title, staffRoleNames[req.Role])
+
+	return staff, nil
+}
+
+// RemoveStaff takes a user's role on an event away. The host removes anyone;
+// staff can step down themselves.
+func (uc *Usecase) RemoveStaff(ctx context.Context, eventID, requestingUserID, userID uuid.UUID) error {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return ErrEventNotFound
+	}
+	if evt.HostID != requestingUserID && userID != requestingUserID {
+		return ErrUnauthorized
+	}
+
+	if err := uc.eventRepo.RemoveStaff(ctx, eventID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrStaffNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetStaff lists the staff of an event for its host and staff
+func (uc *Usecase) GetStaff(ctx context.Context, eventID, userID uuid.UUID) ([]event.StaffWithUser, error) {
+	access, err := uc.eventRepo.GetAccess(ctx, eventID, userID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if !access.IsHost && access.StaffRole == "" {
+		return nil, ErrUnauthorized
+	}
+
+	return uc.eventRepo.GetStaff(ctx, eventID)
+}
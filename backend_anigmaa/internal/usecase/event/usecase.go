@@ -2,37 +2,68 @@ package event
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/event"
 	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/anigmaa/backend/internal/infrastructure/realtime"
+	emailUsecase "github.com/anigmaa/backend/internal/usecase/email"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
+	ticketUsecase "github.com/anigmaa/backend/internal/usecase/ticket"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
 var (
 	ErrEventNotFound     = errors.New("event not found")
 	ErrUnauthorized      = errors.New("unauthorized - not event host")
-	ErrEventFull         = errors.New("event is full")
+	ErrEventFull         = event.ErrEventFull         // proxy to domain sentinel
+	ErrCapacityBelowSold = event.ErrCapacityBelowSold // proxy to domain sentinel
 	ErrAlreadyJoined     = errors.New("already joined this event")
 	ErrNotJoined         = errors.New("not joined this event")
 	ErrInvalidTimeRange  = errors.New("end time must be after start time")
 	ErrPastEvent         = errors.New("cannot create event in the past")
 	ErrCannotLeaveAsHost = errors.New("host cannot leave their own event")
 	ErrCannotCancelPast  = errors.New("cannot cancel past event")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrEventForbidden    = event.ErrForbidden // proxy to domain sentinel
+	ErrJoinPending       = errors.New("join request is awaiting the host's approval")
+	ErrJoinRejected      = errors.New("join request was rejected by the host")
+	ErrNotApprovalEvent  = errors.New("event does not require approval to join")
+	ErrInvalidRecurrence = event.ErrInvalidRecurrence // proxy to domain sentinel
+	ErrInvalidTimezone   = errors.New("invalid timezone")
+	ErrNotSeriesEvent    = errors.New("event is not part of a series")
+	ErrInvalidApplyTo    = errors.New("apply_to must be occurrence or series, and recurrence changes need series")
+	ErrStaffNotFound     = errors.New("user is not staff of this event")
+	ErrStaffUserNotFound = errors.New("user not found")
+	ErrCannotStaffHost   = errors.New("the host can't be staff of their own event")
 )
 
+// attendeePageSize is how many attendees are read at a time to notify them all
+const attendeePageSize = 500
+
 // Usecase handles event business logic
 type Usecase struct {
-	eventRepo event.Repository
-	userRepo  user.Repository
+	eventRepo      event.Repository
+	userRepo       user.Repository
+	notificationUC *notificationUsecase.Usecase
+	emailUC        *emailUsecase.Usecase
+	ticketUC       *ticketUsecase.Usecase
+	hub            *realtime.Hub
 }
 
 // NewUsecase creates a new event usecase
-func NewUsecase(eventRepo event.Repository, userRepo user.Repository) *Usecase {
+func NewUsecase(eventRepo event.Repository, userRepo user.Repository, notificationUC *notificationUsecase.Usecase, emailUC *emailUsecase.Usecase, ticketUC *ticketUsecase.Usecase, hub *realtime.Hub) *Usecase {
 	return &Usecase{
-		eventRepo: eventRepo,
-		userRepo:  userRepo,
+		eventRepo:      eventRepo,
+		userRepo:       userRepo,
+		notificationUC: notificationUC,
+		emailUC:        emailUC,
+		ticketUC:       ticketUC,
+		hub:            hub,
 	}
 }
 
@@ -54,6 +85,15 @@ func (uc *Usecase) CreateEvent(ctx context.Context, hostID uuid.UUID, req *event
 		return nil, errors.New("host user not found")
 	}
 
+	// Recurring events become the first occurrence of a new series
+	var series *event.EventSeries
+	if req.Recurrence != nil {
+		series, err = newSeries(hostID, req.StartTime, req.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create event
 	now := time.Now()
 	newEvent := &event.Event{
@@ -76,6 +116,7 @@ func (uc *Usecase) CreateEvent(ctx context.Context, hostID uuid.UUID, req *event
 		Privacy:          req.Privacy,
 		Requirements:     req.Requirements,
 		TicketingEnabled: req.TicketingEnabled,
+		RequiresApproval: req.RequiresApproval,
 		TicketsSold:      0,
 		CreatedAt:        now.UTC(),
 		UpdatedAt:        now.UTC(),
@@ -86,6 +127,15 @@ func (uc *Usecase) CreateEvent(ctx context.Context, hostID uuid.UUID, req *event
 		return nil, errors.New("price must be set for paid events")
 	}
 
+	if series != nil {
+		series.GeneratedUntil = newEvent.StartTime
+		if err := uc.eventRepo.CreateSeries(ctx, series); err != nil {
+			return nil, err
+		}
+		newEvent.SeriesID = &series.ID
+		newEvent.OccurrenceStart = &newEvent.StartTime
+	}
+
 	if err := uc.eventRepo.Create(ctx, newEvent); err != nil {
 		return nil, err
 	}
@@ -111,6 +161,13 @@ func (uc *Usecase) CreateEvent(ctx context.Context, hostID uuid.UUID, req *event
 		// Log error but don't fail
 	}
 
+	// Generate the following occurrences; the series worker retries on failure
+	if series != nil {
+		if err := uc.extendSeries(ctx, series); err != nil {
+			log.Printf("[EventSeries] failed to generate occurrences of series %s: %v", series.ID, err)
+		}
+	}
+
 	return newEvent, nil
 }
 
@@ -129,9 +186,21 @@ func (uc *Usecase) GetEventWithDetails(ctx context.Context, eventID, userID uuid
 	if err != nil {
 		return nil, ErrEventNotFound
 	}
+	if err := event.CheckAccess(ctx, uc.eventRepo, &evt.Event, userID); err != nil {
+		return nil, err
+	}
 	return evt, nil
 }
 
+// CheckEventAccess returns ErrEventForbidden unless the user may view the event
+func (uc *Usecase) CheckEventAccess(ctx context.Context, eventID, userID uuid.UUID) error {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return ErrEventNotFound
+	}
+	return event.CheckAccess(ctx, uc.eventRepo, evt, userID)
+}
+
 // UpdateEvent updates an event
 func (uc *Usecase) UpdateEvent(ctx context.Context, eventID, userID uuid.UUID, req *event.UpdateEventRequest) (*event.Event, error) {
 	// Get existing event
@@ -144,6 +213,36 @@ func (uc *Usecase) UpdateEvent(ctx context.Context, eventID, userID uuid.UUID, r
 	if existingEvent.HostID != userID {
 		return nil, ErrUnauthorized
 	}
+	previous := existingEvent.Schedule()
+	previousCapacity := existingEvent.MaxAttendees
+
+	// Occurrences of a series are edited on their own unless the edit applies to the series
+	scope := event.ScopeOccurrence
+	if req.ApplyTo != nil {
+		scope = *req.ApplyTo
+	}
+	if scope != event.ScopeOccurrence && scope != event.ScopeSeries {
+		return nil, ErrInvalidApplyTo
+	}
+	if req.Recurrence != nil && scope != event.ScopeSeries {
+		return nil, ErrInvalidApplyTo
+	}
+
+	var series *event.EventSeries
+	if scope == event.ScopeSeries {
+		if existingEvent.SeriesID == nil {
+			return nil, ErrNotSeriesEvent
+		}
+		series, err = uc.eventRepo.GetSeries(ctx, *existingEvent.SeriesID)
+		if err != nil {
+			return nil, ErrNotSeriesEvent
+		}
+		if req.Recurrence != nil {
+			if err := applyRecurrence(&event.EventSeries{}, req.Recurrence); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	// Update fields if provided
 	if req.Title != nil {
@@ -185,6 +284,9 @@ func (uc *Usecase) UpdateEvent(ctx context.Context, eventID, userID uuid.UUID, r
 	if req.Requirements != nil {
 		existingEvent.Requirements = req.Requirements
 	}
+	if req.RequiresApproval != nil {
+		existingEvent.RequiresApproval = *req.RequiresApproval
+	}
 	if req.Status != nil {
 		existingEvent.Status = *req.Status
 	}
@@ -194,10 +296,30 @@ func (uc *Usecase) UpdateEvent(ctx context.Context, eventID, userID uuid.UUID, r
 		return nil, ErrInvalidTimeRange
 	}
 
+	// Tickets already sold are never taken back (the repository checks again under a lock)
+	if existingEvent.MaxAttendees != previousCapacity && existingEvent.MaxAttendees < existingEvent.TicketsSold {
+		return nil, ErrCapacityBelowSold
+	}
+
+	if existingEvent.SeriesID != nil {
+		existingEvent.SeriesOverride = scope == event.ScopeOccurrence
+	}
 	existingEvent.UpdatedAt = time.Now().UTC()
 
-	// Save changes
-	if err := uc.eventRepo.Update(ctx, existingEvent); err != nil {
+	// Save changes; a series-wide edit saves the other occurrences and the series with it
+	var updatedIDs []uuid.UUID
+	if series != nil {
+		updatedIDs, err = uc.updateSeries(ctx, existingEvent, series, previous, req.Recurrence)
+	} else {
+		err = uc.eventRepo.Update(ctx, existingEvent)
+	}
+	if err != nil {
+		if errors.Is(err, event.ErrCapacityBelowSold) {
+			return nil, ErrCapacityBelowSold
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
 		return nil, err
 	}
 
@@ -224,9 +346,96 @@ func (uc *Usecase) UpdateEvent(ctx context.Context, eventID, userID uuid.UUID, r
 		}
 	}
 
+	// Let confirmed attendees know the event changed
+	uc.announceEventChange(ctx, existingEvent, previous, true)
+
+	// A raised capacity goes to the waitlist first
+	if existingEvent.MaxAttendees > previousCapacity {
+		uc.promoteWaitlist(ctx, existingEvent.ID)
+	}
+	// The other occurrences may have had a smaller capacity than this one
+	if req.MaxAttendees != nil {
+		for _, id := range updatedIDs {
+			uc.promoteWaitlist(ctx, id)
+		}
+	}
+
 	return existingEvent, nil
 }
 
+// promoteWaitlist offers the spots of an event to its waitlist after its capacity
+// changed. A failed promotion doesn't fail the edit; the waitlist worker retries it.
+func (uc *Usecase) promoteWaitlist(ctx context.Context, eventID uuid.UUID) {
+	if uc.ticketUC == nil {
+		return
+	}
+	if err := uc.ticketUC.PromoteWaitlist(ctx, eventID); err != nil {
+		log.Printf("[EventUsecase] Failed to promote waitlist of event %s: %v", eventID, err)
+	}
+}
+
+// announceEventChange tells an event's confirmed attendees that the host moved
+// or cancelled it: in app when notify is set, by email, and on their streams
+// when its status changed. Edits that leave its schedule as previous, e.g. of
+// the description, aren't announced. Large events have many attendees, so this
+// runs in the background rather than holding up the host's request.
+func (uc *Usecase) announceEventChange(ctx context.Context, e *event.Event, previous event.Schedule, notify bool) {
+	if !e.Schedule().ChangedFrom(previous) {
+		return
+	}
+	changed := *e
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		attendeeIDs := uc.confirmedAttendeeIDs(ctx, &changed)
+		if notify {
+			uc.notificationUC.NotifyEventUpdated(ctx, changed.HostID, changed.ID, changed.Title, attendeeIDs)
+		}
+		uc.emailUC.SendEventChanged(ctx, &changed, previous, attendeeIDs)
+		if changed.Status != previous.Status {
+			uc.publishStatusChange(ctx, &changed, previous.Status, attendeeIDs)
+		}
+	}()
+}
+
+// confirmedAttendeeIDs returns the user IDs of all of an event's confirmed attendees
+func (uc *Usecase) confirmedAttendeeIDs(ctx context.Context, e *event.Event) []uuid.UUID {
+	attendeeIDs := make([]uuid.UUID, 0)
+	after := uuid.Nil
+	for {
+		page, err := uc.eventRepo.GetAttendeeIDs(ctx, e.ID, after, attendeePageSize)
+		if err != nil {
+			log.Printf("[EventUsecase] Failed to load attendees of event %s: %v", e.ID, err)
+			return attendeeIDs
+		}
+
+		attendeeIDs = append(attendeeIDs, page...)
+		if len(page) < attendeePageSize {
+			return attendeeIDs
+		}
+		after = page[len(page)-1]
+	}
+}
+
+// publishStatusChange pushes an event status change to the host and attendees' streams.
+// Pass attendeeIDs when already loaded, or nil to load them.
+func (uc *Usecase) publishStatusChange(ctx context.Context, e *event.Event, previousStatus event.EventStatus, attendeeIDs []uuid.UUID) {
+	if uc.hub == nil {
+		return
+	}
+	if attendeeIDs == nil {
+		attendeeIDs = uc.confirmedAttendeeIDs(ctx, e)
+	}
+
+	recipients := append([]uuid.UUID{e.HostID}, attendeeIDs...)
+	uc.hub.PublishToUsers(ctx, recipients, realtime.EventStatusChanged, event.StatusChange{
+		EventID:        e.ID,
+		Title:          e.Title,
+		PreviousStatus: previousStatus,
+		Status:         e.Status,
+	})
+}
+
 // DeleteEvent deletes an event
 func (uc *Usecase) DeleteEvent(ctx context.Context, eventID, userID uuid.UUID) error {
 	// Get existing event
@@ -240,11 +449,19 @@ func (uc *Usecase) DeleteEvent(ctx context.Context, eventID, userID uuid.UUID) e
 		return ErrUnauthorized
 	}
 
+	// A deleted occurrence of a series must not be generated again
+	if err := uc.excludeOccurrence(ctx, existingEvent); err != nil {
+		return err
+	}
+
 	return uc.eventRepo.Delete(ctx, eventID)
 }
 
-// ListEvents lists events with filters
-func (uc *Usecase) ListEvents(ctx context.Context, filter *event.EventFilter, userID uuid.UUID) ([]event.EventWithDetails, error) {
+// ListEvents lists events with filters.
+// The default order (newest first) is paged by cursor; discovery modes are ranked
+// with some randomness and can only be paged by offset.
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) ListEvents(ctx context.Context, filter *event.EventFilter, userID uuid.UUID, cursor string) ([]event.EventWithDetails, string, error) {
 	// Set default limits
 	if filter.Limit <= 0 {
 		filter.Limit = 20
@@ -253,16 +470,50 @@ func (uc *Usecase) ListEvents(ctx context.Context, filter *event.EventFilter, us
 		filter.Limit = 100
 	}
 
-	return uc.eventRepo.List(ctx, filter, userID)
+	if filter.Mode != "" {
+		if cursor != "" {
+			return nil, "", ErrInvalidCursor
+		}
+		events, err := uc.eventRepo.List(ctx, filter, userID)
+		return events, "", err
+	}
+
+	position, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	if position != nil {
+		filter.BeforeCreatedAt = &position.CreatedAt
+		filter.BeforeID = &position.ID
+		filter.Offset = 0
+	}
+
+	// Fetch one extra event to know whether another page exists
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	events, err := uc.eventRepo.List(ctx, filter, userID)
+	filter.Limit = limit
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[len(events)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return events, nextCursor, nil
 }
 
-// GetByHost gets events created by a host
-func (uc *Usecase) GetByHost(ctx context.Context, hostID uuid.UUID, limit, offset int) ([]event.EventWithDetails, error) {
-	return uc.GetEventsByHost(ctx, hostID, limit, offset)
+// GetByHost gets events created by a host that the viewer may see
+func (uc *Usecase) GetByHost(ctx context.Context, hostID, viewerID uuid.UUID, limit, offset int) ([]event.EventWithDetails, error) {
+	return uc.GetEventsByHost(ctx, hostID, viewerID, limit, offset)
 }
 
-// GetEventsByHost gets events created by a host
-func (uc *Usecase) GetEventsByHost(ctx context.Context, hostID uuid.UUID, limit, offset int) ([]event.EventWithDetails, error) {
+// GetEventsByHost gets events created by a host that the viewer may see
+func (uc *Usecase) GetEventsByHost(ctx context.Context, hostID, viewerID uuid.UUID, limit, offset int) ([]event.EventWithDetails, error) {
 	// Verify host exists
 	_, err := uc.userRepo.GetByID(ctx, hostID)
 	if err != nil {
@@ -276,7 +527,7 @@ func (uc *Usecase) GetEventsByHost(ctx context.Context, hostID uuid.UUID, limit,
 		limit = 100
 	}
 
-	return uc.eventRepo.GetByHost(ctx, hostID, limit, offset)
+	return uc.eventRepo.GetByHost(ctx, hostID, viewerID, limit, offset)
 }
 
 // GetJoinedEvents gets events a user has joined
@@ -291,8 +542,8 @@ func (uc *Usecase) GetJoinedEvents(ctx context.Context, userID uuid.UUID, limit,
 	return uc.eventRepo.GetJoinedEvents(ctx, userID, limit, offset)
 }
 
-// GetNearbyEvents gets events near a location
-func (uc *Usecase) GetNearbyEvents(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]event.EventWithDetails, error) {
+// GetNearbyEvents gets events near a location that the viewer may see
+func (uc *Usecase) GetNearbyEvents(ctx context.Context, lat, lng, radiusKm float64, limit int, viewerID uuid.UUID) ([]event.EventWithDetails, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -300,17 +551,17 @@ func (uc *Usecase) GetNearbyEvents(ctx context.Context, lat, lng, radiusKm float
 		limit = 100
 	}
 
-	return uc.eventRepo.GetNearby(ctx, lat, lng, radiusKm, limit)
+	return uc.eventRepo.GetNearby(ctx, lat, lng, radiusKm, limit, viewerID)
 }
 
-// CountEvents counts total events matching filter
-func (uc *Usecase) CountEvents(ctx context.Context, filter *event.EventFilter) (int, error) {
-	return uc.eventRepo.CountEvents(ctx, filter)
+// CountEvents counts total events matching filter that the viewer may see
+func (uc *Usecase) CountEvents(ctx context.Context, filter *event.EventFilter, viewerID uuid.UUID) (int, error) {
+	return uc.eventRepo.CountEvents(ctx, filter, viewerID)
 }
 
-// CountHostedEvents counts total events by a host
-func (uc *Usecase) CountHostedEvents(ctx context.Context, hostID uuid.UUID) (int, error) {
-	return uc.eventRepo.CountHostedEvents(ctx, hostID)
+// CountHostedEvents counts total events by a host that the viewer may see
+func (uc *Usecase) CountHostedEvents(ctx context.Context, hostID, viewerID uuid.UUID) (int, error) {
+	return uc.eventRepo.CountHostedEvents(ctx, hostID, viewerID)
 }
 
 // CountJoinedEvents counts total events a user has joined
@@ -323,26 +574,35 @@ func (uc *Usecase) CountAttendees(ctx context.Context, eventID uuid.UUID) (int,
 	return uc.eventRepo.CountAttendees(ctx, eventID)
 }
 
-// JoinEvent joins an event
-func (uc *Usecase) JoinEvent(ctx context.Context, eventID, userID uuid.UUID) error {
+// JoinEvent joins an event. For events that require approval a pending join
+// request is sent to the host instead; the returned status tells which.
+func (uc *Usecase) JoinEvent(ctx context.Context, eventID, userID uuid.UUID, req *event.JoinEventRequest) (event.AttendeeStatus, error) {
 	// Get event
 	evt, err := uc.eventRepo.GetByID(ctx, eventID)
 	if err != nil {
-		return ErrEventNotFound
+		return "", ErrEventNotFound
 	}
 
-	// Check if event is full
-	if evt.IsFull() {
-		return ErrEventFull
+	// Friends-only and private events can't be joined by outsiders
+	if err := event.CheckAccess(ctx, uc.eventRepo, evt, userID); err != nil {
+		return "", err
 	}
 
-	// Check if already joined
-	isAttending, err := uc.eventRepo.IsAttending(ctx, eventID, userID)
-	if err != nil {
-		return err
-	}
-	if isAttending {
-		return ErrAlreadyJoined
+	// Check if event is full
+	if evt.IsFull() {
+		return "", ErrEventFull
+	}
+
+	// Check if already joined or requested to join
+	if existing, err := uc.eventRepo.GetAttendee(ctx, eventID, userID); err == nil {
+		switch existing.Status {
+		case event.AttendeeConfirmed:
+			return "", ErrAlreadyJoined
+		case event.AttendeePending:
+			return "", ErrJoinPending
+		case event.AttendeeRejected:
+			return "", ErrJoinRejected
+		}
 	}
 
 	// Create attendee record
@@ -353,11 +613,25 @@ func (uc *Usecase) JoinEvent(ctx context.Context, eventID, userID uuid.UUID) err
 		JoinedAt: time.Now(),
 		Status:   event.AttendeeConfirmed,
 	}
+	if evt.RequiresApproval && evt.HostID != userID {
+		attendee.Status = event.AttendeePending
+		if req != nil {
+			attendee.RequirementsAnswer = req.RequirementsAnswer
+		}
+	}
+
+	if err := uc.eventRepo.Join(ctx, attendee); err != nil {
+		return "", err
+	}
+
+	if attendee.Status == event.AttendeePending {
+		uc.notificationUC.NotifyJoinRequest(ctx, userID, evt.HostID, evt.ID, evt.Title)
+	}
 
-	return uc.eventRepo.Join(ctx, attendee)
+	return attendee.Status, nil
 }
 
-// LeaveEvent leaves an event
+// LeaveEvent leaves an event, or withdraws a pending join request
 func (uc *Usecase) LeaveEvent(ctx context.Context, eventID, userID uuid.UUID) error {
 	// Get event
 	evt, err := uc.eventRepo.GetByID(ctx, eventID)
@@ -370,26 +644,107 @@ func (uc *Usecase) LeaveEvent(ctx context.Context, eventID, userID uuid.UUID) er
 		return ErrCannotLeaveAsHost
 	}
 
-	// Check if joined
-	isAttending, err := uc.eventRepo.IsAttending(ctx, eventID, userID)
+	// Check if joined or waiting for approval
+	attendee, err := uc.eventRepo.GetAttendee(ctx, eventID, userID)
 	if err != nil {
-		return err
+		return ErrNotJoined
 	}
-	if !isAttending {
+	if attendee.Status != event.AttendeeConfirmed && attendee.Status != event.AttendeePending {
 		return ErrNotJoined
 	}
 
 	return uc.eventRepo.Leave(ctx, eventID, userID)
 }
 
-// GetAttendees gets event attendees
-func (uc *Usecase) GetAttendees(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]event.EventAttendee, error) {
-	// Check if event exists
-	_, err := uc.eventRepo.GetByID(ctx, eventID)
+// GetJoinRequests gets the join requests of an event with the given status,
+// along with the requesters' answers to the event's requirements (host only)
+func (uc *Usecase) GetJoinRequests(ctx context.Context, eventID, hostID uuid.UUID, status event.AttendeeStatus, limit, offset int) ([]event.JoinRequest, int, error) {
+	if _, err := uc.getApprovalEvent(ctx, eventID, hostID); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	requests, err := uc.eventRepo.GetJoinRequests(ctx, eventID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.eventRepo.CountJoinRequests(ctx, eventID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return requests, total, nil
+}
+
+// ReviewJoinRequests approves or rejects the pending join requests of the given
+// users (host only) and notifies them. Users without a pending request are
+// skipped; returns the users whose request was reviewed. Approvals that would
+// overbook the event are refused as a whole.
+func (uc *Usecase) ReviewJoinRequests(ctx context.Context, eventID, hostID uuid.UUID, userIDs []uuid.UUID, approve bool) ([]uuid.UUID, error) {
+	evt, err := uc.getApprovalEvent(ctx, eventID, hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := event.AttendeeRejected
+	if approve {
+		status = event.AttendeeConfirmed
+	}
+
+	// The repository checks approvals against the capacity in the same transaction
+	reviewed, err := uc.eventRepo.ReviewJoinRequests(ctx, eventID, userIDs, status)
+	if err != nil {
+		if errors.Is(err, event.ErrEventFull) {
+			return nil, ErrEventFull
+		}
+		return nil, err
+	}
+
+	for _, userID := range reviewed {
+		uc.notificationUC.NotifyJoinRequestReviewed(ctx, hostID, userID, evt.ID, evt.Title, approve)
+	}
+
+	return reviewed, nil
+}
+
+// getApprovalEvent gets an event that requires approval and verifies that userID is its host
+func (uc *Usecase) getApprovalEvent(ctx context.Context, eventID, userID uuid.UUID) (*event.Event, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
 	if err != nil {
 		return nil, ErrEventNotFound
 	}
 
+	if evt.HostID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	// Requests left pending after approval was turned off can still be reviewed
+	if !evt.RequiresApproval {
+		count, err := uc.eventRepo.CountJoinRequests(ctx, eventID, event.AttendeePending)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrNotApprovalEvent
+		}
+	}
+
+	return evt, nil
+}
+
+// GetAttendees gets event attendees
+func (uc *Usecase) GetAttendees(ctx context.Context, eventID, viewerID uuid.UUID, limit, offset int) ([]event.EventAttendee, error) {
+	if err := uc.CheckEventAccess(ctx, eventID, viewerID); err != nil {
+		return nil, err
+	}
+
 	if limit <= 0 {
 		limit = 50
 	}
@@ -424,7 +779,14 @@ func (uc *Usecase) CancelEvent(ctx context.Context, eventID, userID uuid.UUID) e
 	}
 
 	// Update status to cancelled
-	return uc.eventRepo.UpdateStatus(ctx, eventID, event.StatusCancelled)
+	if err := uc.eventRepo.UpdateStatus(ctx, eventID, event.StatusCancelled); err != nil {
+		return err
+	}
+
+	previous := existingEvent.Schedule()
+	existingEvent.Status = event.StatusCancelled
+	uc.announceEventChange(ctx, existingEvent, previous, false)
+	return nil
 }
 
 // GetUpcomingEvents gets upcoming events
@@ -477,7 +839,13 @@ func (uc *Usecase) UpdateEventStatus(ctx context.Context, eventID uuid.UUID) err
 	}
 
 	if newStatus != evt.Status {
-		return uc.eventRepo.UpdateStatus(ctx, eventID, newStatus)
+		if err := uc.eventRepo.UpdateStatus(ctx, eventID, newStatus); err != nil {
+			return err
+		}
+
+		previousStatus := evt.Status
+		evt.Status = newStatus
+		uc.publishStatusChange(ctx, evt, previousStatus, nil)
 	}
 
 	return nil
@@ -536,10 +904,8 @@ func (uc *Usecase) DeleteEventImage(ctx context.Context, eventID, imageID, userI
 // ToggleInterest toggles a user's interest in an event
 // Returns true if user is now interested, false if uninterested
 func (uc *Usecase) ToggleInterest(ctx context.Context, eventID, userID uuid.UUID) (bool, error) {
-	// Check if event exists
-	_, err := uc.eventRepo.GetByID(ctx, eventID)
-	if err != nil {
-		return false, ErrEventNotFound
+	if err := uc.CheckEventAccess(ctx, eventID, userID); err != nil {
+		return false, err
 	}
 
 	// Toggle interest
@@ -548,21 +914,17 @@ func (uc *Usecase) ToggleInterest(ctx context.Context, eventID, userID uuid.UUID
 
 // IsInterested checks if a user is interested in an event
 func (uc *Usecase) IsInterested(ctx context.Context, eventID, userID uuid.UUID) (bool, error) {
-	// Check if event exists
-	_, err := uc.eventRepo.GetByID(ctx, eventID)
-	if err != nil {
-		return false, ErrEventNotFound
+	if err := uc.CheckEventAccess(ctx, eventID, userID); err != nil {
+		return false, err
 	}
 
 	return uc.eventRepo.IsInterested(ctx, eventID, userID)
 }
 
 // GetInterestsCount gets the total number of interests for an event
-func (uc *Usecase) GetInterestsCount(ctx context.Context, eventID uuid.UUID) (int, error) {
-	// Check if event exists
-	_, err := uc.eventRepo.GetByID(ctx, eventID)
-	if err != nil {
-		return 0, ErrEventNotFound
+func (uc *Usecase) GetInterestsCount(ctx context.Context, eventID, viewerID uuid.UUID) (int, error) {
+	if err := uc.CheckEventAccess(ctx, eventID, viewerID); err != nil {
+		return 0, err
 	}
 
 	return uc.eventRepo.GetInterestsCount(ctx, eventID)
@@ -596,11 +958,9 @@ type AttendeeWithDetails struct {
 }
 
 // GetAttendeesWithDetails gets all attendees (both paid tickets and free event attendees)
-func (uc *Usecase) GetAttendeesWithDetails(ctx context.Context, eventID uuid.UUID, limit, offset int) ([]AttendeeWithDetails, int, error) {
-	// Check if event exists
-	_, err := uc.eventRepo.GetByID(ctx, eventID)
-	if err != nil {
-		return nil, 0, ErrEventNotFound
+func (uc *Usecase) GetAttendeesWithDetails(ctx context.Context, eventID, viewerID uuid.UUID, limit, offset int) ([]AttendeeWithDetails, int, error) {
+	if err := uc.CheckEventAccess(ctx, eventID, viewerID); err != nil {
+		return nil, 0, err
 	}
 
 	allAttendees := make([]AttendeeWithDetails, 0)
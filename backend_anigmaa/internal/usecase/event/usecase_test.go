@@ -0,0 +1,526 @@
+package event
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	ticketUsecase "github.com/anigmaa/backend/internal/usecase/ticket"
+	"github.com/google/uuid"
+)
+
+// updateEventRepo stores the events edited by UpdateEvent
+type updateEventRepo struct {
+	event.Repository
+	events map[uuid.UUID]*event.Event
+}
+
+func (r *updateEventRepo) GetByID(ctx context.Context, id uuid.UUID) (*event.Event, error) {
+	e, ok := r.events[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (r *updateEventRepo) Update(ctx context.Context, e *event.Event) error {
+	copied := *e
+	r.events[e.ID] = &copied
+	return nil
+}
+
+func (r *updateEventRepo) GetAttendeeIDs(ctx context.Context, eventID, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+// promotionTicketRepo records the events whose waitlist was promoted
+type promotionTicketRepo struct {
+	ticket.Repository
+	promoted []uuid.UUID
+}
+
+func (r *promotionTicketRepo) OfferWaitlistSpots(ctx context.Context, eventID uuid.UUID, claimWindow time.Duration) ([]ticket.WaitlistEntry, error) {
+	r.promoted = append(r.promoted, eventID)
+	return nil, nil
+}
+
+func TestUpdateEvent_PromotesWaitlist(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxAttendees *int
+		wantPromoted bool
+	}{
+		{"capacity raised", intPtr(20), true},
+		{"capacity lowered", intPtr(5), false},
+		{"capacity unchanged", intPtr(10), false},
+		{"other fields", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := &event.Event{
+				ID:           uuid.New(),
+				HostID:       uuid.New(),
+				Title:        "Jazz Senja",
+				MaxAttendees: 10,
+				StartTime:    time.Now().Add(24 * time.Hour),
+				EndTime:      time.Now().Add(27 * time.Hour),
+			}
+			repo := &updateEventRepo{events: map[uuid.UUID]*event.Event{evt.ID: evt}}
+			tickets := &promotionTicketRepo{}
+			uc := NewUsecase(repo, nil, nil, nil, ticketUsecase.NewUsecase(tickets, repo, nil, nil, nil, nil, nil, nil), nil)
+
+			title := "Jazz Senja Vol. 2"
+			req := &event.UpdateEventRequest{Title: &title, MaxAttendees: tt.maxAttendees}
+			if _, err := uc.UpdateEvent(context.Background(), evt.ID, evt.HostID, req); err != nil {
+				t.Fatalf("UpdateEvent() error = %v", err)
+			}
+
+			if promoted := len(tickets.promoted) > 0; promoted != tt.wantPromoted {
+				t.Errorf("waitlist promoted = %v, want %v", promoted, tt.wantPromoted)
+			}
+		})
+	}
+}
+
+// announceEventRepo reports when an edit is announced, i.e. its attendees looked up
+type announceEventRepo struct {
+	*updateEventRepo
+	announced chan uuid.UUID
+}
+
+func (r *announceEventRepo) GetAttendeeIDs(ctx context.Context, eventID, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	r.announced <- eventID
+	return nil, nil
+}
+
+func TestUpdateEvent_AnnouncesScheduleChanges(t *testing.T) {
+	description := "Now with a live band after sunset"
+	venue := "Taman Menteng"
+
+	tests := []struct {
+		name         string
+		req          func(evt *event.Event) *event.UpdateEventRequest
+		wantAnnounce bool
+	}{
+		{"description edited", func(evt *event.Event) *event.UpdateEventRequest {
+			return &event.UpdateEventRequest{Description: &description}
+		}, false},
+		{"capacity raised", func(evt *event.Event) *event.UpdateEventRequest {
+			return &event.UpdateEventRequest{MaxAttendees: intPtr(20)}
+		}, false},
+		{"same start time resent", func(evt *event.Event) *event.UpdateEventRequest {
+			return &event.UpdateEventRequest{StartTime: &evt.StartTime, EndTime: &evt.EndTime}
+		}, false},
+		{"start moved", func(evt *event.Event) *event.UpdateEventRequest {
+			start, end := evt.StartTime.Add(time.Hour), evt.EndTime.Add(time.Hour)
+			return &event.UpdateEventRequest{StartTime: &start, EndTime: &end}
+		}, true},
+		{"venue changed", func(evt *event.Event) *event.UpdateEventRequest {
+			return &event.UpdateEventRequest{LocationName: &venue}
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := &event.Event{
+				ID:           uuid.New(),
+				HostID:       uuid.New(),
+				Title:        "Jazz Senja",
+				LocationName: "Kopi Kenangan",
+				MaxAttendees: 10,
+				StartTime:    time.Now().Add(24 * time.Hour).Truncate(time.Minute),
+			}
+			evt.EndTime = evt.StartTime.Add(3 * time.Hour)
+			repo := &announceEventRepo{
+				updateEventRepo: &updateEventRepo{events: map[uuid.UUID]*event.Event{evt.ID: evt}},
+				announced:       make(chan uuid.UUID, 1),
+			}
+			uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+			if _, err := uc.UpdateEvent(context.Background(), evt.ID, evt.HostID, tt.req(evt)); err != nil {
+				t.Fatalf("UpdateEvent() error = %v", err)
+			}
+
+			wait := 100 * time.Millisecond
+			if tt.wantAnnounce {
+				wait = time.Second
+			}
+			select {
+			case <-repo.announced:
+				if !tt.wantAnnounce {
+					t.Error("edit was announced, want it kept quiet")
+				}
+			case <-time.After(wait):
+				if tt.wantAnnounce {
+					t.Error("edit wasn't announced")
+				}
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// joinRequestRepo holds the attendee statuses of one event and reviews join
+// requests the way ReviewJoinRequests does
+type joinRequestRepo struct {
+	event.Repository
+	evt       *event.Event
+	attendees map[uuid.UUID]event.AttendeeStatus
+}
+
+func (r *joinRequestRepo) GetByID(ctx context.Context, id uuid.UUID) (*event.Event, error) {
+	return r.evt, nil
+}
+
+func (r *joinRequestRepo) ReviewJoinRequests(ctx context.Context, eventID uuid.UUID, userIDs []uuid.UUID, status event.AttendeeStatus) ([]uuid.UUID, error) {
+	if status == event.AttendeeConfirmed && r.evt.MaxAttendees > 0 {
+		confirmed, pending := 0, 0
+		for _, s := range r.attendees {
+			if s == event.AttendeeConfirmed {
+				confirmed++
+			}
+		}
+		for _, userID := range userIDs {
+			if r.attendees[userID] == event.AttendeePending {
+				pending++
+			}
+		}
+		if confirmed+pending > r.evt.MaxAttendees {
+			return nil, event.ErrEventFull
+		}
+	}
+
+	reviewed := make([]uuid.UUID, 0)
+	for _, userID := range userIDs {
+		if r.attendees[userID] == event.AttendeePending {
+			r.attendees[userID] = status
+			reviewed = append(reviewed, userID)
+		}
+	}
+	return reviewed, nil
+}
+
+func TestReviewJoinRequests(t *testing.T) {
+	confirmed, pending, rejected, unknown := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	otherPending := uuid.New()
+
+	tests := []struct {
+		name         string
+		maxAttendees int
+		userIDs      []uuid.UUID
+		approve      bool
+		wantReviewed int
+		wantErr      error
+	}{
+		{"only pending requests take a spot", 3, []uuid.UUID{confirmed, pending, rejected, unknown}, true, 1, nil},
+		{"approvals that overbook are refused", 3, []uuid.UUID{pending, otherPending}, true, 0, ErrEventFull},
+		{"unlimited capacity", 0, []uuid.UUID{pending, otherPending}, true, 2, nil},
+		{"rejections ignore capacity", 2, []uuid.UUID{pending, otherPending}, false, 2, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostID := uuid.New()
+			repo := &joinRequestRepo{
+				evt: &event.Event{ID: uuid.New(), HostID: hostID, RequiresApproval: true, MaxAttendees: tt.maxAttendees},
+				attendees: map[uuid.UUID]event.AttendeeStatus{
+					uuid.New():   event.AttendeeConfirmed,
+					confirmed:    event.AttendeeConfirmed,
+					pending:      event.AttendeePending,
+					otherPending: event.AttendeePending,
+					rejected:     event.AttendeeRejected,
+				},
+			}
+			uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+			reviewed, err := uc.ReviewJoinRequests(context.Background(), repo.evt.ID, hostID, tt.userIDs, tt.approve)
+			if err != tt.wantErr {
+				t.Fatalf("ReviewJoinRequests() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(reviewed) != tt.wantReviewed {
+				t.Errorf("reviewed %d requests, want %d", len(reviewed), tt.wantReviewed)
+			}
+			if tt.wantErr != nil && repo.attendees[pending] != event.AttendeePending {
+				t.Errorf("pending request is %s, want it left pending", repo.attendees[pending])
+			}
+		})
+	}
+
+	repo := &joinRequestRepo{evt: &event.Event{ID: uuid.New(), HostID: uuid.New(), RequiresApproval: true}}
+	uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+	if _, err := uc.ReviewJoinRequests(context.Background(), repo.evt.ID, uuid.New(), []uuid.UUID{pending}, true); err != ErrUnauthorized {
+		t.Errorf("ReviewJoinRequests() by another user error = %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+// seriesEventRepo records the series-wide edits saved through UpdateSeriesEvent
+type seriesEventRepo struct {
+	*updateEventRepo
+	series      *event.EventSeries
+	otherIDs    []uuid.UUID
+	oversold    bool
+	updates     int
+	seriesEdits []seriesEdit
+}
+
+type seriesEdit struct {
+	e     event.Event
+	shift time.Duration
+	keep  []time.Time
+}
+
+func (r *seriesEventRepo) Update(ctx context.Context, e *event.Event) error {
+	r.updates++
+	return r.updateEventRepo.Update(ctx, e)
+}
+
+func (r *seriesEventRepo) GetSeries(ctx context.Context, id uuid.UUID) (*event.EventSeries, error) {
+	copied := *r.series
+	return &copied, nil
+}
+
+func (r *seriesEventRepo) UpdateSeries(ctx context.Context, s *event.EventSeries) error {
+	return nil
+}
+
+func (r *seriesEventRepo) GetSeriesOccurrences(ctx context.Context, seriesID uuid.UUID, from time.Time, limit int) ([]event.Event, error) {
+	occurrences := make([]event.Event, 0, len(r.events))
+	for _, e := range r.events {
+		occurrences = append(occurrences, *e)
+	}
+	return occurrences, nil
+}
+
+func (r *seriesEventRepo) GetSeriesTemplate(ctx context.Context, seriesID uuid.UUID) (*event.Event, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r *seriesEventRepo) UpdateSeriesEvent(ctx context.Context, e *event.Event, series *event.EventSeries, shift time.Duration, keep []time.Time) ([]uuid.UUID, error) {
+	if r.oversold {
+		return nil, event.ErrCapacityBelowSold
+	}
+	r.seriesEdits = append(r.seriesEdits, seriesEdit{e: *e, shift: shift, keep: keep})
+	return r.otherIDs, nil
+}
+
+// newSeriesEventRepo sets up the first occurrence of a weekly series with 8
+// tickets sold, and a second occurrence
+func newSeriesEventRepo() (*seriesEventRepo, *event.Event) {
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+	series := &event.EventSeries{ID: uuid.New(), RRule: "FREQ=WEEKLY", Timezone: "UTC", StartsAt: start}
+	evt := &event.Event{
+		ID:              uuid.New(),
+		HostID:          uuid.New(),
+		Title:           "Morning Run",
+		MaxAttendees:    10,
+		TicketsSold:     8,
+		StartTime:       start,
+		EndTime:         start.Add(time.Hour),
+		SeriesID:        &series.ID,
+		OccurrenceStart: &start,
+	}
+	next := *evt
+	next.ID = uuid.New()
+	next.TicketsSold = 0
+
+	repo := &seriesEventRepo{
+		updateEventRepo: &updateEventRepo{events: map[uuid.UUID]*event.Event{evt.ID: evt, next.ID: &next}},
+		series:          series,
+		otherIDs:        []uuid.UUID{next.ID},
+	}
+	return repo, evt
+}
+
+func TestUpdateEvent_Series(t *testing.T) {
+	repo, evt := newSeriesEventRepo()
+	uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+	series := event.ScopeSeries
+	start := evt.StartTime.Add(time.Hour)
+	end := evt.EndTime.Add(time.Hour)
+	req := &event.UpdateEventRequest{StartTime: &start, EndTime: &end, ApplyTo: &series}
+	if _, err := uc.UpdateEvent(context.Background(), evt.ID, evt.HostID, req); err != nil {
+		t.Fatalf("UpdateEvent() error = %v", err)
+	}
+
+	if repo.updates != 0 || len(repo.seriesEdits) != 1 {
+		t.Fatalf("saved %d single and %d series edits, want the series edit only", repo.updates, len(repo.seriesEdits))
+	}
+	edit := repo.seriesEdits[0]
+	if edit.shift != time.Hour || edit.e.SeriesOverride || edit.keep != nil {
+		t.Errorf("series edit = shift %s, override %v, keep %v; want a 1h shift of the series and nothing pruned", edit.shift, edit.e.SeriesOverride, edit.keep)
+	}
+
+	// A new recurrence prunes the slots that no longer fit it
+	req = &event.UpdateEventRequest{ApplyTo: &series, Recurrence: &event.RecurrenceRequest{RRule: "FREQ=WEEKLY;COUNT=1"}}
+	if _, err := uc.UpdateEvent(context.Background(), evt.ID, evt.HostID, req); err != nil {
+		t.Fatalf("UpdateEvent() with a recurrence error = %v", err)
+	}
+	if edit := repo.seriesEdits[1]; edit.keep == nil || len(edit.keep) > 1 {
+		t.Errorf("kept slots = %v, want at most the one occurrence of the new rule", edit.keep)
+	}
+}
+
+// deletedEventRepo loses the event between reading and saving it
+type deletedEventRepo struct {
+	*updateEventRepo
+}
+
+func (r *deletedEventRepo) Update(ctx context.Context, e *event.Event) error {
+	return sql.ErrNoRows
+}
+
+func TestUpdateEvent_DeletedMeanwhile(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+	evt := &event.Event{ID: uuid.New(), HostID: uuid.New(), Title: "Jazz Senja", MaxAttendees: 10, StartTime: start, EndTime: start.Add(3 * time.Hour)}
+	repo := &deletedEventRepo{&updateEventRepo{events: map[uuid.UUID]*event.Event{evt.ID: evt}}}
+	uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+	title := "Jazz Senja Vol. 2"
+	if _, err := uc.UpdateEvent(context.Background(), evt.ID, evt.HostID, &event.UpdateEventRequest{Title: &title}); err != ErrEventNotFound {
+		t.Errorf("UpdateEvent() error = %v, want %v", err, ErrEventNotFound)
+	}
+}
+
+func TestUpdateEvent_CapacityBelowSold(t *testing.T) {
+	occurrence, series := event.ScopeOccurrence, event.ScopeSeries
+
+	tests := []struct {
+		name         string
+		maxAttendees int
+		applyTo      *event.SeriesScope
+		oversold     bool
+		want         error
+	}{
+		{"occurrence below its tickets sold", 5, &occurrence, false, ErrCapacityBelowSold},
+		{"series below the tickets sold of this occurrence", 5, &series, false, ErrCapacityBelowSold},
+		{"series below the tickets sold of another occurrence", 9, &series, true, ErrCapacityBelowSold},
+		{"occurrence down to its tickets sold", 8, &occurrence, false, nil},
+		{"series down to the tickets sold", 9, &series, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, evt := newSeriesEventRepo()
+			repo.oversold = tt.oversold
+			uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+			req := &event.UpdateEventRequest{MaxAttendees: &tt.maxAttendees, ApplyTo: tt.applyTo}
+			if _, err := uc.UpdateEvent(context.Background(), evt.ID, evt.HostID, req); err != tt.want {
+				t.Fatalf("UpdateEvent() error = %v, want %v", err, tt.want)
+			}
+
+			saved := repo.events[evt.ID].MaxAttendees
+			if tt.want != nil && (saved != 10 || len(repo.seriesEdits) != 0) {
+				t.Errorf("capacity = %d with %d series edits, want nothing saved", saved, len(repo.seriesEdits))
+			}
+		})
+	}
+}
+
+// generationEventRepo records the occurrences a series generation run creates
+type generationEventRepo struct {
+	event.Repository
+	series    *event.EventSeries
+	template  *event.Event
+	copiedOf  *event.Event
+	generated []event.Event
+	updated   *event.EventSeries
+}
+
+func (r *generationEventRepo) GetSeriesToExtend(ctx context.Context, until time.Time) ([]event.EventSeries, error) {
+	return []event.EventSeries{*r.series}, nil
+}
+
+func (r *generationEventRepo) GetSeriesTemplate(ctx context.Context, seriesID uuid.UUID) (*event.Event, error) {
+	if r.template == nil {
+		return nil, sql.ErrNoRows
+	}
+	copied := *r.template
+	return &copied, nil
+}
+
+func (r *generationEventRepo) CreateOccurrences(ctx context.Context, seriesID uuid.UUID, template *event.Event, occurrences []event.Event) ([]event.Event, error) {
+	r.copiedOf = template
+	r.generated = occurrences
+	return occurrences, nil
+}
+
+func (r *generationEventRepo) GetImages(ctx context.Context, eventID uuid.UUID) ([]string, error) {
+	return nil, nil
+}
+
+func (r *generationEventRepo) UpdateSeries(ctx context.Context, s *event.EventSeries) error {
+	copied := *s
+	r.updated = &copied
+	return nil
+}
+
+func TestGenerateSeriesOccurrences(t *testing.T) {
+	start := time.Now().Add(-time.Hour).Truncate(time.Minute).UTC()
+	template := &event.Event{
+		ID:               uuid.New(),
+		HostID:           uuid.New(),
+		Title:            "Morning Run",
+		TicketingEnabled: true,
+		StartTime:        start,
+		EndTime:          start.Add(90 * time.Minute),
+		OccurrenceStart:  &start,
+		SeriesOverride:   true,
+	}
+
+	tests := []struct {
+		name     string
+		template *event.Event
+	}{
+		{"copies the latest occurrence", template},
+		{"nothing to copy", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &generationEventRepo{
+				series:   &event.EventSeries{ID: uuid.New(), RRule: "FREQ=WEEKLY", Timezone: "UTC", StartsAt: start},
+				template: tt.template,
+			}
+			uc := NewUsecase(repo, nil, nil, nil, nil, nil)
+
+			if err := uc.GenerateSeriesOccurrences(context.Background()); err != nil {
+				t.Fatalf("GenerateSeriesOccurrences() error = %v", err)
+			}
+			if repo.updated == nil || repo.updated.GeneratedUntil.Before(time.Now().Add(seriesHorizon-time.Minute)) {
+				t.Errorf("series saved = %+v, want its horizon moved forward", repo.updated)
+			}
+			if tt.template == nil {
+				if repo.generated != nil {
+					t.Errorf("generated %d occurrences, want none", len(repo.generated))
+				}
+				return
+			}
+
+			// The template goes along so its ticket tiers and promo codes are copied
+			if repo.copiedOf == nil || repo.copiedOf.ID != template.ID {
+				t.Fatalf("occurrences copied from %v, want the template %s", repo.copiedOf, template.ID)
+			}
+			if len(repo.generated) < 12 {
+				t.Fatalf("generated %d occurrences, want the next 90 days of a weekly series", len(repo.generated))
+			}
+			for _, o := range repo.generated {
+				if o.OccurrenceStart == nil || !o.StartTime.Equal(*o.OccurrenceStart) || o.EndTime.Sub(o.StartTime) != 90*time.Minute {
+					t.Errorf("occurrence = %s to %s (slot %v), want 90 minutes from its slot", o.StartTime, o.EndTime, o.OccurrenceStart)
+				}
+				if o.StartTime.Sub(start)%(7*24*time.Hour) != 0 || o.SeriesOverride || !o.TicketingEnabled {
+					t.Errorf("occurrence = %+v, want a weekly copy of the template that follows the series", o)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package invitation
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/invitation"
+	"github.com/anigmaa/backend/internal/domain/user"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEventNotFound        = errors.New("event not found")
+	ErrEventEnded           = errors.New("event has already ended")
+	ErrUnauthorized         = errors.New("unauthorized - not event host")
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+	ErrInvitationNotActive  = errors.New("invitation is already declined or revoked")
+	ErrInviteeNotFound      = errors.New("invited user not found")
+	ErrCannotInviteHost     = errors.New("the host can't be invited to their own event")
+	ErrInviteLinkNotFound   = errors.New("invite link not found")
+	ErrInviteLinkExpired    = errors.New("invite link has expired or been revoked")
+	ErrInviteLinkExhausted  = invitation.ErrInviteLinkExhausted // proxy to domain sentinel
+	ErrInvitationRevoked    = invitation.ErrInvitationRevoked   // proxy to domain sentinel
+	ErrInvalidInviteLink    = errors.New("invite link must expire in the future")
+)
+
+// Invite links expire after this long unless the host picks another expiry
+const defaultInviteLinkTTL = 7 * 24 * time.Hour
+
+// Usecase handles event invitations and invite links
+type Usecase struct {
+	invitationRepo invitation.Repository
+	eventRepo      event.Repository
+	userRepo       user.Repository
+	notificationUC *notificationUsecase.Usecase
+}
+
+// NewUsecase creates a new invitation usecase
+func NewUsecase(invitationRepo invitation.Repository, eventRepo event.Repository, userRepo user.Repository, notificationUC *notificationUsecase.Usecase) *Usecase {
+	return &Usecase{
+		invitationRepo: invitationRepo,
+		eventRepo:      eventRepo,
+		userRepo:       userRepo,
+		notificationUC: notificationUC,
+	}
+}
+
+// getHostedEvent gets an event and verifies that userID is its host
+func (uc *Usecase) getHostedEvent(ctx context.Context, eventID, userID uuid.UUID) (*event.Event, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	if evt.HostID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return evt, nil
+}
+
+// InviteUsers invites users to an event (host only) and notifies them.
+// Users already invited are skipped; returns the invitations that were sent.
+func (uc *Usecase) InviteUsers(ctx context.Context, eventID, hostID uuid.UUID, req *invitation.CreateInvitationsRequest) ([]invitation.Invitation, error) {
+	evt, err := uc.getHostedEvent(ctx, eventID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	if evt.IsCompleted() {
+		return nil, ErrEventEnded
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	invitations := make([]invitation.Invitation, 0, len(req.UserIDs))
+	for _, userID := range req.UserIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		if userID == hostID {
+			return nil, ErrCannotInviteHost
+		}
+		if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+			return nil, ErrInviteeNotFound
+		}
+
+		invitations = append(invitations, invitation.Invitation{
+			EventID:   eventID,
+			InviterID: hostID,
+			InviteeID: userID,
+		})
+	}
+
+	sent, err := uc.invitationRepo.CreateInvitations(ctx, invitations)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, inv := range sent {
+		uc.notificationUC.NotifyEventInvitation(ctx, hostID, inv.InviteeID, eventID, inv.ID, evt.Title)
+	}
+
+	return sent, nil
+}
+
+// GetEventInvitations gets the invitations of an event with their status (host only)
+func (uc *Usecase) GetEventInvitations(ctx context.Context, eventID, hostID uuid.UUID, status *invitation.Status, limit, offset int) ([]invitation.InvitationWithDetails, int, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	invitations, err := uc.invitationRepo.GetEventInvitations(ctx, eventID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.invitationRepo.CountEventInvitations(ctx, eventID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return invitations, total, nil
+}
+
+// RevokeInvitation revokes a pending or accepted invitation (host only).
+// Invitees who already joined or bought a ticket keep their place.
+func (uc *Usecase) RevokeInvitation(ctx context.Context, eventID, invitationID, hostID uuid.UUID) error {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return err
+	}
+
+	inv, err := uc.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil || inv.EventID != eventID {
+		return ErrInvitationNotFound
+	}
+
+	if err := uc.invitationRepo.Revoke(ctx, invitationID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvitationNotActive
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetMyInvitations gets the invitations a user received
+func (uc *Usecase) GetMyInvitations(ctx context.Context, userID uuid.UUID, status *invitation.Status, limit, offset int) ([]invitation.InvitationWithDetails, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	invitations, err := uc.invitationRepo.GetUserInvitations(ctx, userID, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.invitationRepo.CountUserInvitations(ctx, userID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return invitations, total, nil
+}
+
+// RespondToInvitation accepts or declines a pending invitation (invitee only).
+// Accepting grants access to the event and credits the inviter's
+// invites_successful_count (update_invites_count_trigger).
+func (uc *Usecase) RespondToInvitation(ctx context.Context, invitationID, userID uuid.UUID, accept bool) (*invitation.Invitation, error) {
+	inv, err := uc.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil || inv.InviteeID != userID {
+		return nil, ErrInvitationNotFound
+	}
+
+	status := invitation.StatusDeclined
+	if accept {
+		status = invitation.StatusAccepted
+	}
+
+	if err := uc.invitationRepo.Respond(ctx, invitationID, status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvitationNotPending
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	inv.Status = status
+	inv.RespondedAt = &now
+	return inv, nil
+}
+
+// CreateInviteLink creates an expiring, optionally usage-capped invite link (host only).
+// Links expire after 7 days by default and never outlive the event.
+func (uc *Usecase) CreateInviteLink(ctx context.Context, eventID, hostID uuid.UUID, req *invitation.CreateInviteLinkRequest) (*invitation.InviteLink, error) {
+	evt, err := uc.getHostedEvent(ctx, eventID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	if evt.IsCompleted() {
+		return nil, ErrEventEnded
+	}
+
+	expiresAt := time.Now().Add(defaultInviteLinkTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, ErrInvalidInviteLink
+		}
+		expiresAt = *req.ExpiresAt
+	}
+	if expiresAt.After(evt.EndTime) {
+		expiresAt = evt.EndTime
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &invitation.InviteLink{
+		EventID:   eventID,
+		CreatedBy: hostID,
+		Token:     token,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: expiresAt.UTC(),
+	}
+	if err := uc.invitationRepo.CreateLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// GetEventInviteLinks gets the invite links of an event with their usage (host only)
+func (uc *Usecase) GetEventInviteLinks(ctx context.Context, eventID, hostID uuid.UUID) ([]invitation.InviteLink, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, err
+	}
+
+	return uc.invitationRepo.GetEventLinks(ctx, eventID)
+}
+
+// RevokeInviteLink stops an invite link from being redeemed (host only).
+// Invitations already accepted through it stay valid.
+func (uc *Usecase) RevokeInviteLink(ctx context.Context, eventID, linkID, hostID uuid.UUID) error {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return err
+	}
+
+	link, err := uc.invitationRepo.GetLinkByID(ctx, linkID)
+	if err != nil || link.EventID != eventID {
+		return ErrInviteLinkNotFound
+	}
+
+	if err := uc.invitationRepo.RevokeLink(ctx, linkID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInviteLinkExpired
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RedeemInviteLink accepts an invitation through an invite link and returns the
+// ID of the event it grants access to. Users who already accepted an invitation
+// to the event don't use up the link, and users whose invitation the host revoked
+// can't get back in through one.
+func (uc *Usecase) RedeemInviteLink(ctx context.Context, token string, userID uuid.UUID) (uuid.UUID, error) {
+	link, err := uc.invitationRepo.GetLinkByToken(ctx, token)
+	if err != nil {
+		return uuid.Nil, ErrInviteLinkNotFound
+	}
+
+	evt, err := uc.eventRepo.GetByID(ctx, link.EventID)
+	if err != nil {
+		return uuid.Nil, ErrEventNotFound
+	}
+	if evt.HostID == userID {
+		return uuid.Nil, ErrCannotInviteHost
+	}
+
+	if !link.IsActiveAt(time.Now()) || evt.IsCompleted() {
+		return uuid.Nil, ErrInviteLinkExpired
+	}
+
+	access, err := uc.eventRepo.GetAccess(ctx, link.EventID, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if access.IsInvited {
+		return link.EventID, nil
+	}
+
+	if link.IsExhausted() {
+		return uuid.Nil, ErrInviteLinkExhausted
+	}
+
+	if _, err := uc.invitationRepo.RedeemLink(ctx, link, userID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return link.EventID, nil
+}
+
+// generateInviteToken generates a random, URL-safe invite link token
+func generateInviteToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
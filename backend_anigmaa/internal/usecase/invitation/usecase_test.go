@@ -0,0 +1,202 @@
+package invitation
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/invitation"
+	"github.com/google/uuid"
+)
+
+// fakeInvitationRepo holds one invite link and the invitations of its event,
+// keyed by invitee, and redeems the link the way RedeemLink does
+type fakeInvitationRepo struct {
+	invitation.Repository
+	link        *invitation.InviteLink
+	invitations map[uuid.UUID]*invitation.Invitation
+}
+
+func (r *fakeInvitationRepo) GetLinkByToken(ctx context.Context, token string) (*invitation.InviteLink, error) {
+	if token != r.link.Token {
+		return nil, sql.ErrNoRows
+	}
+	copied := *r.link
+	return &copied, nil
+}
+
+func (r *fakeInvitationRepo) RedeemLink(ctx context.Context, link *invitation.InviteLink, inviteeID uuid.UUID) (*invitation.Invitation, error) {
+	if r.link.IsExhausted() {
+		return nil, invitation.ErrInviteLinkExhausted
+	}
+	inv, ok := r.invitations[inviteeID]
+	if ok && inv.Status == invitation.StatusRevoked {
+		return nil, invitation.ErrInvitationRevoked
+	}
+	if !ok {
+		inv = &invitation.Invitation{ID: uuid.New(), EventID: link.EventID, InviterID: link.CreatedBy, InviteeID: inviteeID}
+		r.invitations[inviteeID] = inv
+	}
+	inv.Status = invitation.StatusAccepted
+	inv.InviteLinkID = &link.ID
+	r.link.UsedCount++
+	link.UsedCount++
+	return inv, nil
+}
+
+// fakeEventRepo serves one event; a user counts as invited once their
+// invitation was accepted
+type fakeEventRepo struct {
+	event.Repository
+	evt         *event.Event
+	invitations *fakeInvitationRepo
+}
+
+func (r *fakeEventRepo) GetByID(ctx context.Context, id uuid.UUID) (*event.Event, error) {
+	if id != r.evt.ID {
+		return nil, sql.ErrNoRows
+	}
+	copied := *r.evt
+	return &copied, nil
+}
+
+func (r *fakeEventRepo) GetAccess(ctx context.Context, eventID, userID uuid.UUID) (*event.Access, error) {
+	inv, ok := r.invitations.invitations[userID]
+	return &event.Access{
+		IsHost:    userID == r.evt.HostID,
+		IsInvited: ok && inv.Status == invitation.StatusAccepted,
+	}, nil
+}
+
+type redeemFixture struct {
+	uc     *Usecase
+	repo   *fakeInvitationRepo
+	evt    *event.Event
+	link   *invitation.InviteLink
+	host   uuid.UUID
+	friend uuid.UUID
+}
+
+// newRedeemFixture sets up a private event and a link of its host that may be used twice
+func newRedeemFixture() *redeemFixture {
+	host := uuid.New()
+	evt := &event.Event{
+		ID:        uuid.New(),
+		HostID:    host,
+		Privacy:   event.PrivacyPrivate,
+		Status:    event.StatusUpcoming,
+		StartTime: time.Now().Add(48 * time.Hour),
+		EndTime:   time.Now().Add(50 * time.Hour),
+	}
+	maxUses := 2
+	link := &invitation.InviteLink{
+		ID:        uuid.New(),
+		EventID:   evt.ID,
+		CreatedBy: host,
+		Token:     "token",
+		MaxUses:   &maxUses,
+		ExpiresAt: time.Now().Add(24 * time.Hour),
+	}
+	repo := &fakeInvitationRepo{link: link, invitations: make(map[uuid.UUID]*invitation.Invitation)}
+	uc := NewUsecase(repo, &fakeEventRepo{evt: evt, invitations: repo}, nil, nil)
+	return &redeemFixture{uc: uc, repo: repo, evt: evt, link: link, host: host, friend: uuid.New()}
+}
+
+func TestRedeemInviteLink(t *testing.T) {
+	f := newRedeemFixture()
+
+	eventID, err := f.uc.RedeemInviteLink(context.Background(), "token", f.friend)
+	if err != nil {
+		t.Fatalf("RedeemInviteLink() error = %v", err)
+	}
+	if eventID != f.evt.ID {
+		t.Errorf("RedeemInviteLink() = %s, want %s", eventID, f.evt.ID)
+	}
+	if inv := f.repo.invitations[f.friend]; inv == nil || inv.Status != invitation.StatusAccepted {
+		t.Errorf("invitation = %+v, want it accepted", inv)
+	}
+
+	// Redeeming again doesn't use up the link
+	if _, err := f.uc.RedeemInviteLink(context.Background(), "token", f.friend); err != nil {
+		t.Fatalf("RedeemInviteLink() again error = %v", err)
+	}
+	if f.link.UsedCount != 1 {
+		t.Errorf("link used %d times, want 1", f.link.UsedCount)
+	}
+}
+
+func TestRedeemInviteLink_Rejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(f *redeemFixture) (token string, userID uuid.UUID)
+		want  error
+	}{
+		{
+			name: "revoked invitation",
+			setup: func(f *redeemFixture) (string, uuid.UUID) {
+				f.repo.invitations[f.friend] = &invitation.Invitation{ID: uuid.New(), EventID: f.evt.ID, InviterID: f.host, InviteeID: f.friend, Status: invitation.StatusRevoked}
+				return "token", f.friend
+			},
+			want: ErrInvitationRevoked,
+		},
+		{
+			name: "unknown token",
+			setup: func(f *redeemFixture) (string, uuid.UUID) {
+				return "other", f.friend
+			},
+			want: ErrInviteLinkNotFound,
+		},
+		{
+			name: "host",
+			setup: func(f *redeemFixture) (string, uuid.UUID) {
+				return "token", f.host
+			},
+			want: ErrCannotInviteHost,
+		},
+		{
+			name: "expired link",
+			setup: func(f *redeemFixture) (string, uuid.UUID) {
+				f.link.ExpiresAt = time.Now().Add(-time.Minute)
+				return "token", f.friend
+			},
+			want: ErrInviteLinkExpired,
+		},
+		{
+			name: "revoked link",
+			setup: func(f *redeemFixture) (string, uuid.UUID) {
+				revokedAt := time.Now().Add(-time.Minute)
+				f.link.RevokedAt = &revokedAt
+				return "token", f.friend
+			},
+			want: ErrInviteLinkExpired,
+		},
+		{
+			name: "used up link",
+			setup: func(f *redeemFixture) (string, uuid.UUID) {
+				f.link.UsedCount = *f.link.MaxUses
+				return "token", f.friend
+			},
+			want: ErrInviteLinkExhausted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRedeemFixture()
+			token, userID := tt.setup(f)
+			usedBefore := f.link.UsedCount
+
+			if _, err := f.uc.RedeemInviteLink(context.Background(), token, userID); err != tt.want {
+				t.Errorf("RedeemInviteLink() error = %v, want %v", err, tt.want)
+			}
+			if f.link.UsedCount != usedBefore {
+				t.Errorf("link used %d times, want %d", f.link.UsedCount, usedBefore)
+			}
+			if inv, ok := f.repo.invitations[userID]; ok && inv.Status == invitation.StatusAccepted {
+				t.Errorf("invitation = %+v, want it not accepted", inv)
+			}
+		})
+	}
+}
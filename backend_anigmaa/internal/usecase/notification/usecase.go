@@ -0,0 +1,359 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/notification"
+	"github.com/anigmaa/backend/internal/infrastructure/realtime"
+	"github.com/anigmaa/backend/pkg/utils"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+)
+
+const (
+	// defaultPageSize is the page size of notification lists when none is asked for
+	defaultPageSize = 20
+	// maxPageSize caps the page size of notification lists
+	maxPageSize = 100
+)
+
+// Usecase handles notification business logic
+type Usecase struct {
+	notificationRepo notification.Repository
+	hub              *realtime.Hub
+}
+
+// NewUsecase creates a new notification usecase
+func NewUsecase(notificationRepo notification.Repository, hub *realtime.Hub) *Usecase {
+	return &Usecase{
+		notificationRepo: notificationRepo,
+		hub:              hub,
+	}
+}
+
+// NotifyInput describes a notification to deliver to a single recipient
+type NotifyInput struct {
+	RecipientID uuid.UUID
+	ActorID     *uuid.UUID
+	Type        notification.NotificationType
+	Title       string
+	Message     string
+	Link        string
+	Metadata    map[string]interface{}
+}
+
+// Notify stores a notification for a recipient.
+// Notifications where the actor is also the recipient are silently dropped.
+func (uc *Usecase) Notify(ctx context.Context, input *NotifyInput) (*notification.Notification, error) {
+	if input.ActorID != nil && *input.ActorID == input.RecipientID {
+		return nil, nil
+	}
+
+	n := &notification.Notification{
+		ID:        uuid.New(),
+		UserID:    input.RecipientID,
+		ActorID:   input.ActorID,
+		Type:      input.Type,
+		Title:     input.Title,
+		IsRead:    false,
+		CreatedAt: time.Now(),
+	}
+	if input.Message != "" {
+		n.Message = &input.Message
+	}
+	if input.Link != "" {
+		n.Link = &input.Link
+	}
+	if len(input.Metadata) > 0 {
+		metadata, err := json.Marshal(input.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		n.Metadata = metadata
+	}
+
+	if err := uc.notificationRepo.Create(ctx, n); err != nil {
+		return nil, err
+	}
+
+	// Push to the recipient's open streams; the stored notification is the source of truth
+	if err := uc.hub.Publish(ctx, n.UserID, realtime.EventNotification, n); err != nil {
+		log.Printf("[NotificationUsecase] Failed to publish notification %s: %v", n.ID, err)
+	}
+
+	return n, nil
+}
+
+// notify is the fire-and-forget variant used by the emitters below.
+// A failed notification must never fail the action that triggered it.
+func (uc *Usecase) notify(ctx context.Context, input *NotifyInput) {
+	if uc == nil {
+		return
+	}
+	if _, err := uc.Notify(ctx, input); err != nil {
+		log.Printf("[NotificationUsecase] Failed to create %s notification for user %s: %v", input.Type, input.RecipientID, err)
+	}
+}
+
+// NotifyPostLiked notifies a post author that someone liked their post
+func (uc *Usecase) NotifyPostLiked(ctx context.Context, actorID, authorID, postID uuid.UUID) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: authorID,
+		ActorID:     &actorID,
+		Type:        notification.TypeLikePost,
+		Title:       "New like",
+		Message:     "liked your post",
+		Link:        fmt.Sprintf("/posts/%s", postID),
+		Metadata:    map[string]interface{}{"post_id": postID},
+	})
+}
+
+// NotifyPostCommented notifies a post author that someone commented on their post
+func (uc *Usecase) NotifyPostCommented(ctx context.Context, actorID, authorID, postID, commentID uuid.UUID) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: authorID,
+		ActorID:     &actorID,
+		Type:        notification.TypeCommentPost,
+		Title:       "New comment",
+		Message:     "commented on your post",
+		Link:        fmt.Sprintf("/posts/%s", postID),
+		Metadata:    map[string]interface{}{"post_id": postID, "comment_id": commentID},
+	})
+}
+
+// NotifyMentioned notifies a user that they were mentioned in a post or comment.
+// commentID is uuid.Nil when the mention is in the post body itself.
+func (uc *Usecase) NotifyMentioned(ctx context.Context, actorID, mentionedID, postID, commentID uuid.UUID) {
+	metadata := map[string]interface{}{"post_id": postID}
+	message := "mentioned you in a post"
+	if commentID != uuid.Nil {
+		metadata["comment_id"] = commentID
+		message = "mentioned you in a comment"
+	}
+
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: mentionedID,
+		ActorID:     &actorID,
+		Type:        notification.TypeMention,
+		Title:       "New mention",
+		Message:     message,
+		Link:        fmt.Sprintf("/posts/%s", postID),
+		Metadata:    metadata,
+	})
+}
+
+// NotifyFollowed notifies a user that someone started following them
+func (uc *Usecase) NotifyFollowed(ctx context.Context, followerID, followingID uuid.UUID) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: followingID,
+		ActorID:     &followerID,
+		Type:        notification.TypeFollow,
+		Title:       "New follower",
+		Message:     "started following you",
+		Link:        fmt.Sprintf("/users/%s", followerID),
+		Metadata:    map[string]interface{}{"user_id": followerID},
+	})
+}
+
+// NotifyEventUpdated notifies attendees that the host changed an event
+func (uc *Usecase) NotifyEventUpdated(ctx context.Context, hostID, eventID uuid.UUID, eventTitle string, attendeeIDs []uuid.UUID) {
+	for _, attendeeID := range attendeeIDs {
+		uc.notify(ctx, &NotifyInput{
+			RecipientID: attendeeID,
+			ActorID:     &hostID,
+			Type:        notification.TypeEventUpdate,
+			Title:       "Event updated",
+			Message:     fmt.Sprintf("%s has been updated", eventTitle),
+			Link:        fmt.Sprintf("/events/%s", eventID),
+			Metadata:    map[string]interface{}{"event_id": eventID},
+		})
+	}
+}
+
+// NotifyEventInvitation notifies a user that a host invited them to an event
+func (uc *Usecase) NotifyEventInvitation(ctx context.Context, hostID, inviteeID, eventID, invitationID uuid.UUID, eventTitle string) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: inviteeID,
+		ActorID:     &hostID,
+		Type:        notification.TypeEventInvitation,
+		Title:       "Event invitation",
+		Message:     fmt.Sprintf("invited you to %s", eventTitle),
+		Link:        fmt.Sprintf("/events/%s", eventID),
+		Metadata:    map[string]interface{}{"event_id": eventID, "invitation_id": invitationID},
+	})
+}
+
+// NotifyWaitlistSpotOffered tells a waitlisted user that a spot opened up for them until expiresAt
+func (uc *Usecase) NotifyWaitlistSpotOffered(ctx context.Context, userID, eventID uuid.UUID, eventTitle string, expiresAt time.Time) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: userID,
+		Type:        notification.TypeEventUpdate,
+		Title:       "A spot opened up",
+		Message:     fmt.Sprintf("Get your ticket for %s before the spot passes to the next person in line", eventTitle),
+		Link:        fmt.Sprintf("/events/%s", eventID),
+		Metadata:    map[string]interface{}{"event_id": eventID, "offer_expires_at": expiresAt},
+	})
+}
+
+// NotifyEventReminder reminds a ticket holder or attendee that an event starts soon
+func (uc *Usecase) NotifyEventReminder(ctx context.Context, userID, eventID uuid.UUID, eventTitle string, startTime time.Time) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: userID,
+		Type:        notification.TypeEventReminder,
+		Title:       "Event reminder",
+		Message:     fmt.Sprintf("%s starts %s", eventTitle, startsIn(time.Until(startTime))),
+		Link:        fmt.Sprintf("/events/%s", eventID),
+		Metadata:    map[string]interface{}{"event_id": eventID, "start_time": startTime},
+	})
+}
+
+// startsIn describes how far away a start time is, rounded to the hour once it's an hour or more
+func startsIn(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d == time.Minute:
+		return "in 1 minute"
+	case d < time.Hour:
+		return fmt.Sprintf("in %d minutes", int(d.Minutes()))
+	case d < 90*time.Minute:
+		return "in 1 hour"
+	default:
+		return fmt.Sprintf("in %d hours", int(d.Round(time.Hour).Hours()))
+	}
+}
+
+// NotifyJoinRequest notifies a host that a user asked to join their event
+func (uc *Usecase) NotifyJoinRequest(ctx context.Context, requesterID, hostID, eventID uuid.UUID, eventTitle string) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: hostID,
+		ActorID:     &requesterID,
+		Type:        notification.TypeEventUpdate,
+		Title:       "New join request",
+		Message:     fmt.Sprintf("asked to join %s", eventTitle),
+		Link:        fmt.Sprintf("/events/%s/join-requests", eventID),
+		Metadata:    map[string]interface{}{"event_id": eventID, "user_id": requesterID},
+	})
+}
+
+// NotifyJoinRequestReviewed tells a user whether the host approved their join request
+func (uc *Usecase) NotifyJoinRequestReviewed(ctx context.Context, hostID, userID, eventID uuid.UUID, eventTitle string, approved bool) {
+	title, message := "Join request declined", fmt.Sprintf("Your request to join %s was declined", eventTitle)
+	if approved {
+		title, message = "Join request approved", fmt.Sprintf("You're in! Your request to join %s was approved", eventTitle)
+	}
+
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: userID,
+		ActorID:     &hostID,
+		Type:        notification.TypeEventUpdate,
+		Title:       title,
+		Message:     message,
+		Link:        fmt.Sprintf("/events/%s", eventID),
+		Metadata:    map[string]interface{}{"event_id": eventID, "approved": approved},
+	})
+}
+
+// NotifyStaffRoleGranted tells a user that a host made them staff of an event
+func (uc *Usecase) NotifyStaffRoleGranted(ctx context.Context, hostID, userID, eventID uuid.UUID, eventTitle, role string) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: userID,
+		ActorID:     &hostID,
+		Type:        notification.TypeEventUpdate,
+		Title:       "You're on the team",
+		Message:     fmt.Sprintf("made you %s of %s", role, eventTitle),
+		Link:        fmt.Sprintf("/events/%s", eventID),
+		Metadata:    map[string]interface{}{"event_id": eventID},
+	})
+}
+
+// NotifyCommunityJoined notifies a community owner that a new member joined
+func (uc *Usecase) NotifyCommunityJoined(ctx context.Context, memberID, ownerID, communityID uuid.UUID, communityName string) {
+	uc.notify(ctx, &NotifyInput{
+		RecipientID: ownerID,
+		ActorID:     &memberID,
+		Type:        notification.TypeSystem,
+		Title:       "New community member",
+		Message:     fmt.Sprintf("joined %s", communityName),
+		Link:        fmt.Sprintf("/communities/%s", communityID),
+		Metadata:    map[string]interface{}{"community_id": communityID},
+	})
+}
+
+// PageSize returns the page size GetNotifications uses for the requested limit
+func PageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// GetNotifications lists a user's notifications newest first, PageSize(limit) at a time.
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) GetNotifications(ctx context.Context, userID uuid.UUID, cursor string, limit int, unreadOnly bool) ([]notification.NotificationWithActor, string, error) {
+	limit = PageSize(limit)
+
+	position, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+
+	// Fetch one extra row to know whether another page exists
+	filter := &notification.NotificationFilter{
+		UnreadOnly: unreadOnly,
+		Limit:      limit + 1,
+	}
+	if position != nil {
+		filter.BeforeCreatedAt = &position.CreatedAt
+		filter.BeforeID = &position.ID
+	}
+
+	notifications, err := uc.notificationRepo.ListByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		last := notifications[len(notifications)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return notifications, nextCursor, nil
+}
+
+// MarkAsRead marks a single notification as read
+func (uc *Usecase) MarkAsRead(ctx context.Context, notificationID, userID uuid.UUID) error {
+	if err := uc.notificationRepo.MarkAsRead(ctx, notificationID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotificationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// MarkAllAsRead marks all of a user's notifications as read
+func (uc *Usecase) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return uc.notificationRepo.MarkAllAsRead(ctx, userID)
+}
+
+// GetUnreadCount returns the number of unread notifications for a user
+func (uc *Usecase) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return uc.notificationRepo.CountUnread(ctx, userID)
+}
@@ -0,0 +1,264 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/notification"
+	"github.com/google/uuid"
+)
+
+// fakeNotificationRepo stores notifications in memory and lists them newest
+// first with the same keyset as the Postgres repository
+type fakeNotificationRepo struct {
+	notification.Repository
+	notifications []notification.Notification
+}
+
+func (r *fakeNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
+	r.notifications = append(r.notifications, *n)
+	return nil
+}
+
+func (r *fakeNotificationRepo) ListByUser(ctx context.Context, userID uuid.UUID, filter *notification.NotificationFilter) ([]notification.NotificationWithActor, error) {
+	var list []notification.NotificationWithActor
+	for _, n := range r.notifications {
+		if n.UserID != userID || (filter.UnreadOnly && n.IsRead) {
+			continue
+		}
+		if filter.BeforeCreatedAt != nil && !older(n, *filter.BeforeCreatedAt, *filter.BeforeID) {
+			continue
+		}
+		list = append(list, notification.NotificationWithActor{Notification: n})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return older(list[j].Notification, list[i].CreatedAt, list[i].ID)
+	})
+	if len(list) > filter.Limit {
+		list = list[:filter.Limit]
+	}
+	return list, nil
+}
+
+func (r *fakeNotificationRepo) MarkAsRead(ctx context.Context, id, userID uuid.UUID) error {
+	for i := range r.notifications {
+		if r.notifications[i].ID == id && r.notifications[i].UserID == userID {
+			r.notifications[i].IsRead = true
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (r *fakeNotificationRepo) MarkAllAsRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	marked := 0
+	for i := range r.notifications {
+		if r.notifications[i].UserID == userID && !r.notifications[i].IsRead {
+			r.notifications[i].IsRead = true
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (r *fakeNotificationRepo) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	unread := 0
+	for _, n := range r.notifications {
+		if n.UserID == userID && !n.IsRead {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+// older reports whether n comes strictly after the (createdAt, id) position in a newest-first list
+func older(n notification.Notification, createdAt time.Time, id uuid.UUID) bool {
+	if !n.CreatedAt.Equal(createdAt) {
+		return n.CreatedAt.Before(createdAt)
+	}
+	return bytes.Compare(n.ID[:], id[:]) < 0
+}
+
+func TestEmitters(t *testing.T) {
+	actor, recipient := uuid.New(), uuid.New()
+	postID, commentID, eventID := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		emit     func(uc *Usecase, actorID uuid.UUID)
+		wantType notification.NotificationType
+	}{
+		{"post liked", func(uc *Usecase, actorID uuid.UUID) {
+			uc.NotifyPostLiked(context.Background(), actorID, recipient, postID)
+		}, notification.TypeLikePost},
+		{"post commented", func(uc *Usecase, actorID uuid.UUID) {
+			uc.NotifyPostCommented(context.Background(), actorID, recipient, postID, commentID)
+		}, notification.TypeCommentPost},
+		{"followed", func(uc *Usecase, actorID uuid.UUID) {
+			uc.NotifyFollowed(context.Background(), actorID, recipient)
+		}, notification.TypeFollow},
+		{"mentioned", func(uc *Usecase, actorID uuid.UUID) {
+			uc.NotifyMentioned(context.Background(), actorID, recipient, postID, commentID)
+		}, notification.TypeMention},
+		{"event updated", func(uc *Usecase, actorID uuid.UUID) {
+			uc.NotifyEventUpdated(context.Background(), actorID, eventID, "Jazz Senja", []uuid.UUID{recipient})
+		}, notification.TypeEventUpdate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeNotificationRepo{}
+			uc := NewUsecase(repo, nil)
+
+			tt.emit(uc, actor)
+			if len(repo.notifications) != 1 {
+				t.Fatalf("created %d notifications, want 1", len(repo.notifications))
+			}
+			n := repo.notifications[0]
+			if n.UserID != recipient || n.ActorID == nil || *n.ActorID != actor || n.Type != tt.wantType || n.IsRead {
+				t.Errorf("notification = %s to %s by %v (read %v), want unread %s to %s by %s", n.Type, n.UserID, n.ActorID, n.IsRead, tt.wantType, recipient, actor)
+			}
+
+			// Acting on your own post, profile or event doesn't notify you
+			tt.emit(uc, recipient)
+			if len(repo.notifications) != 1 {
+				t.Errorf("self-action created %d more notifications, want none", len(repo.notifications)-1)
+			}
+		})
+	}
+}
+
+func TestReadState(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeNotificationRepo{}
+	uc := NewUsecase(repo, nil)
+	userID, other := uuid.New(), uuid.New()
+	for i := 0; i < 3; i++ {
+		uc.NotifyFollowed(ctx, uuid.New(), userID)
+	}
+	uc.NotifyFollowed(ctx, uuid.New(), other)
+	first := repo.notifications[0].ID
+
+	if n, _ := uc.GetUnreadCount(ctx, userID); n != 3 {
+		t.Fatalf("unread = %d, want 3", n)
+	}
+
+	if err := uc.MarkAsRead(ctx, first, other); err != ErrNotificationNotFound {
+		t.Errorf("MarkAsRead() of another user's notification error = %v, want %v", err, ErrNotificationNotFound)
+	}
+	if err := uc.MarkAsRead(ctx, first, userID); err != nil {
+		t.Fatalf("MarkAsRead() error = %v", err)
+	}
+	if n, _ := uc.GetUnreadCount(ctx, userID); n != 2 {
+		t.Errorf("unread after marking one read = %d, want 2", n)
+	}
+	unread, _, err := uc.GetNotifications(ctx, userID, "", 0, true)
+	if err != nil || len(unread) != 2 {
+		t.Errorf("GetNotifications(unread only) = %d notifications, %v; want 2", len(unread), err)
+	}
+
+	if marked, err := uc.MarkAllAsRead(ctx, userID); err != nil || marked != 2 {
+		t.Errorf("MarkAllAsRead() = %d, %v; want 2", marked, err)
+	}
+	if n, _ := uc.GetUnreadCount(ctx, userID); n != 0 {
+		t.Errorf("unread after marking all read = %d, want 0", n)
+	}
+	if n, _ := uc.GetUnreadCount(ctx, other); n != 1 {
+		t.Errorf("another user's unread = %d, want 1", n)
+	}
+}
+
+func TestGetNotifications_Paging(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeNotificationRepo{}
+	uc := NewUsecase(repo, nil)
+	userID := uuid.New()
+
+	// Two notifications share a timestamp, so the cursor has to break the tie by ID
+	start := time.Now().Add(-time.Hour)
+	for _, offset := range []time.Duration{0, time.Minute, time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		repo.Create(ctx, &notification.Notification{ID: uuid.New(), UserID: userID, Type: notification.TypeFollow, Title: "New follower", CreatedAt: start.Add(offset)}) //nolint:errcheck
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var last time.Time
+	cursor, pages := "", 0
+	for {
+		page, next, err := uc.GetNotifications(ctx, userID, cursor, 2, false)
+		if err != nil {
+			t.Fatalf("GetNotifications() error = %v", err)
+		}
+		pages++
+		for _, n := range page {
+			if seen[n.ID] {
+				t.Errorf("notification %s listed twice", n.ID)
+			}
+			if !last.IsZero() && n.CreatedAt.After(last) {
+				t.Errorf("notification %s is newer than the one before it", n.ID)
+			}
+			seen[n.ID] = true
+			last = n.CreatedAt
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != 5 || pages != 3 {
+		t.Errorf("listed %d notifications in %d pages, want 5 in 3", len(seen), pages)
+	}
+
+	if _, _, err := uc.GetNotifications(ctx, userID, "not-a-cursor", 2, false); err != ErrInvalidCursor {
+		t.Errorf("GetNotifications() with a bad cursor error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		limit, want int
+	}{
+		{0, defaultPageSize},
+		{-5, defaultPageSize},
+		{1, 1},
+		{50, 50},
+		{maxPageSize, maxPageSize},
+		{1000, maxPageSize},
+	}
+
+	for _, tt := range tests {
+		if got := PageSize(tt.limit); got != tt.want {
+			t.Errorf("PageSize(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestStartsIn(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Minute, "now"},
+		{20 * time.Second, "now"},
+		{40 * time.Second, "in 1 minute"},
+		{15 * time.Minute, "in 15 minutes"},
+		{59*time.Minute + 20*time.Second, "in 59 minutes"},
+		{59*time.Minute + 40*time.Second, "in 1 hour"},
+		{time.Hour, "in 1 hour"},
+		{89 * time.Minute, "in 1 hour"},
+		{90 * time.Minute, "in 2 hours"},
+		{23*time.Hour + 50*time.Minute, "in 24 hours"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.d.String(), func(t *testing.T) {
+			if got := startsIn(tt.d); got != tt.want {
+				t.Errorf("startsIn(%s) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
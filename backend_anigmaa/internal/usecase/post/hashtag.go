@@ -0,0 +1,239 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/anigmaa/backend/pkg/utils"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidHashtag = errors.New("invalid hashtag")
+
+const (
+	maxHashtagsPerPost = 30
+	maxMentionsPerPost = 20
+	maxHashtagLength   = 100
+
+	defaultTrendingWindow = 24 * time.Hour
+	maxTrendingWindow     = 7 * 24 * time.Hour
+)
+
+var (
+	// hashtagPattern matches #hashtags at the start of a word, so URL fragments
+	// (example.com/#top) and HTML entities (&#39;) are skipped
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/])#([\p{L}\p{N}_]+)`)
+	// mentionPattern matches @username mentions (same charset as users_username_format)
+	// at the start of a word, so email addresses are skipped
+	mentionPattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_.-])@([a-zA-Z0-9_-]{3,50})`)
+)
+
+// normalizeHashtag lower-cases a hashtag and strips its leading '#'.
+// Tags must be letters, digits and underscores, with at least one letter.
+func normalizeHashtag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" || len(tag) > maxHashtagLength {
+		return "", false
+	}
+
+	hasLetter := false
+	for _, r := range tag {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r) || r == '_':
+		default:
+			return "", false
+		}
+	}
+
+	return tag, hasLetter
+}
+
+// extractHashtags returns the normalized hashtags of content plus any the client
+// sent explicitly, deduplicated in order of appearance
+func extractHashtags(content string, explicit []string) []string {
+	candidates := make([]string, 0)
+	for _, match := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		candidates = append(candidates, match[1])
+	}
+	candidates = append(candidates, explicit...)
+
+	seen := make(map[string]bool)
+	hashtags := make([]string, 0)
+	for _, candidate := range candidates {
+		tag, ok := normalizeHashtag(candidate)
+		if !ok || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		hashtags = append(hashtags, tag)
+		if len(hashtags) == maxHashtagsPerPost {
+			break
+		}
+	}
+
+	return hashtags
+}
+
+// extractMentions returns the @usernames mentioned in content plus any the client
+// sent explicitly, deduplicated case-insensitively in order of appearance
+func extractMentions(content string, explicit []string) []string {
+	candidates := make([]string, 0)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		candidates = append(candidates, match[1])
+	}
+	candidates = append(candidates, explicit...)
+
+	seen := make(map[string]bool)
+	usernames := make([]string, 0)
+	for _, candidate := range candidates {
+		username := strings.TrimPrefix(strings.TrimSpace(candidate), "@")
+		key := strings.ToLower(username)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentionsPerPost {
+			break
+		}
+	}
+
+	return usernames
+}
+
+// resolveMentions resolves the users mentioned in content to their IDs.
+// Usernames that don't exist are ignored.
+func (uc *Usecase) resolveMentions(ctx context.Context, content string, explicit []string) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	userIDs := make([]uuid.UUID, 0)
+	for _, username := range extractMentions(content, explicit) {
+		mentioned, err := uc.userRepo.GetByUsername(ctx, username)
+		if err != nil || mentioned == nil || seen[mentioned.ID] {
+			continue
+		}
+		seen[mentioned.ID] = true
+		userIDs = append(userIDs, mentioned.ID)
+	}
+	return userIDs
+}
+
+// notifyMentioned notifies each mentioned user except the actor.
+// commentID is uuid.Nil when the mention is in the post body itself.
+func (uc *Usecase) notifyMentioned(ctx context.Context, actorID, postID, commentID uuid.UUID, userIDs []uuid.UUID) {
+	if uc.notificationUC == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		if userID == actorID {
+			continue
+		}
+		uc.notificationUC.NotifyMentioned(ctx, actorID, userID, postID, commentID)
+	}
+}
+
+// saveTags stores the hashtags and mentions of a post and notifies the users it
+// mentions for the first time. previous holds the users the post already
+// mentioned before an edit.
+func (uc *Usecase) saveTags(ctx context.Context, p *post.Post, explicitHashtags, explicitMentions []string, previous []uuid.UUID) {
+	hashtags := extractHashtags(p.Content, explicitHashtags)
+	mentionedIDs := uc.resolveMentions(ctx, p.Content, explicitMentions)
+
+	if err := uc.postRepo.SetTags(ctx, p.ID, hashtags, mentionedIDs); err != nil {
+		// Log error but don't fail the post
+		log.Printf("[PostUsecase] Failed to save tags of post %s: %v", p.ID, err)
+	}
+
+	// Mentioned users can't see a private post, so they aren't told about it
+	if p.Visibility == post.VisibilityPrivate {
+		return
+	}
+
+	notified := make(map[uuid.UUID]bool, len(previous))
+	for _, userID := range previous {
+		notified[userID] = true
+	}
+	newIDs := make([]uuid.UUID, 0, len(mentionedIDs))
+	for _, userID := range mentionedIDs {
+		if !notified[userID] {
+			newIDs = append(newIDs, userID)
+		}
+	}
+
+	uc.notifyMentioned(ctx, p.AuthorID, p.ID, uuid.Nil, newIDs)
+}
+
+// GetHashtagPosts gets the posts using a hashtag that the viewer may see, newest first.
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) GetHashtagPosts(ctx context.Context, tag string, viewerID uuid.UUID, cursor string, limit, offset int) ([]post.PostWithDetails, string, error) {
+	tag, ok := normalizeHashtag(tag)
+	if !ok {
+		return nil, "", ErrInvalidHashtag
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	before, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	if before != nil || offset < 0 {
+		offset = 0
+	}
+
+	// Fetch one extra post to know whether another page exists
+	posts, err := uc.postRepo.GetHashtagPosts(ctx, tag, viewerID, before, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[len(posts)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return posts, nextCursor, nil
+}
+
+// CountHashtagPosts counts the posts using a hashtag that the viewer may see
+func (uc *Usecase) CountHashtagPosts(ctx context.Context, tag string, viewerID uuid.UUID) (int, error) {
+	tag, ok := normalizeHashtag(tag)
+	if !ok {
+		return 0, ErrInvalidHashtag
+	}
+	return uc.postRepo.CountHashtagPosts(ctx, tag, viewerID)
+}
+
+// GetTrendingHashtags ranks the hashtags of public posts created within the window
+// (default 24 hours, at most 7 days)
+func (uc *Usecase) GetTrendingHashtags(ctx context.Context, window time.Duration, limit int) ([]post.TrendingHashtag, error) {
+	if window <= 0 {
+		window = defaultTrendingWindow
+	}
+	if window > maxTrendingWindow {
+		window = maxTrendingWindow
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	return uc.postRepo.GetTrendingHashtags(ctx, time.Now().Add(-window), limit)
+}
@@ -0,0 +1,194 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anigmaa/backend/internal/domain/notification"
+	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/anigmaa/backend/internal/domain/user"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
+	"github.com/google/uuid"
+)
+
+// fakeTagPostRepo records the tags stored for each post
+type fakeTagPostRepo struct {
+	*fakePostRepo
+	hashtags map[uuid.UUID][]string
+	mentions map[uuid.UUID][]uuid.UUID
+}
+
+func (r *fakeTagPostRepo) SetTags(ctx context.Context, postID uuid.UUID, hashtags []string, mentionedIDs []uuid.UUID) error {
+	r.hashtags[postID] = hashtags
+	r.mentions[postID] = mentionedIDs
+	return nil
+}
+
+// fakeUsernameRepo resolves usernames case-insensitively, like the users table
+type fakeUsernameRepo struct {
+	user.Repository
+	users []*user.User
+}
+
+func (r *fakeUsernameRepo) GetByUsername(ctx context.Context, username string) (*user.User, error) {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Username, username) {
+			return u, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+// fakeNotificationRepo records the users notified
+type fakeNotificationRepo struct {
+	notification.Repository
+	recipients []uuid.UUID
+}
+
+func (r *fakeNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
+	r.recipients = append(r.recipients, n.UserID)
+	return nil
+}
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		explicit []string
+		want     []string
+	}{
+		{"lower-cased and deduplicated", "#Jakarta meetup #jakarta #KopiPagi", nil, []string{"jakarta", "kopipagi"}},
+		{"unicode letters", "Selamat #ramadhan2025 dan #café", nil, []string{"ramadhan2025", "café"}},
+		{"digits only skipped", "Room #42 on floor #3", nil, []string{}},
+		{"url fragments and entities skipped", "see example.com/#top or &#39;quoted&#39;", nil, []string{}},
+		{"explicit tags merged", "#music tonight", []string{"#Music", "Live", "not valid!"}, []string{"music", "live"}},
+		{"after punctuation", "(#jazz),#blues", nil, []string{"jazz", "blues"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractHashtags(tt.content, tt.explicit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractHashtags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	many := ""
+	for i := 0; i < maxHashtagsPerPost+5; i++ {
+		many += " #tag" + string(rune('a'+i%26)) + strings.Repeat("x", i/26)
+	}
+	if got := extractHashtags(many, nil); len(got) != maxHashtagsPerPost {
+		t.Errorf("extractHashtags() kept %d tags, want %d", len(got), maxHashtagsPerPost)
+	}
+}
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		explicit []string
+		want     []string
+	}{
+		{"deduplicated case-insensitively", "hi @Budi and @budi, cc @sari_w", nil, []string{"Budi", "sari_w"}},
+		{"email addresses skipped", "mail budi@example.com or @andi", nil, []string{"andi"}},
+		{"too short skipped", "@ab @abc", nil, []string{"abc"}},
+		{"explicit mentions merged", "@andi", []string{"@Andi", "sari"}, []string{"andi", "sari"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractMentions(tt.content, tt.explicit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractMentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveTags(t *testing.T) {
+	author := &user.User{ID: uuid.New(), Username: "author"}
+	budi := &user.User{ID: uuid.New(), Username: "budi"}
+	sari := &user.User{ID: uuid.New(), Username: "sari"}
+
+	tests := []struct {
+		name         string
+		content      string
+		visibility   post.PostVisibility
+		previous     []uuid.UUID
+		wantMentions []uuid.UUID
+		wantNotified []uuid.UUID
+	}{
+		{
+			name:         "new post",
+			content:      "#Ngopi with @Budi and @sari, unknown @nobody",
+			visibility:   post.VisibilityPublic,
+			wantMentions: []uuid.UUID{budi.ID, sari.ID},
+			wantNotified: []uuid.UUID{budi.ID, sari.ID},
+		},
+		{
+			name:         "edit only notifies new mentions",
+			content:      "#ngopi with @budi and @sari",
+			visibility:   post.VisibilityPublic,
+			previous:     []uuid.UUID{budi.ID},
+			wantMentions: []uuid.UUID{budi.ID, sari.ID},
+			wantNotified: []uuid.UUID{sari.ID},
+		},
+		{
+			name:         "author mentioning themselves",
+			content:      "#ngopi note to @author",
+			visibility:   post.VisibilityPublic,
+			wantMentions: []uuid.UUID{author.ID},
+		},
+		{
+			name:         "private post stores mentions without notifying",
+			content:      "#ngopi with @budi",
+			visibility:   post.VisibilityPrivate,
+			wantMentions: []uuid.UUID{budi.ID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTagPostRepo{
+				fakePostRepo: &fakePostRepo{},
+				hashtags:     make(map[uuid.UUID][]string),
+				mentions:     make(map[uuid.UUID][]uuid.UUID),
+			}
+			notifications := &fakeNotificationRepo{}
+			uc := &Usecase{
+				postRepo:       repo,
+				userRepo:       &fakeUsernameRepo{users: []*user.User{author, budi, sari}},
+				notificationUC: notificationUsecase.NewUsecase(notifications, nil),
+			}
+
+			p := &post.Post{ID: uuid.New(), AuthorID: author.ID, Content: tt.content, Visibility: tt.visibility}
+			uc.saveTags(context.Background(), p, nil, nil, tt.previous)
+
+			if got := repo.hashtags[p.ID]; !reflect.DeepEqual(got, []string{"ngopi"}) {
+				t.Errorf("hashtags = %v, want [ngopi]", got)
+			}
+			if got := repo.mentions[p.ID]; !reflect.DeepEqual(got, tt.wantMentions) {
+				t.Errorf("mentions = %v, want %v", got, tt.wantMentions)
+			}
+			if len(notifications.recipients) != len(tt.wantNotified) {
+				t.Fatalf("notified %v, want %v", notifications.recipients, tt.wantNotified)
+			}
+			for i := range tt.wantNotified {
+				if notifications.recipients[i] != tt.wantNotified[i] {
+					t.Errorf("notified %v, want %v", notifications.recipients, tt.wantNotified)
+				}
+			}
+		})
+	}
+}
+
+func TestGetHashtagPosts_InvalidTag(t *testing.T) {
+	uc := &Usecase{postRepo: &fakePostRepo{}}
+	for _, tag := range []string{"", "#", "2025", "bad-tag", strings.Repeat("a", maxHashtagLength+1)} {
+		if _, _, err := uc.GetHashtagPosts(context.Background(), tag, uuid.Nil, "", 20, 0); err != ErrInvalidHashtag {
+			t.Errorf("GetHashtagPosts(%q) error = %v, want %v", tag, err, ErrInvalidHashtag)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package post
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/anigmaa/backend/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// Authors with at least this many followers are not fanned out on write;
+	// their posts are pulled into their followers' timelines on read
+	popularAuthorFollowers = 10000
+	// Number of entries loaded from the database when a timeline is rebuilt.
+	// Also bounds how deep offset pagination can go.
+	timelineSeedSize = 800
+)
+
+// GetFeed gets a user's home timeline: posts by the user and the authors they
+// follow, mixed with discovery posts by authors they don't follow, newest first.
+// Pages are keyed by cursor; clients that still page by offset get the same
+// order, up to timelineSeedSize posts deep.
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) GetFeed(ctx context.Context, userID uuid.UUID, cursor string, limit, offset int) ([]post.PostWithDetails, string, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	position, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+
+	var before *post.TimelineEntry
+	if position != nil {
+		before = &post.TimelineEntry{PostID: position.ID, CreatedAt: position.CreatedAt}
+		offset = 0
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset+limit > timelineSeedSize {
+		return []post.PostWithDetails{}, "", nil
+	}
+
+	// Fetch one extra entry to know whether another page exists
+	entries, err := uc.getTimelineEntries(ctx, userID, before, offset+limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if offset >= len(entries) {
+		return []post.PostWithDetails{}, "", nil
+	}
+	entries = entries[offset:]
+
+	nextCursor := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		last := entries[len(entries)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.PostID)
+	}
+
+	postIDs := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		postIDs[i] = entry.PostID
+	}
+
+	// One batched query; posts deleted or no longer visible since fan-out are dropped
+	posts, err := uc.postRepo.GetByIDsWithDetails(ctx, postIDs, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextCursor, nil
+}
+
+// getTimelineEntries merges the posts of the authors the user follows with
+// discovery posts by everyone else
+func (uc *Usecase) getTimelineEntries(ctx context.Context, userID uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, error) {
+	entries, err := uc.getFollowedEntries(ctx, userID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery, err := uc.postRepo.GetDiscoveryEntries(ctx, userID, before, limit)
+	if err != nil {
+		// The timeline still works without discovery posts
+		log.Printf("[Timeline] Failed to get discovery posts for user %s: %v", userID, err)
+		return entries, nil
+	}
+
+	return mergeTimelineEntries(entries, discovery, limit), nil
+}
+
+// getFollowedEntries merges the user's cached timeline with the recent posts of
+// popular authors they follow
+func (uc *Usecase) getFollowedEntries(ctx context.Context, userID uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, error) {
+	entries, found, err := uc.timelineRepo.Get(ctx, userID, before, limit)
+	if err != nil {
+		// Serve the timeline from the database while the cache is unavailable
+		log.Printf("[Timeline] Failed to read timeline of user %s: %v", userID, err)
+		authorIDs, err := uc.timelineAuthors(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return uc.postRepo.GetTimelineEntries(ctx, userID, authorIDs, before, limit)
+	}
+
+	if !found {
+		seed, err := uc.seedTimeline(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		entries = entriesBefore(seed, before, limit)
+	}
+
+	popularIDs, err := uc.userRepo.GetFollowingIDs(ctx, userID, popularAuthorFollowers)
+	if err != nil {
+		return nil, err
+	}
+	if len(popularIDs) > 0 {
+		pulled, err := uc.postRepo.GetTimelineEntries(ctx, userID, popularIDs, before, limit)
+		if err != nil {
+			return nil, err
+		}
+		entries = mergeTimelineEntries(entries, pulled, limit)
+	}
+
+	return entries, nil
+}
+
+// seedTimeline rebuilds a user's cached timeline from the database
+func (uc *Usecase) seedTimeline(ctx context.Context, userID uuid.UUID) ([]post.TimelineEntry, error) {
+	authorIDs, err := uc.timelineAuthors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.postRepo.GetTimelineEntries(ctx, userID, authorIDs, nil, timelineSeedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.timelineRepo.Seed(ctx, userID, entries); err != nil {
+		log.Printf("[Timeline] Failed to seed timeline of user %s: %v", userID, err)
+	}
+
+	return entries, nil
+}
+
+// timelineAuthors returns the user and every author they follow
+func (uc *Usecase) timelineAuthors(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	followingIDs, err := uc.userRepo.GetFollowingIDs(ctx, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return append(followingIDs, userID), nil
+}
+
+// timelineRecipients returns the users whose cached timelines receive a post on write.
+// Followers of popular authors pull their posts on read instead.
+func (uc *Usecase) timelineRecipients(ctx context.Context, p *post.Post) []uuid.UUID {
+	recipients := []uuid.UUID{p.AuthorID}
+	if p.Visibility == post.VisibilityPrivate {
+		return recipients
+	}
+
+	stats, err := uc.userRepo.GetStats(ctx, p.AuthorID)
+	if err != nil {
+		log.Printf("[Timeline] Failed to get stats of author %s: %v", p.AuthorID, err)
+		return recipients
+	}
+	if stats.FollowersCount >= popularAuthorFollowers {
+		return recipients
+	}
+
+	followerIDs, err := uc.userRepo.GetFollowerIDs(ctx, p.AuthorID)
+	if err != nil {
+		log.Printf("[Timeline] Failed to get followers of author %s: %v", p.AuthorID, err)
+		return recipients
+	}
+
+	return append(recipients, followerIDs...)
+}
+
+// fanOutPost pushes a new post to the cached timelines of its author and their followers
+func (uc *Usecase) fanOutPost(ctx context.Context, p *post.Post) {
+	entry := post.TimelineEntry{
+		PostID: p.ID,
+		// Postgres stores microseconds; keep the cached position identical to the stored one
+		CreatedAt: p.CreatedAt.Round(time.Microsecond),
+	}
+
+	if err := uc.timelineRepo.Add(ctx, uc.timelineRecipients(ctx, p), entry); err != nil {
+		log.Printf("[Timeline] Failed to fan out post %s: %v", p.ID, err)
+	}
+}
+
+// removeFromTimelines removes a deleted post from the cached timelines it was pushed to
+func (uc *Usecase) removeFromTimelines(ctx context.Context, p *post.Post) {
+	if err := uc.timelineRepo.Remove(ctx, uc.timelineRecipients(ctx, p), p.ID); err != nil {
+		log.Printf("[Timeline] Failed to remove post %s from timelines: %v", p.ID, err)
+	}
+}
+
+// timelineEntryBefore reports whether a sorts before b in timeline order (newest first)
+func timelineEntryBefore(a, b post.TimelineEntry) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.PostID.String() > b.PostID.String()
+}
+
+// entriesBefore returns up to limit entries of a sorted timeline that come after the cursor
+func entriesBefore(entries []post.TimelineEntry, before *post.TimelineEntry, limit int) []post.TimelineEntry {
+	start := 0
+	if before != nil {
+		start = sort.Search(len(entries), func(i int) bool {
+			return timelineEntryBefore(*before, entries[i])
+		})
+	}
+
+	end := start + limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+	return entries[start:end]
+}
+
+// mergeTimelineEntries merges two sorted timelines, dropping duplicates, and keeps the first limit entries
+func mergeTimelineEntries(a, b []post.TimelineEntry, limit int) []post.TimelineEntry {
+	seen := make(map[uuid.UUID]bool, len(a)+len(b))
+	merged := make([]post.TimelineEntry, 0, len(a)+len(b))
+	for _, entry := range append(append([]post.TimelineEntry{}, a...), b...) {
+		if !seen[entry.PostID] {
+			seen[entry.PostID] = true
+			merged = append(merged, entry)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return timelineEntryBefore(merged[i], merged[j])
+	})
+
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
@@ -0,0 +1,306 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/anigmaa/backend/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// fakeTimelinePostRepo serves the posts of each author and the discovery pool
+type fakeTimelinePostRepo struct {
+	*fakePostRepo
+	byAuthor  map[uuid.UUID][]post.TimelineEntry
+	discovery []post.TimelineEntry
+}
+
+func (r *fakeTimelinePostRepo) GetTimelineEntries(ctx context.Context, viewerID uuid.UUID, authorIDs []uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, error) {
+	var entries []post.TimelineEntry
+	for _, authorID := range authorIDs {
+		entries = append(entries, r.byAuthor[authorID]...)
+	}
+	sortTimeline(entries)
+	return entriesBefore(entries, before, limit), nil
+}
+
+func (r *fakeTimelinePostRepo) GetDiscoveryEntries(ctx context.Context, viewerID uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, error) {
+	return entriesBefore(r.discovery, before, limit), nil
+}
+
+func (r *fakeTimelinePostRepo) GetByIDsWithDetails(ctx context.Context, postIDs []uuid.UUID, viewerID uuid.UUID) ([]post.PostWithDetails, error) {
+	posts := make([]post.PostWithDetails, len(postIDs))
+	for i, id := range postIDs {
+		posts[i] = post.PostWithDetails{Post: post.Post{ID: id}}
+	}
+	return posts, nil
+}
+
+// fakeTimelineRepo is an in-memory timeline cache
+type fakeTimelineRepo struct {
+	timelines   map[uuid.UUID][]post.TimelineEntry
+	err         error
+	invalidated []uuid.UUID
+}
+
+func (r *fakeTimelineRepo) Add(ctx context.Context, userIDs []uuid.UUID, entry post.TimelineEntry) error {
+	for _, userID := range userIDs {
+		if timeline, ok := r.timelines[userID]; ok {
+			r.timelines[userID] = append(timeline, entry)
+			sortTimeline(r.timelines[userID])
+		}
+	}
+	return nil
+}
+
+func (r *fakeTimelineRepo) Remove(ctx context.Context, userIDs []uuid.UUID, postID uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeTimelineRepo) Get(ctx context.Context, userID uuid.UUID, before *post.TimelineEntry, limit int) ([]post.TimelineEntry, bool, error) {
+	if r.err != nil {
+		return nil, false, r.err
+	}
+	timeline, ok := r.timelines[userID]
+	if !ok {
+		return nil, false, nil
+	}
+	return entriesBefore(timeline, before, limit), true, nil
+}
+
+func (r *fakeTimelineRepo) Seed(ctx context.Context, userID uuid.UUID, entries []post.TimelineEntry) error {
+	r.timelines[userID] = append([]post.TimelineEntry{}, entries...)
+	return nil
+}
+
+func (r *fakeTimelineRepo) Invalidate(ctx context.Context, userID uuid.UUID) error {
+	delete(r.timelines, userID)
+	r.invalidated = append(r.invalidated, userID)
+	return nil
+}
+
+// fakeFollowRepo knows who each user follows; none of them are popular
+type fakeFollowRepo struct {
+	user.Repository
+	following map[uuid.UUID][]uuid.UUID
+}
+
+func (r *fakeFollowRepo) GetFollowingIDs(ctx context.Context, userID uuid.UUID, minFollowers int) ([]uuid.UUID, error) {
+	if minFollowers > 0 {
+		return nil, nil
+	}
+	return r.following[userID], nil
+}
+
+func sortTimeline(entries []post.TimelineEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return timelineEntryBefore(entries[i], entries[j])
+	})
+}
+
+type timelineFixture struct {
+	uc        *Usecase
+	timelines *fakeTimelineRepo
+	viewer    uuid.UUID
+	followed  []post.TimelineEntry
+	discovery []post.TimelineEntry
+}
+
+// newTimelineFixture sets up a viewer following one author who posted every
+// hour, and a discovery pool with a post every three hours
+func newTimelineFixture() *timelineFixture {
+	viewer, friend := uuid.New(), uuid.New()
+	now := time.Now().Truncate(time.Microsecond)
+
+	var followed, discovery []post.TimelineEntry
+	for i := 0; i < 12; i++ {
+		followed = append(followed, post.TimelineEntry{PostID: uuid.New(), CreatedAt: now.Add(-time.Duration(i) * time.Hour)})
+	}
+	for i := 0; i < 4; i++ {
+		discovery = append(discovery, post.TimelineEntry{PostID: uuid.New(), CreatedAt: now.Add(-time.Duration(3*i)*time.Hour - 30*time.Minute)})
+	}
+
+	timelines := &fakeTimelineRepo{timelines: make(map[uuid.UUID][]post.TimelineEntry)}
+	uc := &Usecase{
+		postRepo: &fakeTimelinePostRepo{
+			fakePostRepo: &fakePostRepo{},
+			byAuthor:     map[uuid.UUID][]post.TimelineEntry{friend: followed},
+			discovery:    discovery,
+		},
+		timelineRepo: timelines,
+		userRepo:     &fakeFollowRepo{following: map[uuid.UUID][]uuid.UUID{viewer: {friend}}},
+	}
+	return &timelineFixture{uc: uc, timelines: timelines, viewer: viewer, followed: followed, discovery: discovery}
+}
+
+// readAll pages through the whole timeline by cursor
+func (f *timelineFixture) readAll(t *testing.T, limit int) []uuid.UUID {
+	t.Helper()
+	var ids []uuid.UUID
+	cursor := ""
+	for page := 0; page < 20; page++ {
+		posts, next, err := f.uc.GetFeed(context.Background(), f.viewer, cursor, limit, 0)
+		if err != nil {
+			t.Fatalf("GetFeed() error = %v", err)
+		}
+		for _, p := range posts {
+			ids = append(ids, p.ID)
+		}
+		if next == "" {
+			return ids
+		}
+		cursor = next
+	}
+	t.Fatal("GetFeed() never ran out of pages")
+	return nil
+}
+
+func TestGetFeed_MixesDiscovery(t *testing.T) {
+	f := newTimelineFixture()
+
+	want := append(append([]post.TimelineEntry{}, f.followed...), f.discovery...)
+	sortTimeline(want)
+
+	got := f.readAll(t, 5)
+	if len(got) != len(want) {
+		t.Fatalf("timeline has %d posts, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i].PostID {
+			t.Errorf("post %d = %s, want %s", i, got[i], want[i].PostID)
+		}
+	}
+
+	// The followed posts are cached, discovery posts are not
+	if cached := f.timelines.timelines[f.viewer]; len(cached) != len(f.followed) {
+		t.Errorf("cached %d entries, want the %d followed posts", len(cached), len(f.followed))
+	}
+}
+
+func TestGetFeed_CacheUnavailable(t *testing.T) {
+	f := newTimelineFixture()
+	f.timelines.err = errors.New("connection refused")
+
+	if got := f.readAll(t, 7); len(got) != len(f.followed)+len(f.discovery) {
+		t.Errorf("timeline has %d posts, want %d", len(got), len(f.followed)+len(f.discovery))
+	}
+}
+
+func TestGetFeed_OffsetMatchesCursor(t *testing.T) {
+	f := newTimelineFixture()
+	byCursor := f.readAll(t, 4)
+
+	var byOffset []uuid.UUID
+	for offset := 0; offset < len(byCursor); offset += 4 {
+		posts, _, err := f.uc.GetFeed(context.Background(), f.viewer, "", 4, offset)
+		if err != nil {
+			t.Fatalf("GetFeed() error = %v", err)
+		}
+		for _, p := range posts {
+			byOffset = append(byOffset, p.ID)
+		}
+	}
+
+	if len(byOffset) != len(byCursor) {
+		t.Fatalf("offset pages hold %d posts, cursor pages %d", len(byOffset), len(byCursor))
+	}
+	for i := range byCursor {
+		if byOffset[i] != byCursor[i] {
+			t.Errorf("post %d = %s by offset, %s by cursor", i, byOffset[i], byCursor[i])
+		}
+	}
+}
+
+func TestMergeTimelineEntries(t *testing.T) {
+	now := time.Now()
+	a := post.TimelineEntry{PostID: uuid.New(), CreatedAt: now}
+	b := post.TimelineEntry{PostID: uuid.New(), CreatedAt: now.Add(-time.Minute)}
+	c := post.TimelineEntry{PostID: uuid.New(), CreatedAt: now.Add(-2 * time.Minute)}
+
+	merged := mergeTimelineEntries([]post.TimelineEntry{a, c}, []post.TimelineEntry{b, c}, 10)
+	if len(merged) != 3 || merged[0] != a || merged[1] != b || merged[2] != c {
+		t.Errorf("merged = %v, want a, b, c once each", merged)
+	}
+
+	if merged := mergeTimelineEntries([]post.TimelineEntry{a, c}, []post.TimelineEntry{b}, 2); len(merged) != 2 || merged[1] != b {
+		t.Errorf("merged = %v, want the newest 2", merged)
+	}
+}
+
+// fakePublicPostRepo pages through public posts kept newest first, the way
+// GetPublicPosts orders them
+type fakePublicPostRepo struct {
+	*fakePostRepo
+	public []post.PostWithDetails
+}
+
+func (r *fakePublicPostRepo) GetPublicPosts(ctx context.Context, before *utils.Cursor, limit, offset int) ([]post.PostWithDetails, error) {
+	var page []post.PostWithDetails
+	for _, p := range r.public {
+		if before != nil && !timelineEntryBefore(post.TimelineEntry{PostID: before.ID, CreatedAt: before.CreatedAt}, post.TimelineEntry{PostID: p.ID, CreatedAt: p.CreatedAt}) {
+			continue
+		}
+		page = append(page, p)
+	}
+	if offset >= len(page) {
+		return nil, nil
+	}
+	page = page[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return page, nil
+}
+
+func TestGetPublicPosts_Cursor(t *testing.T) {
+	now := time.Now().Truncate(time.Microsecond)
+	var entries []post.TimelineEntry
+	for i := 0; i < 7; i++ {
+		// Pairs of posts share a timestamp, so the ID has to break the tie
+		entries = append(entries, post.TimelineEntry{PostID: uuid.New(), CreatedAt: now.Add(-time.Duration(i/2) * time.Minute)})
+	}
+	sortTimeline(entries)
+
+	repo := &fakePublicPostRepo{fakePostRepo: &fakePostRepo{}}
+	for _, e := range entries {
+		repo.public = append(repo.public, post.PostWithDetails{Post: post.Post{ID: e.PostID, CreatedAt: e.CreatedAt}})
+	}
+	uc := &Usecase{postRepo: repo}
+
+	var got []uuid.UUID
+	cursor := ""
+	for page := 0; ; page++ {
+		if page == 10 {
+			t.Fatal("GetPublicPosts() never ran out of pages")
+		}
+		posts, next, err := uc.GetPublicPosts(context.Background(), cursor, 3, 0)
+		if err != nil {
+			t.Fatalf("GetPublicPosts() error = %v", err)
+		}
+		for _, p := range posts {
+			got = append(got, p.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(got) != len(entries) {
+		t.Fatalf("read %d posts, want %d", len(got), len(entries))
+	}
+	for i := range entries {
+		if got[i] != entries[i].PostID {
+			t.Errorf("post %d = %s, want %s", i, got[i], entries[i].PostID)
+		}
+	}
+
+	if _, _, err := uc.GetPublicPosts(context.Background(), "not a cursor", 3, 0); err != ErrInvalidCursor {
+		t.Errorf("GetPublicPosts() with a bad cursor error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
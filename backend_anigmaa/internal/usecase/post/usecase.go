@@ -11,6 +11,8 @@ import (
 	"github.com/anigmaa/backend/internal/domain/interaction"
 	"github.com/anigmaa/backend/internal/domain/post"
 	"github.com/anigmaa/backend/internal/domain/user"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -26,31 +28,38 @@ var (
 	ErrNotBookmarked     = errors.New("not bookmarked")
 	ErrCannotRepostOwn   = errors.New("cannot repost your own post")
 	ErrEventNotFound     = errors.New("attached event not found")
+	ErrInvalidCursor     = errors.New("invalid cursor")
 )
 
 // Usecase handles post business logic
 type Usecase struct {
 	postRepo        post.Repository
+	timelineRepo    post.TimelineRepository
 	commentRepo     comment.Repository
 	interactionRepo interaction.Repository
 	eventRepo       event.Repository
 	userRepo        user.Repository
+	notificationUC  *notificationUsecase.Usecase
 }
 
 // NewUsecase creates a new post usecase
 func NewUsecase(
 	postRepo post.Repository,
+	timelineRepo post.TimelineRepository,
 	commentRepo comment.Repository,
 	interactionRepo interaction.Repository,
 	eventRepo event.Repository,
 	userRepo user.Repository,
+	notificationUC *notificationUsecase.Usecase,
 ) *Usecase {
 	return &Usecase{
 		postRepo:        postRepo,
+		timelineRepo:    timelineRepo,
 		commentRepo:     commentRepo,
 		interactionRepo: interactionRepo,
 		eventRepo:       eventRepo,
 		userRepo:        userRepo,
+		notificationUC:  notificationUC,
 	}
 }
 
@@ -109,6 +118,12 @@ func (uc *Usecase) CreatePost(ctx context.Context, authorID uuid.UUID, req *post
 		}
 	}
 
+	// Push to the home timelines of the author and their followers
+	uc.fanOutPost(ctx, newPost)
+
+	// Store hashtags and mentions, and notify mentioned users
+	uc.saveTags(ctx, newPost, req.Hashtags, req.Mentions, nil)
+
 	return newPost, nil
 }
 
@@ -121,7 +136,18 @@ func (uc *Usecase) GetPostByID(ctx context.Context, postID uuid.UUID) (*post.Pos
 	return p, nil
 }
 
-// GetPostWithDetails gets a post with all details for a specific user
+// getVisiblePost gets a post the user may see. Every interaction with a post
+// goes through it, so posts hidden from the user are reported as not found.
+func (uc *Usecase) getVisiblePost(ctx context.Context, postID, userID uuid.UUID) (*post.Post, error) {
+	p, err := uc.postRepo.GetVisible(ctx, postID, userID)
+	if err != nil {
+		return nil, ErrPostNotFound
+	}
+	return p, nil
+}
+
+// GetPostWithDetails gets a post with all details for a specific user.
+// Posts the user may not see are reported as not found.
 func (uc *Usecase) GetPostWithDetails(ctx context.Context, postID, userID uuid.UUID) (*post.PostWithDetails, error) {
 	p, err := uc.postRepo.GetWithDetails(ctx, postID, userID)
 	if err != nil {
@@ -158,6 +184,15 @@ func (uc *Usecase) UpdatePost(ctx context.Context, postID, userID uuid.UUID, req
 		return nil, err
 	}
 
+	// Re-parse hashtags and mentions; only newly mentioned users are notified
+	if req.Content != nil {
+		previous, err := uc.postRepo.GetMentionedUserIDs(ctx, postID)
+		if err != nil {
+			log.Printf("[PostUsecase] Failed to get mentions of post %s: %v", postID, err)
+		}
+		uc.saveTags(ctx, existingPost, nil, nil, previous)
+	}
+
 	return existingPost, nil
 }
 
@@ -174,7 +209,12 @@ func (uc *Usecase) DeletePost(ctx context.Context, postID, userID uuid.UUID) err
 		return ErrUnauthorized
 	}
 
-	return uc.postRepo.Delete(ctx, postID)
+	if err := uc.postRepo.Delete(ctx, postID); err != nil {
+		return err
+	}
+
+	uc.removeFromTimelines(ctx, existingPost)
+	return nil
 }
 
 // ArchivePost archives a post
@@ -225,40 +265,6 @@ func (uc *Usecase) UnarchivePost(ctx context.Context, postID, userID uuid.UUID)
 	return existingPost, nil
 }
 
-// GetFeed gets a user's personalized feed
-func (uc *Usecase) GetFeed(ctx context.Context, userID uuid.UUID, limit, offset int) ([]post.PostWithDetails, error) {
-	// DEBUG: Log input parameters
-	log.Printf("DEBUG: GetFeed called with userID=%v, limit=%d, offset=%d", userID, limit, offset)
-
-	if limit <= 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	// Repository layer already sets IsLikedByUser via SQL EXISTS subquery
-	// No need for batch query - it was causing the value to be overwritten incorrectly
-	posts, err := uc.postRepo.GetFeed(ctx, userID, limit, offset)
-	if err != nil {
-		log.Printf("DEBUG: GetFeed error: %v", err)
-		return nil, err
-	}
-
-	// DEBUG: Log results from repository
-	log.Printf("DEBUG: GetFeed repository returned %d posts", len(posts))
-	for i, p := range posts {
-		log.Printf("DEBUG: Repository Post[%d] ID=%v, IsLikedByUser=%v", i, p.ID, p.IsLikedByUser)
-	}
-
-	return posts, nil
-}
-
-// CountFeed counts total posts in user's feed
-func (uc *Usecase) CountFeed(ctx context.Context, userID uuid.UUID) (int, error) {
-	return uc.postRepo.CountFeed(ctx, userID)
-}
-
 // GetUserPosts gets posts by a specific user
 func (uc *Usecase) GetUserPosts(ctx context.Context, authorID, viewerID uuid.UUID, limit, offset int) ([]post.PostWithDetails, error) {
 	// Verify author exists
@@ -277,9 +283,9 @@ func (uc *Usecase) GetUserPosts(ctx context.Context, authorID, viewerID uuid.UUI
 	return uc.postRepo.GetUserPosts(ctx, authorID, viewerID, limit, offset)
 }
 
-// CountUserPosts counts total posts by a user
-func (uc *Usecase) CountUserPosts(ctx context.Context, authorID uuid.UUID) (int, error) {
-	return uc.postRepo.CountUserPosts(ctx, authorID)
+// CountUserPosts counts total posts by a user that the viewer can see
+func (uc *Usecase) CountUserPosts(ctx context.Context, authorID, viewerID uuid.UUID) (int, error) {
+	return uc.postRepo.CountUserPosts(ctx, authorID, viewerID)
 }
 
 // ListPosts lists posts with filters
@@ -296,10 +302,10 @@ func (uc *Usecase) ListPosts(ctx context.Context, filter *post.PostFilter, userI
 
 // LikePost likes a post
 func (uc *Usecase) LikePost(ctx context.Context, postID, userID uuid.UUID) error {
-	// Check if post exists
-	_, err := uc.postRepo.GetByID(ctx, postID)
+	// Check if post exists and the user may see it
+	likedPost, err := uc.getVisiblePost(ctx, postID, userID)
 	if err != nil {
-		return ErrPostNotFound
+		return err
 	}
 
 	// Check if already liked
@@ -325,6 +331,7 @@ func (uc *Usecase) LikePost(ctx context.Context, postID, userID uuid.UUID) error
 	}
 
 	// Note: likes_count is updated by DB trigger on the likes table — do not call IncrementLikes here
+	uc.notificationUC.NotifyPostLiked(ctx, userID, likedPost.AuthorID, postID)
 	return nil
 }
 
@@ -356,10 +363,10 @@ func (uc *Usecase) UnlikePost(ctx context.Context, postID, userID uuid.UUID) err
 
 // RepostPost reposts a post
 func (uc *Usecase) RepostPost(ctx context.Context, userID uuid.UUID, req *post.RepostRequest) error {
-	// Check if post exists
-	originalPost, err := uc.postRepo.GetByID(ctx, req.PostID)
+	// Check if post exists and the user may see it
+	originalPost, err := uc.getVisiblePost(ctx, req.PostID, userID)
 	if err != nil {
-		return ErrPostNotFound
+		return err
 	}
 
 	// Check if trying to repost own post
@@ -421,10 +428,9 @@ func (uc *Usecase) UndoRepost(ctx context.Context, postID, userID uuid.UUID) err
 
 // BookmarkPost bookmarks a post
 func (uc *Usecase) BookmarkPost(ctx context.Context, postID, userID uuid.UUID) error {
-	// Check if post exists
-	_, err := uc.postRepo.GetByID(ctx, postID)
-	if err != nil {
-		return ErrPostNotFound
+	// Check if post exists and the user may see it
+	if _, err := uc.getVisiblePost(ctx, postID, userID); err != nil {
+		return err
 	}
 
 	// Check if already bookmarked
@@ -512,10 +518,9 @@ func (uc *Usecase) CountBookmarks(ctx context.Context, userID uuid.UUID) (int, e
 
 // SharePost tracks a post share
 func (uc *Usecase) SharePost(ctx context.Context, postID, userID uuid.UUID, platform *string) error {
-	// Check if post exists
-	_, err := uc.postRepo.GetByID(ctx, postID)
-	if err != nil {
-		return ErrPostNotFound
+	// Check if post exists and the user may see it
+	if _, err := uc.getVisiblePost(ctx, postID, userID); err != nil {
+		return err
 	}
 
 	// Create share record
@@ -537,10 +542,10 @@ func (uc *Usecase) SharePost(ctx context.Context, postID, userID uuid.UUID, plat
 
 // CreateComment creates a comment on a post
 func (uc *Usecase) CreateComment(ctx context.Context, authorID uuid.UUID, req *comment.CreateCommentRequest) (*comment.CommentWithDetails, error) {
-	// Check if post exists
-	_, err := uc.postRepo.GetByID(ctx, req.PostID)
+	// Check if post exists and the user may see it
+	commentedPost, err := uc.getVisiblePost(ctx, req.PostID, authorID)
 	if err != nil {
-		return nil, ErrPostNotFound
+		return nil, err
 	}
 
 	// If parent comment is specified, verify it exists
@@ -571,6 +576,10 @@ func (uc *Usecase) CreateComment(ctx context.Context, authorID uuid.UUID, req *c
 	// Note: comments_count is automatically updated via database trigger (update_comments_count_trigger)
 	// No manual increment needed here
 
+	// Notify post author and mentioned users
+	uc.notificationUC.NotifyPostCommented(ctx, authorID, commentedPost.AuthorID, req.PostID, newComment.ID)
+	uc.notifyMentioned(ctx, authorID, req.PostID, newComment.ID, uc.resolveMentions(ctx, req.Content, nil))
+
 	// Fetch comment with details to include author info
 	commentWithDetails, err := uc.commentRepo.GetWithDetails(ctx, newComment.ID, authorID)
 	if err != nil {
@@ -639,11 +648,10 @@ func (uc *Usecase) DeleteComment(ctx context.Context, commentID, userID uuid.UUI
 }
 
 // GetCommentsByPost gets comments for a post
-func (uc *Usecase) GetCommentsByPost(ctx context.Context, postID, userID uuid.UUID, limit, offset int) ([]comment.CommentWithDetails, error) {
-	// Check if post exists
-	_, err := uc.postRepo.GetByID(ctx, postID)
-	if err != nil {
-		return nil, ErrPostNotFound
+func (uc *Usecase) GetCommentsByPost(ctx context.Context, postID, userID uuid.UUID, cursor string, limit, offset int) ([]comment.CommentWithDetails, string, error) {
+	// Check if post exists and the user may see it
+	if _, err := uc.getVisiblePost(ctx, postID, userID); err != nil {
+		return nil, "", err
 	}
 
 	if limit <= 0 {
@@ -653,7 +661,33 @@ func (uc *Usecase) GetCommentsByPost(ctx context.Context, postID, userID uuid.UU
 		limit = 100
 	}
 
-	return uc.commentRepo.GetByPost(ctx, postID, userID, limit, offset)
+	position, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	if position != nil {
+		offset = 0
+	}
+
+	// Fetch one extra row to know whether another page exists
+	comments, err := uc.commentRepo.GetByPost(ctx, postID, userID, position, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		nextCursor = utils.EncodeScoredCursor(last.LikesCount, last.CreatedAt, last.ID)
+	}
+
+	return comments, nextCursor, nil
+}
+
+// CountComments counts the comments of a post
+func (uc *Usecase) CountComments(ctx context.Context, postID uuid.UUID) (int, error) {
+	return uc.commentRepo.GetCount(ctx, postID)
 }
 
 // GetCommentReplies gets replies to a comment
@@ -676,11 +710,14 @@ func (uc *Usecase) GetCommentReplies(ctx context.Context, parentCommentID, userI
 
 // LikeComment likes a comment
 func (uc *Usecase) LikeComment(ctx context.Context, commentID, userID uuid.UUID) error {
-	// Check if comment exists
-	_, err := uc.commentRepo.GetByID(ctx, commentID)
+	// Check if comment exists and the user may see its post
+	likedComment, err := uc.commentRepo.GetByID(ctx, commentID)
 	if err != nil {
 		return ErrCommentNotFound
 	}
+	if _, err := uc.getVisiblePost(ctx, likedComment.PostID, userID); err != nil {
+		return ErrCommentNotFound
+	}
 
 	// Check if already liked
 	isLiked, err := uc.interactionRepo.IsLiked(ctx, userID, interaction.LikeableComment, commentID)
@@ -734,11 +771,9 @@ func (uc *Usecase) UnlikeComment(ctx context.Context, commentID, userID uuid.UUI
 	return nil
 }
 
-// GetPublicPosts gets all public posts (for unauthenticated users)
-func (uc *Usecase) GetPublicPosts(ctx context.Context, limit, offset int) ([]post.PostWithDetails, error) {
-	// DEBUG: Log public feed request
-	log.Printf("DEBUG: GetPublicPosts called with limit=%d, offset=%d", limit, offset)
-
+// GetPublicPosts gets the public posts, newest first (for unauthenticated users).
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) GetPublicPosts(ctx context.Context, cursor string, limit, offset int) ([]post.PostWithDetails, string, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -746,26 +781,31 @@ func (uc *Usecase) GetPublicPosts(ctx context.Context, limit, offset int) ([]pos
 		limit = 100
 	}
 
-	// Use a nil userID to indicate no user context - returns public posts only
-	posts, err := uc.postRepo.GetFeed(ctx, uuid.Nil, limit, offset)
+	before, err := utils.DecodeCursor(cursor)
 	if err != nil {
-		log.Printf("DEBUG: GetPublicPosts error: %v", err)
-		return nil, err
+		return nil, "", ErrInvalidCursor
+	}
+	if before != nil || offset < 0 {
+		offset = 0
 	}
 
-	// DEBUG: Log public posts results
-	log.Printf("DEBUG: GetPublicPosts returned %d posts", len(posts))
-	for i, p := range posts {
-		log.Printf("DEBUG: Public Post[%d] ID=%v, IsLikedByUser=%v", i, p.ID, p.IsLikedByUser)
+	// Fetch one extra post to know whether another page exists
+	posts, err := uc.postRepo.GetPublicPosts(ctx, before, limit+1, offset)
+	if err != nil {
+		return nil, "", err
 	}
 
-	// For public posts, there's no user context, so IsLikedByUser should always be false
-	// This is already set correctly by the repository layer when userID is uuid.Nil
-	return posts, nil
+	nextCursor := ""
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[len(posts)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return posts, nextCursor, nil
 }
 
 // CountPublicPosts counts total public posts
 func (uc *Usecase) CountPublicPosts(ctx context.Context) (int, error) {
-	// Use nil userID to count all public posts
-	return uc.postRepo.CountFeed(ctx, uuid.Nil)
+	return uc.postRepo.CountPublicPosts(ctx)
 }
@@ -0,0 +1,137 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/anigmaa/backend/internal/domain/comment"
+	"github.com/anigmaa/backend/internal/domain/interaction"
+	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/google/uuid"
+)
+
+// fakePostRepo holds posts and who follows whom, and applies the visibility
+// rules of postVisibleTo
+type fakePostRepo struct {
+	post.Repository
+	posts   map[uuid.UUID]*post.Post
+	follows map[[2]uuid.UUID]bool // {follower, following}
+}
+
+func (r *fakePostRepo) GetVisible(ctx context.Context, postID, viewerID uuid.UUID) (*post.Post, error) {
+	p, ok := r.posts[postID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	visible := p.Visibility == post.VisibilityPublic || p.AuthorID == viewerID ||
+		(p.Visibility == post.VisibilityFollowers && r.follows[[2]uuid.UUID{viewerID, p.AuthorID}])
+	if !visible {
+		return nil, sql.ErrNoRows
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (r *fakePostRepo) IncrementReposts(ctx context.Context, postID uuid.UUID) error {
+	return nil
+}
+
+// fakeInteractionRepo counts the likes and reposts written
+type fakeInteractionRepo struct {
+	interaction.Repository
+	likes   int
+	reposts int
+}
+
+func (r *fakeInteractionRepo) IsLiked(ctx context.Context, userID uuid.UUID, likeableType interaction.LikeableType, likeableID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeInteractionRepo) Like(ctx context.Context, like *interaction.Like) error {
+	r.likes++
+	return nil
+}
+
+func (r *fakeInteractionRepo) IsReposted(ctx context.Context, userID, postID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeInteractionRepo) Repost(ctx context.Context, repost *interaction.Repost) error {
+	r.reposts++
+	return nil
+}
+
+// fakeCommentRepo counts the comments written
+type fakeCommentRepo struct {
+	comment.Repository
+	created int
+}
+
+func (r *fakeCommentRepo) Create(ctx context.Context, c *comment.Comment) error {
+	r.created++
+	return nil
+}
+
+func (r *fakeCommentRepo) GetWithDetails(ctx context.Context, commentID, userID uuid.UUID) (*comment.CommentWithDetails, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestInteractions_EnforceVisibility(t *testing.T) {
+	author, follower, stranger := uuid.New(), uuid.New(), uuid.New()
+	public := &post.Post{ID: uuid.New(), AuthorID: author, Visibility: post.VisibilityPublic}
+	followersOnly := &post.Post{ID: uuid.New(), AuthorID: author, Visibility: post.VisibilityFollowers}
+	private := &post.Post{ID: uuid.New(), AuthorID: author, Visibility: post.VisibilityPrivate}
+
+	tests := []struct {
+		name    string
+		post    *post.Post
+		viewer  uuid.UUID
+		visible bool
+	}{
+		{"public post", public, stranger, true},
+		{"followers-only post, follower", followersOnly, follower, true},
+		{"followers-only post, stranger", followersOnly, stranger, false},
+		{"private post, follower", private, follower, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interactions := &fakeInteractionRepo{}
+			comments := &fakeCommentRepo{}
+			uc := &Usecase{
+				postRepo: &fakePostRepo{
+					posts:   map[uuid.UUID]*post.Post{public.ID: public, followersOnly.ID: followersOnly, private.ID: private},
+					follows: map[[2]uuid.UUID]bool{{follower, author}: true},
+				},
+				commentRepo:     comments,
+				interactionRepo: interactions,
+			}
+			ctx := context.Background()
+
+			errs := map[string]error{
+				"like":   uc.LikePost(ctx, tt.post.ID, tt.viewer),
+				"repost": uc.RepostPost(ctx, tt.viewer, &post.RepostRequest{PostID: tt.post.ID}),
+			}
+			_, errs["comment"] = uc.CreateComment(ctx, tt.viewer, &comment.CreateCommentRequest{PostID: tt.post.ID, Content: "Mantap!"})
+
+			for action, err := range errs {
+				if tt.visible && err != nil {
+					t.Errorf("%s error = %v, want none", action, err)
+				}
+				if !tt.visible && !errors.Is(err, ErrPostNotFound) {
+					t.Errorf("%s error = %v, want %v", action, err, ErrPostNotFound)
+				}
+			}
+
+			written := interactions.likes + interactions.reposts + comments.created
+			if tt.visible && written != 3 {
+				t.Errorf("wrote %d interactions, want 3", written)
+			}
+			if !tt.visible && written != 0 {
+				t.Errorf("wrote %d interactions to a hidden post, want none", written)
+			}
+		})
+	}
+}
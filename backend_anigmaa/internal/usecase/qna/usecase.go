@@ -3,10 +3,13 @@ package qna
 import (
 	"context"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/event"
 	"github.com/anigmaa/backend/internal/domain/qna"
+	"github.com/anigmaa/backend/internal/infrastructure/realtime"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -17,19 +20,22 @@ var (
 	ErrAlreadyUpvoted  = errors.New("already upvoted")
 	ErrNotUpvoted      = errors.New("not upvoted")
 	ErrAlreadyAnswered = errors.New("question already answered")
+	ErrInvalidCursor   = errors.New("invalid cursor")
 )
 
 // Usecase handles Q&A business logic
 type Usecase struct {
 	qnaRepo   qna.Repository
 	eventRepo event.Repository
+	hub       *realtime.Hub
 }
 
 // NewUsecase creates a new Q&A use case
-func NewUsecase(qnaRepo qna.Repository, eventRepo event.Repository) *Usecase {
+func NewUsecase(qnaRepo qna.Repository, eventRepo event.Repository, hub *realtime.Hub) *Usecase {
 	return &Usecase{
 		qnaRepo:   qnaRepo,
 		eventRepo: eventRepo,
+		hub:       hub,
 	}
 }
 
@@ -66,12 +72,13 @@ func (uc *Usecase) AskQuestion(ctx context.Context, userID uuid.UUID, req *qna.C
 	return qnaWithDetails, nil
 }
 
-// GetEventQnA retrieves all Q&A for an event
-func (uc *Usecase) GetEventQnA(ctx context.Context, eventID, userID uuid.UUID, limit, offset int) ([]qna.QnAWithDetails, error) {
+// GetEventQnA retrieves the Q&A for an event, most upvoted first.
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) GetEventQnA(ctx context.Context, eventID, userID uuid.UUID, cursor string, limit, offset int) ([]qna.QnAWithDetails, string, error) {
 	// Verify event exists
 	_, err := uc.eventRepo.GetByID(ctx, eventID)
 	if err != nil {
-		return nil, ErrEventNotFound
+		return nil, "", ErrEventNotFound
 	}
 
 	if limit <= 0 {
@@ -81,10 +88,31 @@ func (uc *Usecase) GetEventQnA(ctx context.Context, eventID, userID uuid.UUID, l
 		limit = 100
 	}
 
-	return uc.qnaRepo.GetByEvent(ctx, eventID, userID, limit, offset)
+	position, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	if position != nil {
+		offset = 0
+	}
+
+	// Fetch one extra row to know whether another page exists
+	qnaList, err := uc.qnaRepo.GetByEvent(ctx, eventID, userID, position, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(qnaList) > limit {
+		qnaList = qnaList[:limit]
+		last := qnaList[len(qnaList)-1]
+		nextCursor = utils.EncodeScoredCursor(last.Upvotes, last.AskedAt, last.ID)
+	}
+
+	return qnaList, nextCursor, nil
 }
 
-// AnswerQuestion answers a question (event organizer only)
+// AnswerQuestion answers a question (host, co-hosts and Q&A moderators only)
 func (uc *Usecase) AnswerQuestion(ctx context.Context, qnaID, userID uuid.UUID, req *qna.AnswerQnARequest) (*qna.QnA, error) {
 	// Get Q&A
 	q, err := uc.qnaRepo.GetByID(ctx, qnaID)
@@ -97,8 +125,10 @@ func (uc *Usecase) AnswerQuestion(ctx context.Context, qnaID, userID uuid.UUID,
 		return nil, ErrAlreadyAnswered
 	}
 
-	// TODO: Verify user is event organizer
-	// For now, allow anyone to answer
+	// Verify user may moderate the event's Q&A
+	if err := uc.authorizeModerator(ctx, q.EventID, userID); err != nil {
+		return nil, err
+	}
 
 	// Update Q&A with answer
 	now := time.Now()
@@ -111,6 +141,11 @@ func (uc *Usecase) AnswerQuestion(ctx context.Context, qnaID, userID uuid.UUID,
 		return nil, err
 	}
 
+	// Let the asker know their question was answered
+	if err := uc.hub.Publish(ctx, q.AskedByID, realtime.EventQnAAnswered, q); err != nil {
+		log.Printf("[QnAUsecase] Failed to publish answer for question %s: %v", q.ID, err)
+	}
+
 	return q, nil
 }
 
@@ -152,7 +187,7 @@ func (uc *Usecase) RemoveUpvote(ctx context.Context, qnaID, userID uuid.UUID) er
 	return nil
 }
 
-// DeleteQuestion deletes a question (author only)
+// DeleteQuestion deletes a question (author, host, co-hosts and Q&A moderators only)
 func (uc *Usecase) DeleteQuestion(ctx context.Context, qnaID, userID uuid.UUID) error {
 	// Get Q&A
 	q, err := uc.qnaRepo.GetByID(ctx, qnaID)
@@ -160,14 +195,28 @@ func (uc *Usecase) DeleteQuestion(ctx context.Context, qnaID, userID uuid.UUID)
 		return ErrQnANotFound
 	}
 
-	// Check if user is the author
+	// Check if user is the author or a moderator
 	if q.AskedByID != userID {
-		return ErrUnauthorized
+		if err := uc.authorizeModerator(ctx, q.EventID, userID); err != nil {
+			return err
+		}
 	}
 
 	return uc.qnaRepo.Delete(ctx, qnaID)
 }
 
+// authorizeModerator returns ErrUnauthorized unless the user may moderate the event's Q&A
+func (uc *Usecase) authorizeModerator(ctx context.Context, eventID, userID uuid.UUID) error {
+	access, err := uc.eventRepo.GetAccess(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if !access.CanModerateQnA() {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
 // CountEventQnA counts total questions for an event
 func (uc *Usecase) CountEventQnA(ctx context.Context, eventID uuid.UUID) (int, error) {
 	return uc.qnaRepo.CountEventQnA(ctx, eventID)
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anigmaa/backend/internal/domain/search"
+)
+
+var (
+	ErrQueryTooShort = errors.New("search query must be at least 2 characters")
+	ErrQueryTooLong  = errors.New("search query must be at most 200 characters")
+	ErrInvalidType   = errors.New("invalid search type")
+)
+
+// Usecase handles search business logic
+type Usecase struct {
+	searchRepo search.Repository
+}
+
+// NewUsecase creates a new search usecase
+func NewUsecase(searchRepo search.Repository) *Usecase {
+	return &Usecase{
+		searchRepo: searchRepo,
+	}
+}
+
+// ParseTypes parses a comma-separated list of result types, e.g. "event,post".
+// An empty list means every type.
+func ParseTypes(value string) ([]search.ResultType, error) {
+	if strings.TrimSpace(value) == "" {
+		return search.AllTypes, nil
+	}
+
+	seen := make(map[search.ResultType]bool)
+	types := make([]search.ResultType, 0)
+	for _, part := range strings.Split(value, ",") {
+		t := search.ResultType(strings.ToLower(strings.TrimSpace(part)))
+		if !t.IsValid() {
+			return nil, ErrInvalidType
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+
+	return types, nil
+}
+
+// Search searches events, posts, communities and users, best match first.
+// Returns the page and whether more results follow.
+func (uc *Usecase) Search(ctx context.Context, query *search.Query) ([]search.Result, bool, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if utf8.RuneCountInString(query.Text) < 2 {
+		return nil, false, ErrQueryTooShort
+	}
+	if utf8.RuneCountInString(query.Text) > 200 {
+		return nil, false, ErrQueryTooLong
+	}
+
+	if len(query.Types) == 0 {
+		query.Types = search.AllTypes
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 50 {
+		query.Limit = 50
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	// Fetch one extra result to know whether another page exists
+	limit := query.Limit
+	query.Limit = limit + 1
+	results, err := uc.searchRepo.Search(ctx, query)
+	query.Limit = limit
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasNext := len(results) > limit
+	if hasNext {
+		results = results[:limit]
+	}
+
+	return results, hasNext, nil
+}
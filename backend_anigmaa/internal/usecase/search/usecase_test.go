@@ -0,0 +1,134 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anigmaa/backend/internal/domain/search"
+	"github.com/google/uuid"
+)
+
+// fakeSearchRepo returns up to query.Limit of its results and records the query
+type fakeSearchRepo struct {
+	results []search.Result
+	query   search.Query
+	calls   int
+}
+
+func (r *fakeSearchRepo) Search(ctx context.Context, query *search.Query) ([]search.Result, error) {
+	r.calls++
+	r.query = *query
+	results := r.results
+	if query.Offset < len(results) {
+		results = results[query.Offset:]
+	} else {
+		results = nil
+	}
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+func newResults(n int) []search.Result {
+	results := make([]search.Result, n)
+	for i := range results {
+		results[i] = search.Result{Type: search.TypePost, ID: uuid.New(), Rank: float64(n - i)}
+	}
+	return results
+}
+
+func TestParseTypes(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []search.ResultType
+		wantErr error
+	}{
+		{"", search.AllTypes, nil},
+		{"  ", search.AllTypes, nil},
+		{"event", []search.ResultType{search.TypeEvent}, nil},
+		{" Post , user,post", []search.ResultType{search.TypePost, search.TypeUser}, nil},
+		{"event,ticket", nil, ErrInvalidType},
+		{"event,", nil, ErrInvalidType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseTypes(tt.value)
+			if err != tt.wantErr {
+				t.Fatalf("ParseTypes() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want error
+	}{
+		{"empty", "", ErrQueryTooShort},
+		{"one letter after trimming", "  a  ", ErrQueryTooShort},
+		{"too long", strings.Repeat("a", 201), ErrQueryTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeSearchRepo{}
+			uc := NewUsecase(repo)
+			if _, _, err := uc.Search(context.Background(), &search.Query{Text: tt.text}); err != tt.want {
+				t.Errorf("Search() error = %v, want %v", err, tt.want)
+			}
+			if repo.calls != 0 {
+				t.Errorf("repository searched %d times, want none", repo.calls)
+			}
+		})
+	}
+
+	// Length is counted in characters, not bytes
+	uc := NewUsecase(&fakeSearchRepo{})
+	if _, _, err := uc.Search(context.Background(), &search.Query{Text: strings.Repeat("é", 200)}); err != nil {
+		t.Errorf("Search() of 200 accented letters error = %v, want none", err)
+	}
+}
+
+func TestSearch_Paging(t *testing.T) {
+	repo := &fakeSearchRepo{results: newResults(25)}
+	uc := NewUsecase(repo)
+
+	query := &search.Query{Text: "  kopi  ", Limit: 10}
+	results, hasNext, err := uc.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 10 || !hasNext {
+		t.Errorf("Search() = %d results, more %v, want 10 and more", len(results), hasNext)
+	}
+	if repo.query.Text != "kopi" || !reflect.DeepEqual(repo.query.Types, search.AllTypes) {
+		t.Errorf("searched %q in %v, want \"kopi\" in every type", repo.query.Text, repo.query.Types)
+	}
+	if query.Limit != 10 {
+		t.Errorf("query limit = %d after the search, want it restored to 10", query.Limit)
+	}
+
+	results, hasNext, err = uc.Search(context.Background(), &search.Query{Text: "kopi", Limit: 10, Offset: 20})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 5 || hasNext {
+		t.Errorf("last page = %d results, more %v, want 5 and no more", len(results), hasNext)
+	}
+
+	if _, _, err := uc.Search(context.Background(), &search.Query{Text: "kopi", Limit: 500, Offset: -3}); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if repo.query.Limit != 51 || repo.query.Offset != 0 {
+		t.Errorf("searched limit %d offset %d, want 51 (50 plus one) and 0", repo.query.Limit, repo.query.Offset)
+	}
+}
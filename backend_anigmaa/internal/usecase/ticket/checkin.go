@@ -0,0 +1,458 @@
+package ticket
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/internal/infrastructure/realtime"
+	"github.com/anigmaa/backend/pkg/qrcode"
+	"github.com/anigmaa/backend/pkg/utils"
+	"github.com/google/uuid"
+)
+
+// CheckIn checks in a ticket using its attendance code. Only the host and staff
+// allowed to check people in may scan tickets of the event.
+func (uc *Usecase) CheckIn(ctx context.Context, eventID, scannerID uuid.UUID, req *ticket.CheckInRequest) (*ticket.CheckInResult, error) {
+	if _, err := uc.authorizeScanner(ctx, eventID, scannerID); err != nil {
+		return nil, err
+	}
+
+	t, err := uc.ticketFromCode(ctx, eventID, req.AttendanceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.checkIn(ctx, t, &ticket.CheckInScan{
+		ID:        scanID(req.ScanID),
+		Gate:      strings.TrimSpace(req.Gate),
+		ScannedBy: scannerID,
+	})
+}
+
+// CheckInWithQR checks in a ticket from a scanned signed QR payload. Only the
+// host and staff allowed to check people in may scan tickets of the event.
+func (uc *Usecase) CheckInWithQR(ctx context.Context, eventID, scannerID uuid.UUID, req *ticket.QRCheckInRequest) (*ticket.CheckInResult, error) {
+	if _, err := uc.authorizeScanner(ctx, eventID, scannerID); err != nil {
+		return nil, err
+	}
+
+	t, err := uc.ticketFromQR(ctx, eventID, req.QRPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.checkIn(ctx, t, &ticket.CheckInScan{
+		ID:        scanID(req.ScanID),
+		Gate:      strings.TrimSpace(req.Gate),
+		ScannedBy: scannerID,
+	})
+}
+
+// GetCheckInStats gets the live checked-in counts of an event, in total and per gate
+func (uc *Usecase) GetCheckInStats(ctx context.Context, eventID, userID uuid.UUID) (*ticket.CheckInStats, error) {
+	if _, err := uc.authorizeScanner(ctx, eventID, userID); err != nil {
+		return nil, err
+	}
+	return uc.ticketRepo.GetCheckInStats(ctx, eventID)
+}
+
+// authorizeScanner gets an event and verifies that the user may check people in to it
+func (uc *Usecase) authorizeScanner(ctx context.Context, eventID, userID uuid.UUID) (*event.Event, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	access, err := uc.eventRepo.GetAccess(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanCheckIn() {
+		return nil, ErrUnauthorized
+	}
+
+	return evt, nil
+}
+
+// ticketFromCode finds the event's ticket with the attendance code
+func (uc *Usecase) ticketFromCode(ctx context.Context, eventID uuid.UUID, code string) (*ticket.Ticket, error) {
+	if !utils.ValidateAttendanceCode(code) {
+		return nil, ErrInvalidAttendanceCode
+	}
+
+	t, err := uc.ticketRepo.GetByAttendanceCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, err
+	}
+	if t.EventID != eventID {
+		return nil, ErrTicketNotFound
+	}
+
+	return t, nil
+}
+
+// ticketFromQR verifies a scanned ticket QR and finds the event's ticket it was issued for
+func (uc *Usecase) ticketFromQR(ctx context.Context, eventID uuid.UUID, payload string) (*ticket.Ticket, error) {
+	data, err := qrcode.DecodeTicketQR(payload, uc.qrSigner.PublicKey())
+	if err != nil {
+		if errors.Is(err, qrcode.ErrExpired) {
+			return nil, ErrQRExpired
+		}
+		return nil, ErrInvalidQR
+	}
+
+	if data.EventID != eventID {
+		return nil, ErrTicketNotFound
+	}
+
+	t, err := uc.ticketRepo.GetByID(ctx, data.TicketID)
+	if err != nil {
+		return nil, ErrTicketNotFound
+	}
+
+	// A valid signature from an older issue of the ticket must still match the stored ticket
+	if t.EventID != data.EventID || t.UserID != data.UserID || t.AttendanceCode != data.AttendanceCode {
+		return nil, ErrInvalidQR
+	}
+
+	return t, nil
+}
+
+// checkIn checks in an active ticket and logs the scan, responding with what
+// the scanner should show
+func (uc *Usecase) checkIn(ctx context.Context, t *ticket.Ticket, scan *ticket.CheckInScan) (*ticket.CheckInResult, error) {
+	record, err := uc.recordScan(ctx, t, scan)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := uc.checkInResult(ctx, record.scan, record.replayed)
+	if err != nil {
+		return nil, err
+	}
+
+	if !record.replayed && result.Stats != nil {
+		uc.publishCheckInStats(ctx, t.EventID, result.Stats)
+	}
+
+	return result, nil
+}
+
+// scanRecord is a scan as recordScan logged it
+type scanRecord struct {
+	scan     *ticket.CheckInScan
+	replayed bool // The scan ID was already logged; scan is the original
+	tookOver bool // The scan was earlier than the check-in it replaced
+}
+
+// recordScan checks in an active ticket as of the scan time and logs the scan.
+// Scanning a ticket that is already checked in is not an error; the scan is
+// logged as a duplicate. A scan ID that was already logged replays the original
+// outcome, so scanners can safely retry requests that timed out.
+func (uc *Usecase) recordScan(ctx context.Context, t *ticket.Ticket, scan *ticket.CheckInScan) (*scanRecord, error) {
+	previous, err := uc.ticketRepo.GetCheckInScan(ctx, scan.ID)
+	if err == nil {
+		return replayScan(t, previous)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if t.Status != ticket.StatusActive {
+		return nil, ErrTicketNotActive
+	}
+
+	if scan.ScannedAt.IsZero() {
+		scan.ScannedAt = time.Now()
+	}
+	scan.TicketID = t.ID
+	scan.EventID = t.EventID
+	demoted, inserted, err := uc.ticketRepo.RecordCheckInScan(ctx, scan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, err
+	}
+	if !inserted {
+		// A retry of the same scan got there first
+		previous, err := uc.ticketRepo.GetCheckInScan(ctx, scan.ID)
+		if err != nil {
+			return nil, err
+		}
+		return replayScan(t, previous)
+	}
+
+	return &scanRecord{scan: scan, tookOver: demoted > 0}, nil
+}
+
+// replayScan returns a scan that was already logged, if it was of the same ticket
+func replayScan(t *ticket.Ticket, scan *ticket.CheckInScan) (*scanRecord, error) {
+	if scan.TicketID != t.ID {
+		return nil, ErrScanIDReused
+	}
+	return &scanRecord{scan: scan, replayed: true}, nil
+}
+
+// checkInResult describes a logged scan, with where the ticket was first let in
+// if it was a duplicate
+func (uc *Usecase) checkInResult(ctx context.Context, scan *ticket.CheckInScan, replayed bool) (*ticket.CheckInResult, error) {
+	details, err := uc.ticketRepo.GetWithDetails(ctx, scan.TicketID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ticket.CheckInResult{
+		ScanID:    scan.ID,
+		Outcome:   scan.Outcome,
+		Replayed:  replayed,
+		Gate:      scan.Gate,
+		ScannedAt: scan.ScannedAt,
+		Ticket:    details,
+	}
+
+	if scan.Outcome == ticket.CheckInDuplicate {
+		result.FirstCheckedInAt, result.FirstGate = uc.firstAdmission(ctx, scan.TicketID, details.CheckedInAt)
+	}
+
+	// The counts are a convenience for the scanner; the check-in itself already happened
+	stats, err := uc.ticketRepo.GetCheckInStats(ctx, scan.EventID)
+	if err != nil {
+		log.Printf("[TicketUsecase] failed to get check-in stats of event %s: %v", scan.EventID, err)
+	} else {
+		result.Stats = stats
+	}
+
+	return result, nil
+}
+
+// firstAdmission finds when and at which gate a ticket was let in. Tickets checked
+// in before scans were logged have no admitting scan, so only checkedInAt is known.
+func (uc *Usecase) firstAdmission(ctx context.Context, ticketID uuid.UUID, checkedInAt *time.Time) (*time.Time, *string) {
+	first, err := uc.ticketRepo.GetAdmittingScan(ctx, ticketID)
+	if err != nil {
+		return checkedInAt, nil
+	}
+	return &first.ScannedAt, &first.Gate
+}
+
+// publishCheckInStats pushes new check-in counts to everyone who may check people in
+func (uc *Usecase) publishCheckInStats(ctx context.Context, eventID uuid.UUID, stats *ticket.CheckInStats) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		log.Printf("[TicketUsecase] failed to get event %s for check-in stats: %v", eventID, err)
+		return
+	}
+
+	recipients := []uuid.UUID{evt.HostID}
+	staff, err := uc.eventRepo.GetStaff(ctx, eventID)
+	if err != nil {
+		log.Printf("[TicketUsecase] failed to get staff of event %s: %v", eventID, err)
+	}
+	for _, s := range staff {
+		if (event.Access{StaffRole: s.Role}).CanCheckIn() {
+			recipients = append(recipients, s.UserID)
+		}
+	}
+
+	uc.hub.PublishToUsers(ctx, recipients, realtime.EventCheckInStats, stats)
+}
+
+// scanID is the ID a scanner chose for a scan, or a new one if it didn't
+func scanID(id *uuid.UUID) uuid.UUID {
+	if id == nil || *id == uuid.Nil {
+		return uuid.New()
+	}
+	return *id
+}
+
+// IssueKioskToken issues a token that lets a device check people in at one gate
+// of the event without signing in. It expires when the ticket QRs do, or earlier
+// if asked to.
+func (uc *Usecase) IssueKioskToken(ctx context.Context, eventID, userID uuid.UUID, req *ticket.CreateKioskTokenRequest) (*ticket.KioskTokenResponse, error) {
+	evt, err := uc.authorizeScanner(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	gate := strings.TrimSpace(req.Gate)
+	if gate == "" {
+		return nil, ErrGateRequired
+	}
+
+	now := time.Now()
+	expiresAt := evt.EndTime.Add(qrValidityAfterEventEnd)
+	if !expiresAt.After(now) {
+		return nil, ErrCheckInClosed
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) || req.ExpiresAt.After(expiresAt) {
+			return nil, ErrInvalidKioskExpiry
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	secret, err := generateKioskToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &ticket.KioskToken{
+		EventID:   eventID,
+		IssuedBy:  userID,
+		Gate:      gate,
+		TokenHash: hashKioskToken(secret),
+		ExpiresAt: expiresAt,
+	}
+	if err := uc.ticketRepo.CreateKioskToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	return &ticket.KioskTokenResponse{KioskToken: *token, Token: secret}, nil
+}
+
+// GetKioskTokens lists an event's kiosk tokens. Co-hosts see every token, other
+// staff only the ones they issued.
+func (uc *Usecase) GetKioskTokens(ctx context.Context, eventID, userID uuid.UUID) ([]ticket.KioskToken, error) {
+	if _, err := uc.eventRepo.GetByID(ctx, eventID); err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	access, err := uc.eventRepo.GetAccess(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.CanCheckIn() {
+		return nil, ErrUnauthorized
+	}
+
+	var issuedBy *uuid.UUID
+	if !access.IsCoHost() {
+		issuedBy = &userID
+	}
+	return uc.ticketRepo.GetEventKioskTokens(ctx, eventID, issuedBy)
+}
+
+// RevokeKioskToken revokes a kiosk token; the device using it is signed out on its
+// next request. Co-hosts may revoke any token of the event, others only their own.
+func (uc *Usecase) RevokeKioskToken(ctx context.Context, eventID, tokenID, userID uuid.UUID) error {
+	token, err := uc.ticketRepo.GetKioskTokenByID(ctx, tokenID)
+	if err != nil || token.EventID != eventID {
+		return ErrKioskTokenNotFound
+	}
+
+	if token.IssuedBy != userID {
+		access, err := uc.eventRepo.GetAccess(ctx, eventID, userID)
+		if err != nil {
+			return err
+		}
+		if !access.IsCoHost() {
+			return ErrUnauthorized
+		}
+	}
+
+	// Revoking a token twice is fine
+	if err := uc.ticketRepo.RevokeKioskToken(ctx, tokenID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+// GetKioskSession describes the event and gate a kiosk token checks people in to
+func (uc *Usecase) GetKioskSession(ctx context.Context, secret string) (*ticket.KioskSession, error) {
+	token, evt, err := uc.resolveKioskToken(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.ticketRepo.GetCheckInStats(ctx, evt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ticket.KioskSession{
+		EventID:    evt.ID,
+		EventTitle: evt.Title,
+		Gate:       token.Gate,
+		ExpiresAt:  token.ExpiresAt,
+		Stats:      stats,
+	}, nil
+}
+
+// KioskCheckIn checks in a ticket scanned by a kiosk at the token's gate
+func (uc *Usecase) KioskCheckIn(ctx context.Context, secret string, req *ticket.KioskCheckInRequest) (*ticket.CheckInResult, error) {
+	token, evt, err := uc.resolveKioskToken(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	var t *ticket.Ticket
+	if req.QRPayload != "" {
+		t, err = uc.ticketFromQR(ctx, evt.ID, req.QRPayload)
+	} else {
+		t, err = uc.ticketFromCode(ctx, evt.ID, req.AttendanceCode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.checkIn(ctx, t, &ticket.CheckInScan{
+		ID:           scanID(req.ScanID),
+		Gate:         token.Gate,
+		ScannedBy:    token.IssuedBy,
+		KioskTokenID: &token.ID,
+	})
+}
+
+// resolveKioskToken finds the active kiosk token with the secret. The token only
+// works while its issuer may still check people in to the event.
+func (uc *Usecase) resolveKioskToken(ctx context.Context, secret string) (*ticket.KioskToken, *event.Event, error) {
+	if secret == "" {
+		return nil, nil, ErrInvalidKioskToken
+	}
+
+	token, err := uc.ticketRepo.GetKioskTokenByHash(ctx, hashKioskToken(secret))
+	if err != nil || !token.IsActive(time.Now()) {
+		return nil, nil, ErrInvalidKioskToken
+	}
+
+	evt, err := uc.authorizeScanner(ctx, token.EventID, token.IssuedBy)
+	if errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrEventNotFound) {
+		return nil, nil, ErrInvalidKioskToken
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.ticketRepo.TouchKioskToken(ctx, token.ID); err != nil {
+		log.Printf("[TicketUsecase] failed to record use of kiosk token %s: %v", token.ID, err)
+	}
+
+	return token, evt, nil
+}
+
+func generateKioskToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashKioskToken is how kiosk tokens are stored, so a leaked database doesn't leak working tokens
+func hashKioskToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
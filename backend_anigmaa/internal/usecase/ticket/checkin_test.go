@@ -0,0 +1,416 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/google/uuid"
+)
+
+// fakeCheckInRepo adds the scan log to fakeTicketRepo and records scans the way
+// RecordCheckInScan does
+type fakeCheckInRepo struct {
+	*fakeTicketRepo
+	scans map[uuid.UUID]*ticket.CheckInScan
+}
+
+func (r *fakeCheckInRepo) GetByAttendanceCode(ctx context.Context, code string) (*ticket.Ticket, error) {
+	for _, t := range r.tickets {
+		if t.AttendanceCode == code {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeCheckInRepo) GetWithDetails(ctx context.Context, ticketID uuid.UUID) (*ticket.TicketWithDetails, error) {
+	t, ok := r.tickets[ticketID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &ticket.TicketWithDetails{Ticket: *t, UserName: "Sari"}, nil
+}
+
+func (r *fakeCheckInRepo) RecordCheckInScan(ctx context.Context, scan *ticket.CheckInScan) (int, bool, error) {
+	t, ok := r.tickets[scan.TicketID]
+	if !ok {
+		return 0, false, sql.ErrNoRows
+	}
+	if t.Status != ticket.StatusActive {
+		return 0, false, ticket.ErrTicketNotActive
+	}
+	if _, ok := r.scans[scan.ID]; ok {
+		return 0, false, nil
+	}
+
+	demoted := 0
+	scan.Outcome = ticket.CheckInDuplicate
+	if !t.IsCheckedIn || t.CheckedInAt.After(scan.ScannedAt) {
+		at := scan.ScannedAt
+		t.IsCheckedIn = true
+		t.CheckedInAt = &at
+		scan.Outcome = ticket.CheckInAdmitted
+
+		for _, logged := range r.scans {
+			if logged.TicketID == t.ID && logged.Outcome == ticket.CheckInAdmitted && logged.ScannedAt.After(at) {
+				logged.Outcome = ticket.CheckInDuplicate
+				demoted++
+			}
+		}
+	}
+
+	copied := *scan
+	r.scans[scan.ID] = &copied
+	return demoted, true, nil
+}
+
+func (r *fakeCheckInRepo) GetCheckInScan(ctx context.Context, scanID uuid.UUID) (*ticket.CheckInScan, error) {
+	scan, ok := r.scans[scanID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *scan
+	return &copied, nil
+}
+
+func (r *fakeCheckInRepo) GetAdmittingScan(ctx context.Context, ticketID uuid.UUID) (*ticket.CheckInScan, error) {
+	for _, scan := range r.scans {
+		if scan.TicketID == ticketID && scan.Outcome == ticket.CheckInAdmitted {
+			copied := *scan
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeCheckInRepo) GetCheckInStats(ctx context.Context, eventID uuid.UUID) (*ticket.CheckInStats, error) {
+	stats := &ticket.CheckInStats{EventID: eventID}
+	for _, t := range r.tickets {
+		stats.Total++
+		if t.IsCheckedIn {
+			stats.CheckedIn++
+		}
+	}
+
+	gates := make(map[string]*ticket.GateStats)
+	for _, scan := range r.scans {
+		g, ok := gates[scan.Gate]
+		if !ok {
+			g = &ticket.GateStats{Gate: scan.Gate}
+			gates[scan.Gate] = g
+		}
+		if scan.Outcome == ticket.CheckInAdmitted {
+			g.CheckedIn++
+		} else {
+			g.Duplicates++
+		}
+	}
+	stats.Gates = make([]ticket.GateStats, 0, len(gates))
+	for _, g := range gates {
+		stats.Gates = append(stats.Gates, *g)
+	}
+	sort.Slice(stats.Gates, func(i, j int) bool { return stats.Gates[i].Gate < stats.Gates[j].Gate })
+
+	return stats, nil
+}
+
+// fakeStaffEventRepo adds the event's staff to fakeEventRepo
+type fakeStaffEventRepo struct {
+	*fakeEventRepo
+	staff map[uuid.UUID]event.StaffRole
+}
+
+func (r *fakeStaffEventRepo) GetAccess(ctx context.Context, eventID, userID uuid.UUID) (*event.Access, error) {
+	return &event.Access{IsHost: userID == r.evt.HostID, StaffRole: r.staff[userID]}, nil
+}
+
+func (r *fakeStaffEventRepo) GetStaff(ctx context.Context, eventID uuid.UUID) ([]event.StaffWithUser, error) {
+	return nil, nil
+}
+
+// checkInFixture is a ticket fixture that logs scans and has event staff
+type checkInFixture struct {
+	*ticketFixture
+	checkIns *fakeCheckInRepo
+	events   *fakeStaffEventRepo
+}
+
+func newCheckInFixture() *checkInFixture {
+	f := &checkInFixture{ticketFixture: newTicketFixture()}
+	f.checkIns = &fakeCheckInRepo{fakeTicketRepo: f.tickets, scans: make(map[uuid.UUID]*ticket.CheckInScan)}
+	f.events = &fakeStaffEventRepo{fakeEventRepo: f.uc.eventRepo.(*fakeEventRepo), staff: make(map[uuid.UUID]event.StaffRole)}
+	f.uc.ticketRepo = f.checkIns
+	f.uc.eventRepo = f.events
+	return f
+}
+
+// fakeKioskRepo adds kiosk tokens to fakeCheckInRepo
+type fakeKioskRepo struct {
+	*fakeCheckInRepo
+	kioskTokens map[uuid.UUID]*ticket.KioskToken
+}
+
+func (r *fakeKioskRepo) CreateKioskToken(ctx context.Context, token *ticket.KioskToken) error {
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	copied := *token
+	r.kioskTokens[token.ID] = &copied
+	return nil
+}
+
+func (r *fakeKioskRepo) GetKioskTokenByHash(ctx context.Context, tokenHash string) (*ticket.KioskToken, error) {
+	for _, token := range r.kioskTokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeKioskRepo) TouchKioskToken(ctx context.Context, tokenID uuid.UUID) error {
+	now := time.Now()
+	r.kioskTokens[tokenID].LastUsedAt = &now
+	return nil
+}
+
+// newKioskFixture is a check-in fixture whose event has a check-in staff member
+func newKioskFixture() (*checkInFixture, *fakeKioskRepo, uuid.UUID) {
+	f := newCheckInFixture()
+	staffID := uuid.New()
+	f.events.staff[staffID] = event.StaffCheckIn
+
+	kiosk := &fakeKioskRepo{fakeCheckInRepo: f.checkIns, kioskTokens: make(map[uuid.UUID]*ticket.KioskToken)}
+	f.uc.ticketRepo = kiosk
+	return f, kiosk, staffID
+}
+
+func (f *checkInFixture) checkIn(t *testing.T, scanID uuid.UUID, code, gate string) *ticket.CheckInResult {
+	t.Helper()
+	result, err := f.uc.CheckIn(context.Background(), f.evt.ID, f.evt.HostID, &ticket.CheckInRequest{
+		AttendanceCode: code,
+		ScanID:         &scanID,
+		Gate:           gate,
+	})
+	if err != nil {
+		t.Fatalf("CheckIn() error = %v", err)
+	}
+	return result
+}
+
+func TestCheckIn_Replay(t *testing.T) {
+	f := newCheckInFixture()
+	scanID := uuid.New()
+
+	first := f.checkIn(t, scanID, f.active.AttendanceCode, "A")
+	retry := f.checkIn(t, scanID, f.active.AttendanceCode, "B")
+
+	if first.Outcome != ticket.CheckInAdmitted || first.Replayed {
+		t.Errorf("first scan = %s (replayed %v), want checked_in", first.Outcome, first.Replayed)
+	}
+	// A retry that timed out must not turn into a duplicate or move the check-in
+	if retry.Outcome != ticket.CheckInAdmitted || !retry.Replayed || retry.Gate != "A" {
+		t.Errorf("retried scan = %s at gate %q (replayed %v), want the replayed check-in at gate A", retry.Outcome, retry.Gate, retry.Replayed)
+	}
+	if len(f.checkIns.scans) != 1 {
+		t.Errorf("logged %d scans, want 1", len(f.checkIns.scans))
+	}
+}
+
+func TestCheckIn_Duplicate(t *testing.T) {
+	f := newCheckInFixture()
+
+	first := f.checkIn(t, uuid.New(), f.active.AttendanceCode, " A ")
+	second := f.checkIn(t, uuid.New(), f.active.AttendanceCode, "B")
+
+	if second.Outcome != ticket.CheckInDuplicate || second.Replayed {
+		t.Fatalf("second scan = %s (replayed %v), want a new duplicate", second.Outcome, second.Replayed)
+	}
+	if second.FirstGate == nil || *second.FirstGate != "A" {
+		t.Errorf("first gate = %v, want A", second.FirstGate)
+	}
+	if second.FirstCheckedInAt == nil || !second.FirstCheckedInAt.Equal(first.ScannedAt) {
+		t.Errorf("first checked in at = %v, want %v", second.FirstCheckedInAt, first.ScannedAt)
+	}
+}
+
+func TestCheckIn_ScanIDReused(t *testing.T) {
+	f := newCheckInFixture()
+	other := &ticket.Ticket{ID: uuid.New(), EventID: f.evt.ID, AttendanceCode: "QRST2345", Status: ticket.StatusActive}
+	f.tickets.tickets[other.ID] = other
+	scanID := uuid.New()
+
+	f.checkIn(t, scanID, f.active.AttendanceCode, "A")
+	_, err := f.uc.CheckIn(context.Background(), f.evt.ID, f.evt.HostID, &ticket.CheckInRequest{
+		AttendanceCode: other.AttendanceCode,
+		ScanID:         &scanID,
+	})
+	if !errors.Is(err, ErrScanIDReused) {
+		t.Errorf("CheckIn() with a reused scan ID error = %v, want %v", err, ErrScanIDReused)
+	}
+	if other.IsCheckedIn {
+		t.Error("ticket scanned with a reused scan ID was checked in")
+	}
+}
+
+func TestGetCheckInStats_PerGate(t *testing.T) {
+	f, _, staffID := newKioskFixture()
+	second := &ticket.Ticket{ID: uuid.New(), EventID: f.evt.ID, AttendanceCode: "QRST2345", Status: ticket.StatusActive}
+	f.tickets.tickets[second.ID] = second
+
+	f.checkIn(t, uuid.New(), f.active.AttendanceCode, "North")
+	f.checkIn(t, uuid.New(), f.active.AttendanceCode, "South")
+
+	issued, err := f.uc.IssueKioskToken(context.Background(), f.evt.ID, staffID, &ticket.CreateKioskTokenRequest{Gate: "South"})
+	if err != nil {
+		t.Fatalf("IssueKioskToken() error = %v", err)
+	}
+	result, err := f.uc.KioskCheckIn(context.Background(), issued.Token, &ticket.KioskCheckInRequest{AttendanceCode: second.AttendanceCode})
+	if err != nil {
+		t.Fatalf("KioskCheckIn() error = %v", err)
+	}
+	if result.Gate != "South" {
+		t.Errorf("kiosk scan gate = %q, want the token's gate South", result.Gate)
+	}
+
+	stats, err := f.uc.GetCheckInStats(context.Background(), f.evt.ID, staffID)
+	if err != nil {
+		t.Fatalf("GetCheckInStats() error = %v", err)
+	}
+	want := []ticket.GateStats{
+		{Gate: "North", CheckedIn: 1},
+		{Gate: "South", CheckedIn: 1, Duplicates: 1},
+	}
+	if stats.CheckedIn != 2 || !reflect.DeepEqual(stats.Gates, want) {
+		t.Errorf("stats = %d checked in, gates %+v; want 2, %+v", stats.CheckedIn, stats.Gates, want)
+	}
+
+	if _, err := f.uc.GetCheckInStats(context.Background(), f.evt.ID, uuid.New()); err != ErrUnauthorized {
+		t.Errorf("GetCheckInStats() by a stranger error = %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestIssueKioskToken_Expiry(t *testing.T) {
+	at := func(d time.Duration) *time.Time {
+		tm := time.Now().Add(d)
+		return &tm
+	}
+
+	tests := []struct {
+		name      string
+		eventEnd  time.Duration
+		gate      string
+		expiresAt *time.Time
+		want      error
+	}{
+		{"default", 3 * time.Hour, "Main", nil, nil},
+		{"earlier than check-in closes", 3 * time.Hour, "Main", at(time.Hour), nil},
+		{"in the past", 3 * time.Hour, "Main", at(-time.Minute), ErrInvalidKioskExpiry},
+		{"after check-in closes", 3 * time.Hour, "Main", at(3*time.Hour + qrValidityAfterEventEnd + time.Minute), ErrInvalidKioskExpiry},
+		{"check-in closed", -qrValidityAfterEventEnd - time.Minute, "Main", nil, ErrCheckInClosed},
+		{"blank gate", 3 * time.Hour, "  ", nil, ErrGateRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, kiosk, staffID := newKioskFixture()
+			f.evt.EndTime = time.Now().Add(tt.eventEnd)
+
+			issued, err := f.uc.IssueKioskToken(context.Background(), f.evt.ID, staffID, &ticket.CreateKioskTokenRequest{Gate: tt.gate, ExpiresAt: tt.expiresAt})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("IssueKioskToken() error = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if len(kiosk.kioskTokens) != 0 {
+					t.Errorf("stored %d kiosk tokens, want none", len(kiosk.kioskTokens))
+				}
+				return
+			}
+
+			wantExpiry := f.evt.EndTime.Add(qrValidityAfterEventEnd)
+			if tt.expiresAt != nil {
+				wantExpiry = *tt.expiresAt
+			}
+			if !issued.ExpiresAt.Equal(wantExpiry) {
+				t.Errorf("expires at %v, want %v", issued.ExpiresAt, wantExpiry)
+			}
+			stored := kiosk.kioskTokens[issued.ID]
+			if stored == nil || stored.TokenHash != hashKioskToken(issued.Token) || stored.TokenHash == issued.Token {
+				t.Errorf("stored token = %+v, want only the hash of the secret", stored)
+			}
+		})
+	}
+}
+
+func TestKioskCheckIn_Token(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(f *checkInFixture, token *ticket.KioskToken, staffID uuid.UUID)
+		secret func(issued string) string
+		want   error
+	}{
+		{"active", nil, nil, nil},
+		{"revoked", func(f *checkInFixture, token *ticket.KioskToken, staffID uuid.UUID) {
+			now := time.Now()
+			token.RevokedAt = &now
+		}, nil, ErrInvalidKioskToken},
+		{"expired", func(f *checkInFixture, token *ticket.KioskToken, staffID uuid.UUID) {
+			token.ExpiresAt = time.Now().Add(-time.Second)
+		}, nil, ErrInvalidKioskToken},
+		{"issuer demoted", func(f *checkInFixture, token *ticket.KioskToken, staffID uuid.UUID) {
+			f.events.staff[staffID] = event.StaffQnAModerator
+		}, nil, ErrInvalidKioskToken},
+		{"issuer removed", func(f *checkInFixture, token *ticket.KioskToken, staffID uuid.UUID) {
+			delete(f.events.staff, staffID)
+		}, nil, ErrInvalidKioskToken},
+		{"unknown secret", nil, func(string) string { return "not-a-token" }, ErrInvalidKioskToken},
+		{"empty secret", nil, func(string) string { return "" }, ErrInvalidKioskToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, kiosk, staffID := newKioskFixture()
+			issued, err := f.uc.IssueKioskToken(context.Background(), f.evt.ID, staffID, &ticket.CreateKioskTokenRequest{Gate: "East"})
+			if err != nil {
+				t.Fatalf("IssueKioskToken() error = %v", err)
+			}
+			if tt.change != nil {
+				tt.change(f, kiosk.kioskTokens[issued.ID], staffID)
+			}
+			secret := issued.Token
+			if tt.secret != nil {
+				secret = tt.secret(secret)
+			}
+
+			result, err := f.uc.KioskCheckIn(context.Background(), secret, &ticket.KioskCheckInRequest{AttendanceCode: f.active.AttendanceCode})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("KioskCheckIn() error = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if f.tickets.tickets[f.active.ID].IsCheckedIn {
+					t.Error("ticket was checked in with an unusable kiosk token")
+				}
+				return
+			}
+
+			if result.Outcome != ticket.CheckInAdmitted || result.Gate != "East" {
+				t.Errorf("kiosk scan = %s at gate %q, want checked_in at East", result.Outcome, result.Gate)
+			}
+			scan := f.checkIns.scans[result.ScanID]
+			if scan == nil || scan.KioskTokenID == nil || *scan.KioskTokenID != issued.ID || scan.ScannedBy != staffID {
+				t.Errorf("logged scan = %+v, want it attributed to the kiosk token and its issuer", scan)
+			}
+			if kiosk.kioskTokens[issued.ID].LastUsedAt == nil {
+				t.Error("kiosk token use wasn't recorded")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/pkg/qrcode"
+	"github.com/google/uuid"
+)
+
+// fakeTicketRepo keeps tickets in memory. The fakes of the other tests embed it
+// and add what they need.
+type fakeTicketRepo struct {
+	ticket.Repository
+	tickets map[uuid.UUID]*ticket.Ticket
+}
+
+func (r *fakeTicketRepo) GetByID(ctx context.Context, ticketID uuid.UUID) (*ticket.Ticket, error) {
+	t, ok := r.tickets[ticketID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *t
+	return &copied, nil
+}
+
+// fakeEventRepo serves one event, which only its host manages
+type fakeEventRepo struct {
+	event.Repository
+	evt *event.Event
+}
+
+func (r *fakeEventRepo) GetByID(ctx context.Context, eventID uuid.UUID) (*event.Event, error) {
+	if eventID != r.evt.ID {
+		return nil, sql.ErrNoRows
+	}
+	return r.evt, nil
+}
+
+func (r *fakeEventRepo) GetAccess(ctx context.Context, eventID, userID uuid.UUID) (*event.Access, error) {
+	return &event.Access{IsHost: userID == r.evt.HostID}, nil
+}
+
+// ticketFixture is a ticket usecase over an ongoing event with an active and a
+// refunded ticket, both bought the day before
+type ticketFixture struct {
+	uc      *Usecase
+	tickets *fakeTicketRepo
+	evt     *event.Event
+	active  *ticket.Ticket
+}
+
+func newTicketFixture() *ticketFixture {
+	now := time.Now()
+	evt := &event.Event{ID: uuid.New(), HostID: uuid.New(), Title: "Jazz Senja", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(3 * time.Hour)}
+	purchasedAt := now.Add(-24 * time.Hour)
+	active := &ticket.Ticket{ID: uuid.New(), EventID: evt.ID, AttendanceCode: "ABCD2345", Status: ticket.StatusActive, PurchasedAt: purchasedAt}
+	refunded := &ticket.Ticket{ID: uuid.New(), EventID: evt.ID, AttendanceCode: "WXYZ6789", Status: ticket.StatusRefunded, PurchasedAt: purchasedAt}
+
+	tickets := &fakeTicketRepo{tickets: map[uuid.UUID]*ticket.Ticket{active.ID: active, refunded.ID: refunded}}
+	uc := &Usecase{
+		ticketRepo: tickets,
+		eventRepo:  &fakeEventRepo{evt: evt},
+		qrSigner:   qrcode.NewSignerFromSecret("test"),
+	}
+	return &ticketFixture{uc: uc, tickets: tickets, evt: evt, active: active}
+}
@@ -0,0 +1,236 @@
+package ticket
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxScanClockSkew is how far in the future an offline scan may be dated,
+	// to allow for scanners whose clock runs a little fast
+	maxScanClockSkew = 5 * time.Minute
+	// scanWindowBeforeStart is how long before the event starts offline scans
+	// are accepted, for doors that open early
+	scanWindowBeforeStart = 12 * time.Hour
+)
+
+// GetCheckInManifest gets the signed manifest of an event's tickets that
+// scanners use to check people in while offline (host and check-in staff only)
+func (uc *Usecase) GetCheckInManifest(ctx context.Context, eventID, userID uuid.UUID) (*ticket.SignedCheckInManifest, error) {
+	evt, err := uc.authorizeScanner(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.checkInManifest(ctx, evt)
+}
+
+// GetKioskManifest gets the signed manifest of the tickets of a kiosk token's event
+func (uc *Usecase) GetKioskManifest(ctx context.Context, secret string) (*ticket.SignedCheckInManifest, error) {
+	_, evt, err := uc.resolveKioskToken(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	return uc.checkInManifest(ctx, evt)
+}
+
+// checkInManifest lists every ticket of the event and signs the list with the ticket QR key
+func (uc *Usecase) checkInManifest(ctx context.Context, evt *event.Event) (*ticket.SignedCheckInManifest, error) {
+	// One query, so the manifest is a consistent snapshot even while tickets sell
+	tickets, err := uc.ticketRepo.GetManifestTickets(ctx, evt.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event tickets: %w", err)
+	}
+
+	manifest := &ticket.CheckInManifest{
+		EventID:     evt.ID,
+		EventTitle:  evt.Title,
+		GeneratedAt: time.Now().UTC(),
+		ExpiresAt:   evt.EndTime.Add(qrValidityAfterEventEnd).UTC(),
+		Tickets:     make([]ticket.ManifestTicket, 0, len(tickets)),
+	}
+	for _, t := range tickets {
+		manifest.Tickets = append(manifest.Tickets, ticket.ManifestTicket{
+			TicketID:           t.ID,
+			AttendanceCodeHash: hashAttendanceCode(evt.ID, t.AttendanceCode),
+			Status:             t.Status,
+			IsCheckedIn:        t.IsCheckedIn,
+			CheckedInAt:        t.CheckedInAt,
+			HolderName:         t.UserName,
+			TierName:           t.TierName,
+		})
+	}
+
+	signed, err := uc.qrSigner.SignManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ticket.SignedCheckInManifest{
+		Manifest:    signed,
+		Algorithm:   "Ed25519",
+		TicketCount: len(manifest.Tickets),
+		GeneratedAt: manifest.GeneratedAt,
+		ExpiresAt:   manifest.ExpiresAt,
+	}, nil
+}
+
+// hashAttendanceCode hashes an attendance code the way check-in manifests list
+// it. The event ID salts the hash, so hashes can't be compared across events.
+func hashAttendanceCode(eventID uuid.UUID, code string) string {
+	sum := sha256.Sum256([]byte(eventID.String() + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// SyncCheckIns replays the scans a scanner made while offline (host and
+// check-in staff only). Scans without a gate count towards the gate named "".
+func (uc *Usecase) SyncCheckIns(ctx context.Context, eventID, userID uuid.UUID, req *ticket.SyncCheckInsRequest) (*ticket.SyncCheckInsResponse, error) {
+	evt, err := uc.authorizeScanner(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scans := make([]*ticket.CheckInScan, len(req.Scans))
+	for i, s := range req.Scans {
+		scans[i] = &ticket.CheckInScan{
+			ID:        s.ScanID,
+			TicketID:  s.TicketID,
+			Gate:      strings.TrimSpace(s.Gate),
+			ScannedBy: userID,
+			ScannedAt: s.ScannedAt,
+		}
+	}
+
+	return uc.syncScans(ctx, evt, scans)
+}
+
+// SyncKioskCheckIns replays the scans a kiosk made at its token's gate while offline
+func (uc *Usecase) SyncKioskCheckIns(ctx context.Context, secret string, req *ticket.SyncCheckInsRequest) (*ticket.SyncCheckInsResponse, error) {
+	token, evt, err := uc.resolveKioskToken(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	scans := make([]*ticket.CheckInScan, len(req.Scans))
+	for i, s := range req.Scans {
+		scans[i] = &ticket.CheckInScan{
+			ID:           s.ScanID,
+			TicketID:     s.TicketID,
+			Gate:         token.Gate,
+			ScannedBy:    token.IssuedBy,
+			KioskTokenID: &token.ID,
+			ScannedAt:    s.ScannedAt,
+		}
+	}
+
+	return uc.syncScans(ctx, evt, scans)
+}
+
+// syncScans checks tickets in as of the time they were scanned offline, oldest
+// scan first. When several scanners let the same ticket in, the earliest scan
+// keeps the check-in, even if a later one was synced or scanned online before;
+// the others become duplicates. Invalid scans are rejected one by one without
+// failing the batch.
+func (uc *Usecase) syncScans(ctx context.Context, evt *event.Event, scans []*ticket.CheckInScan) (*ticket.SyncCheckInsResponse, error) {
+	resp := &ticket.SyncCheckInsResponse{Scans: make([]ticket.SyncedScan, len(scans))}
+
+	order := make([]int, len(scans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scans[order[a]].ScannedAt.Before(scans[order[b]].ScannedAt)
+	})
+
+	// Scans can't be dated after the manifest they were checked against expired
+	earliest := evt.StartTime.Add(-scanWindowBeforeStart)
+	latest := time.Now().Add(maxScanClockSkew)
+	if closes := evt.EndTime.Add(qrValidityAfterEventEnd); closes.Before(latest) {
+		latest = closes
+	}
+	synced := false
+	for _, i := range order {
+		scan := scans[i]
+		result := &resp.Scans[i]
+		result.ScanID = scan.ID
+		result.TicketID = scan.TicketID
+		result.ScannedAt = scan.ScannedAt
+
+		record, err := uc.syncScan(ctx, evt.ID, scan, earliest, latest)
+		if err != nil {
+			if !isRejectedScan(err) {
+				return nil, err
+			}
+			result.Outcome = ticket.CheckInRejected
+			result.Error = err.Error()
+			resp.Rejected++
+			continue
+		}
+
+		result.Outcome = record.scan.Outcome
+		result.Replayed = record.replayed
+		result.ScannedAt = record.scan.ScannedAt
+		result.Conflict = record.tookOver || record.scan.Outcome == ticket.CheckInDuplicate
+		if record.scan.Outcome == ticket.CheckInDuplicate {
+			result.FirstCheckedInAt, result.FirstGate = uc.firstAdmission(ctx, scan.TicketID, nil)
+			resp.Duplicates++
+		} else {
+			resp.CheckedIn++
+		}
+		synced = synced || !record.replayed
+	}
+
+	stats, err := uc.ticketRepo.GetCheckInStats(ctx, evt.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Stats = stats
+
+	if synced {
+		uc.publishCheckInStats(ctx, evt.ID, stats)
+	}
+
+	return resp, nil
+}
+
+// syncScan validates and records one offline scan. Scans dated before earliest,
+// after latest or before the ticket was bought are rejected.
+func (uc *Usecase) syncScan(ctx context.Context, eventID uuid.UUID, scan *ticket.CheckInScan, earliest, latest time.Time) (*scanRecord, error) {
+	if scan.ScannedAt.Before(earliest) || scan.ScannedAt.After(latest) {
+		return nil, ErrInvalidScanTime
+	}
+
+	t, err := uc.ticketRepo.GetByID(ctx, scan.TicketID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTicketNotFound
+		}
+		return nil, err
+	}
+	if t.EventID != eventID {
+		return nil, ErrTicketNotFound
+	}
+	if scan.ScannedAt.Before(t.PurchasedAt) {
+		return nil, ErrInvalidScanTime
+	}
+
+	return uc.recordScan(ctx, t, scan)
+}
+
+// isRejectedScan reports whether a sync error is about the scan itself rather than the server
+func isRejectedScan(err error) bool {
+	return errors.Is(err, ErrInvalidScanTime) ||
+		errors.Is(err, ErrTicketNotFound) ||
+		errors.Is(err, ErrTicketNotActive) ||
+		errors.Is(err, ErrScanIDReused)
+}
@@ -0,0 +1,223 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/pkg/qrcode"
+	"github.com/google/uuid"
+)
+
+func (r *fakeCheckInRepo) GetManifestTickets(ctx context.Context, eventID uuid.UUID) ([]ticket.TicketWithDetails, error) {
+	tickets := make([]ticket.TicketWithDetails, 0)
+	for _, t := range r.tickets {
+		if t.EventID == eventID {
+			tickets = append(tickets, ticket.TicketWithDetails{Ticket: *t, UserName: "Sari"})
+		}
+	}
+	return tickets, nil
+}
+
+func (f *checkInFixture) sync(t *testing.T, scans ...ticket.OfflineScan) *ticket.SyncCheckInsResponse {
+	t.Helper()
+	resp, err := f.uc.SyncCheckIns(context.Background(), f.evt.ID, f.evt.HostID, &ticket.SyncCheckInsRequest{Scans: scans})
+	if err != nil {
+		t.Fatalf("SyncCheckIns() error = %v", err)
+	}
+	return resp
+}
+
+func TestSyncCheckIns_EarliestScanWins(t *testing.T) {
+	f := newCheckInFixture()
+	base := time.Now().Add(-time.Hour)
+
+	// Two scanners let the same ticket in; the later scan is uploaded first
+	later := ticket.OfflineScan{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: base.Add(2 * time.Minute), Gate: "B"}
+	earlier := ticket.OfflineScan{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: base, Gate: "A"}
+	resp := f.sync(t, later, earlier)
+
+	if got := resp.Scans[0]; got.Outcome != ticket.CheckInDuplicate || !got.Conflict || got.FirstGate == nil || *got.FirstGate != "A" {
+		t.Errorf("later scan = %+v, want a conflicting duplicate first let in at gate A", got)
+	}
+	if got := resp.Scans[1]; got.Outcome != ticket.CheckInAdmitted || got.Conflict {
+		t.Errorf("earlier scan = %+v, want checked in without conflict", got)
+	}
+	if resp.CheckedIn != 1 || resp.Duplicates != 1 || resp.Stats == nil || resp.Stats.CheckedIn != 1 {
+		t.Errorf("counts = %d checked in, %d duplicates, stats %+v", resp.CheckedIn, resp.Duplicates, resp.Stats)
+	}
+}
+
+func TestSyncCheckIns_TakesOverLaterCheckIn(t *testing.T) {
+	f := newCheckInFixture()
+	base := time.Now().Add(-time.Hour)
+
+	online := f.sync(t, ticket.OfflineScan{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: base.Add(5 * time.Minute), Gate: "B"})
+	if online.Scans[0].Outcome != ticket.CheckInAdmitted {
+		t.Fatalf("first sync outcome = %s, want checked_in", online.Scans[0].Outcome)
+	}
+
+	// An offline scanner that let the ticket in earlier syncs afterwards
+	offline := f.sync(t, ticket.OfflineScan{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: base, Gate: "A"})
+	if got := offline.Scans[0]; got.Outcome != ticket.CheckInAdmitted || !got.Conflict {
+		t.Errorf("earlier scan = %+v, want a conflicting check-in", got)
+	}
+	if got := f.tickets.tickets[f.active.ID].CheckedInAt; got == nil || !got.Equal(base) {
+		t.Errorf("checked_in_at = %v, want %v", got, base)
+	}
+	if got := f.checkIns.scans[online.Scans[0].ScanID].Outcome; got != ticket.CheckInDuplicate {
+		t.Errorf("taken over scan outcome = %s, want duplicate", got)
+	}
+}
+
+func TestSyncCheckIns_Replay(t *testing.T) {
+	f := newCheckInFixture()
+	scan := ticket.OfflineScan{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: time.Now().Add(-time.Minute)}
+
+	first := f.sync(t, scan)
+	retry := f.sync(t, scan)
+
+	if got := retry.Scans[0]; got.Outcome != first.Scans[0].Outcome || !got.Replayed {
+		t.Errorf("retried scan = %+v, want replayed %s", got, first.Scans[0].Outcome)
+	}
+	if retry.Duplicates != 0 {
+		t.Errorf("retry counted %d duplicates, want 0", retry.Duplicates)
+	}
+}
+
+func TestSyncCheckIns_Rejected(t *testing.T) {
+	f := newCheckInFixture()
+	var refundedID uuid.UUID
+	for id, tk := range f.tickets.tickets {
+		if tk.Status == ticket.StatusRefunded {
+			refundedID = id
+		}
+	}
+	reused := uuid.New()
+	f.sync(t, ticket.OfflineScan{ScanID: reused, TicketID: f.active.ID, ScannedAt: time.Now().Add(-time.Minute)})
+
+	tests := []struct {
+		name string
+		scan ticket.OfflineScan
+		want error
+	}{
+		{"future", ticket.OfflineScan{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: time.Now().Add(time.Hour)}, ErrInvalidScanTime},
+		{"unknown ticket", ticket.OfflineScan{ScanID: uuid.New(), TicketID: uuid.New(), ScannedAt: time.Now()}, ErrTicketNotFound},
+		{"refunded", ticket.OfflineScan{ScanID: uuid.New(), TicketID: refundedID, ScannedAt: time.Now()}, ErrTicketNotActive},
+		{"reused scan ID", ticket.OfflineScan{ScanID: reused, TicketID: refundedID, ScannedAt: time.Now()}, ErrScanIDReused},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := f.sync(t, tt.scan)
+			if got := resp.Scans[0]; got.Outcome != ticket.CheckInRejected || got.Error != tt.want.Error() {
+				t.Errorf("scan = %+v, want rejected with %q", got, tt.want)
+			}
+			if resp.Rejected != 1 {
+				t.Errorf("rejected = %d, want 1", resp.Rejected)
+			}
+		})
+	}
+}
+
+func TestSyncCheckIns_ScanWindow(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(f *checkInFixture)
+		at     func(f *checkInFixture) time.Time
+		want   error
+	}{
+		{"during the event", nil, func(f *checkInFixture) time.Time { return time.Now().Add(-time.Hour) }, nil},
+		{"as doors open", nil, func(f *checkInFixture) time.Time { return f.evt.StartTime.Add(-time.Hour) }, nil},
+		{"long before the event", nil, func(f *checkInFixture) time.Time {
+			return f.evt.StartTime.Add(-scanWindowBeforeStart - time.Minute)
+		}, ErrInvalidScanTime},
+		{"before the purchase", func(f *checkInFixture) {
+			f.active.PurchasedAt = time.Now().Add(-30 * time.Minute)
+		}, func(f *checkInFixture) time.Time { return time.Now().Add(-time.Hour) }, ErrInvalidScanTime},
+		{"after the manifest expired", func(f *checkInFixture) {
+			f.evt.StartTime = time.Now().Add(-16 * time.Hour)
+			f.evt.EndTime = time.Now().Add(-13 * time.Hour)
+		}, func(f *checkInFixture) time.Time { return time.Now().Add(-30 * time.Minute) }, ErrInvalidScanTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCheckInFixture()
+			if tt.change != nil {
+				tt.change(f)
+			}
+
+			resp := f.sync(t, ticket.OfflineScan{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: tt.at(f)})
+			got := resp.Scans[0]
+			if tt.want != nil {
+				if got.Outcome != ticket.CheckInRejected || got.Error != tt.want.Error() {
+					t.Errorf("scan = %+v, want rejected with %q", got, tt.want)
+				}
+				return
+			}
+			if got.Outcome != ticket.CheckInAdmitted {
+				t.Errorf("scan = %+v, want checked in", got)
+			}
+		})
+	}
+}
+
+// fakeFailingTicketRepo fails to read tickets, as when the database is down
+type fakeFailingTicketRepo struct {
+	*fakeCheckInRepo
+}
+
+func (r *fakeFailingTicketRepo) GetByID(ctx context.Context, ticketID uuid.UUID) (*ticket.Ticket, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestSyncCheckIns_FailsOnServerError(t *testing.T) {
+	f := newCheckInFixture()
+	f.uc.ticketRepo = &fakeFailingTicketRepo{fakeCheckInRepo: f.checkIns}
+
+	// A scan that couldn't be looked up must not be reported as rejected, or
+	// the scanner would drop it instead of retrying
+	_, err := f.uc.SyncCheckIns(context.Background(), f.evt.ID, f.evt.HostID, &ticket.SyncCheckInsRequest{
+		Scans: []ticket.OfflineScan{{ScanID: uuid.New(), TicketID: f.active.ID, ScannedAt: time.Now()}},
+	})
+	if err == nil || errors.Is(err, ErrTicketNotFound) {
+		t.Errorf("SyncCheckIns() error = %v, want the database error", err)
+	}
+}
+
+func TestGetCheckInManifest(t *testing.T) {
+	f := newCheckInFixture()
+
+	signed, err := f.uc.GetCheckInManifest(context.Background(), f.evt.ID, f.evt.HostID)
+	if err != nil {
+		t.Fatalf("GetCheckInManifest() error = %v", err)
+	}
+
+	var manifest ticket.CheckInManifest
+	if err := qrcode.DecodeManifest(signed.Manifest, f.uc.qrSigner.PublicKey(), &manifest); err != nil {
+		t.Fatalf("DecodeManifest() error = %v", err)
+	}
+	if manifest.EventID != f.evt.ID || len(manifest.Tickets) != 2 || signed.TicketCount != 2 {
+		t.Fatalf("manifest = %+v, want both tickets of event %s", manifest, f.evt.ID)
+	}
+
+	for _, mt := range manifest.Tickets {
+		if mt.TicketID != f.active.ID {
+			continue
+		}
+		if want := hashAttendanceCode(f.evt.ID, "ABCD2345"); mt.AttendanceCodeHash != want {
+			t.Errorf("attendance code hash = %s, want %s", mt.AttendanceCodeHash, want)
+		}
+	}
+
+	// A manifest must never pass as a ticket QR
+	if _, err := qrcode.DecodeTicketQR(signed.Manifest, f.uc.qrSigner.PublicKey()); err == nil {
+		t.Error("manifest decoded as a ticket QR")
+	}
+
+	if _, err := f.uc.GetCheckInManifest(context.Background(), f.evt.ID, uuid.New()); err != ErrUnauthorized {
+		t.Errorf("GetCheckInManifest() by a stranger error = %v, want %v", err, ErrUnauthorized)
+	}
+}
@@ -0,0 +1,213 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/google/uuid"
+)
+
+// normalizePromoCode makes promo codes case-insensitive; they are stored upper-case
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// resolvePromoCode looks up a promo code and checks that it can be used for a ticket
+// of the given tier and price. Usage caps are enforced by AtomicPurchase.
+func (uc *Usecase) resolvePromoCode(ctx context.Context, eventID uuid.UUID, code string, tier *ticket.Tier, price float64) (*ticket.PromoCode, error) {
+	promo, err := uc.ticketRepo.GetPromoCodeByCode(ctx, eventID, normalizePromoCode(code))
+	if err != nil {
+		return nil, ErrPromoCodeNotFound
+	}
+
+	var tierID *uuid.UUID
+	if tier != nil {
+		tierID = &tier.ID
+	}
+
+	if !promo.IsValidAt(time.Now()) || !promo.AppliesTo(tierID) || price <= 0 {
+		return nil, ErrPromoCodeInvalid
+	}
+
+	return promo, nil
+}
+
+// QuotePromoCode previews the price of a ticket after applying a promo code.
+// It runs the same checks as PurchaseTicket, including the usage caps, but
+// reserves nothing: a code may still run out before the purchase.
+func (uc *Usecase) QuotePromoCode(ctx context.Context, eventID, userID uuid.UUID, req *ticket.ApplyPromoCodeRequest) (*ticket.PromoCodeQuote, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if err := event.CheckAccess(ctx, uc.eventRepo, evt, userID); err != nil {
+		return nil, err
+	}
+
+	tier, err := uc.resolvePurchaseTier(ctx, &ticket.PurchaseTicketRequest{EventID: eventID, TierID: req.TierID})
+	if err != nil {
+		return nil, err
+	}
+
+	price := ticketPrice(evt, tier)
+	promo, err := uc.resolvePromoCode(ctx, eventID, req.Code, tier, price)
+	if err != nil {
+		return nil, err
+	}
+
+	if promo.MaxUses != nil && promo.UsedCount >= *promo.MaxUses {
+		return nil, ErrPromoCodeExhausted
+	}
+
+	usedByUser, err := uc.ticketRepo.CountUserPromoCodeUses(ctx, promo.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if usedByUser >= promo.MaxUsesPerUser {
+		return nil, ErrPromoCodeLimitReached
+	}
+
+	discount := promo.DiscountFor(price)
+	return &ticket.PromoCodeQuote{
+		Code:           promo.Code,
+		OriginalPrice:  price,
+		DiscountAmount: discount,
+		FinalPrice:     price - discount,
+	}, nil
+}
+
+// CreatePromoCode creates a promo code for an event (host only)
+func (uc *Usecase) CreatePromoCode(ctx context.Context, eventID, hostID uuid.UUID, req *ticket.CreatePromoCodeRequest) (*ticket.PromoCode, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, err
+	}
+
+	code := normalizePromoCode(req.Code)
+	if _, err := uc.ticketRepo.GetPromoCodeByCode(ctx, eventID, code); err == nil {
+		return nil, ErrPromoCodeExists
+	}
+
+	if req.TierID != nil {
+		tier, err := uc.ticketRepo.GetTierByID(ctx, *req.TierID)
+		if err != nil || tier.EventID != eventID {
+			return nil, ErrTierNotFound
+		}
+	}
+
+	promo := &ticket.PromoCode{
+		EventID:        eventID,
+		TierID:         req.TierID,
+		Code:           code,
+		DiscountType:   req.DiscountType,
+		DiscountValue:  req.DiscountValue,
+		MaxUses:        req.MaxUses,
+		MaxUsesPerUser: req.MaxUsesPerUser,
+		ValidFrom:      req.ValidFrom,
+		ValidUntil:     req.ValidUntil,
+		IsActive:       true,
+	}
+	if promo.MaxUsesPerUser <= 0 {
+		promo.MaxUsesPerUser = 1
+	}
+
+	if err := validatePromoCode(promo); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ticketRepo.CreatePromoCode(ctx, promo); err != nil {
+		return nil, err
+	}
+
+	return promo, nil
+}
+
+// GetEventPromoCodes gets the promo codes of an event with their usage (host only)
+func (uc *Usecase) GetEventPromoCodes(ctx context.Context, eventID, hostID uuid.UUID) ([]ticket.PromoCode, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, err
+	}
+
+	return uc.ticketRepo.GetPromoCodesByEvent(ctx, eventID)
+}
+
+// UpdatePromoCode updates a promo code (host only).
+// Tickets already bought keep the discount they were sold with.
+func (uc *Usecase) UpdatePromoCode(ctx context.Context, eventID, promoID, hostID uuid.UUID, req *ticket.UpdatePromoCodeRequest) (*ticket.PromoCode, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, err
+	}
+
+	promo, err := uc.ticketRepo.GetPromoCodeByID(ctx, promoID)
+	if err != nil || promo.EventID != eventID {
+		return nil, ErrPromoCodeNotFound
+	}
+
+	if req.DiscountType != nil {
+		promo.DiscountType = *req.DiscountType
+	}
+	if req.DiscountValue != nil {
+		promo.DiscountValue = *req.DiscountValue
+	}
+	if req.MaxUses != nil {
+		promo.MaxUses = req.MaxUses
+	}
+	if req.MaxUsesPerUser != nil {
+		promo.MaxUsesPerUser = *req.MaxUsesPerUser
+	}
+	if req.ValidFrom != nil {
+		promo.ValidFrom = req.ValidFrom
+	}
+	if req.ValidUntil != nil {
+		promo.ValidUntil = req.ValidUntil
+	}
+	if req.IsActive != nil {
+		promo.IsActive = *req.IsActive
+	}
+
+	if err := validatePromoCode(promo); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ticketRepo.UpdatePromoCode(ctx, promo); err != nil {
+		return nil, err
+	}
+
+	return promo, nil
+}
+
+// DeletePromoCode deletes a promo code (host only)
+func (uc *Usecase) DeletePromoCode(ctx context.Context, eventID, promoID, hostID uuid.UUID) error {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return err
+	}
+
+	promo, err := uc.ticketRepo.GetPromoCodeByID(ctx, promoID)
+	if err != nil || promo.EventID != eventID {
+		return ErrPromoCodeNotFound
+	}
+
+	if err := uc.ticketRepo.DeletePromoCode(ctx, promoID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPromoCodeNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func validatePromoCode(promo *ticket.PromoCode) error {
+	if promo.DiscountType == ticket.DiscountPercentage && promo.DiscountValue > 100 {
+		return fmt.Errorf("%w: percentage discount can't exceed 100", ErrInvalidPromoCode)
+	}
+	if promo.ValidFrom != nil && promo.ValidUntil != nil && !promo.ValidUntil.After(*promo.ValidFrom) {
+		return fmt.Errorf("%w: valid_until must be after valid_from", ErrInvalidPromoCode)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/internal/infrastructure/payment"
+	"github.com/google/uuid"
+)
+
+// fakePromoRepo adds promo codes, with their usage caps, to fakePurchaseRepo
+type fakePromoRepo struct {
+	*fakePurchaseRepo
+	promos       map[string]*ticket.PromoCode
+	transactions []ticket.TicketTransaction
+}
+
+func (r *fakePromoRepo) GetPromoCodeByCode(ctx context.Context, eventID uuid.UUID, code string) (*ticket.PromoCode, error) {
+	promo, ok := r.promos[code]
+	if !ok || promo.EventID != eventID {
+		return nil, sql.ErrNoRows
+	}
+	copied := *promo
+	return &copied, nil
+}
+
+func (r *fakePromoRepo) CountUserPromoCodeUses(ctx context.Context, promoID, userID uuid.UUID) (int, error) {
+	used := 0
+	for _, t := range r.tickets {
+		if t.PromoCodeID != nil && *t.PromoCodeID == promoID && t.UserID == userID {
+			used++
+		}
+	}
+	return used, nil
+}
+
+func (r *fakePromoRepo) AtomicPurchase(ctx context.Context, t *ticket.Ticket) error {
+	var promo *ticket.PromoCode
+	if t.PromoCodeID != nil {
+		for _, p := range r.promos {
+			if p.ID == *t.PromoCodeID {
+				promo = p
+			}
+		}
+		if promo.MaxUses != nil && promo.UsedCount >= *promo.MaxUses {
+			return ticket.ErrPromoCodeExhausted
+		}
+		if used, _ := r.CountUserPromoCodeUses(ctx, promo.ID, t.UserID); used >= promo.MaxUsesPerUser {
+			return ticket.ErrPromoCodeLimitReached
+		}
+	}
+
+	if err := r.fakePurchaseRepo.AtomicPurchase(ctx, t); err != nil {
+		return err
+	}
+	if promo != nil {
+		promo.UsedCount++
+	}
+	return nil
+}
+
+func (r *fakePromoRepo) CreateTransaction(ctx context.Context, tx *ticket.TicketTransaction) error {
+	r.transactions = append(r.transactions, *tx)
+	return nil
+}
+
+// fakeCheckoutGateway records the charges it is asked to create
+type fakeCheckoutGateway struct {
+	payment.Gateway
+	charges []payment.ChargeRequest
+}
+
+func (g *fakeCheckoutGateway) CreateCharge(ctx context.Context, req *payment.ChargeRequest) (*payment.ChargeResponse, error) {
+	g.charges = append(g.charges, *req)
+	return &payment.ChargeResponse{Token: "snap-token", RedirectURL: "https://pay.example/snap"}, nil
+}
+
+func newPromoFixture(tier *ticket.Tier, promos ...*ticket.PromoCode) (*ticketFixture, *fakePromoRepo) {
+	f, purchases := newPurchaseFixture(tier)
+	repo := &fakePromoRepo{fakePurchaseRepo: purchases, promos: make(map[string]*ticket.PromoCode)}
+	for _, promo := range promos {
+		promo.ID = uuid.New()
+		promo.EventID = f.evt.ID
+		promo.IsActive = true
+		if promo.MaxUsesPerUser == 0 {
+			promo.MaxUsesPerUser = 1
+		}
+		repo.promos[promo.Code] = promo
+	}
+	f.uc.ticketRepo = repo
+	return f, repo
+}
+
+func TestQuotePromoCode(t *testing.T) {
+	tier := &ticket.Tier{ID: uuid.New(), Name: "Reguler", Price: 99999, Quota: 100, MaxPerUser: 1}
+	otherTier := uuid.New()
+	past := time.Now().Add(-time.Hour)
+	one := 1
+
+	tests := []struct {
+		name         string
+		code         string
+		wantDiscount float64
+		wantErr      error
+	}{
+		{name: "percentage, rounded to whole rupiah", code: "early15", wantDiscount: 15000},
+		{name: "fixed, capped at the price", code: "FREE", wantDiscount: 99999},
+		{name: "unknown", code: "NOPE", wantErr: ErrPromoCodeNotFound},
+		{name: "expired", code: "LAPSED", wantErr: ErrPromoCodeInvalid},
+		{name: "for another tier", code: "VIPONLY", wantErr: ErrPromoCodeInvalid},
+		{name: "used up", code: "ONCE", wantErr: ErrPromoCodeExhausted},
+	}
+
+	f, _ := newPromoFixture(tier,
+		&ticket.PromoCode{Code: "EARLY15", DiscountType: ticket.DiscountPercentage, DiscountValue: 15},
+		&ticket.PromoCode{Code: "FREE", DiscountType: ticket.DiscountFixed, DiscountValue: 250000},
+		&ticket.PromoCode{Code: "LAPSED", DiscountType: ticket.DiscountFixed, DiscountValue: 10000, ValidUntil: &past},
+		&ticket.PromoCode{Code: "VIPONLY", DiscountType: ticket.DiscountFixed, DiscountValue: 10000, TierID: &otherTier},
+		&ticket.PromoCode{Code: "ONCE", DiscountType: ticket.DiscountFixed, DiscountValue: 10000, MaxUses: &one, UsedCount: 1},
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := f.uc.QuotePromoCode(context.Background(), f.evt.ID, uuid.New(), &ticket.ApplyPromoCodeRequest{Code: tt.code, TierID: &tier.ID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("QuotePromoCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if quote.DiscountAmount != tt.wantDiscount || quote.FinalPrice != tier.Price-tt.wantDiscount {
+				t.Errorf("quote = %+v, want a discount of %.0f", quote, tt.wantDiscount)
+			}
+		})
+	}
+}
+
+func TestPurchaseTicket_PromoCode(t *testing.T) {
+	tier := &ticket.Tier{ID: uuid.New(), Name: "Reguler", Price: 100000, Quota: 100, MaxPerUser: 3}
+	promo := &ticket.PromoCode{Code: "TEMAN20", DiscountType: ticket.DiscountPercentage, DiscountValue: 20}
+	f, repo := newPromoFixture(tier, promo)
+	gateway := &fakeCheckoutGateway{}
+	f.uc.gateway = gateway
+	buyer := uuid.New()
+	code := "teman20"
+	req := &ticket.PurchaseTicketRequest{EventID: f.evt.ID, TierID: &tier.ID, PromoCode: &code}
+
+	resp, err := f.uc.PurchaseTicket(context.Background(), buyer, req)
+	if err != nil {
+		t.Fatalf("PurchaseTicket() error = %v", err)
+	}
+	if resp.Ticket.PricePaid != 80000 || resp.Ticket.DiscountAmount != 20000 {
+		t.Errorf("ticket paid %.0f with a discount of %.0f, want 80000 and 20000", resp.Ticket.PricePaid, resp.Ticket.DiscountAmount)
+	}
+
+	// The discounted amount is what the buyer is charged and what the
+	// payment callback later checks the provider's amount against
+	if len(gateway.charges) != 1 || gateway.charges[0].Amount != 80000 || gateway.charges[0].Items[0].Price != 80000 {
+		t.Errorf("charges = %+v, want one of 80000", gateway.charges)
+	}
+	if len(repo.transactions) != 1 || repo.transactions[0].Amount != 80000 {
+		t.Errorf("transactions = %+v, want one of 80000", repo.transactions)
+	}
+
+	if _, err := f.uc.PurchaseTicket(context.Background(), buyer, req); !errors.Is(err, ErrPromoCodeLimitReached) {
+		t.Errorf("second purchase error = %v, want %v", err, ErrPromoCodeLimitReached)
+	}
+	if promo.UsedCount != 1 {
+		t.Errorf("promo used %d times, want 1", promo.UsedCount)
+	}
+}
+
+func TestValidatePromoCode(t *testing.T) {
+	from := time.Now()
+	until := from.Add(-time.Minute)
+
+	tests := []struct {
+		name  string
+		promo ticket.PromoCode
+		valid bool
+	}{
+		{"percentage", ticket.PromoCode{DiscountType: ticket.DiscountPercentage, DiscountValue: 100}, true},
+		{"percentage over 100", ticket.PromoCode{DiscountType: ticket.DiscountPercentage, DiscountValue: 101}, false},
+		{"fixed over 100", ticket.PromoCode{DiscountType: ticket.DiscountFixed, DiscountValue: 50000}, true},
+		{"ends before it starts", ticket.PromoCode{DiscountType: ticket.DiscountFixed, DiscountValue: 1, ValidFrom: &from, ValidUntil: &until}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePromoCode(&tt.promo)
+			if tt.valid && err != nil {
+				t.Errorf("validatePromoCode() error = %v, want none", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidPromoCode) {
+				t.Errorf("validatePromoCode() error = %v, want %v", err, ErrInvalidPromoCode)
+			}
+		})
+	}
+}
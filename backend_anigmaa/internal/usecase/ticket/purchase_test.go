@@ -0,0 +1,156 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// fakePurchaseRepo sells the tiers it holds and enforces the purchase limits
+// the way AtomicPurchase does
+type fakePurchaseRepo struct {
+	*fakeTicketRepo
+	tiers map[uuid.UUID]*ticket.Tier
+}
+
+func (r *fakePurchaseRepo) GetTiersByEvent(ctx context.Context, eventID uuid.UUID) ([]ticket.Tier, error) {
+	var tiers []ticket.Tier
+	for _, tier := range r.tiers {
+		if tier.EventID == eventID {
+			tiers = append(tiers, *tier)
+		}
+	}
+	return tiers, nil
+}
+
+func (r *fakePurchaseRepo) GetTierByID(ctx context.Context, tierID uuid.UUID) (*ticket.Tier, error) {
+	tier, ok := r.tiers[tierID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *tier
+	return &copied, nil
+}
+
+// GetUserTicketForEvent never finds a ticket, as for purchases racing each
+// other past the fast-path check
+func (r *fakePurchaseRepo) GetUserTicketForEvent(ctx context.Context, userID, eventID uuid.UUID) (*ticket.Ticket, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakePurchaseRepo) AtomicPurchase(ctx context.Context, t *ticket.Ticket) error {
+	held := 0
+	for _, other := range r.tickets {
+		if other.UserID != t.UserID || other.EventID != t.EventID {
+			continue
+		}
+		if !other.HoldsSpot() {
+			continue
+		}
+		if t.TierID == nil && other.TierID == nil {
+			return ticket.ErrAlreadyPurchased
+		}
+		if t.TierID != nil && other.TierID != nil && *other.TierID == *t.TierID {
+			held++
+		}
+	}
+	if t.TierID != nil {
+		tier := r.tiers[*t.TierID]
+		if held >= tier.MaxPerUser {
+			return ticket.ErrTierLimitReached
+		}
+		tier.Sold++
+	}
+
+	copied := *t
+	r.tickets[t.ID] = &copied
+	return nil
+}
+
+type fakePurchaseEventRepo struct {
+	*fakeEventRepo
+}
+
+func (r *fakePurchaseEventRepo) Join(ctx context.Context, attendee *event.EventAttendee) error {
+	return nil
+}
+
+type fakePurchaseUserRepo struct {
+	user.Repository
+}
+
+func (r *fakePurchaseUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return &user.User{ID: id, Name: "Sari"}, nil
+}
+
+func (r *fakePurchaseUserRepo) IncrementEventsAttended(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+func newPurchaseFixture(tiers ...*ticket.Tier) (*ticketFixture, *fakePurchaseRepo) {
+	f := newTicketFixture()
+	f.evt.Privacy = event.PrivacyPublic
+	f.evt.IsFree = true
+	f.evt.MaxAttendees = 100
+
+	repo := &fakePurchaseRepo{fakeTicketRepo: f.tickets, tiers: make(map[uuid.UUID]*ticket.Tier)}
+	for _, tier := range tiers {
+		tier.EventID = f.evt.ID
+		repo.tiers[tier.ID] = tier
+	}
+	f.uc.ticketRepo = repo
+	f.uc.eventRepo = &fakePurchaseEventRepo{fakeEventRepo: &fakeEventRepo{evt: f.evt}}
+	f.uc.userRepo = &fakePurchaseUserRepo{}
+	return f, repo
+}
+
+func TestPurchaseTicket_TierLimit(t *testing.T) {
+	tier := &ticket.Tier{ID: uuid.New(), Name: "Group", Quota: 10, MaxPerUser: 2}
+	f, _ := newPurchaseFixture(tier)
+	buyer := uuid.New()
+	req := &ticket.PurchaseTicketRequest{EventID: f.evt.ID, TierID: &tier.ID}
+
+	for i := 1; i <= 2; i++ {
+		if _, err := f.uc.PurchaseTicket(context.Background(), buyer, req); err != nil {
+			t.Fatalf("purchase %d error = %v, want the ticket", i, err)
+		}
+	}
+	if _, err := f.uc.PurchaseTicket(context.Background(), buyer, req); !errors.Is(err, ErrTierLimitReached) {
+		t.Errorf("purchase 3 error = %v, want %v", err, ErrTierLimitReached)
+	}
+	if tier.Sold != 2 {
+		t.Errorf("tier sold = %d, want 2", tier.Sold)
+	}
+
+	// The limit is per buyer
+	if _, err := f.uc.PurchaseTicket(context.Background(), uuid.New(), req); err != nil {
+		t.Errorf("purchase by another buyer error = %v, want the ticket", err)
+	}
+}
+
+func TestPurchaseTicket_AlreadyPurchased(t *testing.T) {
+	f, _ := newPurchaseFixture()
+	buyer := uuid.New()
+	req := &ticket.PurchaseTicketRequest{EventID: f.evt.ID}
+
+	if _, err := f.uc.PurchaseTicket(context.Background(), buyer, req); err != nil {
+		t.Fatalf("first purchase error = %v, want the ticket", err)
+	}
+	if _, err := f.uc.PurchaseTicket(context.Background(), buyer, req); err != ErrAlreadyPurchased {
+		t.Errorf("second purchase error = %v, want %v", err, ErrAlreadyPurchased)
+	}
+
+	// Once the ticket is cancelled the buyer can get another one
+	for _, held := range f.tickets.tickets {
+		held.Status = ticket.StatusCancelled
+	}
+	if _, err := f.uc.PurchaseTicket(context.Background(), buyer, req); err != nil {
+		t.Errorf("purchase after cancelling error = %v, want the ticket", err)
+	}
+}
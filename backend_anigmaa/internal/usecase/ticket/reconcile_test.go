@@ -0,0 +1,148 @@
+package ticket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/internal/infrastructure/payment"
+)
+
+// fakeReconcileRepo hands out the pending transaction and keeps the report
+type fakeReconcileRepo struct {
+	*fakeRefundRepo
+	claimedAfter  time.Time
+	claimedBefore time.Time
+	entries       []ticket.ReconciliationEntry
+}
+
+func (r *fakeReconcileRepo) ClaimPendingTransactions(ctx context.Context, createdAfter, createdBefore time.Time, limit int) ([]ticket.TicketTransaction, error) {
+	r.claimedAfter, r.claimedBefore = createdAfter, createdBefore
+	if r.transaction.Status != ticket.TransactionPending {
+		return nil, nil
+	}
+	return []ticket.TicketTransaction{*r.transaction}, nil
+}
+
+func (r *fakeReconcileRepo) UpdateTransactionStatus(ctx context.Context, transactionID string, status ticket.TransactionStatus) error {
+	if r.transaction.Status != ticket.TransactionPending {
+		return ticket.ErrAlreadyProcessed
+	}
+	r.transaction.Status = status
+	return nil
+}
+
+func (r *fakeReconcileRepo) CreateReconciliationEntry(ctx context.Context, entry *ticket.ReconciliationEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+// fakeChargeGateway reports a fixed provider status for every charge
+type fakeChargeGateway struct {
+	payment.Gateway
+	status payment.ChargeStatus
+	amount string
+	calls  int
+}
+
+func (g *fakeChargeGateway) GetChargeStatus(ctx context.Context, orderID string) (*payment.ChargeStatusResult, error) {
+	g.calls++
+	return &payment.ChargeStatusResult{OrderID: orderID, Status: g.status, ProviderStatus: string(g.status), GrossAmount: g.amount}, nil
+}
+
+func TestReconcilePendingPayments(t *testing.T) {
+	tests := []struct {
+		name            string
+		ticketStatus    ticket.TicketStatus
+		charge          payment.ChargeStatus
+		amount          string
+		wantTransaction ticket.TransactionStatus
+		wantDiscrepancy ticket.Discrepancy
+	}{
+		{
+			name:            "still pending at the provider",
+			ticketStatus:    ticket.StatusPending,
+			charge:          payment.ChargePending,
+			amount:          "150000.00",
+			wantTransaction: ticket.TransactionPending,
+		},
+		{
+			name:            "paid after the ticket expired",
+			ticketStatus:    ticket.StatusExpired,
+			charge:          payment.ChargeSettled,
+			amount:          "150000.00",
+			wantTransaction: ticket.TransactionPending,
+			wantDiscrepancy: ticket.DiscrepancyPaidAfterExpiry,
+		},
+		{
+			name:            "expired at the provider after the ticket expired",
+			ticketStatus:    ticket.StatusExpired,
+			charge:          payment.ChargeExpired,
+			amount:          "150000.00",
+			wantTransaction: ticket.TransactionFailed,
+		},
+		{
+			name:            "provider amount differs",
+			ticketStatus:    ticket.StatusPending,
+			charge:          payment.ChargeSettled,
+			amount:          "1.00",
+			wantTransaction: ticket.TransactionPending,
+			wantDiscrepancy: ticket.DiscrepancyAmountMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, refunds := newRefundFixture()
+			refunds.transaction.Status = ticket.TransactionPending
+			f.tickets.tickets[f.active.ID].Status = tt.ticketStatus
+			repo := &fakeReconcileRepo{fakeRefundRepo: refunds}
+			f.uc.ticketRepo = repo
+			gateway := &fakeChargeGateway{status: tt.charge, amount: tt.amount}
+			f.uc.gateway = gateway
+
+			if err := f.uc.ReconcilePendingPayments(context.Background(), 15*time.Minute); err != nil {
+				t.Fatalf("ReconcilePendingPayments() error = %v", err)
+			}
+
+			if gateway.calls != 1 {
+				t.Errorf("provider asked %d times, want 1", gateway.calls)
+			}
+			if refunds.transaction.Status != tt.wantTransaction {
+				t.Errorf("transaction status = %s, want %s", refunds.transaction.Status, tt.wantTransaction)
+			}
+			if tt.wantDiscrepancy == "" {
+				if len(repo.entries) != 0 {
+					t.Errorf("entries = %+v, want none", repo.entries)
+				}
+				return
+			}
+			if len(repo.entries) != 1 || repo.entries[0].Discrepancy != tt.wantDiscrepancy {
+				t.Errorf("entries = %+v, want one %s", repo.entries, tt.wantDiscrepancy)
+			}
+		})
+	}
+}
+
+func TestReconcilePendingPayments_Window(t *testing.T) {
+	f, refunds := newRefundFixture()
+	refunds.transaction.Status = ticket.TransactionPending
+	repo := &fakeReconcileRepo{fakeRefundRepo: refunds}
+	f.uc.ticketRepo = repo
+	f.uc.gateway = &fakeChargeGateway{status: payment.ChargePending}
+
+	before := time.Now()
+	if err := f.uc.ReconcilePendingPayments(context.Background(), 15*time.Minute); err != nil {
+		t.Fatalf("ReconcilePendingPayments() error = %v", err)
+	}
+	after := time.Now()
+
+	// Transactions younger than minAge may still get their webhook
+	if repo.claimedBefore.Before(before.Add(-15*time.Minute)) || repo.claimedBefore.After(after.Add(-15*time.Minute)) {
+		t.Errorf("claimed transactions created before %v, want 15m ago", repo.claimedBefore)
+	}
+	if repo.claimedAfter.Before(before.Add(-reconciliationLookback)) || repo.claimedAfter.After(after.Add(-reconciliationLookback)) {
+		t.Errorf("claimed transactions created after %v, want %v ago", repo.claimedAfter, reconciliationLookback)
+	}
+}
@@ -0,0 +1,320 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/anigmaa/backend/internal/infrastructure/payment"
+	"github.com/google/uuid"
+)
+
+// fakeRefundRepo adds one paid transaction and its refunds to fakeTicketRepo
+type fakeRefundRepo struct {
+	*fakeTicketRepo
+	transaction *ticket.TicketTransaction
+	refunds     map[string]*ticket.Refund
+	createErr   error
+	released    int
+}
+
+func (r *fakeRefundRepo) ClaimCancellation(ctx context.Context, ticketID uuid.UUID) error {
+	t := r.tickets[ticketID]
+	if t.Status != ticket.StatusActive || t.IsCheckedIn {
+		return sql.ErrNoRows
+	}
+	t.Status = ticket.StatusCancelled
+	return nil
+}
+
+func (r *fakeRefundRepo) ReleaseCancellation(ctx context.Context, ticketID uuid.UUID) error {
+	if t := r.tickets[ticketID]; t.Status == ticket.StatusCancelled {
+		t.Status = ticket.StatusActive
+	}
+	return nil
+}
+
+func (r *fakeRefundRepo) Update(ctx context.Context, t *ticket.Ticket) error {
+	copied := *t
+	r.tickets[t.ID] = &copied
+	return nil
+}
+
+func (r *fakeRefundRepo) DecrementTicketsSold(ctx context.Context, eventID uuid.UUID, tierID *uuid.UUID) error {
+	r.released++
+	return nil
+}
+
+func (r *fakeRefundRepo) OfferWaitlistSpots(ctx context.Context, eventID uuid.UUID, claimWindow time.Duration) ([]ticket.WaitlistEntry, error) {
+	return nil, nil
+}
+
+func (r *fakeRefundRepo) GetRefundsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]ticket.Refund, error) {
+	var refunds []ticket.Refund
+	for _, refund := range r.refunds {
+		if refund.TicketID == ticketID {
+			refunds = append(refunds, *refund)
+		}
+	}
+	return refunds, nil
+}
+
+func (r *fakeRefundRepo) GetTransactionsByTicketID(ctx context.Context, ticketID uuid.UUID) ([]ticket.TicketTransaction, error) {
+	if ticketID != r.transaction.TicketID {
+		return nil, nil
+	}
+	return []ticket.TicketTransaction{*r.transaction}, nil
+}
+
+func (r *fakeRefundRepo) GetTransaction(ctx context.Context, transactionID string) (*ticket.TicketTransaction, error) {
+	if transactionID != r.transaction.TransactionID {
+		return nil, sql.ErrNoRows
+	}
+	copied := *r.transaction
+	return &copied, nil
+}
+
+func (r *fakeRefundRepo) GetRefundByKey(ctx context.Context, refundKey string) (*ticket.Refund, error) {
+	refund, ok := r.refunds[refundKey]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *refund
+	return &copied, nil
+}
+
+func (r *fakeRefundRepo) CreateRefund(ctx context.Context, refund *ticket.Refund) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	copied := *refund
+	r.refunds[refund.RefundKey] = &copied
+	return nil
+}
+
+func (r *fakeRefundRepo) UpdateRefundStatus(ctx context.Context, refundKey string, status ticket.RefundStatus, providerRefundID, failureReason *string) error {
+	refund, ok := r.refunds[refundKey]
+	if !ok || refund.Status == ticket.RefundSucceeded {
+		return ticket.ErrAlreadyProcessed
+	}
+	refund.Status = status
+	return nil
+}
+
+func (r *fakeRefundRepo) UpdateRefundAttempt(ctx context.Context, refund *ticket.Refund) error {
+	stored, ok := r.refunds[refund.RefundKey]
+	if !ok || stored.Status == ticket.RefundSucceeded {
+		return ticket.ErrAlreadyProcessed
+	}
+	*stored = *refund
+	return nil
+}
+
+func (r *fakeRefundRepo) MarkTransactionRefunded(ctx context.Context, transactionID string) error {
+	if r.transaction.Status != ticket.TransactionSuccess {
+		return ticket.ErrAlreadyProcessed
+	}
+	r.transaction.Status = ticket.TransactionRefunded
+	return nil
+}
+
+// fakeRefundGateway answers refund requests with a fixed result or error
+type fakeRefundGateway struct {
+	payment.Gateway
+	result *payment.RefundResult
+	err    error
+	calls  int
+}
+
+func (g *fakeRefundGateway) RefundCharge(ctx context.Context, orderID string, req *payment.RefundRequest) (*payment.RefundResult, error) {
+	g.calls++
+	return g.result, g.err
+}
+
+func newRefundFixture() (*ticketFixture, *fakeRefundRepo) {
+	f := newTicketFixture()
+	f.active.UserID = uuid.New()
+	f.active.PricePaid = 150000
+	refunds := &fakeRefundRepo{
+		fakeTicketRepo: f.tickets,
+		transaction: &ticket.TicketTransaction{
+			TicketID:      f.active.ID,
+			TransactionID: "TRX-1",
+			Amount:        150000,
+			Status:        ticket.TransactionSuccess,
+		},
+		refunds: make(map[string]*ticket.Refund),
+	}
+	f.uc.ticketRepo = refunds
+	return f, refunds
+}
+
+func TestProcessRefundCallback_Partial(t *testing.T) {
+	f, repo := newRefundFixture()
+	notified := []payment.NotifiedRefund{{RefundKey: "dashboard-1", Amount: 50000}}
+
+	// Midtrans retries notifications, so the same one may arrive twice
+	for i := 0; i < 2; i++ {
+		if err := f.uc.ProcessRefundCallback(context.Background(), "TRX-1", notified, true); err != nil {
+			t.Fatalf("ProcessRefundCallback() error = %v", err)
+		}
+	}
+
+	refund, ok := repo.refunds["dashboard-1"]
+	if !ok || refund.Status != ticket.RefundSucceeded || refund.Amount != 50000 {
+		t.Errorf("refund = %+v, want a succeeded refund of 50000", refund)
+	}
+	if got := f.tickets.tickets[f.active.ID].Status; got != ticket.StatusActive {
+		t.Errorf("ticket status = %s, want it to stay active", got)
+	}
+	if repo.transaction.Status != ticket.TransactionSuccess {
+		t.Errorf("transaction status = %s, want it to stay settled", repo.transaction.Status)
+	}
+}
+
+func TestRequestRefund(t *testing.T) {
+	tests := []struct {
+		name       string
+		createErr  error
+		gateway    *fakeRefundGateway
+		wantErr    bool
+		wantCalls  int
+		wantStatus ticket.RefundStatus
+	}{
+		{
+			name:       "accepted, completes by webhook",
+			gateway:    &fakeRefundGateway{result: &payment.RefundResult{Method: payment.RefundMethodStandard, ProviderRefundID: "42", Pending: true}},
+			wantCalls:  1,
+			wantStatus: ticket.RefundPending,
+		},
+		{
+			name:       "refunded right away",
+			gateway:    &fakeRefundGateway{result: &payment.RefundResult{Method: payment.RefundMethodDirect, ProviderRefundID: "43"}},
+			wantCalls:  1,
+			wantStatus: ticket.RefundSucceeded,
+		},
+		{
+			name:       "rejected by the provider",
+			gateway:    &fakeRefundGateway{err: &payment.RefundError{Method: payment.RefundMethodDirect, Err: errors.New("insufficient balance")}},
+			wantErr:    true,
+			wantCalls:  1,
+			wantStatus: ticket.RefundFailed,
+		},
+		{
+			name:      "not recorded, provider never asked",
+			createErr: errors.New("connection reset"),
+			gateway:   &fakeRefundGateway{result: &payment.RefundResult{Method: payment.RefundMethodStandard}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, repo := newRefundFixture()
+			repo.createErr = tt.createErr
+			f.uc.gateway = tt.gateway
+
+			_, err := f.uc.requestRefund(context.Background(), f.active, "Ticket cancelled by attendee")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requestRefund() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.gateway.calls != tt.wantCalls {
+				t.Errorf("provider asked %d times, want %d", tt.gateway.calls, tt.wantCalls)
+			}
+			if tt.createErr != nil {
+				if len(repo.refunds) != 0 {
+					t.Errorf("refunds = %v, want none recorded", repo.refunds)
+				}
+				return
+			}
+
+			if len(repo.refunds) != 1 {
+				t.Fatalf("recorded %d refunds, want 1", len(repo.refunds))
+			}
+			for _, refund := range repo.refunds {
+				if refund.Status != tt.wantStatus {
+					t.Errorf("refund status = %s, want %s", refund.Status, tt.wantStatus)
+				}
+				if tt.gateway.result != nil && (refund.ProviderRefundID == nil || *refund.ProviderRefundID != tt.gateway.result.ProviderRefundID) {
+					t.Errorf("provider refund ID = %v, want %s", refund.ProviderRefundID, tt.gateway.result.ProviderRefundID)
+				}
+				if tt.gateway.err != nil && (refund.FailureReason == nil || refund.Method != payment.RefundMethodDirect) {
+					t.Errorf("failed refund = %+v, want its failure reason and method recorded", refund)
+				}
+			}
+		})
+	}
+}
+
+// cancelEventRepo lets attendees leave fakeEventRepo's event
+type cancelEventRepo struct {
+	*fakeEventRepo
+}
+
+func (r *cancelEventRepo) Leave(ctx context.Context, eventID, userID uuid.UUID) error {
+	return nil
+}
+
+// newCancelFixture is a refund fixture whose event starts in two days
+func newCancelFixture(gateway *fakeRefundGateway) (*ticketFixture, *fakeRefundRepo) {
+	f, repo := newRefundFixture()
+	f.evt.StartTime = time.Now().Add(48 * time.Hour)
+	f.evt.EndTime = f.evt.StartTime.Add(3 * time.Hour)
+	f.uc.eventRepo = &cancelEventRepo{fakeEventRepo: f.uc.eventRepo.(*fakeEventRepo)}
+	f.uc.gateway = gateway
+	return f, repo
+}
+
+func TestCancelTicket_Once(t *testing.T) {
+	gateway := &fakeRefundGateway{result: &payment.RefundResult{Method: payment.RefundMethodStandard, Pending: true}}
+	f, repo := newCancelFixture(gateway)
+
+	if err := f.uc.CancelTicket(context.Background(), f.active.ID, f.active.UserID); err != nil {
+		t.Fatalf("CancelTicket() error = %v", err)
+	}
+	// A second cancellation, e.g. a double tap, must not refund or free the spot again
+	if err := f.uc.CancelTicket(context.Background(), f.active.ID, f.active.UserID); err != ErrCannotRefund {
+		t.Errorf("CancelTicket() again error = %v, want %v", err, ErrCannotRefund)
+	}
+
+	if gateway.calls != 1 || len(repo.refunds) != 1 || repo.released != 1 {
+		t.Errorf("provider asked %d times, %d refunds recorded, %d spots freed; want 1 each", gateway.calls, len(repo.refunds), repo.released)
+	}
+	if got := f.tickets.tickets[f.active.ID].Status; got != ticket.StatusCancelled {
+		t.Errorf("ticket status = %s, want cancelled until the refund webhook arrives", got)
+	}
+}
+
+func TestCancelTicket_RetryAfterFailedRefund(t *testing.T) {
+	gateway := &fakeRefundGateway{err: &payment.RefundError{Method: payment.RefundMethodDirect, Err: errors.New("insufficient balance")}}
+	f, repo := newCancelFixture(gateway)
+
+	if err := f.uc.CancelTicket(context.Background(), f.active.ID, f.active.UserID); err != ErrRefundFailed {
+		t.Fatalf("CancelTicket() error = %v, want %v", err, ErrRefundFailed)
+	}
+	if got := f.tickets.tickets[f.active.ID].Status; got != ticket.StatusActive || repo.released != 0 {
+		t.Fatalf("ticket status = %s with %d spots freed, want it active again so the user can retry", got, repo.released)
+	}
+
+	var failedKey string
+	for key := range repo.refunds {
+		failedKey = key
+	}
+
+	gateway.err = nil
+	gateway.result = &payment.RefundResult{Method: payment.RefundMethodDirect, ProviderRefundID: "44"}
+	if err := f.uc.CancelTicket(context.Background(), f.active.ID, f.active.UserID); err != nil {
+		t.Fatalf("CancelTicket() retry error = %v", err)
+	}
+
+	refund, ok := repo.refunds[failedKey]
+	if len(repo.refunds) != 1 || !ok || refund.Status != ticket.RefundSucceeded || refund.FailureReason != nil {
+		t.Errorf("refunds = %v, want the failed refund retried under key %s and succeeded", repo.refunds, failedKey)
+	}
+	if got := f.tickets.tickets[f.active.ID].Status; got != ticket.StatusRefunded || repo.released != 1 {
+		t.Errorf("ticket status = %s with %d spots freed, want refunded and 1", got, repo.released)
+	}
+}
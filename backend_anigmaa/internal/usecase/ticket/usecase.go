@@ -2,15 +2,20 @@ package ticket
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/event"
 	"github.com/anigmaa/backend/internal/domain/ticket"
 	"github.com/anigmaa/backend/internal/domain/user"
 	"github.com/anigmaa/backend/internal/infrastructure/payment"
+	"github.com/anigmaa/backend/internal/infrastructure/realtime"
+	emailUsecase "github.com/anigmaa/backend/internal/usecase/email"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
 	"github.com/anigmaa/backend/pkg/qrcode"
 	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
@@ -19,31 +24,72 @@ import (
 var (
 	ErrTicketNotFound        = errors.New("ticket not found")
 	ErrEventNotFound         = errors.New("event not found")
-	ErrEventFull             = ticket.ErrEventFull // proxy to domain sentinel
-	ErrAlreadyPurchased      = errors.New("already purchased ticket for this event")
+	ErrEventFull             = ticket.ErrEventFull        // proxy to domain sentinel
+	ErrAlreadyPurchased      = ticket.ErrAlreadyPurchased // proxy to domain sentinel
 	ErrInvalidAttendanceCode = errors.New("invalid attendance code")
 	ErrAlreadyCheckedIn      = errors.New("ticket already checked in")
-	ErrTicketNotActive       = errors.New("ticket is not active")
+	ErrTicketNotActive       = ticket.ErrTicketNotActive // proxy to domain sentinel
 	ErrCannotRefund          = errors.New("ticket cannot be refunded")
 	ErrEventStarted          = errors.New("event has already started")
 	ErrUnauthorized          = errors.New("unauthorized")
+	ErrInvalidQR             = errors.New("invalid ticket QR")
+	ErrQRExpired             = errors.New("ticket QR has expired")
+	ErrRefundFailed          = errors.New("refund could not be processed")
+	ErrAmountMismatch        = errors.New("amount mismatch")
+	ErrTierNotFound          = errors.New("ticket tier not found")
+	ErrTierRequired          = errors.New("a ticket tier must be selected for this event")
+	ErrTierNotOnSale         = errors.New("ticket tier is not on sale")
+	ErrTierSoldOut           = ticket.ErrTierSoldOut      // proxy to domain sentinel
+	ErrTierLimitReached      = ticket.ErrTierLimitReached // proxy to domain sentinel
+	ErrTierHasSales          = errors.New("ticket tier already has tickets sold")
+	ErrInvalidTier           = errors.New("invalid ticket tier")
+	ErrPromoCodeNotFound     = errors.New("promo code not found")
+	ErrPromoCodeInvalid      = errors.New("promo code is not valid for this ticket")
+	ErrPromoCodeExhausted    = ticket.ErrPromoCodeExhausted    // proxy to domain sentinel
+	ErrPromoCodeLimitReached = ticket.ErrPromoCodeLimitReached // proxy to domain sentinel
+	ErrPromoCodeExists       = errors.New("promo code already exists for this event")
+	ErrInvalidPromoCode      = errors.New("invalid promo code")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrEventForbidden        = event.ErrForbidden          // proxy to domain sentinel
+	ErrAlreadyWaitlisted     = ticket.ErrAlreadyWaitlisted // proxy to domain sentinel
+	ErrNotWaitlisted         = errors.New("not on the waitlist for this event")
+	ErrHostCannotWaitlist    = errors.New("the host can't join the waitlist of their own event")
+	ErrApprovalRequired      = errors.New("the host must approve your join request before you can get a ticket")
+	ErrScanIDReused          = errors.New("scan ID was already used for another ticket")
+	ErrGateRequired          = errors.New("gate is required")
+	ErrCheckInClosed         = errors.New("check-in for this event has closed")
+	ErrInvalidKioskExpiry    = errors.New("kiosk token must expire in the future and before check-in closes")
+	ErrKioskTokenNotFound    = errors.New("kiosk token not found")
+	ErrInvalidKioskToken     = errors.New("kiosk token is invalid, expired or revoked")
+	ErrInvalidScanTime       = errors.New("scan time is outside the event's check-in window")
 )
 
+// qrValidityAfterEventEnd keeps ticket QRs scannable for late check-ins past the scheduled end
+const qrValidityAfterEventEnd = 12 * time.Hour
+
 // Usecase handles ticket business logic
 type Usecase struct {
 	ticketRepo     ticket.Repository
 	eventRepo      event.Repository
 	userRepo       user.Repository
-	midtransClient *payment.MidtransClient
+	gateway        payment.Gateway
+	hub            *realtime.Hub
+	qrSigner       *qrcode.Signer
+	notificationUC *notificationUsecase.Usecase
+	emailUC        *emailUsecase.Usecase
 }
 
 // NewUsecase creates a new ticket usecase
-func NewUsecase(ticketRepo ticket.Repository, eventRepo event.Repository, userRepo user.Repository, midtransClient *payment.MidtransClient) *Usecase {
+func NewUsecase(ticketRepo ticket.Repository, eventRepo event.Repository, userRepo user.Repository, gateway payment.Gateway, hub *realtime.Hub, qrSigner *qrcode.Signer, notificationUC *notificationUsecase.Usecase, emailUC *emailUsecase.Usecase) *Usecase {
 	return &Usecase{
 		ticketRepo:     ticketRepo,
 		eventRepo:      eventRepo,
 		userRepo:       userRepo,
-		midtransClient: midtransClient,
+		gateway:        gateway,
+		hub:            hub,
+		qrSigner:       qrSigner,
+		notificationUC: notificationUC,
+		emailUC:        emailUC,
 	}
 }
 
@@ -60,16 +106,39 @@ func (uc *Usecase) PurchaseTicket(ctx context.Context, userID uuid.UUID, req *ti
 		return nil, ErrEventNotFound
 	}
 
+	// Tickets of friends-only and private events are only sold to people with access
+	if err := event.CheckAccess(ctx, uc.eventRepo, evt, userID); err != nil {
+		return nil, err
+	}
+
+	// Events that require approval only sell tickets to approved attendees
+	if evt.RequiresApproval && evt.HostID != userID {
+		attendee, err := uc.eventRepo.GetAttendee(ctx, evt.ID, userID)
+		if err != nil || attendee.Status != event.AttendeeConfirmed {
+			return nil, ErrApprovalRequired
+		}
+	}
+
 	// Reject immediately if the event is visibly full — this is a fast-path
 	// optimisation only; the real enforcement is inside AtomicPurchase.
 	if evt.IsFull() {
 		return nil, ErrEventFull
 	}
 
-	// Reject if user already holds a ticket for this event.
-	existingTicket, err := uc.ticketRepo.GetUserTicketForEvent(ctx, userID, req.EventID)
-	if err == nil && existingTicket != nil {
-		return nil, ErrAlreadyPurchased
+	// Events with ticket tiers are sold per tier; the per-user limit of the
+	// tier replaces the one-ticket-per-event rule.
+	tier, err := uc.resolvePurchaseTier(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject if user already holds a ticket for this event. A cancelled or
+	// refunded ticket doesn't stop them buying again.
+	if tier == nil {
+		existingTicket, err := uc.ticketRepo.GetUserTicketForEvent(ctx, userID, req.EventID)
+		if err == nil && existingTicket != nil && existingTicket.HoldsSpot() {
+			return nil, ErrAlreadyPurchased
+		}
 	}
 
 	// Verify user exists.
@@ -79,13 +148,23 @@ func (uc *Usecase) PurchaseTicket(ctx context.Context, userID uuid.UUID, req *ti
 	}
 
 	// Determine price and initial status.
-	pricePaid := 0.0
-	if !evt.IsFree && evt.Price != nil {
-		pricePaid = *evt.Price
+	pricePaid := ticketPrice(evt, tier)
+
+	// Apply the promo code. Its validity is checked here; the usage caps are
+	// enforced inside AtomicPurchase under a row lock.
+	var promo *ticket.PromoCode
+	discount := 0.0
+	if req.PromoCode != nil && strings.TrimSpace(*req.PromoCode) != "" {
+		promo, err = uc.resolvePromoCode(ctx, req.EventID, *req.PromoCode, tier, pricePaid)
+		if err != nil {
+			return nil, err
+		}
+		discount = promo.DiscountFor(pricePaid)
+		pricePaid -= discount
 	}
 
 	ticketStatus := ticket.StatusActive
-	if !evt.IsFree && pricePaid > 0 {
+	if pricePaid > 0 {
 		ticketStatus = ticket.StatusPending // awaiting payment confirmation
 	}
 
@@ -101,6 +180,13 @@ func (uc *Usecase) PurchaseTicket(ctx context.Context, userID uuid.UUID, req *ti
 		IsCheckedIn: false,
 		Status:      ticketStatus,
 	}
+	if tier != nil {
+		newTicket.TierID = &tier.ID
+	}
+	if promo != nil {
+		newTicket.PromoCodeID = &promo.ID
+		newTicket.DiscountAmount = discount
+	}
 
 	// --- ATOMIC INSERT: lock event row, check capacity, insert ticket,
 	// increment events.tickets_sold — all in one transaction. ---
@@ -108,6 +194,21 @@ func (uc *Usecase) PurchaseTicket(ctx context.Context, userID uuid.UUID, req *ti
 		if errors.Is(err, ticket.ErrEventFull) {
 			return nil, ErrEventFull
 		}
+		if errors.Is(err, ticket.ErrTierSoldOut) {
+			return nil, ErrTierSoldOut
+		}
+		if errors.Is(err, ticket.ErrTierLimitReached) {
+			return nil, ErrTierLimitReached
+		}
+		if errors.Is(err, ticket.ErrAlreadyPurchased) {
+			return nil, ErrAlreadyPurchased
+		}
+		if errors.Is(err, ticket.ErrPromoCodeExhausted) {
+			return nil, ErrPromoCodeExhausted
+		}
+		if errors.Is(err, ticket.ErrPromoCodeLimitReached) {
+			return nil, ErrPromoCodeLimitReached
+		}
 		return nil, err
 	}
 
@@ -116,34 +217,40 @@ func (uc *Usecase) PurchaseTicket(ctx context.Context, userID uuid.UUID, req *ti
 		Ticket: newTicket,
 	}
 
-	// For paid events: create a Midtrans Snap token so the user can pay.
-	if !evt.IsFree && pricePaid > 0 {
+	// For paid events: create a payment charge so the user can pay.
+	if pricePaid > 0 {
 		orderID := payment.GenerateOrderID(newTicket.ID.String())
 
-		snapReq := &payment.SnapRequest{
-			TransactionDetails: payment.TransactionDetails{
-				OrderID:     orderID,
-				GrossAmount: pricePaid,
-			},
-			CustomerDetails: payment.CustomerDetails{
+		itemID, itemName := evt.ID.String(), evt.Title
+		if tier != nil {
+			itemID, itemName = tier.ID.String(), evt.Title+" - "+tier.Name
+		}
+		if promo != nil {
+			itemName += " (" + promo.Code + ")"
+		}
+
+		chargeReq := &payment.ChargeRequest{
+			OrderID: orderID,
+			Amount:  pricePaid,
+			Customer: payment.CustomerDetails{
 				FirstName: usr.Name,
 				Email:     usr.Email,
 			},
-			ItemDetails: []payment.ItemDetail{
+			Items: []payment.ItemDetail{
 				{
-					ID:       evt.ID.String(),
-					Name:     evt.Title,
+					ID:       itemID,
+					Name:     itemName,
 					Price:    pricePaid,
 					Quantity: 1,
 				},
 			},
 		}
 
-		snapResp, err := uc.midtransClient.CreateSnapToken(ctx, snapReq)
+		chargeResp, err := uc.gateway.CreateCharge(ctx, chargeReq)
 		if err != nil {
 			// Roll back: delete the ticket and restore the counter.
 			_ = uc.ticketRepo.Delete(ctx, newTicket.ID)
-			_ = uc.ticketRepo.DecrementTicketsSold(ctx, newTicket.EventID)
+			_ = uc.ticketRepo.DecrementTicketsSold(ctx, newTicket.EventID, newTicket.TierID)
 			return nil, errors.New("failed to create payment: " + err.Error())
 		}
 
@@ -163,12 +270,12 @@ func (uc *Usecase) PurchaseTicket(ctx context.Context, userID uuid.UUID, req *ti
 
 		if err := uc.ticketRepo.CreateTransaction(ctx, transaction); err != nil {
 			_ = uc.ticketRepo.Delete(ctx, newTicket.ID)
-			_ = uc.ticketRepo.DecrementTicketsSold(ctx, newTicket.EventID)
+			_ = uc.ticketRepo.DecrementTicketsSold(ctx, newTicket.EventID, newTicket.TierID)
 			return nil, errors.New("failed to create transaction: " + err.Error())
 		}
 
-		response.PaymentToken = &snapResp.Token
-		response.PaymentURL = &snapResp.RedirectURL
+		response.PaymentToken = &chargeResp.Token
+		response.PaymentURL = &chargeResp.RedirectURL
 		// QR is NOT generated here — ticket is pending and not yet valid.
 		// It will be available once the webhook confirms payment.
 		return response, nil
@@ -189,14 +296,196 @@ func (uc *Usecase) PurchaseTicket(ctx context.Context, userID uuid.UUID, req *ti
 		log.Printf("[TicketUsecase] failed to increment events_attended: %v", err)
 	}
 
-	qrCode, err := qrcode.GenerateTicketQR(newTicket.ID, newTicket.EventID, newTicket.UserID, newTicket.AttendanceCode)
-	if err == nil {
-		response.QRCode = &qrCode
-	}
+	response.QRCode = uc.generateQR(newTicket, evt.EndTime)
+	uc.sendTicketEmail(ctx, newTicket, evt)
 
 	return response, nil
 }
 
+// ticketPrice returns the undiscounted price of a ticket of the given tier (nil for events without tiers)
+func ticketPrice(evt *event.Event, tier *ticket.Tier) float64 {
+	if tier != nil {
+		return tier.Price
+	}
+	if !evt.IsFree && evt.Price != nil {
+		return *evt.Price
+	}
+	return 0
+}
+
+// resolvePurchaseTier returns the tier a purchase is for, or nil for events without tiers
+func (uc *Usecase) resolvePurchaseTier(ctx context.Context, req *ticket.PurchaseTicketRequest) (*ticket.Tier, error) {
+	if req.TierID == nil {
+		tiers, err := uc.ticketRepo.GetTiersByEvent(ctx, req.EventID)
+		if err != nil {
+			return nil, err
+		}
+		if len(tiers) > 0 {
+			return nil, ErrTierRequired
+		}
+		return nil, nil
+	}
+
+	tier, err := uc.ticketRepo.GetTierByID(ctx, *req.TierID)
+	if err != nil || tier.EventID != req.EventID {
+		return nil, ErrTierNotFound
+	}
+
+	if !tier.IsOnSale(time.Now()) {
+		return nil, ErrTierNotOnSale
+	}
+
+	// Fast-path only; the quota is enforced inside AtomicPurchase.
+	if tier.IsSoldOut() {
+		return nil, ErrTierSoldOut
+	}
+
+	return tier, nil
+}
+
+// CreateTier adds a ticket tier to an event (host only)
+func (uc *Usecase) CreateTier(ctx context.Context, eventID, hostID uuid.UUID, req *ticket.CreateTierRequest) (*ticket.Tier, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, err
+	}
+
+	tier := &ticket.Tier{
+		EventID:     eventID,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Quota:       req.Quota,
+		SalesStart:  req.SalesStart,
+		SalesEnd:    req.SalesEnd,
+		MaxPerUser:  req.MaxPerUser,
+		SortOrder:   req.SortOrder,
+	}
+	if tier.MaxPerUser <= 0 {
+		tier.MaxPerUser = 1
+	}
+
+	if err := validateTier(tier); err != nil {
+		return nil, err
+	}
+
+	if err := uc.ticketRepo.CreateTier(ctx, tier); err != nil {
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+// GetEventTiers gets the ticket tiers of an event the viewer may see
+func (uc *Usecase) GetEventTiers(ctx context.Context, eventID, viewerID uuid.UUID) ([]ticket.Tier, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if err := event.CheckAccess(ctx, uc.eventRepo, evt, viewerID); err != nil {
+		return nil, err
+	}
+
+	return uc.ticketRepo.GetTiersByEvent(ctx, eventID)
+}
+
+// UpdateTier updates a ticket tier (host only).
+// The quota can't be lowered below the number of tickets already sold.
+func (uc *Usecase) UpdateTier(ctx context.Context, eventID, tierID, hostID uuid.UUID, req *ticket.UpdateTierRequest) (*ticket.Tier, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, err
+	}
+
+	tier, err := uc.ticketRepo.GetTierByID(ctx, tierID)
+	if err != nil || tier.EventID != eventID {
+		return nil, ErrTierNotFound
+	}
+
+	if req.Name != nil {
+		tier.Name = *req.Name
+	}
+	if req.Description != nil {
+		tier.Description = req.Description
+	}
+	if req.Price != nil {
+		tier.Price = *req.Price
+	}
+	if req.Quota != nil {
+		tier.Quota = *req.Quota
+	}
+	if req.SalesStart != nil {
+		tier.SalesStart = req.SalesStart
+	}
+	if req.SalesEnd != nil {
+		tier.SalesEnd = req.SalesEnd
+	}
+	if req.MaxPerUser != nil {
+		tier.MaxPerUser = *req.MaxPerUser
+	}
+	if req.SortOrder != nil {
+		tier.SortOrder = *req.SortOrder
+	}
+
+	if err := validateTier(tier); err != nil {
+		return nil, err
+	}
+	if tier.Quota < tier.Sold {
+		return nil, fmt.Errorf("%w: quota is below the %d tickets already sold", ErrInvalidTier, tier.Sold)
+	}
+
+	if err := uc.ticketRepo.UpdateTier(ctx, tier); err != nil {
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+// DeleteTier deletes a ticket tier that has no tickets sold (host only)
+func (uc *Usecase) DeleteTier(ctx context.Context, eventID, tierID, hostID uuid.UUID) error {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return err
+	}
+
+	tier, err := uc.ticketRepo.GetTierByID(ctx, tierID)
+	if err != nil || tier.EventID != eventID {
+		return ErrTierNotFound
+	}
+
+	if tier.Sold > 0 {
+		return ErrTierHasSales
+	}
+
+	if err := uc.ticketRepo.DeleteTier(ctx, tierID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// A ticket was sold between the check and the delete
+			return ErrTierHasSales
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getHostedEvent gets an event and verifies that userID is its host
+func (uc *Usecase) getHostedEvent(ctx context.Context, eventID, userID uuid.UUID) (*event.Event, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+
+	if evt.HostID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return evt, nil
+}
+
+func validateTier(tier *ticket.Tier) error {
+	if tier.SalesStart != nil && tier.SalesEnd != nil && !tier.SalesEnd.After(*tier.SalesStart) {
+		return fmt.Errorf("%w: sales_end must be after sales_start", ErrInvalidTier)
+	}
+	return nil
+}
+
 // GetTicketByID gets a ticket by ID
 func (uc *Usecase) GetTicketByID(ctx context.Context, ticketID uuid.UUID) (*ticket.Ticket, error) {
 	t, err := uc.ticketRepo.GetByID(ctx, ticketID)
@@ -219,17 +508,15 @@ func (uc *Usecase) GetTicketWithDetails(ctx context.Context, ticketID, userID uu
 
 	// Only generate QR for active (paid/confirmed) tickets.
 	if t.Status == ticket.StatusActive {
-		qrCode, err := qrcode.GenerateTicketQR(t.ID, t.EventID, t.UserID, t.AttendanceCode)
-		if err == nil {
-			t.QRCode = &qrCode
-		}
+		t.QRCode = uc.generateQR(&t.Ticket, t.EventEndTime)
 	}
 
 	return t, nil
 }
 
-// GetUserTickets gets all tickets for a user
-func (uc *Usecase) GetUserTickets(ctx context.Context, userID uuid.UUID, limit, offset int) ([]ticket.TicketWithDetails, error) {
+// GetUserTickets gets a user's tickets, newest first.
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) GetUserTickets(ctx context.Context, userID uuid.UUID, cursor string, limit, offset int) ([]ticket.TicketWithDetails, string, error) {
 	if limit <= 0 {
 		limit = 20
 	}
@@ -237,21 +524,34 @@ func (uc *Usecase) GetUserTickets(ctx context.Context, userID uuid.UUID, limit,
 		limit = 100
 	}
 
-	tickets, err := uc.ticketRepo.GetByUser(ctx, userID, limit, offset)
+	position, err := utils.DecodeCursor(cursor)
 	if err != nil {
-		return nil, err
+		return nil, "", ErrInvalidCursor
+	}
+	if position != nil {
+		offset = 0
+	}
+
+	// Fetch one extra row to know whether another page exists
+	tickets, err := uc.ticketRepo.GetByUser(ctx, userID, position, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(tickets) > limit {
+		tickets = tickets[:limit]
+		last := tickets[len(tickets)-1]
+		nextCursor = utils.EncodeCursor(last.PurchasedAt, last.ID)
 	}
 
 	for i := range tickets {
 		if tickets[i].Status == ticket.StatusActive {
-			qrCode, err := qrcode.GenerateTicketQR(tickets[i].ID, tickets[i].EventID, tickets[i].UserID, tickets[i].AttendanceCode)
-			if err == nil {
-				tickets[i].QRCode = &qrCode
-			}
+			tickets[i].QRCode = uc.generateQR(&tickets[i].Ticket, tickets[i].EventEndTime)
 		}
 	}
 
-	return tickets, nil
+	return tickets, nextCursor, nil
 }
 
 // CountUserTickets counts total tickets for a user
@@ -276,14 +576,17 @@ func (uc *Usecase) GetTicketsByEvent(ctx context.Context, eventID uuid.UUID, lim
 	return uc.ticketRepo.GetByEvent(ctx, eventID, limit, offset)
 }
 
-// GetEventTickets gets all tickets for an event (host only)
+// GetEventTickets gets all tickets for an event (host and co-hosts only)
 func (uc *Usecase) GetEventTickets(ctx context.Context, eventID, requestingUserID uuid.UUID, limit, offset int) ([]ticket.TicketWithDetails, error) {
-	evt, err := uc.eventRepo.GetByID(ctx, eventID)
-	if err != nil {
+	if _, err := uc.eventRepo.GetByID(ctx, eventID); err != nil {
 		return nil, ErrEventNotFound
 	}
 
-	if evt.HostID != requestingUserID {
+	access, err := uc.eventRepo.GetAccess(ctx, eventID, requestingUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !access.IsCoHost() {
 		return nil, ErrUnauthorized
 	}
 
@@ -301,49 +604,51 @@ func (uc *Usecase) GetEventTickets(ctx context.Context, eventID, requestingUserI
 
 	for i := range tickets {
 		if tickets[i].Status == ticket.StatusActive {
-			qrCode, err := qrcode.GenerateTicketQR(tickets[i].ID, tickets[i].EventID, tickets[i].UserID, tickets[i].AttendanceCode)
-			if err == nil {
-				tickets[i].QRCode = &qrCode
-			}
+			tickets[i].QRCode = uc.generateQR(&tickets[i].Ticket, tickets[i].EventEndTime)
 		}
 	}
 
 	return tickets, nil
 }
 
-// CheckIn checks in a ticket using attendance code
-func (uc *Usecase) CheckIn(ctx context.Context, eventID uuid.UUID, req *ticket.CheckInRequest) (*ticket.Ticket, error) {
-	if !utils.ValidateAttendanceCode(req.AttendanceCode) {
-		return nil, ErrInvalidAttendanceCode
+// GetQRPublicKey returns the key scanner apps use to verify ticket QRs offline
+func (uc *Usecase) GetQRPublicKey() *ticket.QRPublicKeyResponse {
+	return &ticket.QRPublicKeyResponse{
+		Algorithm: "Ed25519",
+		PublicKey: uc.qrSigner.EncodedPublicKey(),
 	}
+}
 
-	t, err := uc.ticketRepo.GetByAttendanceCode(ctx, req.AttendanceCode)
+// generateQR signs a QR for the ticket that expires a while after the event ends.
+// Returns nil if the QR could not be generated; the attendance code still works.
+func (uc *Usecase) generateQR(t *ticket.Ticket, eventEndTime time.Time) *string {
+	qrCode, err := uc.qrSigner.GenerateTicketQR(t.ID, t.EventID, t.UserID, t.AttendanceCode, eventEndTime.Add(qrValidityAfterEventEnd))
 	if err != nil {
-		return nil, ErrTicketNotFound
-	}
-
-	if t.EventID != eventID {
-		return nil, ErrTicketNotFound
-	}
-
-	if t.Status != ticket.StatusActive {
-		return nil, ErrTicketNotActive
+		log.Printf("[TicketUsecase] failed to generate QR for ticket %s: %v", t.ID, err)
+		return nil
 	}
+	return &qrCode
+}
 
-	if t.IsCheckedIn {
-		return nil, ErrAlreadyCheckedIn
+// sendTicketEmail emails an active ticket to its holder with its QR code attached
+func (uc *Usecase) sendTicketEmail(ctx context.Context, t *ticket.Ticket, evt *event.Event) {
+	qrPNG, err := uc.qrSigner.GenerateTicketQRPNG(t.ID, t.EventID, t.UserID, t.AttendanceCode, evt.EndTime.Add(qrValidityAfterEventEnd))
+	if err != nil {
+		log.Printf("[TicketUsecase] failed to generate QR for ticket %s: %v", t.ID, err)
 	}
+	uc.emailUC.SendTicketConfirmation(ctx, t, evt, uc.tierName(ctx, t), qrPNG)
+}
 
-	if err := uc.ticketRepo.CheckIn(ctx, t.ID); err != nil {
-		return nil, err
+// tierName returns the name of a ticket's tier, or "" for events without tiers
+func (uc *Usecase) tierName(ctx context.Context, t *ticket.Ticket) string {
+	if t.TierID == nil {
+		return ""
 	}
-
-	updatedTicket, err := uc.ticketRepo.GetByID(ctx, t.ID)
+	tier, err := uc.ticketRepo.GetTierByID(ctx, *t.TierID)
 	if err != nil {
-		return nil, err
+		return ""
 	}
-
-	return updatedTicket, nil
+	return tier.Name
 }
 
 // CancelTicket cancels a ticket and issues refund (if applicable)
@@ -370,13 +675,37 @@ func (uc *Usecase) CancelTicket(ctx context.Context, ticketID, userID uuid.UUID)
 		return ErrEventStarted
 	}
 
-	t.Status = ticket.StatusCancelled
-	if err := uc.ticketRepo.Update(ctx, t); err != nil {
+	// Claiming the ticket first makes concurrent cancellations of it stop here, so
+	// it is refunded and its spot released only once
+	if err := uc.ticketRepo.ClaimCancellation(ctx, t.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCannotRefund
+		}
 		return err
 	}
+	t.Status = ticket.StatusCancelled
+
+	// Paid tickets are refunded through Midtrans before the spot is released.
+	// If Midtrans rejects the refund the ticket is active again so the user can retry.
+	var refund *ticket.Refund
+	if t.PricePaid > 0 {
+		refund, err = uc.requestRefund(ctx, t, "Ticket cancelled by attendee")
+		if err != nil {
+			if releaseErr := uc.ticketRepo.ReleaseCancellation(ctx, t.ID); releaseErr != nil {
+				log.Printf("[TicketUsecase] failed to reactivate ticket %s after its refund failed: %v", t.ID, releaseErr)
+			}
+			return err
+		}
+		if refund.Status == ticket.RefundSucceeded {
+			t.Status = ticket.StatusRefunded
+			if err := uc.ticketRepo.Update(ctx, t); err != nil {
+				return err
+			}
+		}
+	}
 
 	// Restore the capacity slot.
-	if err := uc.ticketRepo.DecrementTicketsSold(ctx, t.EventID); err != nil {
+	if err := uc.ticketRepo.DecrementTicketsSold(ctx, t.EventID, t.TierID); err != nil {
 		log.Printf("[TicketUsecase] failed to decrement tickets_sold on cancel: %v", err)
 	}
 
@@ -384,33 +713,305 @@ func (uc *Usecase) CancelTicket(ctx context.Context, ticketID, userID uuid.UUID)
 		log.Printf("[TicketUsecase] failed to leave event: %v", err)
 	}
 
-	if t.PricePaid > 0 {
-		refundTransaction := &ticket.TicketTransaction{
-			ID:            uuid.New(),
-			TicketID:      t.ID,
-			TransactionID: uuid.New().String(),
-			Amount:        t.PricePaid,
-			PaymentMethod: "midtrans",
-			Status:        ticket.TransactionRefunded,
-			CreatedAt:     time.Now(),
+	// Offer the freed spot to the next user on the waitlist
+	uc.promoteWaitlist(ctx, evt)
+
+	uc.emailUC.SendTicketCancelled(ctx, t, evt, refund)
+
+	return nil
+}
+
+// requestRefund refunds the settled payment of a ticket through the payment gateway.
+// The refund is recorded as pending before the provider is asked, so a refund the
+// provider accepted can always be matched by its webhook, and failed refunds stay
+// visible. A retry after a failed refund reuses its key, so the provider never
+// refunds the payment twice. If the refund can't be recorded the provider is never asked.
+func (uc *Usecase) requestRefund(ctx context.Context, t *ticket.Ticket, reason string) (*ticket.Refund, error) {
+	transactions, err := uc.ticketRepo.GetTransactionsByTicketID(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var paid *ticket.TicketTransaction
+	for i := range transactions {
+		if transactions[i].Status == ticket.TransactionSuccess {
+			paid = &transactions[i]
+			break
+		}
+	}
+	if paid == nil {
+		return nil, ErrCannotRefund
+	}
+
+	refund, err := uc.pendingRefund(ctx, t, paid, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record refund: %w", err)
+	}
+
+	result, err := uc.gateway.RefundCharge(ctx, paid.TransactionID, &payment.RefundRequest{
+		RefundKey: refund.RefundKey,
+		Amount:    refund.Amount,
+		Reason:    reason,
+	})
+	if err != nil {
+		log.Printf("[TicketUsecase] refund %s for order %s failed: %v", refund.RefundKey, paid.TransactionID, err)
+
+		var refundErr *payment.RefundError
+		if errors.As(err, &refundErr) {
+			refund.Method = refundErr.Method
 		}
-		if err := uc.ticketRepo.CreateTransaction(ctx, refundTransaction); err != nil {
-			log.Printf("[TicketUsecase] failed to record refund transaction: %v", err)
+		failureReason := err.Error()
+		refund.Status = ticket.RefundFailed
+		refund.FailureReason = &failureReason
+		if updateErr := uc.ticketRepo.UpdateRefundAttempt(ctx, refund); updateErr != nil {
+			log.Printf("[TicketUsecase] failed to record failed refund %s: %v", refund.RefundKey, updateErr)
 		}
+		return nil, ErrRefundFailed
+	}
+
+	refund.Method = result.Method
+	if result.ProviderRefundID != "" {
+		refund.ProviderRefundID = &result.ProviderRefundID
+	}
+
+	// The provider has already accepted the refund and the pending record lets
+	// its webhook complete it, so a failure to update it must not fail the cancellation
+	if err := uc.ticketRepo.UpdateRefundAttempt(ctx, refund); err != nil && !errors.Is(err, ticket.ErrAlreadyProcessed) {
+		log.Printf("[TicketUsecase] failed to update refund %s for order %s: %v", refund.RefundKey, paid.TransactionID, err)
+	}
+
+	if result.Pending {
+		// Completion arrives through the refund webhook
+		return refund, nil
+	}
+
+	if err := uc.completeRefund(ctx, refund, refund.ProviderRefundID); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// pendingRefund records the refund of a paid ticket as pending. A failed refund
+// of the same payment is moved back to pending and keeps its key.
+func (uc *Usecase) pendingRefund(ctx context.Context, t *ticket.Ticket, paid *ticket.TicketTransaction, reason string) (*ticket.Refund, error) {
+	refunds, err := uc.ticketRepo.GetRefundsByTicketID(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range refunds {
+		failed := &refunds[i]
+		if failed.Status != ticket.RefundFailed || failed.Partial || failed.TransactionID != paid.TransactionID {
+			continue
+		}
+
+		failed.Reason = &reason
+		failed.Method = payment.RefundMethodStandard
+		failed.Status = ticket.RefundPending
+		failed.FailureReason = nil
+		if err := uc.ticketRepo.UpdateRefundAttempt(ctx, failed); err != nil {
+			return nil, err
+		}
+		return failed, nil
+	}
+
+	refund := &ticket.Refund{
+		ID:            uuid.New(),
+		TicketID:      t.ID,
+		TransactionID: paid.TransactionID,
+		RefundKey:     payment.GenerateOrderID("refund"),
+		Amount:        paid.Amount,
+		Reason:        &reason,
+		Method:        payment.RefundMethodStandard,
+		Status:        ticket.RefundPending,
+	}
+	if err := uc.ticketRepo.CreateRefund(ctx, refund); err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// completeRefund marks a refund and its original payment as refunded
+func (uc *Usecase) completeRefund(ctx context.Context, refund *ticket.Refund, providerRefundID *string) error {
+	if err := uc.ticketRepo.UpdateRefundStatus(ctx, refund.RefundKey, ticket.RefundSucceeded, providerRefundID, nil); err != nil {
+		if errors.Is(err, ticket.ErrAlreadyProcessed) {
+			return nil
+		}
+		return err
+	}
+	refund.Status = ticket.RefundSucceeded
+
+	if err := uc.ticketRepo.MarkTransactionRefunded(ctx, refund.TransactionID); err != nil && !errors.Is(err, ticket.ErrAlreadyProcessed) {
+		log.Printf("[TicketUsecase] failed to mark transaction %s as refunded: %v", refund.TransactionID, err)
 	}
 
 	return nil
 }
 
-// GetCheckedInCount gets the number of checked-in attendees for an event
-func (uc *Usecase) GetCheckedInCount(ctx context.Context, eventID, requestingUserID uuid.UUID) (int, error) {
-	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+// ProcessRefundCallback reconciles a Midtrans refund notification with local refund records.
+//
+// notified are the refunds listed in the notification; when empty every refund
+// still open for the order is completed. Refunds issued outside the app (e.g.
+// from the Midtrans dashboard) have no local record: the payment and the ticket
+// are still marked refunded so the books match the provider. A partial refund
+// leaves the ticket active, see processPartialRefund.
+func (uc *Usecase) ProcessRefundCallback(ctx context.Context, transactionID string, notified []payment.NotifiedRefund, partial bool) error {
+	transaction, err := uc.ticketRepo.GetTransaction(ctx, transactionID)
 	if err != nil {
-		return 0, ErrEventNotFound
+		return errors.New("transaction not found: " + transactionID)
 	}
 
-	if evt.HostID != requestingUserID {
-		return 0, ErrUnauthorized
+	if partial {
+		return uc.processPartialRefund(ctx, transaction, notified)
+	}
+
+	refunds, err := uc.ticketRepo.GetRefundsByTransactionID(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(notified))
+	for _, n := range notified {
+		wanted[n.RefundKey] = true
+	}
+
+	matched := 0
+	for i := range refunds {
+		if len(wanted) > 0 && !wanted[refunds[i].RefundKey] {
+			continue
+		}
+		matched++
+		if refunds[i].Status == ticket.RefundSucceeded {
+			continue
+		}
+		if err := uc.completeRefund(ctx, &refunds[i], nil); err != nil {
+			return err
+		}
+		log.Printf("[RefundCallback] refund %s for order %s succeeded", refunds[i].RefundKey, transactionID)
+	}
+
+	if matched == 0 {
+		log.Printf("[RefundCallback] refund for order %s was not initiated by the app — reconciling", transactionID)
+		if err := uc.ticketRepo.MarkTransactionRefunded(ctx, transactionID); err != nil && !errors.Is(err, ticket.ErrAlreadyProcessed) {
+			return err
+		}
+	}
+
+	return uc.markTicketRefunded(ctx, transaction.TicketID, transaction.Amount)
+}
+
+// processPartialRefund records the refunds of a partial refund notification and
+// tells the ticket holder how much they got back. Only part of the payment was
+// returned, so the payment stays settled and the ticket stays active. Refunds
+// issued outside the app are recorded as they are notified, so a repeated
+// notification isn't reported twice.
+func (uc *Usecase) processPartialRefund(ctx context.Context, transaction *ticket.TicketTransaction, notified []payment.NotifiedRefund) error {
+	if len(notified) == 0 {
+		log.Printf("[RefundCallback] partial refund for order %s lists no refunds — nothing to record", transaction.TransactionID)
+		return nil
+	}
+
+	refunded := 0.0
+	for _, n := range notified {
+		_, err := uc.ticketRepo.GetRefundByKey(ctx, n.RefundKey)
+		if errors.Is(err, sql.ErrNoRows) {
+			reason := "Partial refund issued through the payment provider"
+			err = uc.ticketRepo.CreateRefund(ctx, &ticket.Refund{
+				TicketID:      transaction.TicketID,
+				TransactionID: transaction.TransactionID,
+				RefundKey:     n.RefundKey,
+				Amount:        n.Amount,
+				Reason:        &reason,
+				Method:        payment.RefundMethodStandard,
+				Status:        ticket.RefundPending,
+				Partial:       true,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := uc.ticketRepo.UpdateRefundStatus(ctx, n.RefundKey, ticket.RefundSucceeded, nil, nil); err != nil {
+			if errors.Is(err, ticket.ErrAlreadyProcessed) {
+				continue
+			}
+			return err
+		}
+		log.Printf("[RefundCallback] partial refund %s of %.2f for order %s succeeded", n.RefundKey, n.Amount, transaction.TransactionID)
+		refunded += n.Amount
+	}
+
+	if refunded == 0 {
+		return nil
+	}
+
+	t, err := uc.ticketRepo.GetByID(ctx, transaction.TicketID)
+	if err != nil {
+		return ErrTicketNotFound
+	}
+	if evt, err := uc.eventRepo.GetByID(ctx, t.EventID); err == nil {
+		uc.emailUC.SendRefundIssued(ctx, t, evt, refunded, true)
+	}
+
+	return nil
+}
+
+// markTicketRefunded moves a ticket to refunded, releasing its seat if it still held
+// one, and lets its holder know amount was refunded
+func (uc *Usecase) markTicketRefunded(ctx context.Context, ticketID uuid.UUID, amount float64) error {
+	t, err := uc.ticketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		return ErrTicketNotFound
+	}
+
+	if t.Status == ticket.StatusRefunded {
+		return nil
+	}
+
+	heldSeat := t.Status == ticket.StatusActive || t.Status == ticket.StatusPending
+	t.Status = ticket.StatusRefunded
+	if err := uc.ticketRepo.Update(ctx, t); err != nil {
+		return err
+	}
+
+	if heldSeat {
+		if err := uc.ticketRepo.DecrementTicketsSold(ctx, t.EventID, t.TierID); err != nil {
+			log.Printf("[RefundCallback] failed to decrement tickets_sold for ticket %s: %v", t.ID, err)
+		}
+		if err := uc.eventRepo.Leave(ctx, t.EventID, t.UserID); err != nil {
+			log.Printf("[RefundCallback] failed to leave event for ticket %s: %v", t.ID, err)
+		}
+		if err := uc.PromoteWaitlist(ctx, t.EventID); err != nil {
+			log.Printf("[RefundCallback] failed to promote waitlist of event %s: %v", t.EventID, err)
+		}
+	}
+
+	if evt, err := uc.eventRepo.GetByID(ctx, t.EventID); err == nil {
+		uc.emailUC.SendRefundIssued(ctx, t, evt, amount, false)
+	}
+
+	return nil
+}
+
+// GetTicketRefunds gets the refunds of a ticket (user must own the ticket)
+func (uc *Usecase) GetTicketRefunds(ctx context.Context, ticketID, userID uuid.UUID) ([]ticket.Refund, error) {
+	t, err := uc.ticketRepo.GetByID(ctx, ticketID)
+	if err != nil {
+		return nil, ErrTicketNotFound
+	}
+
+	if t.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return uc.ticketRepo.GetRefundsByTicketID(ctx, ticketID)
+}
+
+// GetCheckedInCount gets the number of checked-in attendees for an event
+func (uc *Usecase) GetCheckedInCount(ctx context.Context, eventID, requestingUserID uuid.UUID) (int, error) {
+	if _, err := uc.authorizeScanner(ctx, eventID, requestingUserID); err != nil {
+		return 0, err
 	}
 
 	return uc.ticketRepo.GetCheckedInCount(ctx, eventID)
@@ -477,6 +1078,9 @@ func (uc *Usecase) GetTransaction(ctx context.Context, transactionID string, use
 //   - Cancels ticket
 //   - Decrements events.tickets_sold to free the capacity slot
 //
+// The stored transaction amount is the price after any promo code discount,
+// which is also the gross amount sent to Midtrans.
+//
 // grossAmountStr: the raw gross_amount string from the Midtrans webhook payload.
 // Pass "" to skip amount validation (e.g. in tests).
 func (uc *Usecase) ProcessPaymentCallback(ctx context.Context, transactionID string, status ticket.TransactionStatus, grossAmountStr string) error {
@@ -499,7 +1103,7 @@ func (uc *Usecase) ProcessPaymentCallback(ctx context.Context, transactionID str
 			if diff > 0.01 {
 				log.Printf("[PaymentCallback] AMOUNT MISMATCH order=%s db=%.2f webhook=%.2f — rejecting",
 					transactionID, transaction.Amount, webhookAmount)
-				return fmt.Errorf("amount mismatch: expected %.2f got %.2f", transaction.Amount, webhookAmount)
+				return fmt.Errorf("%w: expected %.2f got %.2f", ErrAmountMismatch, transaction.Amount, webhookAmount)
 			}
 		}
 	}
@@ -544,6 +1148,16 @@ func (uc *Usecase) ProcessPaymentCallback(ctx context.Context, transactionID str
 			log.Printf("[PaymentCallback] failed to increment events_attended for user %s: %v", t.UserID, err)
 		}
 
+		if evt, err := uc.eventRepo.GetByID(ctx, t.EventID); err == nil {
+			now := time.Now()
+			transaction.Status = status
+			transaction.CompletedAt = &now
+			uc.emailUC.SendPaymentReceipt(ctx, t, transaction, evt, uc.tierName(ctx, t))
+			uc.sendTicketEmail(ctx, t, evt)
+		} else {
+			log.Printf("[PaymentCallback] failed to get event %s for ticket emails: %v", t.EventID, err)
+		}
+
 	case ticket.TransactionFailed:
 		// Cancel the ticket and free the capacity slot.
 		t.Status = ticket.StatusCancelled
@@ -551,20 +1165,36 @@ func (uc *Usecase) ProcessPaymentCallback(ctx context.Context, transactionID str
 			return err
 		}
 
-		if err := uc.ticketRepo.DecrementTicketsSold(ctx, t.EventID); err != nil {
+		if err := uc.ticketRepo.DecrementTicketsSold(ctx, t.EventID, t.TierID); err != nil {
 			log.Printf("[PaymentCallback] failed to decrement tickets_sold for event %s: %v", t.EventID, err)
 		}
 
 		if err := uc.eventRepo.Leave(ctx, t.EventID, t.UserID); err != nil {
 			log.Printf("[PaymentCallback] failed to remove attendee for ticket %s: %v", t.ID, err)
 		}
+
+		if err := uc.PromoteWaitlist(ctx, t.EventID); err != nil {
+			log.Printf("[PaymentCallback] failed to promote waitlist of event %s: %v", t.EventID, err)
+		}
+	}
+
+	// Push the outcome to the buyer's open streams so the app can leave the payment screen
+	if err := uc.hub.Publish(ctx, t.UserID, realtime.EventPaymentUpdate, ticket.PaymentUpdate{
+		TicketID:          t.ID,
+		EventID:           t.EventID,
+		TransactionID:     transactionID,
+		TransactionStatus: status,
+		TicketStatus:      t.Status,
+	}); err != nil {
+		log.Printf("[PaymentCallback] failed to publish payment update for ticket %s: %v", t.ID, err)
 	}
 
 	return nil
 }
 
 // ExpireStaleTickets is called by the background expiry worker.
-// It bulk-expires pending tickets older than 1 hour and frees their capacity slots.
+// It bulk-expires pending tickets older than 1 hour, frees their capacity slots
+// and offers them to the waitlists of their events.
 func (uc *Usecase) ExpireStaleTickets(ctx context.Context) error {
 	cutoff := time.Now().Add(-1 * time.Hour)
 
@@ -579,15 +1209,200 @@ func (uc *Usecase) ExpireStaleTickets(ctx context.Context) error {
 
 	log.Printf("[TicketExpiry] expiring %d stale pending tickets", len(expired))
 
+	freed := make(map[uuid.UUID]bool)
 	for _, t := range expired {
-		if err := uc.ticketRepo.DecrementTicketsSold(ctx, t.EventID); err != nil {
+		if err := uc.ticketRepo.DecrementTicketsSold(ctx, t.EventID, t.TierID); err != nil {
 			log.Printf("[TicketExpiry] failed to decrement tickets_sold for event %s: %v", t.EventID, err)
+			continue
+		}
+		freed[t.EventID] = true
+	}
+
+	for eventID := range freed {
+		if err := uc.PromoteWaitlist(ctx, eventID); err != nil {
+			log.Printf("[TicketExpiry] failed to promote waitlist of event %s: %v", eventID, err)
+		}
+	}
+
+	return nil
+}
+
+// TransactionStatusFromCharge converts a gateway charge status to the internal
+// TransactionStatus enum.
+// Returns (status, ok) — ok is false for statuses that require no DB action
+// (e.g. "pending" means payment not yet attempted, nothing to update).
+func TransactionStatusFromCharge(status payment.ChargeStatus) (ticket.TransactionStatus, bool) {
+	switch status {
+	case payment.ChargeSettled:
+		return ticket.TransactionSuccess, true
+	case payment.ChargeFailed, payment.ChargeExpired:
+		return ticket.TransactionFailed, true
+	default:
+		// Pending (including fraud review) and refunds: no payment outcome to apply
+		return "", false
+	}
+}
+
+// reconciliationLookback bounds how far back ReconcilePendingPayments looks.
+// Provider payments expire after 1 hour, so older pending transactions can no
+// longer change and only need reporting once.
+const reconciliationLookback = 72 * time.Hour
+
+// reconciliationBatchSize caps the provider status calls made per run
+const reconciliationBatchSize = 100
+
+// ReconcilePendingPayments is called by the background reconciliation worker.
+// It asks the payment gateway for the status of transactions that have been
+// pending for longer than minAge, i.e. whose webhook was probably lost, and
+// applies the outcome through ProcessPaymentCallback. Discrepancies are
+// recorded in the reconciliation report.
+//
+// Each run checks the transactions checked least recently, so ones that stay
+// pending at the provider rotate out of the batch instead of starving newer ones.
+func (uc *Usecase) ReconcilePendingPayments(ctx context.Context, minAge time.Duration) error {
+	now := time.Now()
+	transactions, err := uc.ticketRepo.ClaimPendingTransactions(ctx, now.Add(-reconciliationLookback), now.Add(-minAge), reconciliationBatchSize)
+	if err != nil {
+		return err
+	}
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	log.Printf("[PaymentReconciliation] checking %d pending transactions", len(transactions))
+
+	for i := range transactions {
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
+		uc.reconcileTransaction(ctx, &transactions[i])
 	}
 
 	return nil
 }
 
+// reconcileTransaction checks one pending transaction against the gateway.
+// Errors are logged rather than returned so one bad transaction doesn't stop the run.
+func (uc *Usecase) reconcileTransaction(ctx context.Context, tx *ticket.TicketTransaction) {
+	charge, err := uc.gateway.GetChargeStatus(ctx, tx.TransactionID)
+	if err != nil {
+		log.Printf("[PaymentReconciliation] failed to get status for %s: %v", tx.TransactionID, err)
+		return
+	}
+
+	status, ok := TransactionStatusFromCharge(charge.Status)
+	if !ok {
+		// Still pending at the provider (or the buyer never opened the payment page)
+		return
+	}
+
+	t, err := uc.ticketRepo.GetByID(ctx, tx.TicketID)
+	if err != nil {
+		log.Printf("[PaymentReconciliation] ticket %s not found for %s: %v", tx.TicketID, tx.TransactionID, err)
+		return
+	}
+
+	entry := &ticket.ReconciliationEntry{
+		TransactionID:  tx.TransactionID,
+		TicketID:       t.ID,
+		EventID:        t.EventID,
+		LocalStatus:    t.Status,
+		ProviderStatus: charge.ProviderStatus,
+		LocalAmount:    tx.Amount,
+		ProviderAmount: &charge.GrossAmount,
+	}
+
+	// The expiry worker already released an expired ticket's capacity slot, so
+	// the payment outcome can't go through ProcessPaymentCallback: activating
+	// the ticket could oversell the event, and cancelling it would free the
+	// slot a second time.
+	if t.Status == ticket.StatusExpired {
+		if status == ticket.TransactionSuccess {
+			details := "buyer paid after the ticket expired; the payment must be refunded"
+			entry.Discrepancy = ticket.DiscrepancyPaidAfterExpiry
+			entry.Details = &details
+			uc.recordDiscrepancy(ctx, entry)
+			return
+		}
+
+		if err := uc.ticketRepo.UpdateTransactionStatus(ctx, tx.TransactionID, status); err != nil && !errors.Is(err, ticket.ErrAlreadyProcessed) {
+			log.Printf("[PaymentReconciliation] failed to close transaction %s: %v", tx.TransactionID, err)
+		}
+		return
+	}
+
+	if err := uc.ProcessPaymentCallback(ctx, tx.TransactionID, status, charge.GrossAmount); err != nil {
+		details := err.Error()
+		entry.Discrepancy = ticket.DiscrepancyApplyFailed
+		if errors.Is(err, ErrAmountMismatch) {
+			entry.Discrepancy = ticket.DiscrepancyAmountMismatch
+		}
+		entry.Details = &details
+		uc.recordDiscrepancy(ctx, entry)
+		return
+	}
+
+	details := fmt.Sprintf("applied %s from provider status", status)
+	entry.Discrepancy = ticket.DiscrepancyMissedWebhook
+	entry.Details = &details
+	uc.recordDiscrepancy(ctx, entry)
+}
+
+func (uc *Usecase) recordDiscrepancy(ctx context.Context, entry *ticket.ReconciliationEntry) {
+	log.Printf("[PaymentReconciliation] %s for %s (ticket=%s provider_status=%s)",
+		entry.Discrepancy, entry.TransactionID, entry.TicketID, entry.ProviderStatus)
+
+	if err := uc.ticketRepo.CreateReconciliationEntry(ctx, entry); err != nil {
+		log.Printf("[PaymentReconciliation] failed to record %s for %s: %v", entry.Discrepancy, entry.TransactionID, err)
+	}
+}
+
+// GetEventReconciliationReport lists payment discrepancies for an event (host only)
+func (uc *Usecase) GetEventReconciliationReport(ctx context.Context, eventID, requestingUserID uuid.UUID, limit, offset int) ([]ticket.ReconciliationEntry, int, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, 0, ErrEventNotFound
+	}
+
+	if evt.HostID != requestingUserID {
+		return nil, 0, ErrUnauthorized
+	}
+
+	return uc.getReconciliationReport(ctx, &eventID, limit, offset)
+}
+
+// GetReconciliationReport lists payment discrepancies across all events (admin only)
+func (uc *Usecase) GetReconciliationReport(ctx context.Context, requestingUserID uuid.UUID, limit, offset int) ([]ticket.ReconciliationEntry, int, error) {
+	u, err := uc.userRepo.GetByID(ctx, requestingUserID)
+	if err != nil || u.Role != "admin" {
+		return nil, 0, ErrUnauthorized
+	}
+
+	return uc.getReconciliationReport(ctx, nil, limit, offset)
+}
+
+func (uc *Usecase) getReconciliationReport(ctx context.Context, eventID *uuid.UUID, limit, offset int) ([]ticket.ReconciliationEntry, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	entries, err := uc.ticketRepo.GetReconciliationEntries(ctx, eventID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.ticketRepo.CountReconciliationEntries(ctx, eventID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
 // GetUpcomingTickets gets upcoming tickets for a user
 func (uc *Usecase) GetUpcomingTickets(ctx context.Context, userID uuid.UUID, limit int) ([]ticket.TicketWithDetails, error) {
 	if limit <= 0 {
@@ -597,7 +1412,7 @@ func (uc *Usecase) GetUpcomingTickets(ctx context.Context, userID uuid.UUID, lim
 		limit = 50
 	}
 
-	tickets, err := uc.ticketRepo.GetByUser(ctx, userID, limit*2, 0)
+	tickets, err := uc.ticketRepo.GetByUser(ctx, userID, nil, limit*2, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -625,7 +1440,7 @@ func (uc *Usecase) GetPastTickets(ctx context.Context, userID uuid.UUID, limit i
 		limit = 50
 	}
 
-	tickets, err := uc.ticketRepo.GetByUser(ctx, userID, limit*2, 0)
+	tickets, err := uc.ticketRepo.GetByUser(ctx, userID, nil, limit*2, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,185 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/google/uuid"
+)
+
+// waitlistClaimWindow is how long a waitlisted user has to buy an offered spot
+// before it passes to the next user in line
+const waitlistClaimWindow = 30 * time.Minute
+
+// JoinWaitlist puts the user at the end of a full event's waitlist.
+// If a spot is already free the user is offered it right away.
+func (uc *Usecase) JoinWaitlist(ctx context.Context, eventID, userID uuid.UUID) (*ticket.WaitlistEntry, error) {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, ErrEventNotFound
+	}
+	if err := event.CheckAccess(ctx, uc.eventRepo, evt, userID); err != nil {
+		return nil, err
+	}
+	if evt.HostID == userID {
+		return nil, ErrHostCannotWaitlist
+	}
+	if evt.IsOngoing() || evt.IsCompleted() {
+		return nil, ErrEventStarted
+	}
+
+	// Events with tiers allow several tickets per user, so only single-ticket
+	// events are checked. Users whose ticket was cancelled or refunded may queue again.
+	tiers, err := uc.ticketRepo.GetTiersByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tiers) == 0 {
+		existingTicket, err := uc.ticketRepo.GetUserTicketForEvent(ctx, userID, eventID)
+		if err == nil && existingTicket != nil && existingTicket.HoldsSpot() {
+			return nil, ErrAlreadyPurchased
+		}
+	}
+
+	entry := &ticket.WaitlistEntry{
+		EventID: eventID,
+		UserID:  userID,
+	}
+	if err := uc.ticketRepo.JoinWaitlist(ctx, entry); err != nil {
+		if errors.Is(err, ticket.ErrAlreadyWaitlisted) {
+			return nil, ErrAlreadyWaitlisted
+		}
+		return nil, err
+	}
+
+	uc.promoteWaitlist(ctx, evt)
+
+	return uc.GetWaitlistEntry(ctx, eventID, userID)
+}
+
+// GetWaitlistEntry gets the user's place in the event's waitlist, or their open claim
+func (uc *Usecase) GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*ticket.WaitlistEntry, error) {
+	entry, err := uc.ticketRepo.GetWaitlistEntry(ctx, eventID, userID)
+	if err != nil {
+		return nil, ErrNotWaitlisted
+	}
+
+	// A lapsed claim waits for the next promotion run to be passed on
+	if entry.Status == ticket.WaitlistOffered && !entry.HasOpenClaim(time.Now()) {
+		return nil, ErrNotWaitlisted
+	}
+
+	return entry, nil
+}
+
+// LeaveWaitlist takes the user off the event's waitlist. An open claim they
+// give up goes to the next user in line.
+func (uc *Usecase) LeaveWaitlist(ctx context.Context, eventID, userID uuid.UUID) error {
+	entry, err := uc.ticketRepo.LeaveWaitlist(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotWaitlisted
+		}
+		return err
+	}
+
+	if entry.Status == ticket.WaitlistOffered {
+		if err := uc.PromoteWaitlist(ctx, eventID); err != nil {
+			log.Printf("[Waitlist] failed to promote waitlist of event %s: %v", eventID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetEventWaitlist gets the users waiting for an event in queue order (host only).
+// Users holding a claim are listed first.
+func (uc *Usecase) GetEventWaitlist(ctx context.Context, eventID, hostID uuid.UUID, limit, offset int) ([]ticket.WaitlistEntryWithUser, int, error) {
+	if _, err := uc.getHostedEvent(ctx, eventID, hostID); err != nil {
+		return nil, 0, err
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	entries, err := uc.ticketRepo.GetEventWaitlist(ctx, eventID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.ticketRepo.CountEventWaitlist(ctx, eventID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// PromoteWaitlist offers the free spots of an event to the next users on its
+// waitlist. Call it whenever capacity frees up: a ticket is cancelled, refunded
+// or expires, or the host raises MaxAttendees.
+func (uc *Usecase) PromoteWaitlist(ctx context.Context, eventID uuid.UUID) error {
+	evt, err := uc.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return ErrEventNotFound
+	}
+
+	return uc.offerWaitlistSpots(ctx, evt)
+}
+
+// ProcessWaitlists is called by the background waitlist worker. It passes lapsed
+// claims on and fills spots that freed up without a promotion, e.g. tickets
+// whose expiry happened while the waitlist was empty.
+func (uc *Usecase) ProcessWaitlists(ctx context.Context) error {
+	eventIDs, err := uc.ticketRepo.GetWaitlistedEventIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, eventID := range eventIDs {
+		if err := uc.PromoteWaitlist(ctx, eventID); err != nil {
+			log.Printf("[Waitlist] failed to promote waitlist of event %s: %v", eventID, err)
+		}
+	}
+
+	return nil
+}
+
+// promoteWaitlist is the fire-and-forget variant used after a spot is freed.
+// A failed promotion must never fail the action that freed the spot; the
+// waitlist worker retries it.
+func (uc *Usecase) promoteWaitlist(ctx context.Context, evt *event.Event) {
+	if err := uc.offerWaitlistSpots(ctx, evt); err != nil {
+		log.Printf("[Waitlist] failed to promote waitlist of event %s: %v", evt.ID, err)
+	}
+}
+
+func (uc *Usecase) offerWaitlistSpots(ctx context.Context, evt *event.Event) error {
+	// Spots of an event that already started aren't offered anymore
+	if evt.IsOngoing() || evt.IsCompleted() {
+		return nil
+	}
+
+	offered, err := uc.ticketRepo.OfferWaitlistSpots(ctx, evt.ID, waitlistClaimWindow)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range offered {
+		log.Printf("[Waitlist] offered a spot of event %s to user %s", evt.ID, entry.UserID)
+		if entry.OfferExpiresAt != nil {
+			uc.notificationUC.NotifyWaitlistSpotOffered(ctx, entry.UserID, evt.ID, evt.Title, *entry.OfferExpiresAt)
+		}
+	}
+
+	return nil
+}
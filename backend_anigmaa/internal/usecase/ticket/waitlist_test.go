@@ -0,0 +1,285 @@
+package ticket
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	"github.com/anigmaa/backend/internal/domain/ticket"
+	"github.com/google/uuid"
+)
+
+// fakeWaitlistRepo keeps the waitlist of the fixture event in queue order and
+// offers spots the way OfferWaitlistSpots does
+type fakeWaitlistRepo struct {
+	*fakePurchaseRepo
+	evt     *event.Event
+	entries []*ticket.WaitlistEntry
+}
+
+// GetUserTicketForEvent finds the user's latest ticket for the fixture event
+func (r *fakeWaitlistRepo) GetUserTicketForEvent(ctx context.Context, userID, eventID uuid.UUID) (*ticket.Ticket, error) {
+	var latest *ticket.Ticket
+	for _, t := range r.tickets {
+		if t.UserID == userID && t.EventID == eventID && (latest == nil || t.PurchasedAt.After(latest.PurchasedAt)) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil, sql.ErrNoRows
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+func (r *fakeWaitlistRepo) JoinWaitlist(ctx context.Context, entry *ticket.WaitlistEntry) error {
+	for _, e := range r.entries {
+		if e.UserID == entry.UserID && (e.Status == ticket.WaitlistWaiting || e.Status == ticket.WaitlistOffered) {
+			return ticket.ErrAlreadyWaitlisted
+		}
+	}
+	entry.ID = uuid.New()
+	entry.Status = ticket.WaitlistWaiting
+	entry.JoinedAt = time.Now()
+	copied := *entry
+	r.entries = append(r.entries, &copied)
+	return nil
+}
+
+func (r *fakeWaitlistRepo) GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*ticket.WaitlistEntry, error) {
+	position := 0
+	for _, e := range r.entries {
+		if e.Status == ticket.WaitlistWaiting {
+			position++
+		}
+		if e.UserID == userID && (e.Status == ticket.WaitlistWaiting || e.Status == ticket.WaitlistOffered) {
+			copied := *e
+			if e.Status == ticket.WaitlistWaiting {
+				copied.Position = position
+			}
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeWaitlistRepo) LeaveWaitlist(ctx context.Context, eventID, userID uuid.UUID) (*ticket.WaitlistEntry, error) {
+	for _, e := range r.entries {
+		if e.UserID == userID && (e.Status == ticket.WaitlistWaiting || e.Status == ticket.WaitlistOffered) {
+			left := *e
+			e.Status = ticket.WaitlistLeft
+			return &left, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeWaitlistRepo) OfferWaitlistSpots(ctx context.Context, eventID uuid.UUID, claimWindow time.Duration) ([]ticket.WaitlistEntry, error) {
+	now := time.Now()
+	free := r.evt.MaxAttendees - r.evt.TicketsSold
+	for _, e := range r.entries {
+		if e.Status == ticket.WaitlistOffered && !e.HasOpenClaim(now) {
+			e.Status = ticket.WaitlistExpired
+		}
+		if e.HasOpenClaim(now) {
+			free--
+		}
+	}
+
+	offered := make([]ticket.WaitlistEntry, 0)
+	for _, e := range r.entries {
+		if free <= 0 {
+			break
+		}
+		if e.Status != ticket.WaitlistWaiting {
+			continue
+		}
+		expiresAt := now.Add(claimWindow)
+		e.Status = ticket.WaitlistOffered
+		e.OfferedAt = &now
+		e.OfferExpiresAt = &expiresAt
+		offered = append(offered, *e)
+		free--
+	}
+	return offered, nil
+}
+
+func (r *fakeWaitlistRepo) GetWaitlistedEventIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return []uuid.UUID{r.evt.ID}, nil
+}
+
+func (r *fakeWaitlistRepo) status(userID uuid.UUID) ticket.WaitlistStatus {
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if r.entries[i].UserID == userID {
+			return r.entries[i].Status
+		}
+	}
+	return ""
+}
+
+// newWaitlistFixture sets up a sold out event with 2 spots starting tomorrow
+func newWaitlistFixture() (*ticketFixture, *fakeWaitlistRepo) {
+	f, purchases := newPurchaseFixture()
+	f.evt.StartTime = time.Now().Add(24 * time.Hour)
+	f.evt.EndTime = time.Now().Add(27 * time.Hour)
+	f.evt.MaxAttendees = 2
+	f.evt.TicketsSold = 2
+
+	waitlist := &fakeWaitlistRepo{fakePurchaseRepo: purchases, evt: f.evt}
+	f.uc.ticketRepo = waitlist
+	return f, waitlist
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	f, repo := newWaitlistFixture()
+	first, second := uuid.New(), uuid.New()
+
+	for i, userID := range []uuid.UUID{first, second} {
+		entry, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, userID)
+		if err != nil {
+			t.Fatalf("JoinWaitlist() error = %v", err)
+		}
+		if entry.Status != ticket.WaitlistWaiting || entry.Position != i+1 {
+			t.Errorf("entry = %s at %d, want waiting at %d", entry.Status, entry.Position, i+1)
+		}
+	}
+
+	if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, first); err != ErrAlreadyWaitlisted {
+		t.Errorf("JoinWaitlist() again error = %v, want %v", err, ErrAlreadyWaitlisted)
+	}
+	if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, f.evt.HostID); err != ErrHostCannotWaitlist {
+		t.Errorf("JoinWaitlist() by the host error = %v, want %v", err, ErrHostCannotWaitlist)
+	}
+
+	// A spot that is already free is offered right away
+	f.evt.TicketsSold = 1
+	late := uuid.New()
+	if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, late); err != nil {
+		t.Fatalf("JoinWaitlist() error = %v", err)
+	}
+	if got := repo.status(first); got != ticket.WaitlistOffered {
+		t.Errorf("first in line is %s, want offered", got)
+	}
+	if got := repo.status(late); got != ticket.WaitlistWaiting {
+		t.Errorf("late user is %s, want waiting behind the others", got)
+	}
+}
+
+func TestJoinWaitlist_TicketHolders(t *testing.T) {
+	tests := []struct {
+		status ticket.TicketStatus
+		want   error
+	}{
+		{ticket.StatusPending, ErrAlreadyPurchased},
+		{ticket.StatusActive, ErrAlreadyPurchased},
+		{ticket.StatusCancelled, nil},
+		{ticket.StatusRefunded, nil},
+		{ticket.StatusExpired, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			f, repo := newWaitlistFixture()
+			userID := uuid.New()
+			held := &ticket.Ticket{ID: uuid.New(), UserID: userID, EventID: f.evt.ID, Status: tt.status, PurchasedAt: time.Now().Add(-time.Hour)}
+			repo.tickets[held.ID] = held
+
+			if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, userID); err != tt.want {
+				t.Errorf("JoinWaitlist() with a %s ticket error = %v, want %v", tt.status, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinWaitlist_EventStarted(t *testing.T) {
+	f, _ := newWaitlistFixture()
+	f.evt.StartTime = time.Now().Add(-time.Minute)
+
+	if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, uuid.New()); err != ErrEventStarted {
+		t.Errorf("JoinWaitlist() error = %v, want %v", err, ErrEventStarted)
+	}
+}
+
+func TestLeaveWaitlist_PassesClaimOn(t *testing.T) {
+	f, repo := newWaitlistFixture()
+	first, second := uuid.New(), uuid.New()
+	for _, userID := range []uuid.UUID{first, second} {
+		if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, userID); err != nil {
+			t.Fatalf("JoinWaitlist() error = %v", err)
+		}
+	}
+
+	f.evt.TicketsSold = 1
+	if err := f.uc.PromoteWaitlist(context.Background(), f.evt.ID); err != nil {
+		t.Fatalf("PromoteWaitlist() error = %v", err)
+	}
+	if got := repo.status(second); got != ticket.WaitlistWaiting {
+		t.Fatalf("second in line is %s, want waiting", got)
+	}
+
+	if err := f.uc.LeaveWaitlist(context.Background(), f.evt.ID, first); err != nil {
+		t.Fatalf("LeaveWaitlist() error = %v", err)
+	}
+	if got := repo.status(second); got != ticket.WaitlistOffered {
+		t.Errorf("second in line is %s, want offered the spot given up", got)
+	}
+
+	if err := f.uc.LeaveWaitlist(context.Background(), f.evt.ID, first); err != ErrNotWaitlisted {
+		t.Errorf("LeaveWaitlist() again error = %v, want %v", err, ErrNotWaitlisted)
+	}
+}
+
+func TestProcessWaitlists_PassesLapsedClaims(t *testing.T) {
+	f, repo := newWaitlistFixture()
+	first, second := uuid.New(), uuid.New()
+	for _, userID := range []uuid.UUID{first, second} {
+		if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, userID); err != nil {
+			t.Fatalf("JoinWaitlist() error = %v", err)
+		}
+	}
+
+	f.evt.TicketsSold = 1
+	if err := f.uc.PromoteWaitlist(context.Background(), f.evt.ID); err != nil {
+		t.Fatalf("PromoteWaitlist() error = %v", err)
+	}
+
+	// The first user lets the claim lapse
+	lapsed := time.Now().Add(-time.Minute)
+	repo.entries[0].OfferExpiresAt = &lapsed
+	if _, err := f.uc.GetWaitlistEntry(context.Background(), f.evt.ID, first); err != ErrNotWaitlisted {
+		t.Errorf("GetWaitlistEntry() of a lapsed claim error = %v, want %v", err, ErrNotWaitlisted)
+	}
+
+	if err := f.uc.ProcessWaitlists(context.Background()); err != nil {
+		t.Fatalf("ProcessWaitlists() error = %v", err)
+	}
+	if got := repo.status(first); got != ticket.WaitlistExpired {
+		t.Errorf("first in line is %s, want expired", got)
+	}
+	entry, err := f.uc.GetWaitlistEntry(context.Background(), f.evt.ID, second)
+	if err != nil {
+		t.Fatalf("GetWaitlistEntry() error = %v", err)
+	}
+	if !entry.HasOpenClaim(time.Now()) {
+		t.Errorf("second in line = %+v, want an open claim", entry)
+	}
+}
+
+func TestPromoteWaitlist_StartedEvent(t *testing.T) {
+	f, repo := newWaitlistFixture()
+	userID := uuid.New()
+	if _, err := f.uc.JoinWaitlist(context.Background(), f.evt.ID, userID); err != nil {
+		t.Fatalf("JoinWaitlist() error = %v", err)
+	}
+
+	f.evt.StartTime = time.Now().Add(-time.Minute)
+	f.evt.TicketsSold = 0
+	if err := f.uc.PromoteWaitlist(context.Background(), f.evt.ID); err != nil {
+		t.Fatalf("PromoteWaitlist() error = %v", err)
+	}
+	if got := repo.status(userID); got != ticket.WaitlistWaiting {
+		t.Errorf("user is %s, want no spot offered once the event started", got)
+	}
+}
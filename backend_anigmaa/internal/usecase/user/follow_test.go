@@ -0,0 +1,70 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anigmaa/backend/internal/domain/post"
+	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// fakeFollowRepo stores follows as {follower, following} pairs
+type fakeFollowRepo struct {
+	user.Repository
+	follows map[[2]uuid.UUID]bool
+}
+
+func (r *fakeFollowRepo) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return &user.User{ID: id}, nil
+}
+
+func (r *fakeFollowRepo) IsFollowing(ctx context.Context, followerID, followingID uuid.UUID) (bool, error) {
+	return r.follows[[2]uuid.UUID{followerID, followingID}], nil
+}
+
+func (r *fakeFollowRepo) Follow(ctx context.Context, followerID, followingID uuid.UUID) error {
+	r.follows[[2]uuid.UUID{followerID, followingID}] = true
+	return nil
+}
+
+func (r *fakeFollowRepo) Unfollow(ctx context.Context, followerID, followingID uuid.UUID) error {
+	delete(r.follows, [2]uuid.UUID{followerID, followingID})
+	return nil
+}
+
+// fakeTimelineRepo records the timelines invalidated
+type fakeTimelineRepo struct {
+	post.TimelineRepository
+	invalidated []uuid.UUID
+}
+
+func (r *fakeTimelineRepo) Invalidate(ctx context.Context, userID uuid.UUID) error {
+	r.invalidated = append(r.invalidated, userID)
+	return nil
+}
+
+func TestFollow_InvalidatesTimeline(t *testing.T) {
+	follower, author := uuid.New(), uuid.New()
+	timelines := &fakeTimelineRepo{}
+	uc := &Usecase{
+		userRepo:     &fakeFollowRepo{follows: make(map[[2]uuid.UUID]bool)},
+		timelineRepo: timelines,
+	}
+
+	if err := uc.Follow(context.Background(), follower, author); err != nil {
+		t.Fatalf("Follow() error = %v", err)
+	}
+	if err := uc.Unfollow(context.Background(), follower, author); err != nil {
+		t.Fatalf("Unfollow() error = %v", err)
+	}
+
+	// A rejected unfollow changes nothing, so the timeline stays cached
+	if err := uc.Unfollow(context.Background(), follower, author); err != ErrNotFollowing {
+		t.Errorf("second Unfollow() error = %v, want %v", err, ErrNotFollowing)
+	}
+
+	if len(timelines.invalidated) != 2 || timelines.invalidated[0] != follower || timelines.invalidated[1] != follower {
+		t.Errorf("invalidated %v, want the follower's timeline twice", timelines.invalidated)
+	}
+}
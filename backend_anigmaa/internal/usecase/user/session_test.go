@@ -0,0 +1,330 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/auth"
+	"github.com/anigmaa/backend/internal/domain/user"
+	"github.com/anigmaa/backend/pkg/jwt"
+	"github.com/google/uuid"
+)
+
+// fakeSessionRepo stores sessions in memory and rotates them with the same
+// compare-and-swap as the Postgres repository
+type fakeSessionRepo struct {
+	auth.SessionRepository
+	sessions map[uuid.UUID]*auth.Session
+	// beforeRotate, when set, runs just before a rotation, e.g. to let a
+	// concurrent refresh win the race
+	beforeRotate func(s *auth.Session)
+}
+
+func (r *fakeSessionRepo) CreateSession(ctx context.Context, session *auth.Session) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *fakeSessionRepo) GetSessionByID(ctx context.Context, id uuid.UUID) (*auth.Session, error) {
+	s, ok := r.sessions[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) RotateSession(ctx context.Context, id uuid.UUID, oldRefreshTokenID, newRefreshTokenID, newAccessTokenID string, expiresAt time.Time) error {
+	s, ok := r.sessions[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if r.beforeRotate != nil {
+		r.beforeRotate(s)
+	}
+	if s.RefreshTokenID != oldRefreshTokenID || s.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	s.RefreshTokenID = newRefreshTokenID
+	s.AccessTokenID = &newAccessTokenID
+	s.ExpiresAt = expiresAt
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeSession(ctx context.Context, id uuid.UUID, reason string) error {
+	s, ok := r.sessions[id]
+	if !ok || s.RevokedAt != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	s.RevokedReason = &reason
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeUserSessions(ctx context.Context, userID, exceptSessionID uuid.UUID, reason string) ([]auth.Session, error) {
+	var revoked []auth.Session
+	for _, s := range r.sessions {
+		if s.UserID != userID || s.ID == exceptSessionID || s.RevokedAt != nil {
+			continue
+		}
+		now := time.Now()
+		s.RevokedAt = &now
+		s.RevokedReason = &reason
+		revoked = append(revoked, *s)
+	}
+	return revoked, nil
+}
+
+// fakeRevocationRepo keeps revoked jtis and per-user cut-offs the way the
+// Redis repository does
+type fakeRevocationRepo struct {
+	auth.RevocationRepository
+	tokens  map[string]bool
+	cutoffs map[uuid.UUID]time.Time
+}
+
+func (r *fakeRevocationRepo) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	r.tokens[tokenID] = true
+	return nil
+}
+
+func (r *fakeRevocationRepo) IsTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	return r.tokens[tokenID], nil
+}
+
+func (r *fakeRevocationRepo) RevokeUserTokens(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error {
+	r.cutoffs[userID] = issuedBefore
+	return nil
+}
+
+func (r *fakeRevocationRepo) IsUserTokenRevoked(ctx context.Context, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	cutoff, ok := r.cutoffs[userID]
+	return ok && issuedAt.Unix() <= cutoff.Unix(), nil
+}
+
+// fakeAccountRepo serves a single user that can be deleted
+type fakeAccountRepo struct {
+	user.Repository
+	user    *user.User
+	deleted bool
+}
+
+func (r *fakeAccountRepo) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if r.deleted || id != r.user.ID {
+		return nil, sql.ErrNoRows
+	}
+	return r.user, nil
+}
+
+func (r *fakeAccountRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.deleted = true
+	return nil
+}
+
+// sessionFixture is a user usecase over in-memory sessions and revocations
+type sessionFixture struct {
+	uc          *Usecase
+	user        *user.User
+	sessions    *fakeSessionRepo
+	revocations *fakeRevocationRepo
+	jwtManager  *jwt.JWTManager
+}
+
+func newSessionFixture() *sessionFixture {
+	f := &sessionFixture{
+		user:        &user.User{ID: uuid.New(), Email: "budi@example.com"},
+		sessions:    &fakeSessionRepo{sessions: make(map[uuid.UUID]*auth.Session)},
+		revocations: &fakeRevocationRepo{tokens: make(map[string]bool), cutoffs: make(map[uuid.UUID]time.Time)},
+		jwtManager:  jwt.NewJWTManager("test-secret", time.Hour, 24*time.Hour),
+	}
+	f.uc = &Usecase{
+		userRepo:       &fakeAccountRepo{user: f.user},
+		jwtManager:     f.jwtManager,
+		revocationRepo: f.revocations,
+		sessionRepo:    f.sessions,
+	}
+	return f
+}
+
+// login starts a new device session the way a sign-in does
+func (f *sessionFixture) login(t *testing.T) (*jwt.TokenPair, uuid.UUID) {
+	t.Helper()
+	pair, err := f.uc.createSession(context.Background(), f.user, &auth.DeviceInfo{DeviceName: "Pixel 8"})
+	if err != nil {
+		t.Fatalf("createSession() error = %v", err)
+	}
+	claims, err := f.jwtManager.Verify(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	return pair, claims.GetSessionID()
+}
+
+// accessTokenID returns the jti of an access token
+func (f *sessionFixture) accessTokenID(t *testing.T, token string) string {
+	t.Helper()
+	claims, err := f.jwtManager.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	return claims.ID
+}
+
+func TestRefreshToken_Rotation(t *testing.T) {
+	f := newSessionFixture()
+	pair, sessionID := f.login(t)
+	ctx := context.Background()
+
+	resp, err := f.uc.RefreshToken(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if resp.RefreshToken == pair.RefreshToken {
+		t.Fatal("refresh token wasn't rotated")
+	}
+	session := f.sessions.sessions[sessionID]
+	if session.AccessTokenID == nil || *session.AccessTokenID != f.accessTokenID(t, resp.AccessToken) {
+		t.Error("session doesn't track the new access token")
+	}
+
+	// The rotated token keeps working until it is rotated itself
+	if _, err := f.uc.RefreshToken(ctx, resp.RefreshToken); err != nil {
+		t.Errorf("RefreshToken() with the rotated token error = %v", err)
+	}
+}
+
+func TestRefreshToken_ReuseRevokesFamily(t *testing.T) {
+	f := newSessionFixture()
+	pair, sessionID := f.login(t)
+	ctx := context.Background()
+
+	resp, err := f.uc.RefreshToken(ctx, pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	// Replaying the rotated token kills the session and its latest access token
+	if _, err := f.uc.RefreshToken(ctx, pair.RefreshToken); err != ErrTokenReused {
+		t.Fatalf("RefreshToken() with a rotated token error = %v, want %v", err, ErrTokenReused)
+	}
+	session := f.sessions.sessions[sessionID]
+	if session.RevokedReason == nil || *session.RevokedReason != auth.RevokedReasonRefreshTokenReuse {
+		t.Errorf("session revoked reason = %v, want %s", session.RevokedReason, auth.RevokedReasonRefreshTokenReuse)
+	}
+	if !f.revocations.tokens[f.accessTokenID(t, resp.AccessToken)] {
+		t.Error("latest access token of the family wasn't revoked")
+	}
+
+	// The legitimate client's newest refresh token is dead too
+	if _, err := f.uc.RefreshToken(ctx, resp.RefreshToken); err != ErrUnauthorized {
+		t.Errorf("RefreshToken() after reuse error = %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestRefreshToken_LostRotationRace(t *testing.T) {
+	f := newSessionFixture()
+	pair, sessionID := f.login(t)
+
+	// Another refresh with the same token rotates the session first
+	winnerAccessID := uuid.New().String()
+	f.sessions.beforeRotate = func(s *auth.Session) {
+		s.RefreshTokenID = uuid.New().String()
+		s.AccessTokenID = &winnerAccessID
+	}
+
+	if _, err := f.uc.RefreshToken(context.Background(), pair.RefreshToken); err != ErrTokenReused {
+		t.Fatalf("RefreshToken() error = %v, want %v", err, ErrTokenReused)
+	}
+	if f.sessions.sessions[sessionID].RevokedAt == nil {
+		t.Error("session wasn't revoked")
+	}
+	if !f.revocations.tokens[winnerAccessID] {
+		t.Error("the winning refresh's access token wasn't revoked")
+	}
+}
+
+func TestRefreshToken_UserCutoff(t *testing.T) {
+	f := newSessionFixture()
+	pair, _ := f.login(t)
+
+	if err := f.uc.DeleteAccount(context.Background(), f.user.ID); err != nil {
+		t.Fatalf("DeleteAccount() error = %v", err)
+	}
+	if _, ok := f.revocations.cutoffs[f.user.ID]; !ok {
+		t.Fatal("no revocation cut-off recorded for the deleted user")
+	}
+
+	if _, err := f.uc.RefreshToken(context.Background(), pair.RefreshToken); err != ErrUnauthorized {
+		t.Errorf("RefreshToken() after account deletion error = %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(f *sessionFixture, sessionID uuid.UUID) (userID, target uuid.UUID)
+		want    error
+		revoked bool
+	}{
+		{"own session", func(f *sessionFixture, sessionID uuid.UUID) (uuid.UUID, uuid.UUID) {
+			return f.user.ID, sessionID
+		}, nil, true},
+		{"another user's session", func(f *sessionFixture, sessionID uuid.UUID) (uuid.UUID, uuid.UUID) {
+			return uuid.New(), sessionID
+		}, ErrSessionNotFound, false},
+		{"unknown session", func(f *sessionFixture, sessionID uuid.UUID) (uuid.UUID, uuid.UUID) {
+			return f.user.ID, uuid.New()
+		}, ErrSessionNotFound, false},
+		{"already revoked", func(f *sessionFixture, sessionID uuid.UUID) (uuid.UUID, uuid.UUID) {
+			f.sessions.RevokeSession(context.Background(), sessionID, auth.RevokedReasonLogout) //nolint:errcheck
+			return f.user.ID, sessionID
+		}, ErrSessionNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSessionFixture()
+			pair, sessionID := f.login(t)
+			userID, target := tt.setup(f, sessionID)
+
+			if err := f.uc.RevokeSession(context.Background(), userID, target); err != tt.want {
+				t.Fatalf("RevokeSession() error = %v, want %v", err, tt.want)
+			}
+			if revoked := f.revocations.tokens[pair.AccessTokenID]; revoked != tt.revoked {
+				t.Errorf("access token revoked = %v, want %v", revoked, tt.revoked)
+			}
+			if tt.revoked {
+				if _, err := f.uc.RefreshToken(context.Background(), pair.RefreshToken); err != ErrUnauthorized {
+					t.Errorf("RefreshToken() of a revoked session error = %v, want %v", err, ErrUnauthorized)
+				}
+			}
+		})
+	}
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	f := newSessionFixture()
+	current, currentID := f.login(t)
+	phone, _ := f.login(t)
+	laptop, _ := f.login(t)
+
+	n, err := f.uc.RevokeOtherSessions(context.Background(), f.user.ID, currentID)
+	if err != nil {
+		t.Fatalf("RevokeOtherSessions() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("revoked %d sessions, want 2", n)
+	}
+	for _, pair := range []*jwt.TokenPair{phone, laptop} {
+		if !f.revocations.tokens[pair.AccessTokenID] {
+			t.Errorf("access token %s of another session wasn't revoked", pair.AccessTokenID)
+		}
+	}
+	if f.revocations.tokens[current.AccessTokenID] || f.sessions.sessions[currentID].RevokedAt != nil {
+		t.Error("the current session was revoked")
+	}
+}
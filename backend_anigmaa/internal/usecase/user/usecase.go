@@ -9,14 +9,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
     "regexp"
     "strings"
 	"time"
 
 	"github.com/anigmaa/backend/internal/domain/auth"
+	"github.com/anigmaa/backend/internal/domain/post"
 	"github.com/anigmaa/backend/internal/domain/user"
+	emailUsecase "github.com/anigmaa/backend/internal/usecase/email"
+	notificationUsecase "github.com/anigmaa/backend/internal/usecase/notification"
 	"github.com/anigmaa/backend/pkg/jwt"
+	"github.com/anigmaa/backend/pkg/utils"
 	"github.com/google/uuid"
 )
 
@@ -28,6 +33,9 @@ var (
 	ErrCannotFollowSelf = errors.New("cannot follow yourself")
 	ErrInvalidToken     = errors.New("invalid or expired token")
 	ErrTokenAlreadyUsed = errors.New("token has already been used")
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrTokenReused      = errors.New("refresh token reuse detected - session revoked")
+	ErrInvalidCursor    = errors.New("invalid cursor")
 )
 
 // Usecase handles user business logic
@@ -36,15 +44,25 @@ type Usecase struct {
 	authTokenRepo  auth.Repository
 	jwtManager     *jwt.JWTManager
 	googleClientID string
+	notificationUC *notificationUsecase.Usecase
+	emailUC        *emailUsecase.Usecase
+	revocationRepo auth.RevocationRepository
+	sessionRepo    auth.SessionRepository
+	timelineRepo   post.TimelineRepository
 }
 
 // NewUsecase creates a new user usecase
-func NewUsecase(userRepo user.Repository, authTokenRepo auth.Repository, jwtManager *jwt.JWTManager, googleClientID string) *Usecase {
+func NewUsecase(userRepo user.Repository, authTokenRepo auth.Repository, jwtManager *jwt.JWTManager, googleClientID string, notificationUC *notificationUsecase.Usecase, emailUC *emailUsecase.Usecase, revocationRepo auth.RevocationRepository, sessionRepo auth.SessionRepository, timelineRepo post.TimelineRepository) *Usecase {
 	return &Usecase{
 		userRepo:       userRepo,
 		authTokenRepo:  authTokenRepo,
 		jwtManager:     jwtManager,
 		googleClientID: googleClientID,
+		notificationUC: notificationUC,
+		emailUC:        emailUC,
+		revocationRepo: revocationRepo,
+		sessionRepo:    sessionRepo,
+		timelineRepo:   timelineRepo,
 	}
 }
 
@@ -64,31 +82,184 @@ func (uc *Usecase) RefreshToken(ctx context.Context, refreshToken string) (*Auth
 		return nil, ErrUnauthorized
 	}
 
+	// Only session-bound refresh tokens can be exchanged; legacy stateless tokens must log in again
+	sessionID := claims.GetSessionID()
+	if !claims.IsRefreshToken() || sessionID == uuid.Nil {
+		return nil, ErrUnauthorized
+	}
+
+	// Reject refresh tokens revoked by logout or account deletion
+	if revoked, err := auth.IsRevoked(ctx, uc.revocationRepo, claims); err != nil {
+		return nil, err
+	} else if revoked {
+		return nil, ErrUnauthorized
+	}
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrUnauthorized
+		}
+		return nil, err
+	}
+	if !session.IsActive() || session.UserID != claims.UserID {
+		return nil, ErrUnauthorized
+	}
+
+	// A validly signed token from this family that is not the current one has
+	// already been rotated: someone is replaying it, so kill the whole family
+	if session.RefreshTokenID != claims.ID {
+		uc.revokeReusedSession(ctx, session)
+		return nil, ErrTokenReused
+	}
+
 	// Get user
 	existingUser, err := uc.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 
-	// Generate new tokens
-	accessToken, err := uc.jwtManager.Generate(existingUser.ID, existingUser.Email)
+	// Rotate: issue a new pair and make it the only valid refresh token of the session
+	pair, err := uc.jwtManager.GenerateTokenPair(existingUser.ID, existingUser.Email, session.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, err := uc.jwtManager.GenerateRefreshToken(existingUser.ID, existingUser.Email)
-	if err != nil {
+	if err := uc.sessionRepo.RotateSession(ctx, session.ID, claims.ID, pair.RefreshTokenID, pair.AccessTokenID, pair.RefreshExpiresAt); err != nil {
+		if err == sql.ErrNoRows {
+			// Lost a race against another refresh with the same token. The winner
+			// issued a newer access token, so re-read the session to revoke that one
+			if current, err := uc.sessionRepo.GetSessionByID(ctx, session.ID); err == nil {
+				session = current
+			}
+			uc.revokeReusedSession(ctx, session)
+			return nil, ErrTokenReused
+		}
 		return nil, err
 	}
 
 	return &AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
 		User:         existingUser,
 		ExpiresIn:    3600, // 1 hour
 	}, nil
 }
 
+// createSession starts a new device session and issues its first token pair
+func (uc *Usecase) createSession(ctx context.Context, u *user.User, device *auth.DeviceInfo) (*jwt.TokenPair, error) {
+	sessionID := uuid.New()
+	pair, err := uc.jwtManager.GenerateTokenPair(u.ID, u.Email, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &auth.Session{
+		ID:             sessionID,
+		UserID:         u.ID,
+		RefreshTokenID: pair.RefreshTokenID,
+		AccessTokenID:  &pair.AccessTokenID,
+		ExpiresAt:      pair.RefreshExpiresAt,
+	}
+	if device != nil {
+		session.DeviceName = optionalString(device.DeviceName)
+		session.UserAgent = optionalString(device.UserAgent)
+		session.IPAddress = optionalString(device.IPAddress)
+	}
+
+	if err := uc.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// revokeReusedSession revokes a session after refresh token reuse, including its latest access token
+func (uc *Usecase) revokeReusedSession(ctx context.Context, session *auth.Session) {
+	log.Printf("[UserUsecase] Refresh token reuse detected for session %s (user %s) - revoking", session.ID, session.UserID)
+	if err := uc.revokeSession(ctx, session, auth.RevokedReasonRefreshTokenReuse); err != nil && err != sql.ErrNoRows {
+		log.Printf("[UserUsecase] Failed to revoke session %s: %v", session.ID, err)
+	}
+}
+
+// revokeSession marks a session revoked and blacklists its latest access token
+func (uc *Usecase) revokeSession(ctx context.Context, session *auth.Session, reason string) error {
+	if err := uc.sessionRepo.RevokeSession(ctx, session.ID, reason); err != nil {
+		return err
+	}
+	uc.revokeSessionAccessToken(ctx, session)
+	return nil
+}
+
+// revokeSessionAccessToken blacklists the latest access token of a session for its maximum lifetime
+func (uc *Usecase) revokeSessionAccessToken(ctx context.Context, session *auth.Session) {
+	if session.AccessTokenID == nil {
+		return
+	}
+	if err := uc.revocationRepo.RevokeToken(ctx, *session.AccessTokenID, uc.jwtManager.TokenDuration()); err != nil {
+		log.Printf("[UserUsecase] Failed to revoke access token of session %s: %v", session.ID, err)
+	}
+}
+
+// GetSessions lists the user's active device sessions
+func (uc *Usecase) GetSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]auth.SessionResponse, error) {
+	sessions, err := uc.sessionRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]auth.SessionResponse, len(sessions))
+	for i := range sessions {
+		responses[i] = sessions[i].ToResponse(currentSessionID)
+	}
+	return responses, nil
+}
+
+// RevokeSession revokes one of the user's device sessions
+func (uc *Usecase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	// Don't reveal other users' sessions
+	if session.UserID != userID || !session.IsActive() {
+		return ErrSessionNotFound
+	}
+
+	if err := uc.revokeSession(ctx, session, auth.RevokedReasonUserRevoked); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeOtherSessions revokes every session of the user except the current one
+func (uc *Usecase) RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int, error) {
+	revoked, err := uc.sessionRepo.RevokeUserSessions(ctx, userID, currentSessionID, auth.RevokedReasonUserRevoked)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range revoked {
+		uc.revokeSessionAccessToken(ctx, &revoked[i])
+	}
+	return len(revoked), nil
+}
+
+// optionalString returns nil for empty strings
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 // GetProfile gets a user's complete profile
 func (uc *Usecase) GetProfile(ctx context.Context, userID uuid.UUID) (*user.UserProfile, error) {
 	profile, err := uc.userRepo.GetProfile(ctx, userID)
@@ -246,7 +417,15 @@ func (uc *Usecase) Follow(ctx context.Context, followerID, followingID uuid.UUID
 	}
 
 	// Create follow relationship
-	return uc.userRepo.Follow(ctx, followerID, followingID)
+	if err := uc.userRepo.Follow(ctx, followerID, followingID); err != nil {
+		return err
+	}
+
+	// The cached home timeline lacks the new author's posts
+	uc.invalidateTimeline(ctx, followerID)
+
+	uc.notificationUC.NotifyFollowed(ctx, followerID, followingID)
+	return nil
 }
 
 // Unfollow unfollows a user
@@ -261,18 +440,60 @@ func (uc *Usecase) Unfollow(ctx context.Context, followerID, followingID uuid.UU
 	}
 
 	// Remove follow relationship
-	return uc.userRepo.Unfollow(ctx, followerID, followingID)
+	if err := uc.userRepo.Unfollow(ctx, followerID, followingID); err != nil {
+		return err
+	}
+
+	// The cached home timeline still holds the unfollowed author's posts
+	uc.invalidateTimeline(ctx, followerID)
+	return nil
+}
+
+// invalidateTimeline drops a user's cached home timeline after their follows changed
+func (uc *Usecase) invalidateTimeline(ctx context.Context, userID uuid.UUID) {
+	if err := uc.timelineRepo.Invalidate(ctx, userID); err != nil {
+		log.Printf("[UserUsecase] Failed to invalidate timeline of user %s: %v", userID, err)
+	}
 }
 
-// GetFollowers gets a user's followers
-func (uc *Usecase) GetFollowers(ctx context.Context, userID uuid.UUID, limit, offset int) ([]user.User, error) {
+// GetFollowers gets a user's followers, most recent first.
+// Returns the page and the cursor for the next page ("" when there is none).
+func (uc *Usecase) GetFollowers(ctx context.Context, userID uuid.UUID, cursor string, limit, offset int) ([]user.Follower, string, error) {
 	// Check if user exists
 	_, err := uc.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, ErrUserNotFound
+		return nil, "", ErrUserNotFound
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
 	}
 
-	return uc.userRepo.GetFollowers(ctx, userID, limit, offset)
+	position, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	if position != nil {
+		offset = 0
+	}
+
+	// Fetch one extra row to know whether another page exists
+	followers, err := uc.userRepo.GetFollowers(ctx, userID, position, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(followers) > limit {
+		followers = followers[:limit]
+		last := followers[len(followers)-1]
+		nextCursor = utils.EncodeCursor(last.FollowedAt, last.ID)
+	}
+
+	return followers, nextCursor, nil
 }
 
 // GetFollowing gets users that a user is following
@@ -353,6 +574,36 @@ func (uc *Usecase) VerifyEmail(ctx context.Context, userID uuid.UUID) error {
 	return uc.userRepo.Update(ctx, existingUser)
 }
 
+// Logout revokes the access token used for the request, ends its device session
+// and, if given, revokes the client's refresh token
+func (uc *Usecase) Logout(ctx context.Context, accessClaims *jwt.Claims, refreshToken string) error {
+	if err := uc.revocationRepo.RevokeToken(ctx, accessClaims.ID, accessClaims.RemainingLifetime()); err != nil {
+		return err
+	}
+
+	// Ending the session makes its refresh token unusable even if the client doesn't send it
+	if sessionID := accessClaims.GetSessionID(); sessionID != uuid.Nil {
+		if err := uc.sessionRepo.RevokeSession(ctx, sessionID, auth.RevokedReasonLogout); err != nil && err != sql.ErrNoRows {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	refreshClaims, err := uc.jwtManager.Verify(refreshToken)
+	if err != nil {
+		// Expired or malformed refresh tokens are unusable anyway
+		return nil
+	}
+	if refreshClaims.UserID != accessClaims.UserID {
+		return ErrUnauthorized
+	}
+
+	return uc.revocationRepo.RevokeToken(ctx, refreshClaims.ID, refreshClaims.RemainingLifetime())
+}
+
 // DeleteAccount deletes a user account
 func (uc *Usecase) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
 	// Check if user exists
@@ -361,7 +612,22 @@ func (uc *Usecase) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
 		return ErrUserNotFound
 	}
 
-	return uc.userRepo.Delete(ctx, userID)
+	// End all device sessions before the user row (and its sessions) disappear
+	if _, err := uc.sessionRepo.RevokeUserSessions(ctx, userID, uuid.Nil, auth.RevokedReasonAccountDeleted); err != nil {
+		log.Printf("[UserUsecase] Failed to revoke sessions of user %s: %v", userID, err)
+	}
+
+	if err := uc.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	// Revoke every access and refresh token issued so far; the cut-off only has
+	// to outlive the longest-lived token
+	if err := uc.revocationRepo.RevokeUserTokens(ctx, userID, time.Now(), uc.jwtManager.RefreshDuration()); err != nil {
+		log.Printf("[UserUsecase] Failed to revoke tokens of deleted user %s: %v", userID, err)
+	}
+
+	return nil
 }
 
 // GoogleTokenInfo represents the user info from Google ID token
@@ -398,7 +664,7 @@ type GoogleTokenInfo struct {
 }
 
 // LoginWithGoogle authenticates a user using Google ID token
-func (uc *Usecase) LoginWithGoogle(ctx context.Context, req *user.GoogleAuthRequest) (*AuthResponse, error) {
+func (uc *Usecase) LoginWithGoogle(ctx context.Context, req *user.GoogleAuthRequest, device *auth.DeviceInfo) (*AuthResponse, error) {
 	fmt.Printf("DEBUG: LoginWithGoogle called\n")
 
 	// Verify Google ID token and get user info
@@ -464,6 +730,7 @@ func (uc *Usecase) LoginWithGoogle(ctx context.Context, req *user.GoogleAuthRequ
 		fmt.Printf("DEBUG: User created successfully\n")
 
 		// No email verification needed - Google already verifies emails
+		uc.emailUC.SendWelcome(ctx, newUser)
 
 		existingUser = newUser
 	} else {
@@ -479,22 +746,19 @@ func (uc *Usecase) LoginWithGoogle(ctx context.Context, req *user.GoogleAuthRequ
 
 	// Generate tokens
 	fmt.Printf("DEBUG: Generating JWT tokens for user %s\n", existingUser.ID)
-	accessToken, err := uc.jwtManager.Generate(existingUser.ID, existingUser.Email)
-	if err != nil {
-		fmt.Printf("DEBUG: Failed to generate access token: %v\n", err)
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	if device != nil && device.DeviceName == "" {
+		device.DeviceName = req.DeviceName
 	}
-
-	refreshToken, err := uc.jwtManager.GenerateRefreshToken(existingUser.ID, existingUser.Email)
+	pair, err := uc.createSession(ctx, existingUser, device)
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to generate refresh token: %v\n", err)
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		log.Printf("[UserUsecase] Failed to create session for user %s: %v", existingUser.ID, err)
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
 	fmt.Printf("DEBUG: Login successful, returning tokens\n")
 	return &AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
 		User:         existingUser,
 		ExpiresIn:    3600, // 1 hour
 	}, nil
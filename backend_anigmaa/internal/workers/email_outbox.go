@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	email_uc "github.com/anigmaa/backend/internal/usecase/email"
+)
+
+// EmailOutboxWorker periodically sends the emails queued in the outbox and
+// retries failed deliveries. Instances claim emails with row locks, so it's
+// safe to run on every server instance.
+//
+// Run it in a goroutine via Start(); stop it by cancelling the context.
+type EmailOutboxWorker struct {
+	emailUsecase *email_uc.Usecase
+	interval     time.Duration
+}
+
+// NewEmailOutboxWorker creates a worker that runs every interval.
+// Recommended interval: 30 seconds; emails go out within that long.
+func NewEmailOutboxWorker(uc *email_uc.Usecase, interval time.Duration) *EmailOutboxWorker {
+	return &EmailOutboxWorker{
+		emailUsecase: uc,
+		interval:     interval,
+	}
+}
+
+// Start runs the delivery loop until ctx is cancelled. Call in a goroutine.
+// The first run happens right away so emails queued during downtime go out.
+func (w *EmailOutboxWorker) Start(ctx context.Context) {
+	log.Printf("[EmailOutbox] worker started (interval=%s)", w.interval)
+
+	w.run(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[EmailOutbox] worker stopped")
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *EmailOutboxWorker) run(ctx context.Context) {
+	if err := w.emailUsecase.ProcessOutbox(ctx); err != nil {
+		log.Printf("[EmailOutbox] error during delivery run: %v", err)
+	}
+}
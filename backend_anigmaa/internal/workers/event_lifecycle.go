@@ -0,0 +1,85 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	event_uc "github.com/anigmaa/backend/internal/usecase/event"
+)
+
+// eventLifecycleLockKey is the lock held by the instance running a lifecycle pass
+const eventLifecycleLockKey = "lock:workers:event_lifecycle"
+
+// Locker is a lock shared by every server instance (see cache.RedisClient)
+type Locker interface {
+	AcquireLock(ctx context.Context, key string, ttl time.Duration) (token string, ok bool, err error)
+	ReleaseLock(ctx context.Context, key, token string) error
+}
+
+// EventLifecycleWorker periodically moves events from upcoming to ongoing to
+// ended as their times pass, and reminds ticket holders 24 hours and 1 hour
+// before an event starts. Only one server instance runs a pass at a time;
+// the others skip it.
+//
+// Run it in a goroutine via Start(); stop it by cancelling the context.
+type EventLifecycleWorker struct {
+	eventUsecase *event_uc.Usecase
+	locker       Locker
+	interval     time.Duration
+}
+
+// NewEventLifecycleWorker creates a worker that runs every interval.
+// Recommended interval: 1 minute; statuses and reminders lag by up to that long.
+func NewEventLifecycleWorker(uc *event_uc.Usecase, locker Locker, interval time.Duration) *EventLifecycleWorker {
+	return &EventLifecycleWorker{
+		eventUsecase: uc,
+		locker:       locker,
+		interval:     interval,
+	}
+}
+
+// Start runs the lifecycle loop until ctx is cancelled. Call in a goroutine.
+// The first run happens right away so statuses catch up after downtime.
+func (w *EventLifecycleWorker) Start(ctx context.Context) {
+	log.Printf("[EventLifecycle] worker started (interval=%s)", w.interval)
+
+	w.run(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[EventLifecycle] worker stopped")
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *EventLifecycleWorker) run(ctx context.Context) {
+	// The lock outlives a stuck pass by at most one interval
+	token, ok, err := w.locker.AcquireLock(ctx, eventLifecycleLockKey, w.interval)
+	if err != nil {
+		log.Printf("[EventLifecycle] failed to acquire lock: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer func() {
+		if err := w.locker.ReleaseLock(context.Background(), eventLifecycleLockKey, token); err != nil {
+			log.Printf("[EventLifecycle] failed to release lock: %v", err)
+		}
+	}()
+
+	if err := w.eventUsecase.AdvanceEventStatuses(ctx); err != nil {
+		log.Printf("[EventLifecycle] error during status run: %v", err)
+	}
+	if err := w.eventUsecase.SendEventReminders(ctx); err != nil {
+		log.Printf("[EventLifecycle] error during reminder run: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anigmaa/backend/internal/domain/event"
+	event_uc "github.com/anigmaa/backend/internal/usecase/event"
+	"github.com/google/uuid"
+)
+
+// fakeLocker is a lock that is either free or held by another instance
+type fakeLocker struct {
+	heldElsewhere bool
+	err           error
+	held          bool
+	released      int
+}
+
+func (l *fakeLocker) AcquireLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	if l.err != nil {
+		return "", false, l.err
+	}
+	if l.heldElsewhere || l.held {
+		return "", false, nil
+	}
+	l.held = true
+	return "token", true, nil
+}
+
+func (l *fakeLocker) ReleaseLock(ctx context.Context, key, token string) error {
+	l.held = false
+	l.released++
+	return nil
+}
+
+// lifecycleEventRepo counts the lifecycle passes run against it
+type lifecycleEventRepo struct {
+	event.Repository
+	transitions int
+	claims      int
+}
+
+func (r *lifecycleEventRepo) TransitionStatuses(ctx context.Context, now time.Time) ([]event.StatusTransition, error) {
+	r.transitions++
+	return nil, errors.New("connection reset")
+}
+
+func (r *lifecycleEventRepo) ClaimReminders(ctx context.Context, kind event.ReminderKind, from, until time.Time) ([]event.Event, error) {
+	r.claims++
+	return nil, nil
+}
+
+func (r *lifecycleEventRepo) GetAttendeeIDs(ctx context.Context, eventID, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func TestEventLifecycleWorker_Run(t *testing.T) {
+	tests := []struct {
+		name         string
+		locker       *fakeLocker
+		wantRun      bool
+		wantReleased int
+	}{
+		{"lock free", &fakeLocker{}, true, 1},
+		{"lock held by another instance", &fakeLocker{heldElsewhere: true}, false, 0},
+		{"lock unavailable", &fakeLocker{err: errors.New("redis down")}, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &lifecycleEventRepo{}
+			w := NewEventLifecycleWorker(event_uc.NewUsecase(repo, nil, nil, nil, nil, nil), tt.locker, time.Minute)
+
+			w.run(context.Background())
+
+			// A failed status pass must not hold back the reminders
+			if ran := repo.transitions == 1 && repo.claims > 0; ran != tt.wantRun {
+				t.Errorf("pass ran = %v (transitions %d, claims %d), want %v", ran, repo.transitions, repo.claims, tt.wantRun)
+			}
+			if tt.locker.released != tt.wantReleased || tt.locker.held {
+				t.Errorf("lock released %d times (held %v), want %d", tt.locker.released, tt.locker.held, tt.wantReleased)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	event_uc "github.com/anigmaa/backend/internal/usecase/event"
+)
+
+// EventSeriesWorker periodically generates the upcoming occurrences of
+// recurring event series, keeping them filled up to the generation horizon.
+//
+// Run it in a goroutine via Start(); stop it by cancelling the context.
+type EventSeriesWorker struct {
+	eventUsecase *event_uc.Usecase
+	interval     time.Duration
+}
+
+// NewEventSeriesWorker creates a worker that runs every interval.
+// Recommended interval: 1 hour; each series is extended about once a day.
+func NewEventSeriesWorker(uc *event_uc.Usecase, interval time.Duration) *EventSeriesWorker {
+	return &EventSeriesWorker{
+		eventUsecase: uc,
+		interval:     interval,
+	}
+}
+
+// Start runs the generation loop until ctx is cancelled. Call in a goroutine.
+// The first run happens right away so series catch up after downtime.
+func (w *EventSeriesWorker) Start(ctx context.Context) {
+	log.Printf("[EventSeries] worker started (interval=%s)", w.interval)
+
+	w.run(ctx)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[EventSeries] worker stopped")
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *EventSeriesWorker) run(ctx context.Context) {
+	if err := w.eventUsecase.GenerateSeriesOccurrences(ctx); err != nil {
+		log.Printf("[EventSeries] error during generation run: %v", err)
+	}
+}
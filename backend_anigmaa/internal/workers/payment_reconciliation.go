@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ticket_uc "github.com/anigmaa/backend/internal/usecase/ticket"
+)
+
+// PaymentReconciliationWorker periodically asks the payment gateway for the
+// status of transactions that are still pending locally, so a payment whose
+// webhook was lost is applied instead of waiting for TicketExpiryWorker to
+// expire the ticket. Discrepancies are recorded in the reconciliation report.
+//
+// Run it in a goroutine via Start(); stop it by cancelling the context.
+type PaymentReconciliationWorker struct {
+	ticketUsecase *ticket_uc.Usecase
+	interval      time.Duration
+	minAge        time.Duration
+}
+
+// NewPaymentReconciliationWorker creates a worker that runs every interval and
+// checks transactions pending for longer than minAge. minAge gives the regular
+// webhook time to arrive first.
+// Recommended: interval 10 minutes, minAge 15 minutes.
+func NewPaymentReconciliationWorker(uc *ticket_uc.Usecase, interval, minAge time.Duration) *PaymentReconciliationWorker {
+	return &PaymentReconciliationWorker{
+		ticketUsecase: uc,
+		interval:      interval,
+		minAge:        minAge,
+	}
+}
+
+// Start runs the reconciliation loop until ctx is cancelled. Call in a goroutine.
+func (w *PaymentReconciliationWorker) Start(ctx context.Context) {
+	log.Printf("[PaymentReconciliation] worker started (interval=%s, min_age=%s)", w.interval, w.minAge)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	// Run once immediately on startup to catch webhooks lost while the server was down.
+	w.run(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[PaymentReconciliation] worker stopped")
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *PaymentReconciliationWorker) run(ctx context.Context) {
+	if err := w.ticketUsecase.ReconcilePendingPayments(ctx, w.minAge); err != nil {
+		log.Printf("[PaymentReconciliation] error during reconciliation run: %v", err)
+	}
+}
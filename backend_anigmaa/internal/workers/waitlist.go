@@ -0,0 +1,47 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ticket_uc "github.com/anigmaa/backend/internal/usecase/ticket"
+)
+
+// WaitlistWorker periodically passes lapsed waitlist claims to the next user
+// in line and offers spots that freed up without triggering a promotion.
+//
+// Run it in a goroutine via Start(); stop it by cancelling the context.
+type WaitlistWorker struct {
+	ticketUsecase *ticket_uc.Usecase
+	interval      time.Duration
+}
+
+// NewWaitlistWorker creates a worker that runs every interval.
+// Recommended interval: 1 minute, well below the 30 minute claim window.
+func NewWaitlistWorker(uc *ticket_uc.Usecase, interval time.Duration) *WaitlistWorker {
+	return &WaitlistWorker{
+		ticketUsecase: uc,
+		interval:      interval,
+	}
+}
+
+// Start runs the waitlist loop until ctx is cancelled. Call in a goroutine.
+func (w *WaitlistWorker) Start(ctx context.Context) {
+	log.Printf("[Waitlist] worker started (interval=%s)", w.interval)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[Waitlist] worker stopped")
+			return
+		case <-ticker.C:
+			if err := w.ticketUsecase.ProcessWaitlists(ctx); err != nil {
+				log.Printf("[Waitlist] error during waitlist run: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+-- ============================================================================
+-- AUTH SESSIONS TABLE
+-- ============================================================================
+-- Persists refresh tokens as device sessions. Each session is one refresh
+-- token family: every refresh rotates refresh_token_id, and presenting an
+-- older (already rotated) token from the same family revokes the session.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),  -- Also the token family ID (JWT "sid" claim)
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_id VARCHAR(64) NOT NULL,  -- jti of the only refresh token currently valid for this session
+    access_token_id VARCHAR(64),  -- jti of the latest access token, revoked together with the session
+    device_name VARCHAR(255),
+    user_agent VARCHAR(500),
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_reason VARCHAR(50)  -- logout, user_revoked, refresh_token_reuse, account_deleted
+);
+
+-- ============================================================================
+-- INDEXES
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user ON auth_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user_active ON auth_sessions(user_id, last_used_at DESC) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_expires ON auth_sessions(expires_at);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created table:
+-- - auth_sessions: Refresh token families / device sessions
+--
+-- Features:
+-- - Refresh token rotation (refresh_token_id is replaced on every refresh)
+-- - Reuse detection (a rotated token revokes the whole family)
+-- - Per-device listing and revocation
+-- ============================================================================
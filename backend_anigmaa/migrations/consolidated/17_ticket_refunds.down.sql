@@ -0,0 +1,6 @@
+-- ============================================================================
+-- ROLLBACK TICKET REFUNDS
+-- ============================================================================
+
+DROP TABLE IF EXISTS ticket_refunds;
+DROP TYPE IF EXISTS refund_status;
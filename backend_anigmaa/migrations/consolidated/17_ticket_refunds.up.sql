@@ -0,0 +1,59 @@
+-- ============================================================================
+-- TICKET REFUNDS TABLE
+-- ============================================================================
+-- Tracks refunds requested from the payment provider for cancelled paid
+-- tickets. A refund starts as 'pending' and moves to 'succeeded' or 'failed'
+-- either from the provider's API response or from a refund webhook.
+-- ============================================================================
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'refund_status') THEN
+        CREATE TYPE refund_status AS ENUM ('pending', 'succeeded', 'failed');
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS ticket_refunds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    transaction_id VARCHAR(255) NOT NULL,  -- Order ID of the original payment (ticket_transactions.transaction_id)
+    refund_key VARCHAR(255) UNIQUE NOT NULL,  -- Idempotency key sent to the provider
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount >= 0),
+    reason VARCHAR(255),
+    method VARCHAR(20) NOT NULL,  -- refund, direct_refund
+    status refund_status NOT NULL DEFAULT 'pending',
+    partial BOOLEAN NOT NULL DEFAULT FALSE,  -- Part of the payment, refunded outside the app; the ticket stays active
+    provider_refund_id VARCHAR(255),  -- Midtrans refund_chargeback_id
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- ============================================================================
+-- INDEXES
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_ticket_refunds_ticket ON ticket_refunds(ticket_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_refunds_transaction ON ticket_refunds(transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_refunds_status ON ticket_refunds(status);
+
+-- A cancelled ticket is refunded once; a failed refund is retried under its own key
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_refunds_one_per_ticket ON ticket_refunds(ticket_id)
+    WHERE status IN ('pending', 'succeeded') AND NOT partial;
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created table:
+-- - ticket_refunds: Provider refunds for cancelled tickets
+--
+-- Features:
+-- - Refund status tracking (pending, succeeded, failed)
+-- - Idempotent refund keys for safe retries and webhook reconciliation
+-- - At most one pending or succeeded cancellation refund per ticket
+-- ============================================================================
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- ROLLBACK PAYMENT RECONCILIATION REPORTS
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_ticket_transactions_pending_checked;
+ALTER TABLE ticket_transactions DROP COLUMN IF EXISTS last_checked_at;
+DROP TABLE IF EXISTS payment_reconciliation_reports;
@@ -0,0 +1,51 @@
+-- ============================================================================
+-- PAYMENT RECONCILIATION REPORTS TABLE
+-- ============================================================================
+-- Records discrepancies found by the payment reconciliation worker, which
+-- polls the payment provider for transactions still 'pending' locally
+-- (usually because a webhook was lost). Hosts see the entries for their
+-- events; admins see all of them.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS payment_reconciliation_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id VARCHAR(255) NOT NULL,  -- ticket_transactions.transaction_id (order ID)
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    discrepancy VARCHAR(50) NOT NULL,  -- missed_webhook, paid_after_expiry, amount_mismatch, apply_failed
+    local_status VARCHAR(20) NOT NULL,  -- Ticket status when the discrepancy was found
+    provider_status VARCHAR(50) NOT NULL,  -- Raw provider status, e.g. settlement
+    local_amount DECIMAL(10, 2) NOT NULL,
+    provider_amount VARCHAR(50),  -- gross_amount as reported by the provider
+    details TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- The worker re-checks unresolved transactions every run; report each discrepancy once
+    UNIQUE(transaction_id, discrepancy)
+);
+
+-- When the worker last asked the provider about a pending transaction. Each run
+-- checks the least recently checked ones first, so transactions that stay
+-- pending can't crowd newer ones out of the batch.
+ALTER TABLE ticket_transactions ADD COLUMN IF NOT EXISTS last_checked_at TIMESTAMP WITH TIME ZONE;
+
+-- ============================================================================
+-- INDEXES
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_payment_reconciliation_event ON payment_reconciliation_reports(event_id);
+CREATE INDEX IF NOT EXISTS idx_payment_reconciliation_created ON payment_reconciliation_reports(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ticket_transactions_pending_checked ON ticket_transactions(last_checked_at NULLS FIRST, created_at) WHERE status = 'pending';
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created table:
+-- - payment_reconciliation_reports: Discrepancies between local and provider payment status
+--
+-- Modified table:
+-- - ticket_transactions: last_checked_at
+--
+-- Features:
+-- - One entry per transaction and discrepancy type
+-- - Partial index for finding the least recently checked pending transactions
+-- ============================================================================
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- ROLLBACK TICKET TIERS
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_tickets_user_event_untiered;
+DROP INDEX IF EXISTS idx_tickets_tier_user;
+ALTER TABLE tickets DROP COLUMN IF EXISTS tier_id;
+DROP TABLE IF EXISTS ticket_tiers;
+
+-- Fails while a user still holds several tickets of one event; those have to
+-- be cleaned up by hand before rolling back.
+ALTER TABLE tickets ADD CONSTRAINT tickets_user_id_event_id_key UNIQUE (user_id, event_id);
@@ -0,0 +1,54 @@
+-- ============================================================================
+-- TICKET TIERS TABLE
+-- ============================================================================
+-- Lets a host sell several ticket types for one event (early bird, VIP,
+-- group...), each with its own price, quota, sale window and per-user limit.
+-- Events without tiers keep using events.price and events.max_attendees.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS ticket_tiers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    price DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    quota INTEGER NOT NULL CHECK (quota > 0),
+    sold INTEGER NOT NULL DEFAULT 0 CHECK (sold >= 0),  -- Pending + active tickets, maintained by AtomicPurchase
+    sales_start TIMESTAMP WITH TIME ZONE,  -- NULL = on sale immediately
+    sales_end TIMESTAMP WITH TIME ZONE,  -- NULL = on sale until the event starts
+    max_per_user INTEGER NOT NULL DEFAULT 1 CHECK (max_per_user > 0),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (sales_end IS NULL OR sales_start IS NULL OR sales_end > sales_start)
+);
+
+ALTER TABLE tickets
+ADD COLUMN IF NOT EXISTS tier_id UUID REFERENCES ticket_tiers(id) ON DELETE SET NULL;
+
+-- A buyer may hold up to max_per_user tickets of a tier, so the one ticket per
+-- user and event rule only applies to events without tiers now.
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS tickets_user_id_event_id_key;
+
+-- ============================================================================
+-- INDEXES
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_ticket_tiers_event ON ticket_tiers(event_id, sort_order);
+CREATE INDEX IF NOT EXISTS idx_tickets_tier_user ON tickets(tier_id, user_id) WHERE tier_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_user_event_untiered ON tickets(user_id, event_id) WHERE tier_id IS NULL AND status IN ('pending', 'active');
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created table:
+-- - ticket_tiers: Ticket types per event
+--
+-- Altered table:
+-- - tickets: tier_id (NULL for events without tiers); one ticket per user and
+--   event only for tickets without a tier
+--
+-- Features:
+-- - Per-tier price, quota, sale window and per-user limit
+-- - Per-tier sold counter locked during purchase to prevent overselling
+-- ============================================================================
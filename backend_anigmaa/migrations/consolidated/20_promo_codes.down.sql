@@ -0,0 +1,9 @@
+-- ============================================================================
+-- ROLLBACK PROMO CODES
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_tickets_promo_code;
+ALTER TABLE tickets DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE tickets DROP COLUMN IF EXISTS promo_code_id;
+DROP TABLE IF EXISTS promo_codes;
+DROP TYPE IF EXISTS discount_type;
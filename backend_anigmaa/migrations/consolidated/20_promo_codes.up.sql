@@ -0,0 +1,64 @@
+-- ============================================================================
+-- PROMO CODES TABLE
+-- ============================================================================
+-- Host-managed discount codes for ticket purchases. A code gives a percentage
+-- or fixed discount, can be capped in total and per user, limited to a
+-- validity window and restricted to one ticket tier.
+-- Usage is counted from tickets (pending or active) that used the code, so
+-- cancelled and expired tickets release their use automatically.
+-- ============================================================================
+
+-- ============================================================================
+-- ENUMS
+-- ============================================================================
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'discount_type') THEN
+        CREATE TYPE discount_type AS ENUM ('percentage', 'fixed');
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    tier_id UUID REFERENCES ticket_tiers(id) ON DELETE CASCADE,  -- NULL = any tier
+    code VARCHAR(50) NOT NULL,  -- Stored upper-case
+    discount_type discount_type NOT NULL,
+    discount_value DECIMAL(10, 2) NOT NULL CHECK (discount_value > 0),
+    max_uses INTEGER CHECK (max_uses > 0),  -- NULL = unlimited
+    max_uses_per_user INTEGER NOT NULL DEFAULT 1 CHECK (max_uses_per_user > 0),
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(event_id, code),
+    CHECK (discount_type <> 'percentage' OR discount_value <= 100),
+    CHECK (valid_until IS NULL OR valid_from IS NULL OR valid_until > valid_from)
+);
+
+ALTER TABLE tickets
+ADD COLUMN IF NOT EXISTS promo_code_id UUID REFERENCES promo_codes(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+-- ============================================================================
+-- INDEXES
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_promo_codes_event ON promo_codes(event_id);
+CREATE INDEX IF NOT EXISTS idx_tickets_promo_code ON tickets(promo_code_id, user_id) WHERE promo_code_id IS NOT NULL;
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created table:
+-- - promo_codes: Discount codes per event
+--
+-- Altered table:
+-- - tickets: promo_code_id, discount_amount
+--
+-- Features:
+-- - Percentage or fixed discounts, optionally restricted to a tier
+-- - Total and per-user usage caps enforced under a row lock at purchase
+-- ============================================================================
@@ -0,0 +1,6 @@
+-- ============================================================================
+-- ROLLBACK HOME TIMELINE INDEXES
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_posts_visibility_created;
+DROP INDEX IF EXISTS idx_posts_author_created;
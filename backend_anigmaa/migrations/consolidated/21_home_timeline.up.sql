@@ -0,0 +1,18 @@
+-- ============================================================================
+-- HOME TIMELINE INDEXES
+-- ============================================================================
+-- The home timeline merges the recent posts of followed authors with public
+-- discovery posts. Both candidate pools are read newest-first, per author
+-- and per visibility respectively.
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_posts_author_created ON posts(author_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_posts_visibility_created ON posts(visibility, created_at DESC);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created indexes:
+-- - idx_posts_author_created: Recent posts of followed authors
+-- - idx_posts_visibility_created: Recent public posts for discovery
+-- ============================================================================
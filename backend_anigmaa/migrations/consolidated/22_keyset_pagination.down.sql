@@ -0,0 +1,8 @@
+-- ============================================================================
+-- ROLLBACK KEYSET PAGINATION INDEXES
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_comments_post_likes_created;
+DROP INDEX IF EXISTS idx_tickets_user_purchased;
+DROP INDEX IF EXISTS idx_follows_following_created;
+DROP INDEX IF EXISTS idx_events_created_id;
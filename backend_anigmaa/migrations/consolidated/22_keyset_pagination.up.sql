@@ -0,0 +1,22 @@
+-- ============================================================================
+-- KEYSET PAGINATION INDEXES
+-- ============================================================================
+-- List endpoints are paged by cursor: each page continues strictly after the
+-- last row of the previous one in the list's sort order. These indexes match
+-- those orders so a page is read without sorting the whole list.
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_events_created_id ON events(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_follows_following_created ON follows(following_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tickets_user_purchased ON tickets(user_id, purchased_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_comments_post_likes_created ON comments(post_id, likes_count DESC, created_at DESC, id DESC);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created indexes:
+-- - idx_events_created_id: Event list, newest first
+-- - idx_follows_following_created: A user's followers, most recent first
+-- - idx_tickets_user_purchased: A user's tickets, most recent first
+-- - idx_comments_post_likes_created: A post's comments, most liked first
+-- ============================================================================
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- ROLLBACK HASHTAGS AND MENTIONS
+-- ============================================================================
+
+DROP TABLE IF EXISTS post_mentions;
+DROP TABLE IF EXISTS post_hashtags;
+DROP TABLE IF EXISTS hashtags;
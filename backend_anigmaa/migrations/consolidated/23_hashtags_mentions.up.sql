@@ -0,0 +1,66 @@
+-- ============================================================================
+-- HASHTAGS AND MENTIONS
+-- ============================================================================
+-- Hashtags and @mentions are parsed from post content when a post is created
+-- or edited. Tags are stored once, lower-case, and linked to posts; mentions
+-- are resolved to user IDs. post_hashtags keeps the post's creation time so
+-- trending hashtags can be counted over a recent window without a join.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS hashtags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tag VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS post_hashtags (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    hashtag_id UUID NOT NULL REFERENCES hashtags(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (post_id, hashtag_id)
+);
+
+CREATE TABLE IF NOT EXISTS post_mentions (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, user_id)
+);
+
+-- Posts of a hashtag, newest first
+CREATE INDEX IF NOT EXISTS idx_post_hashtags_hashtag_created ON post_hashtags(hashtag_id, created_at DESC, post_id DESC);
+-- Trending window scan
+CREATE INDEX IF NOT EXISTS idx_post_hashtags_created ON post_hashtags(created_at);
+CREATE INDEX IF NOT EXISTS idx_post_mentions_user ON post_mentions(user_id);
+
+-- Backfill from existing posts
+INSERT INTO hashtags (tag)
+SELECT DISTINCT lower(m[1])
+FROM posts p, regexp_matches(p.content, '#([[:alnum:]_]{1,100})', 'g') AS m
+WHERE lower(m[1]) ~ '[[:alpha:]]'
+ON CONFLICT (tag) DO NOTHING;
+
+INSERT INTO post_hashtags (post_id, hashtag_id, created_at)
+SELECT DISTINCT p.id, h.id, p.created_at
+FROM posts p, regexp_matches(p.content, '#([[:alnum:]_]{1,100})', 'g') AS m
+JOIN hashtags h ON h.tag = lower(m[1])
+ON CONFLICT DO NOTHING;
+
+INSERT INTO post_mentions (post_id, user_id)
+SELECT DISTINCT p.id, u.id
+FROM posts p, regexp_matches(p.content, '@([a-zA-Z0-9_-]{3,50})', 'g') AS m
+JOIN users u ON lower(u.username) = lower(m[1])
+ON CONFLICT DO NOTHING;
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - hashtags: Distinct lower-case hashtags
+-- - post_hashtags: Hashtags used by each post, with the post's creation time
+-- - post_mentions: Users mentioned by each post
+--
+-- Created indexes:
+-- - idx_post_hashtags_hashtag_created: Posts of a hashtag, newest first
+-- - idx_post_hashtags_created: Trending hashtags over a recent window
+-- - idx_post_mentions_user: Posts mentioning a user
+-- ============================================================================
@@ -0,0 +1,13 @@
+-- ============================================================================
+-- ROLLBACK FULL-TEXT SEARCH
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_users_search;
+DROP INDEX IF EXISTS idx_communities_search;
+DROP INDEX IF EXISTS idx_posts_search;
+DROP INDEX IF EXISTS idx_events_search;
+
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE communities DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE events DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,65 @@
+-- ============================================================================
+-- FULL-TEXT SEARCH
+-- ============================================================================
+-- Events, posts, communities and users get a generated tsvector column kept up
+-- to date by PostgreSQL on every write, indexed with GIN.
+--
+-- Much of our content is in Bahasa Indonesia, so prose is stemmed with both the
+-- indonesian and english configurations; names are also indexed unstemmed
+-- ('simple') so exact names always match. Searches query all three.
+--
+-- Weights: A = title/name, B = body, C = secondary fields.
+-- ============================================================================
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(description, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(location_name, '')), 'C')
+    ) STORED;
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(content, '')), 'B') ||
+        setweight(to_tsvector('indonesian', coalesce(content, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'B')
+    ) STORED;
+
+ALTER TABLE communities ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(description, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(username, '')), 'A') ||
+        setweight(to_tsvector('indonesian', coalesce(bio, '')), 'C') ||
+        setweight(to_tsvector('english', coalesce(bio, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_events_search ON events USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_communities_search ON communities USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_users_search ON users USING GIN (search_vector);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Added columns:
+-- - events.search_vector: title, description, location name
+-- - posts.search_vector: content
+-- - communities.search_vector: name, description
+-- - users.search_vector: name, username, bio
+--
+-- Created indexes:
+-- - idx_events_search, idx_posts_search, idx_communities_search,
+--   idx_users_search: GIN indexes on the search vectors
+-- ============================================================================
@@ -0,0 +1,11 @@
+-- ============================================================================
+-- ROLLBACK EVENT INVITATIONS AND INVITE LINKS
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_invitations_event_invitee;
+
+ALTER TABLE invitations DROP COLUMN IF EXISTS invite_link_id;
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_status_check;
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS invitations_event_id_fkey;
+
+DROP TABLE IF EXISTS event_invite_links;
@@ -0,0 +1,61 @@
+-- ============================================================================
+-- EVENT INVITATIONS AND INVITE LINKS
+-- ============================================================================
+-- Hosts invite users to their events directly or share an invite link that
+-- expires and can be capped to a number of uses. Redeeming a link records an
+-- accepted invitation from the link's creator, so both paths credit the
+-- inviter's invites_successful_count through update_invites_count_trigger.
+-- An accepted invitation grants access to friends_only and private events.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS event_invite_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    max_uses INTEGER CHECK (max_uses IS NULL OR max_uses > 0),
+    used_count INTEGER NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_invite_links_event ON event_invite_links(event_id, created_at DESC);
+
+-- Invitations were created before events existed; link them now
+DELETE FROM invitations WHERE event_id IS NOT NULL AND event_id NOT IN (SELECT id FROM events);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'invitations_event_id_fkey') THEN
+        ALTER TABLE invitations
+            ADD CONSTRAINT invitations_event_id_fkey FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'invitations_status_check') THEN
+        ALTER TABLE invitations
+            ADD CONSTRAINT invitations_status_check CHECK (status IN ('pending', 'accepted', 'declined', 'revoked'));
+    END IF;
+END $$;
+
+-- The link an invitation was accepted through, if any
+ALTER TABLE invitations
+    ADD COLUMN IF NOT EXISTS invite_link_id UUID REFERENCES event_invite_links(id) ON DELETE SET NULL;
+
+-- Access checks look up a user's invitation to an event
+CREATE INDEX IF NOT EXISTS idx_invitations_event_invitee ON invitations(event_id, invitee_id, status);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - event_invite_links: expiring, usage-capped invite tokens of an event
+--
+-- Altered tables:
+-- - invitations: event_id references events, status is checked,
+--   invite_link_id records the link an invitation was accepted through
+--
+-- Created indexes:
+-- - idx_event_invite_links_event: links of an event, newest first
+-- - idx_invitations_event_invitee: access checks
+-- ============================================================================
@@ -0,0 +1,5 @@
+-- ============================================================================
+-- ROLLBACK EVENT WAITLIST
+-- ============================================================================
+
+DROP TABLE IF EXISTS event_waitlist;
@@ -0,0 +1,41 @@
+-- ============================================================================
+-- EVENT WAITLIST
+-- ============================================================================
+-- Users who find an event full queue up first-come first-served. When a spot
+-- frees up (a ticket is cancelled or expires, or the host raises the capacity)
+-- the next waiting user is offered a time-boxed claim. Open claims hold their
+-- spot: AtomicPurchase only sells it to the claimant until the claim lapses,
+-- after which it passes to the next user in line.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS event_waitlist (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'offered', 'claimed', 'expired', 'left')),
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    offered_at TIMESTAMP WITH TIME ZONE,
+    offer_expires_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status <> 'offered' OR offer_expires_at IS NOT NULL)
+);
+
+-- A user is in line for an event at most once at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_waitlist_active_user
+    ON event_waitlist(event_id, user_id) WHERE status IN ('waiting', 'offered');
+
+-- Queue order and open claims of an event
+CREATE INDEX IF NOT EXISTS idx_event_waitlist_queue
+    ON event_waitlist(event_id, status, joined_at, id);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - event_waitlist: FIFO waitlist entries and their time-boxed claims
+--
+-- Created indexes:
+-- - idx_event_waitlist_active_user: one waiting or offered entry per user and event
+-- - idx_event_waitlist_queue: queue order and open claims of an event
+-- ============================================================================
@@ -0,0 +1,16 @@
+-- ============================================================================
+-- ROLLBACK APPROVAL-BASED RSVP
+-- ============================================================================
+-- PostgreSQL does not support removing enum values; 'rejected' stays in
+-- attendee_status. Rejected requests are turned into cancelled ones.
+
+UPDATE event_attendees SET status = 'cancelled' WHERE status = 'rejected';
+DELETE FROM event_attendees WHERE status = 'pending';
+
+DROP INDEX IF EXISTS idx_event_attendees_event_status;
+
+ALTER TABLE event_attendees
+    DROP COLUMN IF EXISTS responded_at,
+    DROP COLUMN IF EXISTS requirements_answer;
+
+ALTER TABLE events DROP COLUMN IF EXISTS requires_approval;
@@ -0,0 +1,36 @@
+-- ============================================================================
+-- APPROVAL-BASED RSVP
+-- ============================================================================
+-- Hosts can require approval to join an event. Join requests are stored as
+-- pending event_attendees with the requester's answer to the event's
+-- requirements; the host approves (confirmed) or rejects them. Attendee
+-- counts already only include confirmed attendees.
+-- ============================================================================
+
+-- Rejected join requests (the enum value can't be used in this transaction)
+ALTER TYPE attendee_status ADD VALUE IF NOT EXISTS 'rejected';
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE event_attendees
+    ADD COLUMN IF NOT EXISTS requirements_answer TEXT,
+    ADD COLUMN IF NOT EXISTS responded_at TIMESTAMP WITH TIME ZONE;
+
+-- Hosts list the join requests of an event by status, oldest first
+CREATE INDEX IF NOT EXISTS idx_event_attendees_event_status
+    ON event_attendees(event_id, status, joined_at);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Altered types:
+-- - attendee_status: 'rejected'
+--
+-- Altered tables:
+-- - events: requires_approval
+-- - event_attendees: requirements_answer, responded_at
+--
+-- Created indexes:
+-- - idx_event_attendees_event_status: join requests of an event by status
+-- ============================================================================
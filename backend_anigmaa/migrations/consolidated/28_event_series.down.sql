@@ -0,0 +1,13 @@
+-- ============================================================================
+-- ROLLBACK RECURRING EVENT SERIES
+-- ============================================================================
+-- Generated occurrences are kept as standalone events.
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_series_occurrence_key;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS series_override,
+    DROP COLUMN IF EXISTS occurrence_start,
+    DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS event_series;
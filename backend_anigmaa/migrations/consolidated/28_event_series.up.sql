@@ -0,0 +1,54 @@
+-- ============================================================================
+-- RECURRING EVENT SERIES
+-- ============================================================================
+-- A series holds an RFC 5545 RRULE (DAILY, WEEKLY or MONTHLY) evaluated in the
+-- host's timezone from the start of its first occurrence. Occurrences are
+-- regular events linked to the series and generated ahead of time, so each
+-- one keeps its own attendees, tickets and capacity. occurrence_start is the
+-- slot of the rule an occurrence was generated for, independent of later
+-- edits to its start time; series_override marks occurrences edited on their
+-- own, which series-wide edits leave alone.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS event_series (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rrule TEXT NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    exception_dates DATE[] NOT NULL DEFAULT '{}',
+    generated_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES event_series(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS occurrence_start TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS series_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- One occurrence per slot of a series. Deferrable so a series-wide time change
+-- can shift every slot in one statement.
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_series_occurrence_key;
+ALTER TABLE events ADD CONSTRAINT events_series_occurrence_key
+    UNIQUE (series_id, occurrence_start) DEFERRABLE INITIALLY IMMEDIATE;
+
+-- Series the generation worker has to extend
+CREATE INDEX IF NOT EXISTS idx_event_series_generated_until
+    ON event_series(generated_until);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - event_series: recurrence rule, timezone and exception dates of a series
+--
+-- Altered tables:
+-- - events: series_id, occurrence_start, series_override
+--
+-- Created constraints:
+-- - events_series_occurrence_key: one occurrence per slot of a series
+--
+-- Created indexes:
+-- - idx_event_series_generated_until: series due for generating occurrences
+-- ============================================================================
@@ -0,0 +1,7 @@
+-- ============================================================================
+-- ROLLBACK EVENT REMINDERS
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_events_active_start_time;
+
+DROP TABLE IF EXISTS event_reminders;
@@ -0,0 +1,31 @@
+-- ============================================================================
+-- EVENT REMINDERS
+-- ============================================================================
+-- The lifecycle worker reminds ticket holders and attendees 24 hours and 1
+-- hour before an event starts. A row is claimed per reminder before it is
+-- sent, so each one goes out once even with several server instances. Rows
+-- are keyed by the start time they were sent for: rescheduling an event
+-- sends its reminders again for the new time.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS event_reminders (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    kind VARCHAR(8) NOT NULL CHECK (kind IN ('24h', '1h')),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, kind, start_time)
+);
+
+-- Status transitions and reminders scan active events by start and end time
+CREATE INDEX IF NOT EXISTS idx_events_active_start_time ON events(start_time)
+    WHERE status IN ('upcoming', 'ongoing');
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - event_reminders: reminders sent per event, kind and start time
+--
+-- Created indexes:
+-- - idx_events_active_start_time: upcoming and ongoing events by start time
+-- ============================================================================
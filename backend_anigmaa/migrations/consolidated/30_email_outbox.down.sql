@@ -0,0 +1,6 @@
+-- ============================================================================
+-- ROLLBACK EMAIL OUTBOX
+-- ============================================================================
+
+DROP TABLE IF EXISTS email_attachments;
+DROP TABLE IF EXISTS email_outbox;
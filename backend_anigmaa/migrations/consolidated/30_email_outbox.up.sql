@@ -0,0 +1,49 @@
+-- ============================================================================
+-- EMAIL OUTBOX
+-- ============================================================================
+-- Transactional emails (ticket confirmations, receipts, refunds, event
+-- changes) are rendered and stored here before they're sent. The outbox
+-- worker claims due emails with FOR UPDATE SKIP LOCKED, so several server
+-- instances can deliver side by side, and retries failed deliveries with
+-- exponential backoff until it gives up and marks them failed.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS email_outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    recipient VARCHAR(255) NOT NULL,
+    template VARCHAR(50) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    subject TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS email_attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    email_id UUID NOT NULL REFERENCES email_outbox(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    content BYTEA NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_due ON email_outbox(next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_email_attachments_email_id ON email_attachments(email_id);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - email_outbox: rendered emails with their delivery status and attempts
+-- - email_attachments: files sent with an email (e.g. ticket QR codes)
+--
+-- Created indexes:
+-- - idx_email_outbox_due: pending emails by next attempt
+-- - idx_email_attachments_email_id: attachments of an email
+-- ============================================================================
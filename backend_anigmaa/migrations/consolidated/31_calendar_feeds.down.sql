@@ -0,0 +1,10 @@
+-- ============================================================================
+-- ROLLBACK CALENDAR FEEDS
+-- ============================================================================
+
+DROP TRIGGER IF EXISTS bump_events_calendar_sequence ON events;
+DROP FUNCTION IF EXISTS bump_event_calendar_sequence();
+
+ALTER TABLE events DROP COLUMN IF EXISTS calendar_sequence;
+
+DROP TABLE IF EXISTS calendar_feed_tokens;
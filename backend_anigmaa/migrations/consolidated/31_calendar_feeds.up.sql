@@ -0,0 +1,53 @@
+-- ============================================================================
+-- CALENDAR FEEDS
+-- ============================================================================
+-- Events are exported as iCalendar (.ics). Each user can subscribe to a feed
+-- of the events they hold tickets for or host through a secret URL; the
+-- token in it can be reset to revoke old subscriptions. Only a SHA-256 hash
+-- of the token is stored, like kiosk tokens.
+--
+-- Calendar apps only replace an event they already imported when its
+-- SEQUENCE goes up, so events count the changes that matter to a calendar
+-- entry: its time, place, title and whether it is cancelled.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS calendar_sequence INTEGER NOT NULL DEFAULT 0;
+
+-- ============================================================================
+-- TRIGGERS
+-- ============================================================================
+
+CREATE OR REPLACE FUNCTION bump_event_calendar_sequence()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.start_time IS DISTINCT FROM OLD.start_time
+        OR NEW.end_time IS DISTINCT FROM OLD.end_time
+        OR NEW.title IS DISTINCT FROM OLD.title
+        OR NEW.location_name IS DISTINCT FROM OLD.location_name
+        OR NEW.location_address IS DISTINCT FROM OLD.location_address
+        OR (NEW.status = 'cancelled') IS DISTINCT FROM (OLD.status = 'cancelled') THEN
+        NEW.calendar_sequence := OLD.calendar_sequence + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS bump_events_calendar_sequence ON events;
+CREATE TRIGGER bump_events_calendar_sequence BEFORE UPDATE ON events
+    FOR EACH ROW EXECUTE FUNCTION bump_event_calendar_sequence();
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - calendar_feed_tokens: hash of the secret calendar feed token per user
+--
+-- Altered tables:
+-- - events: calendar_sequence, bumped by the bump_events_calendar_sequence trigger
+-- ============================================================================
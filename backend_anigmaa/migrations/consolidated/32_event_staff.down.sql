@@ -0,0 +1,7 @@
+-- ============================================================================
+-- ROLLBACK EVENT STAFF AND KIOSK CHECK-IN
+-- ============================================================================
+
+DROP TABLE IF EXISTS ticket_check_ins;
+DROP TABLE IF EXISTS event_kiosk_tokens;
+DROP TABLE IF EXISTS event_staff;
@@ -0,0 +1,68 @@
+-- ============================================================================
+-- EVENT STAFF AND KIOSK CHECK-IN
+-- ============================================================================
+-- Hosts grant people scoped roles on their events: co-hosts help run the
+-- whole event, check-in staff work the door and Q&A moderators answer and
+-- remove questions. Staff with check-in rights issue kiosk tokens that only
+-- let a device at one gate check tickets in, so the door doesn't need
+-- anyone's full session.
+--
+-- Every scan is logged. The scan ID is chosen by the scanning device, so a
+-- retried request is recognised instead of being counted twice; a second
+-- scan of a ticket that is already in is logged as a duplicate.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS event_staff (
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('co_host', 'check_in', 'qna_moderator')),
+    added_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_staff_user_id ON event_staff(user_id);
+
+CREATE TABLE IF NOT EXISTS event_kiosk_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    issued_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    gate VARCHAR(50) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE, -- SHA-256 of the token, which is only shown once
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_kiosk_tokens_event_id ON event_kiosk_tokens(event_id);
+
+CREATE TABLE IF NOT EXISTS ticket_check_ins (
+    id UUID PRIMARY KEY, -- scan ID chosen by the scanning device
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    gate VARCHAR(50) NOT NULL DEFAULT '',
+    scanned_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kiosk_token_id UUID REFERENCES event_kiosk_tokens(id) ON DELETE SET NULL,
+    outcome VARCHAR(20) NOT NULL CHECK (outcome IN ('checked_in', 'duplicate')),
+    scanned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Gate counts of an event, and the scan that let a ticket in
+CREATE INDEX IF NOT EXISTS idx_ticket_check_ins_event_gate ON ticket_check_ins(event_id, gate);
+CREATE INDEX IF NOT EXISTS idx_ticket_check_ins_ticket_id ON ticket_check_ins(ticket_id);
+
+-- ============================================================================
+-- SUMMARY
+-- ============================================================================
+-- Created tables:
+-- - event_staff: roles granted on an event by its host
+-- - event_kiosk_tokens: check-in-only device tokens per event and gate
+-- - ticket_check_ins: log of every check-in scan and its outcome
+--
+-- Created indexes:
+-- - idx_event_staff_user_id: events a user is staff of
+-- - idx_event_kiosk_tokens_event_id: kiosk tokens of an event
+-- - idx_ticket_check_ins_event_gate: scans of an event by gate
+-- - idx_ticket_check_ins_ticket_id: scans of a ticket
+-- ============================================================================
@@ -0,0 +1,214 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar files
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is the longest a content line may be in octets, excluding CRLF (RFC 5545 3.1)
+const maxLineLength = 75
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is an iCalendar object (RFC 5545) holding events
+type Calendar struct {
+	ProdID string
+	// Name is shown by calendar apps for subscribed feeds (X-WR-CALNAME)
+	Name string
+	// Timezone event times are written in; UTC when nil
+	Timezone *Timezone
+	// RefreshInterval tells subscribers how often to fetch the calendar again; 0 leaves it to them
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Timezone is a timezone without daylight saving time, like every timezone of Indonesia
+type Timezone struct {
+	ID     string // IANA name, e.g. "Asia/Jakarta"
+	Name   string // abbreviation, e.g. "WIB"
+	Offset time.Duration
+}
+
+// Event is a VEVENT. Events are identified by UID across exports; calendar apps
+// replace an event they already have when its Sequence is higher.
+type Event struct {
+	UID          string
+	Sequence     int
+	Summary      string
+	Description  string
+	Start        time.Time
+	End          time.Time
+	Location     string
+	Address      string
+	Geo          *Geo
+	URL          string
+	Status       string
+	Created      time.Time
+	LastModified time.Time
+}
+
+// Geo is the position of an event's location
+type Geo struct {
+	Lat float64
+	Lng float64
+}
+
+// Marshal encodes the calendar as an .ics file
+func (c *Calendar) Marshal() []byte {
+	w := &writer{}
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + c.ProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		interval := formatDuration(c.RefreshInterval)
+		w.line("REFRESH-INTERVAL;VALUE=DURATION:" + interval)
+		w.line("X-PUBLISHED-TTL:" + interval)
+	}
+
+	if tz := c.Timezone; tz != nil {
+		offset := formatOffset(tz.Offset)
+		w.line("X-WR-TIMEZONE:" + tz.ID)
+		w.line("BEGIN:VTIMEZONE")
+		w.line("TZID:" + tz.ID)
+		w.line("BEGIN:STANDARD")
+		w.line("DTSTART:19700101T000000")
+		w.line("TZOFFSETFROM:" + offset)
+		w.line("TZOFFSETTO:" + offset)
+		w.line("TZNAME:" + tz.Name)
+		w.line("END:STANDARD")
+		w.line("END:VTIMEZONE")
+	}
+
+	for i := range c.Events {
+		c.writeEvent(w, &c.Events[i])
+	}
+
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func (c *Calendar) writeEvent(w *writer, e *Event) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + e.UID)
+	// The last change stamps the event, so exports of an unchanged event are identical
+	w.line("DTSTAMP:" + formatUTC(e.LastModified))
+	w.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	w.line("DTSTART" + c.formatTime(e.Start))
+	w.line("DTEND" + c.formatTime(e.End))
+	w.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+
+	location := e.Location
+	if e.Address != "" {
+		if location != "" {
+			location += ", "
+		}
+		location += e.Address
+	}
+	if location != "" {
+		w.line("LOCATION:" + escapeText(location))
+	}
+	if e.Geo != nil {
+		w.line(fmt.Sprintf("GEO:%.6f;%.6f", e.Geo.Lat, e.Geo.Lng))
+		// Apple Calendar shows a map and travel time only for its own structured location
+		w.line(fmt.Sprintf("X-APPLE-STRUCTURED-LOCATION;VALUE=URI;X-ADDRESS=%s;X-APPLE-RADIUS=100;X-TITLE=%s:geo:%.6f,%.6f",
+			quoteParam(e.Address), quoteParam(e.Location), e.Geo.Lat, e.Geo.Lng))
+	}
+
+	if e.URL != "" {
+		w.line("URL:" + e.URL)
+	}
+	if e.Status != "" {
+		w.line("STATUS:" + e.Status)
+	}
+	w.line("CREATED:" + formatUTC(e.Created))
+	w.line("LAST-MODIFIED:" + formatUTC(e.LastModified))
+	w.line("END:VEVENT")
+}
+
+// formatTime formats a DTSTART/DTEND property (parameters and value) in the
+// calendar's timezone
+func (c *Calendar) formatTime(t time.Time) string {
+	if c.Timezone == nil {
+		return ":" + formatUTC(t)
+	}
+	local := t.In(time.FixedZone(c.Timezone.Name, int(c.Timezone.Offset.Seconds())))
+	return fmt.Sprintf(";TZID=%s:%s", c.Timezone.ID, local.Format("20060102T150405"))
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// formatOffset formats a UTC offset, e.g. "+0700"
+func formatOffset(offset time.Duration) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	minutes := int(offset.Minutes())
+	return fmt.Sprintf("%s%02d%02d", sign, minutes/60, minutes%60)
+}
+
+// formatDuration formats a duration of whole minutes, e.g. "PT1H" or "PT90M"
+func formatDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("PT%dH", int(d.Hours()))
+	}
+	return fmt.Sprintf("PT%dM", int(d.Minutes()))
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+// escapeText escapes a TEXT value (RFC 5545 3.3.11)
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// quoteParam quotes a parameter value, which can't contain double quotes or line breaks
+func quoteParam(s string) string {
+	s = strings.NewReplacer(`"`, "'", "\r", "", "\n", " ").Replace(s)
+	return `"` + s + `"`
+}
+
+// writer writes content lines, folding the ones longer than maxLineLength
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) line(s string) {
+	limit := maxLineLength
+	for len(s) > limit {
+		// Fold between characters, never inside a multi-byte one
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// The space starting a continuation line counts towards its length
+		limit = maxLineLength - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
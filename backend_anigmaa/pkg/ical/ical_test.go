@@ -0,0 +1,93 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshal(t *testing.T) {
+	start := time.Date(2025, time.January, 4, 12, 0, 0, 0, time.UTC)
+	cal := &Calendar{
+		ProdID:          "-//Anigmaa//Events//EN",
+		Name:            "Anigmaa",
+		Timezone:        &Timezone{ID: "Asia/Jakarta", Name: "WIB", Offset: 7 * time.Hour},
+		RefreshInterval: time.Hour,
+		Events: []Event{{
+			UID:          "e1@anigmaa.com",
+			Sequence:     2,
+			Summary:      "Jazz, Kopi; & Senja",
+			Description:  "Bawa teman\nGratis kopi",
+			Start:        start,
+			End:          start.Add(3 * time.Hour),
+			Location:     "Kopi Kenangan",
+			Address:      "Jl. Sudirman 1",
+			Geo:          &Geo{Lat: -6.2, Lng: 106.816666},
+			Status:       StatusConfirmed,
+			Created:      start.Add(-48 * time.Hour),
+			LastModified: start.Add(-24 * time.Hour),
+		}},
+	}
+
+	out := string(cal.Marshal())
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"TZID:Asia/Jakarta\r\n",
+		"TZOFFSETTO:+0700\r\n",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n",
+		"SEQUENCE:2\r\n",
+		"DTSTART;TZID=Asia/Jakarta:20250104T190000\r\n",
+		"DTEND;TZID=Asia/Jakarta:20250104T220000\r\n",
+		"DTSTAMP:20250103T120000Z\r\n",
+		`SUMMARY:Jazz\, Kopi\; & Senja` + "\r\n",
+		`DESCRIPTION:Bawa teman\nGratis kopi` + "\r\n",
+		"GEO:-6.200000;106.816666\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar doesn't contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMarshalUTC(t *testing.T) {
+	start := time.Date(2025, time.January, 4, 19, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	cal := &Calendar{Events: []Event{{UID: "e1", Start: start, End: start}}}
+
+	out := string(cal.Marshal())
+	if !strings.Contains(out, "DTSTART:20250104T120000Z\r\n") {
+		t.Errorf("start isn't in UTC:\n%s", out)
+	}
+	if strings.Contains(out, "VTIMEZONE") {
+		t.Errorf("calendar without timezone has a VTIMEZONE:\n%s", out)
+	}
+}
+
+func TestFolding(t *testing.T) {
+	w := &writer{}
+	// Multi-byte characters must not be split across lines
+	w.line("SUMMARY:" + strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(w.buf.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("long line wasn't folded: %q", lines)
+	}
+
+	var unfolded strings.Builder
+	for i, line := range lines {
+		if len(line) > maxLineLength {
+			t.Errorf("line %d is %d octets long, want at most %d", i, len(line), maxLineLength)
+		}
+		if i > 0 {
+			if !strings.HasPrefix(line, " ") {
+				t.Errorf("continuation line %d doesn't start with a space", i)
+			}
+			line = line[1:]
+		}
+		unfolded.WriteString(line)
+	}
+	if unfolded.String() != "SUMMARY:"+strings.Repeat("é", 100) {
+		t.Errorf("unfolded line = %q", unfolded.String())
+	}
+}
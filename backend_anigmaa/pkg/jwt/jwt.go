@@ -13,13 +13,30 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// Token types carried in the "typ" claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 // Claims represents JWT claims
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	TokenType string    `json:"typ,omitempty"`
+	SessionID string    `json:"sid,omitempty"` // Device session / refresh token family
 	jwt.RegisteredClaims
 }
 
+// TokenPair is an access token and refresh token issued together for one session
+type TokenPair struct {
+	AccessToken      string
+	AccessTokenID    string
+	RefreshToken     string
+	RefreshTokenID   string
+	RefreshExpiresAt time.Time
+}
+
 // JWTManager handles JWT operations
 type JWTManager struct {
 	secretKey       string
@@ -37,11 +54,13 @@ func NewJWTManager(secretKey string, tokenDuration, refreshDuration time.Duratio
 }
 
 // Generate generates a new access token
+// Every token carries a unique jti so it can be revoked individually before it expires.
 func (m *JWTManager) Generate(userID uuid.UUID, email string) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.tokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -52,20 +71,52 @@ func (m *JWTManager) Generate(userID uuid.UUID, email string) (string, error) {
 	return token.SignedString([]byte(m.secretKey))
 }
 
-// GenerateRefreshToken generates a refresh token with longer expiration
-func (m *JWTManager) GenerateRefreshToken(userID uuid.UUID, email string) (string, error) {
-	claims := Claims{
-		UserID: userID,
-		Email:  email,
+// GenerateTokenPair generates an access token and a longer-lived refresh token bound to a device session
+func (m *JWTManager) GenerateTokenPair(userID uuid.UUID, email string, sessionID uuid.UUID) (*TokenPair, error) {
+	now := time.Now()
+
+	access := Claims{
+		UserID:    userID,
+		Email:     email,
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.tokenDuration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, access).SignedString([]byte(m.secretKey))
+	if err != nil {
+		return nil, err
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secretKey))
+	refreshExpiresAt := now.Add(m.refreshDuration)
+	refresh := Claims{
+		UserID:    userID,
+		Email:     email,
+		TokenType: TokenTypeRefresh,
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refresh).SignedString([]byte(m.secretKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessTokenID:    access.ID,
+		RefreshToken:     refreshToken,
+		RefreshTokenID:   refresh.ID,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
 }
 
 // Verify verifies and parses a JWT token
@@ -93,6 +144,42 @@ func (m *JWTManager) Verify(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// TokenDuration returns the lifetime of access tokens
+func (m *JWTManager) TokenDuration() time.Duration {
+	return m.tokenDuration
+}
+
+// RefreshDuration returns the lifetime of refresh tokens, the longest-lived tokens this manager issues
+func (m *JWTManager) RefreshDuration() time.Duration {
+	return m.refreshDuration
+}
+
+// RemainingLifetime returns how long the token is still valid for (zero if already expired)
+func (c *Claims) RemainingLifetime() time.Duration {
+	if c.ExpiresAt == nil {
+		return 0
+	}
+	remaining := time.Until(c.ExpiresAt.Time)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// IsRefreshToken checks if the claims belong to a refresh token
+func (c *Claims) IsRefreshToken() bool {
+	return c.TokenType == TokenTypeRefresh
+}
+
+// GetSessionID returns the device session the token belongs to (uuid.Nil for legacy tokens)
+func (c *Claims) GetSessionID() uuid.UUID {
+	id, err := uuid.Parse(c.SessionID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
 // GetUserID extracts user ID from token
 func (m *JWTManager) GetUserID(tokenString string) (uuid.UUID, error) {
 	claims, err := m.Verify(tokenString)
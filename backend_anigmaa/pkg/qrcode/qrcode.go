@@ -1,42 +1,135 @@
 package qrcode
 
 import (
+	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/skip2/go-qrcode"
 )
 
+// payloadVersion prefixes every signed payload so the format can evolve
+const payloadVersion = "v1"
+
+// manifestVersion prefixes signed check-in manifests. The prefix is signed too,
+// so a manifest never verifies as a ticket QR.
+const manifestVersion = "m1"
+
+var (
+	ErrMalformedPayload = errors.New("malformed ticket QR payload")
+	ErrInvalidSignature = errors.New("invalid ticket QR signature")
+	ErrExpired          = errors.New("ticket QR has expired")
+)
+
 // TicketQRData represents the data encoded in a ticket QR code
 type TicketQRData struct {
 	TicketID       uuid.UUID `json:"ticket_id"`
 	EventID        uuid.UUID `json:"event_id"`
 	AttendanceCode string    `json:"attendance_code"`
 	UserID         uuid.UUID `json:"user_id"`
+	IssuedAt       int64     `json:"iat"`
+	ExpiresAt      int64     `json:"exp"`
 }
 
-// GenerateTicketQR generates a QR code for a ticket and returns it as a base64-encoded PNG
-func GenerateTicketQR(ticketID, eventID, userID uuid.UUID, attendanceCode string) (string, error) {
-	// Create QR data payload
-	data := TicketQRData{
-		TicketID:       ticketID,
-		EventID:        eventID,
-		AttendanceCode: attendanceCode,
-		UserID:         userID,
+// Signer signs ticket QR payloads with an Ed25519 key.
+// Scanners only need the public key to verify a ticket, so check-in keeps
+// working offline and a leaked scanner cannot mint tickets.
+type Signer struct {
+	privateKey ed25519.PrivateKey
+}
+
+// NewSigner creates a signer from a base64-encoded 32-byte Ed25519 seed
+func NewSigner(encodedSeed string) (*Signer, error) {
+	seed, err := base64.StdEncoding.DecodeString(encodedSeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode QR signing key: %w", err)
 	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("QR signing key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	return &Signer{privateKey: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// NewSignerFromSecret derives a signer from an arbitrary secret string.
+// Intended for development when no dedicated signing key is configured.
+func NewSignerFromSecret(secret string) *Signer {
+	seed := sha256.Sum256([]byte("ticket-qr:" + secret))
+	return &Signer{privateKey: ed25519.NewKeyFromSeed(seed[:])}
+}
+
+// PublicKey returns the verification key to distribute to scanner apps
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.privateKey.Public().(ed25519.PublicKey)
+}
 
-	// Marshal to JSON
+// EncodedPublicKey returns the verification key as base64
+func (s *Signer) EncodedPublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.PublicKey())
+}
+
+// Sign produces the signed payload string: v1.<base64url(json)>.<base64url(signature)>
+func (s *Signer) Sign(data *TicketQRData) (string, error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal QR data: %w", err)
 	}
 
-	// Generate QR code (256x256 pixels, medium error correction)
-	qrCode, err := qrcode.Encode(string(jsonData), qrcode.Medium, 256)
+	body := payloadVersion + "." + base64.RawURLEncoding.EncodeToString(jsonData)
+	signature := ed25519.Sign(s.privateKey, []byte(body))
+
+	return body + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// SignManifest signs a check-in manifest for offline scanners, which verify it
+// with the same public key as ticket QRs: m1.<base64url(json)>.<base64url(signature)>
+func (s *Signer) SignManifest(manifest interface{}) (string, error) {
+	jsonData, err := json.Marshal(manifest)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	body := manifestVersion + "." + base64.RawURLEncoding.EncodeToString(jsonData)
+	signature := ed25519.Sign(s.privateKey, []byte(body))
+
+	return body + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// DecodeManifest verifies a signed check-in manifest against publicKey and decodes it into v
+func DecodeManifest(signed string, publicKey ed25519.PublicKey, v interface{}) error {
+	parts := strings.Split(strings.TrimSpace(signed), ".")
+	if len(parts) != 3 || parts[0] != manifestVersion {
+		return ErrMalformedPayload
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
 	if err != nil {
-		return "", fmt.Errorf("failed to generate QR code: %w", err)
+		return ErrMalformedPayload
+	}
+
+	if !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return ErrInvalidSignature
+	}
+
+	jsonData, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrMalformedPayload
+	}
+
+	return json.Unmarshal(jsonData, v)
+}
+
+// GenerateTicketQR signs the ticket data and returns the QR code as a base64-encoded PNG.
+// The QR stays valid until expiresAt.
+func (s *Signer) GenerateTicketQR(ticketID, eventID, userID uuid.UUID, attendanceCode string, expiresAt time.Time) (string, error) {
+	qrCode, err := s.GenerateTicketQRPNG(ticketID, eventID, userID, attendanceCode, expiresAt)
+	if err != nil {
+		return "", err
 	}
 
 	// Convert to base64
@@ -46,11 +139,64 @@ func GenerateTicketQR(ticketID, eventID, userID uuid.UUID, attendanceCode string
 	return fmt.Sprintf("data:image/png;base64,%s", base64QR), nil
 }
 
-// DecodeTicketQR decodes a JSON string from QR code back into TicketQRData
-func DecodeTicketQR(qrContent string) (*TicketQRData, error) {
+// GenerateTicketQRPNG signs the ticket data and returns the QR code as a PNG image,
+// e.g. to attach it to an email
+func (s *Signer) GenerateTicketQRPNG(ticketID, eventID, userID uuid.UUID, attendanceCode string, expiresAt time.Time) ([]byte, error) {
+	// Create QR data payload
+	data := &TicketQRData{
+		TicketID:       ticketID,
+		EventID:        eventID,
+		AttendanceCode: attendanceCode,
+		UserID:         userID,
+		IssuedAt:       time.Now().Unix(),
+		ExpiresAt:      expiresAt.Unix(),
+	}
+
+	payload, err := s.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate QR code (256x256 pixels, medium error correction)
+	qrCode, err := qrcode.Encode(payload, qrcode.Medium, 256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+
+	return qrCode, nil
+}
+
+// DecodeTicketQR decodes the signed string read from a QR code back into TicketQRData.
+// It verifies the signature against publicKey and rejects expired payloads.
+func DecodeTicketQR(qrContent string, publicKey ed25519.PublicKey) (*TicketQRData, error) {
+	parts := strings.Split(strings.TrimSpace(qrContent), ".")
+	if len(parts) != 3 || parts[0] != payloadVersion {
+		return nil, ErrMalformedPayload
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedPayload
+	}
+
+	// Verify before parsing so tampered JSON is never trusted
+	if !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	jsonData, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedPayload
+	}
+
 	var data TicketQRData
-	if err := json.Unmarshal([]byte(qrContent), &data); err != nil {
+	if err := json.Unmarshal(jsonData, &data); err != nil {
 		return nil, fmt.Errorf("failed to decode QR data: %w", err)
 	}
+
+	if time.Now().Unix() > data.ExpiresAt {
+		return nil, ErrExpired
+	}
+
 	return &data, nil
 }
@@ -0,0 +1,142 @@
+package qrcode
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestData(expiresAt time.Time) *TicketQRData {
+	return &TicketQRData{
+		TicketID:       uuid.New(),
+		EventID:        uuid.New(),
+		AttendanceCode: "ABCD2345",
+		UserID:         uuid.New(),
+		IssuedAt:       time.Now().Unix(),
+		ExpiresAt:      expiresAt.Unix(),
+	}
+}
+
+func TestSignAndDecodeTicketQR(t *testing.T) {
+	signer := NewSignerFromSecret("test")
+	data := newTestData(time.Now().Add(time.Hour))
+
+	payload, err := signer.Sign(data)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if !strings.HasPrefix(payload, payloadVersion+".") {
+		t.Errorf("payload = %q, want the %s prefix", payload, payloadVersion)
+	}
+
+	got, err := DecodeTicketQR(payload, signer.PublicKey())
+	if err != nil {
+		t.Fatalf("DecodeTicketQR() error = %v", err)
+	}
+	if *got != *data {
+		t.Errorf("DecodeTicketQR() = %+v, want %+v", got, data)
+	}
+}
+
+func TestDecodeTicketQR_Rejects(t *testing.T) {
+	signer := NewSignerFromSecret("test")
+	valid, err := signer.Sign(newTestData(time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	expired, err := signer.Sign(newTestData(time.Now().Add(-time.Minute)))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	otherKey, err := NewSignerFromSecret("other").Sign(newTestData(time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	manifest, err := signer.SignManifest(map[string]string{"event_id": uuid.NewString()})
+	if err != nil {
+		t.Fatalf("SignManifest() error = %v", err)
+	}
+
+	parts := strings.Split(valid, ".")
+	tamperedData := newTestData(time.Now().Add(time.Hour))
+	tamperedData.AttendanceCode = "WXYZ6789"
+	tampered, err := signer.Sign(tamperedData)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	tamperedParts := strings.Split(tampered, ".")
+
+	tests := []struct {
+		name    string
+		payload string
+		want    error
+	}{
+		{"expired", expired, ErrExpired},
+		{"signed with another key", otherKey, ErrInvalidSignature},
+		{"tampered body", tamperedParts[0] + "." + tamperedParts[1] + "." + parts[2], ErrInvalidSignature},
+		{"signature cut short", parts[0] + "." + parts[1] + "." + parts[2][:10], ErrInvalidSignature},
+		{"signature not base64", parts[0] + "." + parts[1] + ".!!!", ErrMalformedPayload},
+		{"unknown version", "v2." + parts[1] + "." + parts[2], ErrMalformedPayload},
+		{"missing signature", parts[0] + "." + parts[1], ErrMalformedPayload},
+		{"check-in manifest", manifest, ErrMalformedPayload},
+		{"empty", "", ErrMalformedPayload},
+		{"plain attendance code", "ABCD2345", ErrMalformedPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeTicketQR(tt.payload, signer.PublicKey()); !errors.Is(err, tt.want) {
+				t.Errorf("DecodeTicketQR() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	seed := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	signer, err := NewSigner(seed)
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	if got := len(signer.PublicKey()); got != 32 {
+		t.Errorf("public key is %d bytes, want 32", got)
+	}
+
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString(make([]byte, 16))} {
+		if _, err := NewSigner(key); err == nil {
+			t.Errorf("NewSigner(%q) succeeded, want an error", key)
+		}
+	}
+}
+
+func TestDecodeManifest(t *testing.T) {
+	signer := NewSignerFromSecret("test")
+	signed, err := signer.SignManifest(map[string]int{"tickets": 3})
+	if err != nil {
+		t.Fatalf("SignManifest() error = %v", err)
+	}
+
+	var got map[string]int
+	if err := DecodeManifest(signed, signer.PublicKey(), &got); err != nil {
+		t.Fatalf("DecodeManifest() error = %v", err)
+	}
+	if got["tickets"] != 3 {
+		t.Errorf("DecodeManifest() = %v, want 3 tickets", got)
+	}
+
+	if err := DecodeManifest(signed, NewSignerFromSecret("other").PublicKey(), &got); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("DecodeManifest() with another key error = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	ticketQR, err := signer.Sign(newTestData(time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if err := DecodeManifest(ticketQR, signer.PublicKey(), &got); !errors.Is(err, ErrMalformedPayload) {
+		t.Errorf("DecodeManifest() of a ticket QR error = %v, want %v", err, ErrMalformedPayload)
+	}
+}
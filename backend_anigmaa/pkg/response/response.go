@@ -22,10 +22,13 @@ type ErrorInfo struct {
 
 // PaginationMeta contains pagination metadata
 type PaginationMeta struct {
-	Total   int  `json:"total"`
+	// Total is left out of keyset-paginated pages unless the client asked for it
+	Total   *int `json:"total,omitempty"`
 	Limit   int  `json:"limit"`
 	Offset  int  `json:"offset"`
 	HasNext bool `json:"hasNext"`
+	// NextCursor is set by keyset-paginated endpoints; pass it back as ?cursor= to get the next page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // PaginatedResponse represents a paginated API response
@@ -71,13 +74,24 @@ func Paginated(c *gin.Context, statusCode int, message string, data interface{},
 func NewPaginationMeta(total, limit, offset, currentCount int) *PaginationMeta {
 	hasNext := offset+currentCount < total
 	return &PaginationMeta{
-		Total:   total,
+		Total:   &total,
 		Limit:   limit,
 		Offset:  offset,
 		HasNext: hasNext,
 	}
 }
 
+// NewCursorPaginationMeta creates pagination metadata for keyset-paginated endpoints.
+// Total is not computed for cursor pages, so it is left out.
+func NewCursorPaginationMeta(limit int, nextCursor string) *PaginationMeta {
+	return &PaginationMeta{
+		Limit:      limit,
+		Offset:     0,
+		HasNext:    nextCursor != "",
+		NextCursor: nextCursor,
+	}
+}
+
 // BadRequest sends a 400 Bad Request response
 func BadRequest(c *gin.Context, message string, details string) {
 	Error(c, http.StatusBadRequest, message, "BAD_REQUEST", details)
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a keyset pagination position: the sort timestamp and ID of the
+// last item of the previous page. Lists ranked by a score first (likes, upvotes)
+// also carry that item's score.
+type Cursor struct {
+	Score     int
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// EncodeCursor encodes a keyset position into an opaque URL-safe string
+// Example: EncodeCursor(n.CreatedAt, n.ID) -> "MjAyNS0wMS0wMVQx..."
+func EncodeCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// EncodeScoredCursor encodes a keyset position of a list ordered by score, then timestamp and ID
+func EncodeScoredCursor(score int, createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String() + "|" + strconv.Itoa(score)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor decodes a cursor produced by EncodeCursor or EncodeScoredCursor.
+// An empty string returns (nil, nil), meaning "start from the first page".
+func DecodeCursor(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 2 && len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	position := &Cursor{CreatedAt: createdAt, ID: id}
+	if len(parts) == 3 {
+		if position.Score, err = strconv.Atoi(parts[2]); err != nil {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return position, nil
+}
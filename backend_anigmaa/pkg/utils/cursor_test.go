@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.FixedZone("WIB", 7*60*60))
+	id := uuid.New()
+
+	got, err := DecodeCursor(EncodeCursor(createdAt, id))
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(createdAt) || got.ID != id || got.Score != 0 {
+		t.Errorf("DecodeCursor() = %+v, want %s and %s", got, createdAt, id)
+	}
+
+	scored, err := DecodeCursor(EncodeScoredCursor(42, createdAt, id))
+	if err != nil {
+		t.Fatalf("DecodeCursor() of a scored cursor error = %v", err)
+	}
+	if scored.Score != 42 || !scored.CreatedAt.Equal(createdAt) || scored.ID != id {
+		t.Errorf("DecodeCursor() = %+v, want score 42", scored)
+	}
+
+	if got, err := DecodeCursor(""); got != nil || err != nil {
+		t.Errorf("DecodeCursor(\"\") = %v, %v, want the first page", got, err)
+	}
+}
+
+func TestDecodeCursor_Rejects(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	id := uuid.NewString()
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"no separator", encode("2025-01-02T03:04:05Z")},
+		{"too many parts", encode("2025-01-02T03:04:05Z|" + id + "|1|2")},
+		{"bad timestamp", encode("yesterday|" + id)},
+		{"bad id", encode("2025-01-02T03:04:05Z|not-a-uuid")},
+		{"bad score", encode("2025-01-02T03:04:05Z|" + id + "|many")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.cursor); err != ErrInvalidCursor {
+				t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}